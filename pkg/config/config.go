@@ -0,0 +1,298 @@
+// Package config loads filter and transformer pipelines from declarative YAML or JSON
+// definitions, so that pipelines can live in configuration files.
+//
+// A pipeline lists filters and transformers; each entry has a type and the parameters of that
+// type inline:
+//
+//	filters:
+//	  - type: namespace
+//	    namespaces: [production]
+//	  - type: not
+//	    filter:
+//	      type: gvk.kind
+//	      kinds: [Secret]
+//	transformers:
+//	  - type: labels.set
+//	    labels:
+//	      team: platform
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+var (
+	// ErrUnknownType is returned for entries whose type has no registered factory.
+	ErrUnknownType = errors.New("unknown type")
+
+	// ErrInvalidEntry is returned for entries that are not objects with a type, or whose
+	// parameters do not match their type.
+	ErrInvalidEntry = errors.New("invalid entry")
+)
+
+// Pipeline is a loaded pipeline definition.
+type Pipeline struct {
+	// Filters are the filters of the pipeline, in definition order.
+	Filters []types.Filter
+
+	// Transformers are the transformers of the pipeline, in definition order.
+	Transformers []types.Transformer
+}
+
+// Definition is the declarative form of a pipeline.
+type Definition struct {
+	Filters      []Entry `json:"filters,omitempty"`
+	Transformers []Entry `json:"transformers,omitempty"`
+}
+
+// Entry is the definition of a single filter or transformer: its type and its parameters.
+type Entry struct {
+	// Type selects the factory building the filter or transformer.
+	Type string
+
+	// Params are the remaining fields of the entry, decoded by the factory.
+	Params Params
+}
+
+// UnmarshalJSON decodes an entry, separating the type from the parameters.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEntry, err)
+	}
+
+	raw, ok := fields["type"]
+	if !ok {
+		return fmt.Errorf("%w: missing type", ErrInvalidEntry)
+	}
+
+	if err := json.Unmarshal(raw, &e.Type); err != nil || e.Type == "" {
+		return fmt.Errorf("%w: type must be a non-empty string", ErrInvalidEntry)
+	}
+
+	delete(fields, "type")
+
+	e.Params = Params{fields: fields}
+
+	return nil
+}
+
+// Params are the parameters of an entry.
+type Params struct {
+	fields map[string]json.RawMessage
+}
+
+// Decode decodes the parameters into target, typically a pointer to a struct with json tags.
+// Fields not defined by target are rejected.
+func (p Params) Decode(target any) error {
+	data, err := json.Marshal(p.fields)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEntry, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEntry, err)
+	}
+
+	return nil
+}
+
+// FilterFactory builds a filter from the parameters of an entry. The loader builds the nested
+// entries of composite filters.
+type FilterFactory func(loader *Loader, params Params) (types.Filter, error)
+
+// TransformerFactory builds a transformer from the parameters of an entry. The loader builds
+// the nested entries of composite transformers.
+type TransformerFactory func(loader *Loader, params Params) (types.Transformer, error)
+
+// Option is a generic option for the Loader.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple loader options at once.
+type Options struct {
+	// Filters are additional filter factories by type, overriding the built-in ones.
+	Filters map[string]FilterFactory
+
+	// Transformers are additional transformer factories by type, overriding the built-in ones.
+	Transformers map[string]TransformerFactory
+}
+
+// ApplyTo applies the loader options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	for name, factory := range opts.Filters {
+		target.Filters[name] = factory
+	}
+
+	for name, factory := range opts.Transformers {
+		target.Transformers[name] = factory
+	}
+}
+
+// WithFilter registers a filter factory for the given type, e.g. for project specific filters.
+func WithFilter(name string, factory FilterFactory) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Filters[name] = factory
+	})
+}
+
+// WithTransformer registers a transformer factory for the given type.
+func WithTransformer(name string, factory TransformerFactory) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Transformers[name] = factory
+	})
+}
+
+// Loader builds filters and transformers from their definitions.
+type Loader struct {
+	filters      map[string]FilterFactory
+	transformers map[string]TransformerFactory
+}
+
+// New creates a Loader with the built-in filter and transformer types, and the ones registered
+// by the options.
+func New(opts ...Option) *Loader {
+	options := Options{
+		Filters:      builtinFilters(),
+		Transformers: builtinTransformers(),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return &Loader{
+		filters:      options.Filters,
+		transformers: options.Transformers,
+	}
+}
+
+// Load builds a pipeline from a YAML or JSON definition.
+func (l *Loader) Load(data []byte) (*Pipeline, error) {
+	var definition Definition
+	if err := yaml.UnmarshalStrict(data, &definition); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline definition: %w", err)
+	}
+
+	return l.Build(definition)
+}
+
+// LoadFile builds a pipeline from a YAML or JSON definition file.
+func (l *Loader) LoadFile(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline definition %s: %w", path, err)
+	}
+
+	pipeline, err := l.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return pipeline, nil
+}
+
+// Build builds a pipeline from its definition.
+func (l *Loader) Build(definition Definition) (*Pipeline, error) {
+	filters, err := l.Filters(definition.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("filters%w", err)
+	}
+
+	transformers, err := l.Transformers(definition.Transformers)
+	if err != nil {
+		return nil, fmt.Errorf("transformers%w", err)
+	}
+
+	return &Pipeline{
+		Filters:      filters,
+		Transformers: transformers,
+	}, nil
+}
+
+// Filter builds a filter from its definition.
+func (l *Loader) Filter(entry Entry) (types.Filter, error) {
+	factory, ok := l.filters[entry.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w %q for filter", ErrUnknownType, entry.Type)
+	}
+
+	f, err := factory(l, entry.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Type, err)
+	}
+
+	return f, nil
+}
+
+// Filters builds filters from their definitions. Errors are prefixed with the index of the
+// failing entry.
+func (l *Loader) Filters(entries []Entry) ([]types.Filter, error) {
+	filters := make([]types.Filter, 0, len(entries))
+
+	for i, entry := range entries {
+		f, err := l.Filter(entry)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// Transformer builds a transformer from its definition.
+func (l *Loader) Transformer(entry Entry) (types.Transformer, error) {
+	factory, ok := l.transformers[entry.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w %q for transformer", ErrUnknownType, entry.Type)
+	}
+
+	t, err := factory(l, entry.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", entry.Type, err)
+	}
+
+	return t, nil
+}
+
+// Transformers builds transformers from their definitions. Errors are prefixed with the index
+// of the failing entry.
+func (l *Loader) Transformers(entries []Entry) ([]types.Transformer, error) {
+	transformers := make([]types.Transformer, 0, len(entries))
+
+	for i, entry := range entries {
+		t, err := l.Transformer(entry)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		transformers = append(transformers, t)
+	}
+
+	return transformers, nil
+}
+
+// FilterTypes returns the registered filter types, sorted.
+func (l *Loader) FilterTypes() []string {
+	return slices.Sorted(maps.Keys(l.filters))
+}
+
+// TransformerTypes returns the registered transformer types, sorted.
+func (l *Loader) TransformerTypes() []string {
+	return slices.Sorted(maps.Keys(l.transformers))
+}
@@ -0,0 +1,435 @@
+package config
+
+import (
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/field"
+	jqfilter "github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/jq"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/jsonpath"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/annotations"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/name"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/namespace"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/owner"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/scope"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/source"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	jqtransformer "github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/jq"
+	annotationstransformer "github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/annotations"
+	labelstransformer "github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	nametransformer "github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/name"
+	namespacetransformer "github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/namespace"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/jq"
+)
+
+// Parameters shared by several types.
+type (
+	filtersParams struct {
+		Filters []Entry `json:"filters"`
+	}
+
+	namespacesParams struct {
+		Namespaces []string `json:"namespaces"`
+	}
+
+	namesParams struct {
+		Names []string `json:"names"`
+	}
+
+	keysParams struct {
+		Keys []string `json:"keys"`
+	}
+
+	jqParams struct {
+		Expression string         `json:"expression"`
+		Variables  map[string]any `json:"variables,omitempty"`
+	}
+
+	pathParams struct {
+		Path string `json:"path"`
+	}
+
+	pathValueParams struct {
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+
+	pathStringParams struct {
+		Path  string `json:"path"`
+		Value string `json:"value"`
+	}
+
+	ownerParams struct {
+		Kind string `json:"kind"`
+		Name string `json:"name,omitempty"`
+	}
+)
+
+// filterFactory adapts a function taking decoded parameters to a FilterFactory.
+func filterFactory[P any](build func(loader *Loader, params P) (types.Filter, error)) FilterFactory {
+	return func(loader *Loader, params Params) (types.Filter, error) {
+		var p P
+		if err := params.Decode(&p); err != nil {
+			return nil, err
+		}
+
+		return build(loader, p)
+	}
+}
+
+// transformerFactory adapts a function taking decoded parameters to a TransformerFactory.
+func transformerFactory[P any](build func(loader *Loader, params P) (types.Transformer, error)) TransformerFactory {
+	return func(loader *Loader, params Params) (types.Transformer, error) {
+		var p P
+		if err := params.Decode(&p); err != nil {
+			return nil, err
+		}
+
+		return build(loader, p)
+	}
+}
+
+// filterOf adapts a filter constructor that cannot fail.
+func filterOf[P any](build func(params P) types.Filter) FilterFactory {
+	return filterFactory(func(_ *Loader, params P) (types.Filter, error) {
+		return build(params), nil
+	})
+}
+
+// transformerOf adapts a transformer constructor that cannot fail.
+func transformerOf[P any](build func(params P) types.Transformer) TransformerFactory {
+	return transformerFactory(func(_ *Loader, params P) (types.Transformer, error) {
+		return build(params), nil
+	})
+}
+
+// jqOptions converts the variables of a jq entry to engine options, in a stable order.
+func jqOptions(params jqParams) []jq.Option {
+	opts := make([]jq.Option, 0, len(params.Variables))
+
+	for _, key := range slices.Sorted(maps.Keys(params.Variables)) {
+		opts = append(opts, jq.WithVariable(key, params.Variables[key]))
+	}
+
+	return opts
+}
+
+// builtinFilters returns the factories of the built-in filter types. Types are named after the
+// package and function building the filter, composition types after the combinator.
+//
+//nolint:funlen // flat table of built-in types
+func builtinFilters() map[string]FilterFactory {
+	return map[string]FilterFactory{
+		// Composition
+		"and": filterFactory(func(l *Loader, p filtersParams) (types.Filter, error) {
+			filters, err := l.Filters(p.Filters)
+			if err != nil {
+				return nil, err
+			}
+
+			return filter.And(filters...), nil
+		}),
+		"or": filterFactory(func(l *Loader, p filtersParams) (types.Filter, error) {
+			filters, err := l.Filters(p.Filters)
+			if err != nil {
+				return nil, err
+			}
+
+			return filter.Or(filters...), nil
+		}),
+		"not": filterFactory(func(l *Loader, p struct {
+			Filter Entry `json:"filter"`
+		}) (types.Filter, error) {
+			f, err := l.Filter(p.Filter)
+			if err != nil {
+				return nil, err
+			}
+
+			return filter.Not(f), nil
+		}),
+		"if": filterFactory(func(l *Loader, p struct {
+			Condition Entry `json:"condition"`
+			Then      Entry `json:"then"`
+		}) (types.Filter, error) {
+			condition, err := l.Filter(p.Condition)
+			if err != nil {
+				return nil, err
+			}
+
+			then, err := l.Filter(p.Then)
+			if err != nil {
+				return nil, err
+			}
+
+			return filter.If(condition, then), nil
+		}),
+		"named": filterFactory(func(l *Loader, p struct {
+			Name   string `json:"name"`
+			Filter Entry  `json:"filter"`
+		}) (types.Filter, error) {
+			f, err := l.Filter(p.Filter)
+			if err != nil {
+				return nil, err
+			}
+
+			return filter.Named(p.Name, f), nil
+		}),
+
+		// Metadata
+		"namespace.filter": filterOf(func(p namespacesParams) types.Filter {
+			return namespace.Filter(p.Namespaces...)
+		}),
+		"namespace.exclude": filterOf(func(p namespacesParams) types.Filter {
+			return namespace.Exclude(p.Namespaces...)
+		}),
+		"name.exact": filterOf(func(p namesParams) types.Filter {
+			return name.Exact(p.Names...)
+		}),
+		"name.prefix": filterOf(func(p struct {
+			Prefix string `json:"prefix"`
+		}) types.Filter {
+			return name.Prefix(p.Prefix)
+		}),
+		"name.suffix": filterOf(func(p struct {
+			Suffix string `json:"suffix"`
+		}) types.Filter {
+			return name.Suffix(p.Suffix)
+		}),
+		"name.regex": filterFactory(func(_ *Loader, p struct {
+			Pattern string `json:"pattern"`
+		}) (types.Filter, error) {
+			return name.Regex(p.Pattern)
+		}),
+		"labels.hasLabels": filterOf(func(p keysParams) types.Filter {
+			return labels.HasLabels(p.Keys...)
+		}),
+		"labels.matchLabels": filterOf(func(p struct {
+			Labels map[string]string `json:"labels"`
+		}) types.Filter {
+			return labels.MatchLabels(p.Labels)
+		}),
+		"labels.selector": filterFactory(func(_ *Loader, p struct {
+			Selector string `json:"selector"`
+		}) (types.Filter, error) {
+			return labels.Selector(p.Selector)
+		}),
+		"annotations.hasAnnotations": filterOf(func(p keysParams) types.Filter {
+			return annotations.HasAnnotations(p.Keys...)
+		}),
+		"annotations.matchAnnotations": filterOf(func(p struct {
+			Annotations map[string]string `json:"annotations"`
+		}) types.Filter {
+			return annotations.MatchAnnotations(p.Annotations)
+		}),
+		"gvk.group": filterOf(func(p struct {
+			Groups []string `json:"groups"`
+		}) types.Filter {
+			return gvk.Group(p.Groups...)
+		}),
+		"gvk.version": filterOf(func(p struct {
+			Versions []string `json:"versions"`
+		}) types.Filter {
+			return gvk.Version(p.Versions...)
+		}),
+		"gvk.kind": filterOf(func(p struct {
+			Kinds []string `json:"kinds"`
+		}) types.Filter {
+			return gvk.Kind(p.Kinds...)
+		}),
+		"gvk.match": filterFactory(func(_ *Loader, p struct {
+			Patterns []struct {
+				Group   string `json:"group,omitempty"`
+				Version string `json:"version,omitempty"`
+				Kind    string `json:"kind,omitempty"`
+			} `json:"patterns"`
+		}) (types.Filter, error) {
+			patterns := make([]schema.GroupVersionKind, 0, len(p.Patterns))
+			for _, pattern := range p.Patterns {
+				patterns = append(patterns, schema.GroupVersionKind(pattern))
+			}
+
+			return gvk.Match(patterns...)
+		}),
+		"scope.clusterScoped": filterOf(func(struct{}) types.Filter {
+			return scope.ClusterScoped()
+		}),
+		"scope.namespaced": filterOf(func(struct{}) types.Filter {
+			return scope.Namespaced()
+		}),
+		"owner.hasOwner": filterOf(func(struct{}) types.Filter {
+			return owner.HasOwner()
+		}),
+		"owner.ownedBy": filterOf(func(p ownerParams) types.Filter {
+			return owner.OwnedBy(p.Kind, p.Name)
+		}),
+		"owner.controlledBy": filterOf(func(p ownerParams) types.Filter {
+			return owner.ControlledBy(p.Kind, p.Name)
+		}),
+		"source.match": filterFactory(func(_ *Loader, p struct {
+			Patterns []struct {
+				Type string `json:"type,omitempty"`
+				Path string `json:"path,omitempty"`
+				File string `json:"file,omitempty"`
+			} `json:"patterns"`
+		}) (types.Filter, error) {
+			patterns := make([]source.Source, 0, len(p.Patterns))
+			for _, pattern := range p.Patterns {
+				patterns = append(patterns, source.Source(pattern))
+			}
+
+			return source.Match(patterns...)
+		}),
+
+		// Content
+		"jq.filter": filterFactory(func(_ *Loader, p jqParams) (types.Filter, error) {
+			return jqfilter.Filter(p.Expression, jqOptions(p)...)
+		}),
+		"jsonpath.exists": filterFactory(func(_ *Loader, p struct {
+			Expression string `json:"expression"`
+		}) (types.Filter, error) {
+			return jsonpath.Exists(p.Expression)
+		}),
+		"jsonpath.equals": filterFactory(func(_ *Loader, p struct {
+			Expression string `json:"expression"`
+			Value      any    `json:"value"`
+		}) (types.Filter, error) {
+			return jsonpath.Equals(p.Expression, p.Value)
+		}),
+		"field.exists": filterFactory(func(_ *Loader, p pathParams) (types.Filter, error) {
+			return field.Exists(p.Path)
+		}),
+		"field.equals": filterFactory(func(_ *Loader, p pathValueParams) (types.Filter, error) {
+			return field.Equals(p.Path, p.Value)
+		}),
+		"field.in": filterFactory(func(_ *Loader, p struct {
+			Path   string `json:"path"`
+			Values []any  `json:"values"`
+		}) (types.Filter, error) {
+			return field.In(p.Path, p.Values...)
+		}),
+		"field.greaterThan": filterFactory(func(_ *Loader, p pathValueParams) (types.Filter, error) {
+			return field.GreaterThan(p.Path, p.Value)
+		}),
+		"field.greaterOrEqual": filterFactory(func(_ *Loader, p pathValueParams) (types.Filter, error) {
+			return field.GreaterOrEqual(p.Path, p.Value)
+		}),
+		"field.lessThan": filterFactory(func(_ *Loader, p pathValueParams) (types.Filter, error) {
+			return field.LessThan(p.Path, p.Value)
+		}),
+		"field.lessOrEqual": filterFactory(func(_ *Loader, p pathValueParams) (types.Filter, error) {
+			return field.LessOrEqual(p.Path, p.Value)
+		}),
+		"field.startsWith": filterFactory(func(_ *Loader, p pathStringParams) (types.Filter, error) {
+			return field.StartsWith(p.Path, p.Value)
+		}),
+		"field.endsWith": filterFactory(func(_ *Loader, p pathStringParams) (types.Filter, error) {
+			return field.EndsWith(p.Path, p.Value)
+		}),
+		"field.contains": filterFactory(func(_ *Loader, p pathStringParams) (types.Filter, error) {
+			return field.Contains(p.Path, p.Value)
+		}),
+		"field.regex": filterFactory(func(_ *Loader, p pathStringParams) (types.Filter, error) {
+			return field.Regex(p.Path, p.Value)
+		}),
+	}
+}
+
+// builtinTransformers returns the factories of the built-in transformer types, named like the
+// filter types.
+func builtinTransformers() map[string]TransformerFactory {
+	return map[string]TransformerFactory{
+		// Composition
+		"chain": transformerFactory(func(l *Loader, p struct {
+			Transformers []Entry `json:"transformers"`
+		}) (types.Transformer, error) {
+			transformers, err := l.Transformers(p.Transformers)
+			if err != nil {
+				return nil, err
+			}
+
+			return transformer.Chain(transformers...), nil
+		}),
+		"if": transformerFactory(func(l *Loader, p struct {
+			Condition   Entry `json:"condition"`
+			Transformer Entry `json:"transformer"`
+		}) (types.Transformer, error) {
+			condition, err := l.Filter(p.Condition)
+			if err != nil {
+				return nil, err
+			}
+
+			t, err := l.Transformer(p.Transformer)
+			if err != nil {
+				return nil, err
+			}
+
+			return transformer.If(condition, t), nil
+		}),
+
+		// Metadata
+		"namespace.set": transformerOf(func(p struct {
+			Namespace string `json:"namespace"`
+		}) types.Transformer {
+			return namespacetransformer.Set(p.Namespace)
+		}),
+		"namespace.ensureDefault": transformerOf(func(p struct {
+			Namespace string `json:"namespace"`
+		}) types.Transformer {
+			return namespacetransformer.EnsureDefault(p.Namespace)
+		}),
+		"name.setPrefix": transformerOf(func(p struct {
+			Prefix string `json:"prefix"`
+		}) types.Transformer {
+			return nametransformer.SetPrefix(p.Prefix)
+		}),
+		"name.setSuffix": transformerOf(func(p struct {
+			Suffix string `json:"suffix"`
+		}) types.Transformer {
+			return nametransformer.SetSuffix(p.Suffix)
+		}),
+		"name.replace": transformerOf(func(p struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		}) types.Transformer {
+			return nametransformer.Replace(p.From, p.To)
+		}),
+		"labels.set": transformerOf(func(p struct {
+			Labels map[string]string `json:"labels"`
+		}) types.Transformer {
+			return labelstransformer.Set(p.Labels)
+		}),
+		"labels.remove": transformerOf(func(p keysParams) types.Transformer {
+			return labelstransformer.Remove(p.Keys...)
+		}),
+		"labels.propagate": transformerOf(func(p struct {
+			Labels    map[string]string `json:"labels"`
+			Selectors bool              `json:"selectors"`
+		}) types.Transformer {
+			return labelstransformer.Propagate(p.Labels, labelstransformer.WithSelectors(p.Selectors))
+		}),
+		"annotations.set": transformerOf(func(p struct {
+			Annotations map[string]string `json:"annotations"`
+		}) types.Transformer {
+			return annotationstransformer.Set(p.Annotations)
+		}),
+		"annotations.remove": transformerOf(func(p keysParams) types.Transformer {
+			return annotationstransformer.Remove(p.Keys...)
+		}),
+		"annotations.propagate": transformerOf(func(p struct {
+			Annotations map[string]string `json:"annotations"`
+		}) types.Transformer {
+			return annotationstransformer.Propagate(p.Annotations)
+		}),
+
+		// Content
+		"jq.transform": transformerFactory(func(_ *Loader, p jqParams) (types.Transformer, error) {
+			return jqtransformer.Transform(p.Expression, jqOptions(p)...)
+		}),
+	}
+}
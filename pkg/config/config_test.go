@@ -0,0 +1,194 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/config"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const pipelineYAML = `
+filters:
+  - type: namespace.filter
+    namespaces: [production]
+  - type: not
+    filter:
+      type: or
+      filters:
+        - type: gvk.kind
+          kinds: [Secret]
+        - type: labels.matchLabels
+          labels:
+            skip: "true"
+transformers:
+  - type: labels.set
+    labels:
+      team: platform
+  - type: if
+    condition:
+      type: gvk.kind
+      kinds: [Deployment]
+    transformer:
+      type: jq.transform
+      expression: .spec.replicas = $replicas
+      variables:
+        replicas: 3
+`
+
+func makeObject(kind string, name string, objLabels map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": "production",
+			},
+		},
+	}
+
+	obj.SetLabels(objLabels)
+
+	return obj
+}
+
+func TestLoad(t *testing.T) {
+	objects := []unstructured.Unstructured{
+		makeObject("Deployment", "app", nil),
+		makeObject("Secret", "credentials", nil),
+		makeObject("ConfigMap", "skipped", map[string]string{"skip": "true"}),
+		makeObject("ConfigMap", "config", nil),
+	}
+
+	t.Run("should build filters and transformers from YAML", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p, err := config.New().Load([]byte(pipelineYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(p.Filters).Should(HaveLen(2))
+		g.Expect(p.Transformers).Should(HaveLen(2))
+
+		result, err := pipeline.Apply(t.Context(), objects, p.Filters, p.Transformers)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+
+		g.Expect(result[0].GetName()).Should(Equal("app"))
+		g.Expect(result[0].GetLabels()).Should(HaveKeyWithValue("team", "platform"))
+		g.Expect(result[0].Object).Should(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeNumerically("==", 3))))
+
+		g.Expect(result[1].GetName()).Should(Equal("config"))
+		g.Expect(result[1].Object).ShouldNot(HaveKey("spec"))
+	})
+
+	t.Run("should build pipelines from JSON files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		path := filepath.Join(t.TempDir(), "pipeline.json")
+		err := os.WriteFile(path, []byte(`{"filters": [{"type": "name.prefix", "prefix": "con"}]}`), 0o600)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		p, err := config.New().LoadFile(path)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(p.Transformers).Should(BeEmpty())
+
+		result, err := pipeline.ApplyFilters(t.Context(), objects, p.Filters)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(1))
+		g.Expect(result[0].GetName()).Should(Equal("config"))
+	})
+
+	t.Run("should use custom types", func(t *testing.T) {
+		g := NewWithT(t)
+
+		type kindParams struct {
+			Kind string `json:"kind"`
+		}
+
+		loader := config.New(config.WithFilter("kind", func(_ *config.Loader, params config.Params) (types.Filter, error) {
+			var p kindParams
+			if err := params.Decode(&p); err != nil {
+				return nil, err
+			}
+
+			return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetKind() == p.Kind, nil
+			}, nil
+		}))
+
+		g.Expect(loader.FilterTypes()).Should(ContainElements("kind", "and", "namespace.filter"))
+
+		p, err := loader.Load([]byte("filters:\n- type: kind\n  kind: Secret\n"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := pipeline.ApplyFilters(t.Context(), objects, p.Filters)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(1))
+		g.Expect(result[0].GetName()).Should(Equal("credentials"))
+	})
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected error
+		message  string
+	}{
+		{
+			name:     "should reject unknown filter types",
+			content:  "filters:\n- type: and\n  filters:\n  - type: unknown\n",
+			expected: config.ErrUnknownType,
+			message:  `filters[0]: and: [0]: unknown type "unknown" for filter`,
+		},
+		{
+			name:     "should reject unknown transformer types",
+			content:  "transformers:\n- type: namespace.filter\n",
+			expected: config.ErrUnknownType,
+			message:  "transformers[0]",
+		},
+		{
+			name:     "should reject entries without type",
+			content:  "filters:\n- namespaces: [production]\n",
+			expected: config.ErrInvalidEntry,
+		},
+		{
+			name:     "should reject unknown parameters",
+			content:  "filters:\n- type: namespace.filter\n  namespace: production\n",
+			expected: config.ErrInvalidEntry,
+			message:  "namespace.filter",
+		},
+		{
+			name:    "should report invalid parameters of the built filter",
+			content: "filters:\n- type: name.regex\n  pattern: '['\n",
+			message: "filters[0]: name.regex",
+		},
+		{
+			name:    "should reject unknown top-level fields",
+			content: "filter: []\n",
+			message: "failed to parse pipeline definition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := config.New().Load([]byte(tt.content))
+			g.Expect(err).Should(HaveOccurred())
+
+			if tt.expected != nil {
+				g.Expect(err).Should(MatchError(tt.expected))
+			}
+
+			g.Expect(err.Error()).Should(ContainSubstring(tt.message))
+		})
+	}
+}
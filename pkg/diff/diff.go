@@ -0,0 +1,255 @@
+// Package diff compares two sets of rendered objects, e.g. the output of two renders with
+// different values or chart versions, and reports the objects added, removed and changed, with
+// the paths of the changed fields.
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Key identifies an object across the compared sets. The version is not part of the key, so an
+// object moving to another version of its API is reported as changed.
+type Key struct {
+	// GroupKind is the API group and kind of the object.
+	GroupKind schema.GroupKind
+
+	// Namespace is the namespace of the object, empty for cluster-scoped objects.
+	Namespace string
+
+	// Name is the name of the object.
+	Name string
+}
+
+func (k Key) String() string {
+	if k.Namespace == "" {
+		return fmt.Sprintf("%s %s", k.GroupKind, k.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", k.GroupKind, k.Namespace, k.Name)
+}
+
+// KeyOf returns the key of an object.
+func KeyOf(obj unstructured.Unstructured) Key {
+	return Key{
+		GroupKind: obj.GroupVersionKind().GroupKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// Change is a field differing between the two versions of an object.
+type Change struct {
+	// Path is the path of the field, in the syntax of the field filters and transformers, e.g.
+	// spec.template.spec.containers[0].image or metadata.labels['app.kubernetes.io/version'].
+	Path string
+
+	// Before is the value of the field before, nil if the field was added.
+	Before any
+
+	// After is the value of the field after, nil if the field was removed.
+	After any
+}
+
+func (c Change) String() string {
+	switch {
+	case c.Before == nil:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.After)
+	case c.After == nil:
+		return fmt.Sprintf("- %s: %v", c.Path, c.Before)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", c.Path, c.Before, c.After)
+	}
+}
+
+// ObjectDiff is an object present in both sets with different content.
+type ObjectDiff struct {
+	// Key identifies the object.
+	Key Key
+
+	// Before is the object in the first set.
+	Before unstructured.Unstructured
+
+	// After is the object in the second set.
+	After unstructured.Unstructured
+
+	// Changes are the differing fields, in path order.
+	Changes []Change
+}
+
+// Result is the object-level difference between two sets of objects.
+type Result struct {
+	// Added are the objects only in the second set, in their order there.
+	Added []unstructured.Unstructured
+
+	// Removed are the objects only in the first set, in their order there.
+	Removed []unstructured.Unstructured
+
+	// Changed are the objects in both sets with different content, in their order in the
+	// second set.
+	Changed []ObjectDiff
+}
+
+// Empty reports whether the two sets hold the same objects with the same content.
+func (r *Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+func (r *Result) String() string {
+	var sb strings.Builder
+
+	for _, obj := range r.Added {
+		fmt.Fprintf(&sb, "added %s\n", KeyOf(obj))
+	}
+
+	for _, obj := range r.Removed {
+		fmt.Fprintf(&sb, "removed %s\n", KeyOf(obj))
+	}
+
+	for _, d := range r.Changed {
+		fmt.Fprintf(&sb, "changed %s\n", d.Key)
+
+		for _, c := range d.Changes {
+			fmt.Fprintf(&sb, "  %s\n", c)
+		}
+	}
+
+	return sb.String()
+}
+
+// Objects compares two sets of objects, matching them by group, kind, namespace and name. If a
+// set holds an object more than once, its last occurrence is compared.
+func Objects(before []unstructured.Unstructured, after []unstructured.Unstructured) *Result {
+	result := Result{}
+
+	beforeIndex := index(before)
+	afterIndex := index(after)
+
+	for i := range after {
+		key := KeyOf(after[i])
+		if afterIndex[key] != i {
+			continue
+		}
+
+		j, ok := beforeIndex[key]
+		if !ok {
+			result.Added = append(result.Added, after[i])
+
+			continue
+		}
+
+		changes := compare("", before[j].Object, after[i].Object, nil)
+		if len(changes) > 0 {
+			result.Changed = append(result.Changed, ObjectDiff{
+				Key:     key,
+				Before:  before[j],
+				After:   after[i],
+				Changes: changes,
+			})
+		}
+	}
+
+	for i := range before {
+		key := KeyOf(before[i])
+		if beforeIndex[key] != i {
+			continue
+		}
+
+		if _, ok := afterIndex[key]; !ok {
+			result.Removed = append(result.Removed, before[i])
+		}
+	}
+
+	return &result
+}
+
+// index returns the index of the last occurrence of each object.
+func index(objects []unstructured.Unstructured) map[Key]int {
+	result := make(map[Key]int, len(objects))
+	for i := range objects {
+		result[KeyOf(objects[i])] = i
+	}
+
+	return result
+}
+
+// compare appends the changes between two values at path to changes. Maps are compared by key
+// and lists by index; other values, and values of different types, are compared as a whole.
+func compare(path string, before any, after any, changes []Change) []Change {
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(b)+len(a))
+		for k := range b {
+			keys = append(keys, k)
+		}
+
+		for k := range a {
+			if _, found := b[k]; !found {
+				keys = append(keys, k)
+			}
+		}
+
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			changes = compare(keyPath(path, k), b[k], a[k], changes)
+		}
+
+		return changes
+	case []any:
+		a, ok := after.([]any)
+		if !ok {
+			break
+		}
+
+		for i := range max(len(b), len(a)) {
+			var bv, av any
+
+			if i < len(b) {
+				bv = b[i]
+			}
+
+			if i < len(a) {
+				av = a[i]
+			}
+
+			changes = compare(fmt.Sprintf("%s[%d]", path, i), bv, av, changes)
+		}
+
+		return changes
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		changes = append(changes, Change{Path: path, Before: before, After: after})
+	}
+
+	return changes
+}
+
+// keyPath appends a map key to a path, in brackets if it contains characters of the path syntax.
+func keyPath(path string, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]'\"") {
+		quote := "'"
+		if strings.Contains(key, "'") {
+			quote = `"`
+		}
+
+		return path + "[" + quote + key + quote + "]"
+	}
+
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,180 @@
+package diff_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/diff"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func deployment(name string, image string, replicas int64, lbls map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+			"labels":    lbls,
+		},
+		"spec": map[string]any{
+			"replicas": replicas,
+			"template": map[string]any{
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": image},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func configMap(name string, data map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+		"data":       data,
+	}}
+}
+
+func TestObjects(t *testing.T) {
+	t.Run("should report no differences for equal sets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []unstructured.Unstructured{
+			deployment("web", "nginx:1.25", 1, nil),
+			configMap("config", map[string]any{"key": "value"}),
+		}
+
+		result := diff.Objects(objects, objects)
+		g.Expect(result.Empty()).To(BeTrue())
+		g.Expect(result.String()).To(BeEmpty())
+	})
+
+	t.Run("should report added and removed objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result := diff.Objects(
+			[]unstructured.Unstructured{configMap("old", nil), configMap("kept", nil)},
+			[]unstructured.Unstructured{configMap("kept", nil), configMap("new", nil)},
+		)
+
+		g.Expect(result.Empty()).To(BeFalse())
+		g.Expect(result.Added).To(HaveLen(1))
+		g.Expect(result.Added[0].GetName()).To(Equal("new"))
+		g.Expect(result.Removed).To(HaveLen(1))
+		g.Expect(result.Removed[0].GetName()).To(Equal("old"))
+		g.Expect(result.Changed).To(BeEmpty())
+		g.Expect(result.String()).To(Equal("added ConfigMap default/new\nremoved ConfigMap default/old\n"))
+	})
+
+	t.Run("should report changed fields with their paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result := diff.Objects(
+			[]unstructured.Unstructured{
+				deployment("web", "nginx:1.25", 1, map[string]any{"app.kubernetes.io/version": "1.25", "tier": "web"}),
+			},
+			[]unstructured.Unstructured{
+				deployment("web", "nginx:1.26", 3, map[string]any{"app.kubernetes.io/version": "1.26", "team": "a"}),
+			},
+		)
+
+		g.Expect(result.Added).To(BeEmpty())
+		g.Expect(result.Removed).To(BeEmpty())
+		g.Expect(result.Changed).To(HaveLen(1))
+
+		changed := result.Changed[0]
+		g.Expect(changed.Key.String()).To(Equal("Deployment.apps default/web"))
+		g.Expect(changed.Changes).To(Equal([]diff.Change{
+			{Path: "metadata.labels['app.kubernetes.io/version']", Before: "1.25", After: "1.26"},
+			{Path: "metadata.labels.team", After: "a"},
+			{Path: "metadata.labels.tier", Before: "web"},
+			{Path: "spec.replicas", Before: int64(1), After: int64(3)},
+			{Path: "spec.template.spec.containers[0].image", Before: "nginx:1.25", After: "nginx:1.26"},
+		}))
+
+		g.Expect(result.String()).To(ContainSubstring("changed Deployment.apps default/web\n"))
+		g.Expect(result.String()).To(ContainSubstring("  ~ spec.replicas: 1 -> 3\n"))
+		g.Expect(result.String()).To(ContainSubstring("  + metadata.labels.team: a\n"))
+		g.Expect(result.String()).To(ContainSubstring("  - metadata.labels.tier: web\n"))
+	})
+
+	t.Run("should report paths parseable as field paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		after := deployment("web", "nginx:1.26", 1, map[string]any{"app.kubernetes.io/version": "1.26"})
+
+		result := diff.Objects(
+			[]unstructured.Unstructured{deployment("web", "nginx:1.25", 1, map[string]any{"app.kubernetes.io/version": "1.25"})},
+			[]unstructured.Unstructured{after},
+		)
+		g.Expect(result.Changed).To(HaveLen(1))
+
+		for _, c := range result.Changed[0].Changes {
+			path, err := fieldpath.Parse(c.Path)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(path.Lookup(after.Object)).To(Equal([]any{c.After}))
+		}
+	})
+
+	t.Run("should compare list elements by index", func(t *testing.T) {
+		g := NewWithT(t)
+
+		before := deployment("web", "nginx:1.25", 1, nil)
+		after := deployment("web", "nginx:1.25", 1, nil)
+
+		sidecar := map[string]any{"name": "proxy", "image": "envoy:1.30"}
+		g.Expect(unstructured.SetNestedSlice(after.Object, []any{
+			map[string]any{"name": "app", "image": "nginx:1.25"},
+			sidecar,
+		}, "spec", "template", "spec", "containers")).To(Succeed())
+
+		result := diff.Objects([]unstructured.Unstructured{before}, []unstructured.Unstructured{after})
+		g.Expect(result.Changed).To(HaveLen(1))
+		g.Expect(result.Changed[0].Changes).To(Equal([]diff.Change{
+			{Path: "spec.template.spec.containers[1]", After: sidecar},
+		}))
+	})
+
+	t.Run("should report API version changes as changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		before := deployment("web", "nginx:1.25", 1, nil)
+		before.SetAPIVersion("apps/v1beta2")
+
+		result := diff.Objects(
+			[]unstructured.Unstructured{before},
+			[]unstructured.Unstructured{deployment("web", "nginx:1.25", 1, nil)},
+		)
+
+		g.Expect(result.Added).To(BeEmpty())
+		g.Expect(result.Removed).To(BeEmpty())
+		g.Expect(result.Changed).To(HaveLen(1))
+		g.Expect(result.Changed[0].Changes).To(Equal([]diff.Change{
+			{Path: "apiVersion", Before: "apps/v1beta2", After: "apps/v1"},
+		}))
+	})
+
+	t.Run("should compare values of different types as a whole", func(t *testing.T) {
+		g := NewWithT(t)
+
+		after := configMap("config", nil)
+		after.Object["data"] = []any{"value"}
+
+		result := diff.Objects(
+			[]unstructured.Unstructured{configMap("config", map[string]any{"key": "value"})},
+			[]unstructured.Unstructured{after},
+		)
+
+		g.Expect(result.Changed).To(HaveLen(1))
+		g.Expect(result.Changed[0].Changes).To(Equal([]diff.Change{
+			{Path: "data", Before: map[string]any{"key": "value"}, After: []any{"value"}},
+		}))
+	})
+}
@@ -2,43 +2,136 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
 	"time"
 
+	"golang.org/x/sync/semaphore"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/diff"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/metrics"
 )
 
 // Engine represents the core manifest rendering and processing engine.
 type Engine struct {
 	options Options
+	dryRun  *DryRun
 }
 
 // New creates a new Engine with the given options.
 func New(opts ...Option) (*Engine, error) {
 	options := Options{
-		Renderers:    make([]types.Renderer, 0),
-		Filters:      make([]types.Filter, 0),
-		Transformers: make([]types.Transformer, 0),
+		Renderers:         make([]types.Renderer, 0),
+		Filters:           make([]types.Filter, 0),
+		CollectionFilters: make([]types.CollectionFilter, 0),
+		Transformers:      make([]types.Transformer, 0),
+		Duplicates:        DuplicatePolicyAllow,
+		Failures:          FailurePolicyFailFast,
+		WatchInterval:     defaultWatchInterval,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
 	}
 
+	return newEngine(options, nil)
+}
+
+// With returns a new Engine deriving from e, with the given options applied over the options of e.
+// The derived engine shares the renderers of e, and so their caches, so a base pipeline can be
+// specialized, e.g. per environment, with additional filters, transformers or renderers without
+// creating the renderers again and downloading their charts twice. Options replacing a setting of
+// e, e.g. WithDuplicatePolicy, take precedence; e is not modified.
+func (e *Engine) With(opts ...Option) (*Engine, error) {
+	options := e.options.clone()
+
 	for _, opt := range opts {
 		opt.ApplyTo(&options)
 	}
 
+	// Share the dry-run clients unless the derived engine validates against another cluster
+	var dryRun *DryRun
+	if options.DryRunConfig == e.options.DryRunConfig && options.DryRun == e.options.DryRun {
+		dryRun = e.dryRun
+	}
+
+	return newEngine(options, dryRun)
+}
+
+// newEngine validates the options and creates an Engine, using the given dry-run validation
+// clients if not nil.
+func newEngine(options Options, dryRun *DryRun) (*Engine, error) {
+	switch options.Duplicates {
+	case DuplicatePolicyAllow, DuplicatePolicyKeepFirst, DuplicatePolicyKeepLast,
+		DuplicatePolicyPrefer, DuplicatePolicyMerge, DuplicatePolicyFail:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidDuplicatePolicy, options.Duplicates)
+	}
+
+	switch options.Failures {
+	case FailurePolicyFailFast, FailurePolicyContinueOnError:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFailurePolicy, options.Failures)
+	}
+
+	if options.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("%w: max concurrency %d", ErrInvalidConcurrency, options.MaxConcurrency)
+	}
+
+	if options.Timeout < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimeout, options.Timeout)
+	}
+
+	for name, timeout := range options.Timeouts {
+		if timeout < 0 {
+			return nil, fmt.Errorf("%w: %s for renderer %q", ErrInvalidTimeout, timeout, name)
+		}
+	}
+
+	if options.WatchInterval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidWatchInterval, options.WatchInterval)
+	}
+
+	if r := options.Retry; r != nil && (r.Attempts < 0 || r.Backoff < 0 || r.MaxBackoff < 0) {
+		return nil, fmt.Errorf("%w: %+v", ErrInvalidRetryPolicy, *r)
+	}
+
+	for name, weight := range options.Weights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("%w: weight %d of renderer %q", ErrInvalidConcurrency, weight, name)
+		}
+	}
+
 	for _, renderer := range options.Renderers {
 		if err := types.ValidateRenderer(renderer); err != nil {
 			return nil, fmt.Errorf("invalid renderer: %w", err)
 		}
 	}
 
+	if err := validateNames(options); err != nil {
+		return nil, err
+	}
+
+	if dryRun == nil && (options.DryRunConfig != nil || options.DryRun != nil) {
+		var err error
+
+		dryRun, err = newDryRun(options)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dry-run validation: %w", err)
+		}
+	}
+
 	e := Engine{
 		options: options,
+		dryRun:  dryRun,
 	}
 
 	return &e, nil
@@ -52,16 +145,73 @@ func New(opts ...Option) (*Engine, error) {
 //  2. engine-level: Filters/transformers configured via New() are applied to aggregated results
 //  3. render-time: Filters/transformers passed via opts are merged with engine-level ones
 //
+// Collection filters, deciding on each object given the whole set, run after the filters.
+// Collection transformers, processing the whole set, run after the transformers.
+//
 // Render-time options are additive - they append to engine-level options.
-// Render-time values are passed to all renderers and deep merged with Source-level values.
+// Render-time values are passed to all renderers and deep merged with Source-level values;
+// values set with WithValuesFor are passed only to the renderers with the given name.
+//
+// With FailurePolicyContinueOnError, the objects of the successful renderers are returned together
+// with a *RenderError if some renderers failed.
 func (e *Engine) Render(ctx context.Context, opts ...RenderOption) ([]unstructured.Unstructured, error) {
+	result, err := e.render(ctx, opts...)
+
+	var renderErr *RenderError
+	if errors.As(err, &renderErr) {
+		return result.Objects, err
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Objects, nil
+}
+
+// RenderResult renders like Render, returning the final objects together with the objects, the
+// duration and the error of each renderer, the object counts of the processing stages and the
+// warnings of the render.
+//
+// On error, the returned result holds the renderer results collected so far, without objects,
+// except for the *RenderError of FailurePolicyContinueOnError, returned with the objects of the
+// successful renderers.
+func (e *Engine) RenderResult(ctx context.Context, opts ...RenderOption) (*RenderResult, error) {
+	return e.render(ctx, opts...)
+}
+
+// Diff renders twice, with the before and the after render-time options, and returns the
+// object-level difference between the two outputs, e.g. to preview what changes when bumping
+// values. Engine-level options apply to both renders. To compare different renderers, e.g. two
+// chart versions, render with two engines and compare the outputs with diff.Objects.
+func (e *Engine) Diff(ctx context.Context, before []RenderOption, after []RenderOption) (*diff.Result, error) {
+	beforeObjects, err := e.Render(ctx, before...)
+	if err != nil {
+		return nil, fmt.Errorf("before render failed: %w", err)
+	}
+
+	afterObjects, err := e.Render(ctx, after...)
+	if err != nil {
+		return nil, fmt.Errorf("after render failed: %w", err)
+	}
+
+	return diff.Objects(beforeObjects, afterObjects), nil
+}
+
+// render runs the rendering pipeline, recording its outcome.
+func (e *Engine) render(ctx context.Context, opts ...RenderOption) (*RenderResult, error) {
 	startTime := time.Now()
 
+	result := &RenderResult{}
+
 	// Initialize render options by cloning the engine's options
 	renderOpts := RenderOptions{
-		Filters:      slices.Clone(e.options.Filters),
-		Transformers: slices.Clone(e.options.Transformers),
-		Values:       make(map[string]any),
+		Filters:                slices.Clone(e.options.Filters),
+		CollectionFilters:      slices.Clone(e.options.CollectionFilters),
+		Transformers:           slices.Clone(e.options.Transformers),
+		CollectionTransformers: slices.Clone(e.options.CollectionTransformers),
+		Values:                 make(map[string]any),
+		Timeout:                e.options.Timeout,
 	}
 
 	// Apply render options
@@ -69,33 +219,146 @@ func (e *Engine) Render(ctx context.Context, opts ...RenderOption) ([]unstructur
 		opt.ApplyTo(&renderOpts)
 	}
 
-	var allObjects []unstructured.Unstructured
+	if renderOpts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, renderOpts.Timeout)
+		defer cancel()
+	}
+
+	h := hooks(e.options.Hooks)
+	h.renderStart(ctx)
+
+	objects, err := e.process(ctx, renderOpts, result)
+	if err == nil && e.dryRun != nil {
+		err = e.dryRun.validate(ctx, objects)
+	}
+
+	result.Duration = time.Since(startTime)
+
+	if err != nil {
+		h.error(ctx, err)
+
+		return result, err
+	}
+
+	result.Objects = objects
+
+	metrics.ObserveRender(ctx, result.Duration, len(objects))
+
+	h.renderComplete(ctx, result)
+
+	if renderErr := newRenderError(result.Renderers); renderErr != nil {
+		h.error(ctx, renderErr)
+
+		return result, renderErr
+	}
+
+	return result, nil
+}
+
+// process renders the objects and applies the engine-level pipeline, recording the renderer
+// results, stats and warnings in result.
+func (e *Engine) process(
+	ctx context.Context,
+	renderOpts RenderOptions,
+	result *RenderResult,
+) ([]unstructured.Unstructured, error) {
 	var err error
 
+	for name := range renderOpts.RendererValues {
+		if !slices.ContainsFunc(e.options.Renderers, func(r types.Renderer) bool { return r.Name() == name }) {
+			return nil, fmt.Errorf("%w: values for %q", ErrUnknownRenderer, name)
+		}
+	}
+
 	// Process renderers in parallel or sequentially
 	if e.options.Parallel {
-		allObjects, err = e.renderParallel(ctx, renderOpts.Values)
+		result.Renderers, err = e.renderParallel(ctx, renderOpts)
 	} else {
-		allObjects, err = e.renderSequential(ctx, renderOpts.Values)
+		result.Renderers, err = e.renderSequential(ctx, renderOpts)
 	}
 
-	if err != nil {
+	if err != nil && e.options.Failures != FailurePolicyContinueOnError {
 		return nil, fmt.Errorf("rendering failed: %w", err)
 	}
 
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	// Names of the renderers of the objects, for duplicate handling
+	origins := make([]string, 0)
+
+	for _, r := range result.Renderers {
+		if r.Err == nil && len(r.Objects) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("renderer %q returned no objects", r.Name))
+		}
+
+		objects := r.Objects
+
+		// Unwrap List objects (if enabled)
+		if e.options.ExpandLists {
+			objects, err = k8s.ExpandLists(objects)
+			if err != nil {
+				return nil, fmt.Errorf("list expansion error: %w", err)
+			}
+		}
+
+		allObjects = append(allObjects, objects...)
+
+		for range objects {
+			origins = append(origins, r.Name)
+		}
+	}
+
+	result.Stats.Rendered = len(allObjects)
+
+	// Handle objects rendered more than once
+	allObjects, dropped, err := deduplicate(allObjects, origins, e.options.Duplicates, e.options.Preferred)
+	if err != nil {
+		return nil, fmt.Errorf("duplicate detection error: %w", err)
+	}
+
+	result.Stats.Duplicates = len(dropped)
+
+	action := "dropped"
+	if e.options.Duplicates == DuplicatePolicyMerge {
+		action = "merged"
+	}
+
+	for _, key := range dropped {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"duplicate object %s %s by policy %s",
+			key,
+			action,
+			e.options.Duplicates,
+		))
+	}
+
 	// Apply filters
 	filtered, err := pipeline.ApplyFilters(ctx, allObjects, renderOpts.Filters)
 	if err != nil {
 		return nil, fmt.Errorf("engine filter error: %w", err)
 	}
 
+	// Apply collection filters
+	filtered, err = pipeline.ApplyCollectionFilters(ctx, filtered, renderOpts.CollectionFilters)
+	if err != nil {
+		return nil, fmt.Errorf("engine collection filter error: %w", err)
+	}
+
+	result.Stats.Filtered = len(allObjects) - len(filtered)
+
 	// Apply transformers
 	transformed, err := pipeline.ApplyTransformers(ctx, filtered, renderOpts.Transformers)
 	if err != nil {
 		return nil, fmt.Errorf("engine transformer error: %w", err)
 	}
 
-	metrics.ObserveRender(ctx, time.Since(startTime), len(transformed))
+	// Apply collection transformers
+	transformed, err = pipeline.ApplyCollectionTransformers(ctx, transformed, renderOpts.CollectionTransformers)
+	if err != nil {
+		return nil, fmt.Errorf("engine collection transformer error: %w", err)
+	}
 
 	return transformed, nil
 }
@@ -105,74 +368,226 @@ func (e *Engine) processRenderer(
 	ctx context.Context,
 	renderer types.Renderer,
 	values map[string]any,
-) ([]unstructured.Unstructured, error) {
+) RendererResult {
 	startTime := time.Now()
-	objects, err := renderer.Process(ctx, values)
+	objects, retries, err := e.invokeWithRetry(ctx, renderer, values)
+	if err == nil {
+		objects, err = pipeline.Apply(
+			ctx,
+			objects,
+			e.options.RendererFilters[renderer.Name()],
+			e.options.RendererTransformers[renderer.Name()],
+		)
+	}
+
+	duration := time.Since(startTime)
+
+	metrics.ObserveRenderer(ctx, renderer.Name(), duration, len(objects), err)
 
-	metrics.ObserveRenderer(ctx, renderer.Name(), time.Since(startTime), len(objects), err)
+	result := RendererResult{
+		Name:     renderer.Name(),
+		Objects:  objects,
+		Duration: duration,
+		Retries:  retries,
+	}
 
 	if err != nil {
-		return nil, fmt.Errorf(
-			"error processing renderer %q (%T): %w",
-			renderer.Name(),
-			renderer,
-			err,
-		)
+		result.Objects = nil
+		result.Err = rendererError(renderer, err)
 	}
 
-	return objects, nil
+	hooks(e.options.Hooks).rendererComplete(ctx, result)
+
+	return result
 }
 
-// renderSequential processes renderers sequentially in order.
-func (e *Engine) renderSequential(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
-	allObjects := make([]unstructured.Unstructured, 0)
+// invokeWithRetry runs a renderer, retrying failed runs according to the retry policy.
+// It returns the number of retries along with the outcome of the last run.
+func (e *Engine) invokeWithRetry(
+	ctx context.Context,
+	renderer types.Renderer,
+	values map[string]any,
+) ([]unstructured.Unstructured, int, error) {
+	policy := e.options.Retry
 
-	for _, renderer := range e.options.Renderers {
-		objects, err := e.processRenderer(ctx, renderer, values)
-		if err != nil {
-			return nil, err
+	for attempt := 1; ; attempt++ {
+		objects, abandoned, err := e.invoke(ctx, renderer, values)
+		if err == nil {
+			return objects, attempt - 1, nil
 		}
 
-		allObjects = append(allObjects, objects...)
-	}
+		// An abandoned run may still be executing, a retry would run the renderer concurrently with it
+		if policy == nil || abandoned || attempt >= policy.Attempts || ctx.Err() != nil || !policy.retryable(err) {
+			return nil, attempt - 1, err
+		}
 
-	return allObjects, nil
+		metrics.ObserveRetry(ctx, renderer.Name(), attempt, err)
+
+		if werr := wait(ctx, policy.delay(attempt)); werr != nil {
+			return nil, attempt - 1, timeoutError(ctx, errors.Join(err, werr))
+		}
+	}
 }
 
-// renderParallel processes all renderers concurrently using goroutines.
-// Results are collected in the original renderer order for consistent output.
-func (e *Engine) renderParallel(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
-	type result struct {
+// invoke runs a renderer, bounded by its timeout and by the render timeout if any. Renderers not
+// honoring the context cancellation are abandoned once the deadline expires, which is reported by
+// the returned bool.
+func (e *Engine) invoke(
+	ctx context.Context,
+	renderer types.Renderer,
+	values map[string]any,
+) ([]unstructured.Unstructured, bool, error) {
+	timeout := e.options.Timeouts[renderer.Name()]
+
+	if _, ok := ctx.Deadline(); !ok && timeout <= 0 {
+		objects, err := renderer.Process(ctx, values)
+
+		return objects, false, err
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
 		objects []unstructured.Unstructured
 		err     error
 	}
 
-	results := make([]result, len(e.options.Renderers))
+	// Buffered, so an abandoned renderer doesn't block forever when it eventually returns
+	done := make(chan outcome, 1)
+
+	go func() {
+		objects, err := renderer.Process(ctx, values)
+		done <- outcome{objects: objects, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil {
+			return nil, false, timeoutError(ctx, o.err)
+		}
+
+		return o.objects, false, nil
+	case <-ctx.Done():
+		return nil, true, timeoutError(ctx, ctx.Err())
+	}
+}
+
+// timeoutError marks errors of renderers whose deadline expired with ErrRendererTimeout.
+func timeoutError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrRendererTimeout) {
+		return fmt.Errorf("%w: %w", ErrRendererTimeout, err)
+	}
+
+	return err
+}
+
+// rendererError wraps an error with the name and type of the renderer.
+func rendererError(renderer types.Renderer, err error) error {
+	return fmt.Errorf(
+		"error processing renderer %q (%T): %w",
+		renderer.Name(),
+		unwrapRenderer(renderer),
+		err,
+	)
+}
+
+// renderSequential processes renderers sequentially in order, stopping at the first failure
+// unless the failure policy is FailurePolicyContinueOnError.
+func (e *Engine) renderSequential(ctx context.Context, renderOpts RenderOptions) ([]RendererResult, error) {
+	results := make([]RendererResult, 0, len(e.options.Renderers))
+
+	var firstErr error
+
+	for _, renderer := range e.options.Renderers {
+		result := e.processRenderer(ctx, renderer, rendererValues(renderOpts, renderer))
+		results = append(results, result)
+
+		if result.Err == nil || firstErr != nil {
+			continue
+		}
+
+		firstErr = result.Err
+
+		if e.options.Failures != FailurePolicyContinueOnError {
+			break
+		}
+	}
+
+	return results, firstErr
+}
+
+// renderParallel processes all renderers concurrently using goroutines, bounded by the
+// MaxConcurrency limit if any. Results are collected in the original renderer order for
+// consistent output.
+func (e *Engine) renderParallel(ctx context.Context, renderOpts RenderOptions) ([]RendererResult, error) {
+	results := make([]RendererResult, len(e.options.Renderers))
 	var wg sync.WaitGroup
 
+	var sem *semaphore.Weighted
+	if e.options.MaxConcurrency > 0 {
+		sem = semaphore.NewWeighted(int64(e.options.MaxConcurrency))
+	}
+
 	for i, renderer := range e.options.Renderers {
 		wg.Add(1)
 		go func(idx int, r types.Renderer) {
 			defer wg.Done()
-			objects, err := e.processRenderer(ctx, r, values)
-			results[idx] = result{
-				objects: objects,
-				err:     err,
+
+			if sem != nil {
+				weight := e.weight(r)
+
+				if err := sem.Acquire(ctx, weight); err != nil {
+					results[idx] = RendererResult{
+						Name: r.Name(),
+						Err:  rendererError(r, timeoutError(ctx, err)),
+					}
+
+					hooks(e.options.Hooks).rendererComplete(ctx, results[idx])
+
+					return
+				}
+
+				defer sem.Release(weight)
 			}
+
+			results[idx] = e.processRenderer(ctx, r, rendererValues(renderOpts, r))
 		}(i, renderer)
 	}
 
 	wg.Wait()
 
-	// Collect results in original renderer order
-	allObjects := make([]unstructured.Unstructured, 0)
+	// Report the first failure in original renderer order
 	for _, res := range results {
-		if res.err != nil {
-			return nil, res.err
+		if res.Err != nil {
+			return results, res.Err
 		}
+	}
+
+	return results, nil
+}
+
+// rendererValues returns the render-time values of a renderer: the values for all renderers, with
+// the values for its name deep merged over them.
+func rendererValues(renderOpts RenderOptions, renderer types.Renderer) map[string]any {
+	values, ok := renderOpts.RendererValues[renderer.Name()]
+	if !ok {
+		return renderOpts.Values
+	}
+
+	return util.DeepMerge(renderOpts.Values, values)
+}
 
-		allObjects = append(allObjects, res.objects...)
+// weight returns the weight of a renderer against the MaxConcurrency limit.
+func (e *Engine) weight(renderer types.Renderer) int64 {
+	weight, ok := e.options.Weights[renderer.Name()]
+	if !ok {
+		weight = 1
 	}
 
-	return allObjects, nil
+	return int64(min(weight, e.options.MaxConcurrency))
 }
@@ -0,0 +1,116 @@
+package engine_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+// concurrencyTracker records the maximum number of renderers running at the same time.
+type concurrencyTracker struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (c *concurrencyTracker) renderer(name string) *mockRenderer {
+	return &mockRenderer{
+		name: name,
+		processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+			n := c.running.Add(1)
+			defer c.running.Add(-1)
+
+			for {
+				peak := c.peak.Load()
+				if n <= peak || c.peak.CompareAndSwap(peak, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			return []unstructured.Unstructured{makePod(name)}, nil
+		},
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+
+	t.Run("should bound the number of concurrent renderers", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := &concurrencyTracker{}
+
+		opts := []engine.Option{
+			engine.WithParallel(true),
+			engine.WithMaxConcurrency(2),
+		}
+
+		for range 6 {
+			opts = append(opts, engine.WithRenderer(tracker.renderer("mock")))
+		}
+
+		e, err := engine.New(opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(6))
+		g.Expect(tracker.peak.Load()).To(BeNumerically("<=", 2))
+	})
+
+	t.Run("should count renderers with their weight", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := &concurrencyTracker{}
+
+		e, err := engine.New(
+			engine.WithParallel(true),
+			engine.WithMaxConcurrency(3),
+			engine.WithRendererWeight("heavy", 5),
+			engine.WithRenderer(tracker.renderer("heavy")),
+			engine.WithRenderer(tracker.renderer("heavy")),
+			engine.WithRenderer(tracker.renderer("heavy")),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		// Weights larger than the limit are capped, so heavy renderers run alone
+		g.Expect(tracker.peak.Load()).To(BeNumerically("==", 1))
+	})
+
+	t.Run("should report renderers canceled while waiting", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := &concurrencyTracker{}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		e, err := engine.New(
+			engine.WithParallel(true),
+			engine.WithMaxConcurrency(1),
+			engine.WithRenderer(tracker.renderer("mock")),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(ctx)
+		g.Expect(err).To(MatchError(context.Canceled))
+	})
+
+	t.Run("should reject invalid limits", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithMaxConcurrency(-1))
+		g.Expect(err).To(MatchError(engine.ErrInvalidConcurrency))
+
+		_, err = engine.New(engine.WithRendererWeight("helm", 0))
+		g.Expect(err).To(MatchError(engine.ErrInvalidConcurrency))
+	})
+}
@@ -0,0 +1,87 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/diff"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDiff(t *testing.T) {
+	// Renders pod1, labeled with the "version" value, and pod2 only when the "extra" value is set
+	renderer := &mockRenderer{
+		processFunc: func(_ context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+			if values["fail"] == true {
+				return nil, errors.New("render failed")
+			}
+
+			pod := makePod("pod1")
+			if version, ok := values["version"].(string); ok {
+				pod.SetLabels(map[string]string{"version": version})
+			}
+
+			objects := []unstructured.Unstructured{pod}
+			if values["extra"] == true {
+				objects = append(objects, makePod("pod2"))
+			}
+
+			return objects, nil
+		},
+	}
+
+	t.Run("should diff two renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.Diff(
+			t.Context(),
+			[]engine.RenderOption{engine.WithValues(map[string]any{"version": "1.0"})},
+			[]engine.RenderOption{engine.WithValues(map[string]any{"version": "2.0", "extra": true})},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Removed).To(BeEmpty())
+		g.Expect(result.Added).To(HaveLen(1))
+		g.Expect(result.Added[0].GetName()).To(Equal("pod2"))
+		g.Expect(result.Changed).To(HaveLen(1))
+		g.Expect(result.Changed[0].Key.Name).To(Equal("pod1"))
+		g.Expect(result.Changed[0].Changes).To(Equal([]diff.Change{
+			{Path: "metadata.labels.version", Before: "1.0", After: "2.0"},
+		}))
+	})
+
+	t.Run("should report no differences for the same options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.Diff(t.Context(), nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Empty()).To(BeTrue())
+	})
+
+	t.Run("should fail when a render fails", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(renderer))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Diff(
+			t.Context(),
+			nil,
+			[]engine.RenderOption{engine.WithValues(map[string]any{"fail": true})},
+		)
+		g.Expect(err).To(MatchError(ContainSubstring("after render failed")))
+	})
+}
@@ -0,0 +1,213 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// dryRunFieldManager is the field manager of the server-side apply requests of the dry-run validation.
+const dryRunFieldManager = "k8s-manifests-lib"
+
+var (
+	crdGroupKind       = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	namespaceGroupKind = schema.GroupKind{Kind: "Namespace"}
+)
+
+// ErrDryRunClientRequired is returned by New when the dry-run validation has neither a client and a
+// REST mapper nor a REST configuration to create them from.
+var ErrDryRunClientRequired = errors.New("dry-run validation requires a REST config or a client and a REST mapper")
+
+// DryRun is the configuration of the server-side dry-run validation of the rendered objects.
+type DryRun struct {
+	// Client is the dynamic client sending the dry-run requests.
+	Client dynamic.Interface
+
+	// Mapper resolves the GroupVersionKinds of the objects to resources.
+	Mapper meta.RESTMapper
+}
+
+// ObjectError is the failure of a single object within a ValidationError.
+type ObjectError struct {
+	// Object is the rendered object rejected by the API server.
+	Object unstructured.Unstructured
+
+	// Err is the error returned by the API server, wrapped with the object identity.
+	Err error
+}
+
+// ValidationError is returned by Render and RenderResult when the API server rejects some of the
+// rendered objects during the dry-run validation, see WithDryRunValidation.
+type ValidationError struct {
+	// Objects are the rejected objects, in rendered order.
+	Objects []ObjectError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Objects))
+	for _, o := range e.Objects {
+		msgs = append(msgs, o.Err.Error())
+	}
+
+	return fmt.Sprintf("%d object(s) failed dry-run validation: %s", len(e.Objects), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the rejected objects, so errors.Is and errors.As match any of them,
+// e.g. apierrors.IsInvalid through errors.As with *apierrors.StatusError.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Objects))
+	for _, o := range e.Objects {
+		errs = append(errs, o.Err)
+	}
+
+	return errs
+}
+
+// newDryRun returns the dry-run validation clients, creating them from the REST configuration
+// unless provided explicitly.
+func newDryRun(opts Options) (*DryRun, error) {
+	if opts.DryRun != nil {
+		if opts.DryRun.Client == nil || opts.DryRun.Mapper == nil {
+			return nil, ErrDryRunClientRequired
+		}
+
+		return opts.DryRun, nil
+	}
+
+	if opts.DryRunConfig == nil {
+		return nil, ErrDryRunClientRequired
+	}
+
+	client, err := dynamic.NewForConfig(opts.DryRunConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(opts.DryRunConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	return &DryRun{
+		Client: client,
+		Mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+	}, nil
+}
+
+// renderedDefinitions holds the kinds and namespaces created by the rendered objects themselves.
+type renderedDefinitions struct {
+	kinds      map[schema.GroupKind]bool
+	namespaces map[string]bool
+}
+
+// definitionsOf returns the kinds defined by the rendered CustomResourceDefinitions and the
+// rendered Namespaces.
+func definitionsOf(objects []unstructured.Unstructured) renderedDefinitions {
+	defs := renderedDefinitions{
+		kinds:      make(map[schema.GroupKind]bool),
+		namespaces: make(map[string]bool),
+	}
+
+	for i := range objects {
+		switch objects[i].GroupVersionKind().GroupKind() {
+		case crdGroupKind:
+			group, _, _ := unstructured.NestedString(objects[i].Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(objects[i].Object, "spec", "names", "kind")
+
+			defs.kinds[schema.GroupKind{Group: group, Kind: kind}] = true
+		case namespaceGroupKind:
+			defs.namespaces[objects[i].GetName()] = true
+		}
+	}
+
+	return defs
+}
+
+// covers reports whether the object was rejected only because its kind or its namespace is
+// created by the render itself, and does not exist yet on the cluster.
+func (d renderedDefinitions) covers(obj *unstructured.Unstructured, err error) bool {
+	switch {
+	case meta.IsNoMatchError(err):
+		return d.kinds[obj.GroupVersionKind().GroupKind()]
+	case apierrors.IsNotFound(err):
+		return d.namespaces[obj.GetNamespace()]
+	default:
+		return false
+	}
+}
+
+// validate sends each object to the API server with a server-side dry-run apply, so admission
+// and validation run without persisting anything. All the objects are validated and the rejected
+// ones reported together in a *ValidationError.
+//
+// Objects are applied one at a time, so custom resources whose CustomResourceDefinition and
+// objects whose Namespace are part of the same render can't be validated until these exist on
+// the cluster: they are skipped when rejected for this reason.
+func (d *DryRun) validate(ctx context.Context, objects []unstructured.Unstructured) error {
+	var failed []ObjectError
+
+	defs := definitionsOf(objects)
+
+	for i := range objects {
+		if err := d.apply(ctx, &objects[i]); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("dry-run validation interrupted: %w", ctx.Err())
+			}
+
+			if defs.covers(&objects[i], err) {
+				continue
+			}
+
+			failed = append(failed, ObjectError{
+				Object: objects[i],
+				Err:    fmt.Errorf("%s: %w", keyOf(objects[i]), err),
+			})
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Objects: failed}
+}
+
+// apply sends a single object with a server-side dry-run apply.
+func (d *DryRun) apply(ctx context.Context, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := d.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve resource: %w", err)
+	}
+
+	var ri dynamic.ResourceInterface = d.Client.Resource(mapping.Resource)
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		namespace := obj.GetNamespace()
+		if namespace == "" {
+			namespace = metav1.NamespaceDefault
+		}
+
+		ri = d.Client.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	_, err = ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+		DryRun:       []string{metav1.DryRunAll},
+		FieldManager: dryRunFieldManager,
+		Force:        true,
+	})
+
+	return err
+}
@@ -0,0 +1,214 @@
+package engine_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+// newDryRunClient returns a fake dynamic client rejecting the objects named in invalid and
+// recording the namespace/name of the applied objects.
+func newDryRunClient(invalid ...string) (*fake.FakeDynamicClient, func() []string) {
+	var (
+		mu      sync.Mutex
+		applied []string
+	)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch, ok := action.(clienttesting.PatchAction)
+		if !ok {
+			return false, nil, nil
+		}
+
+		mu.Lock()
+		applied = append(applied, patch.GetNamespace()+"/"+patch.GetName())
+		mu.Unlock()
+
+		for _, name := range invalid {
+			if patch.GetName() == name {
+				return true, nil, apierrors.NewInvalid(
+					schema.GroupKind{Kind: "Pod"},
+					name,
+					field.ErrorList{field.Required(field.NewPath("spec", "containers"), "")},
+				)
+			}
+		}
+
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return applied
+	}
+}
+
+func newDryRunMapper() *meta.DefaultRESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+	return mapper
+}
+
+func TestDryRunValidation(t *testing.T) {
+	t.Run("should validate all rendered objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, applied := newDryRunClient()
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{
+				makePodWithNamespace("pod1", "team-a"),
+				makePod("pod2"),
+				makeService(),
+			})),
+			engine.WithFilter(podFilter()),
+			engine.WithDryRunClient(client, newDryRunMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(applied()).To(Equal([]string{"team-a/pod1", "default/pod2"}))
+	})
+
+	t.Run("should report the rejected objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, applied := newDryRunClient("pod1", "pod3")
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{
+				makePod("pod1"),
+				makePod("pod2"),
+				makePod("pod3"),
+			})),
+			engine.WithDryRunClient(client, newDryRunMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(objects).To(BeNil())
+		g.Expect(applied()).To(HaveLen(3))
+		g.Expect(err.Error()).To(ContainSubstring("2 object(s) failed dry-run validation"))
+
+		var validationErr *engine.ValidationError
+		g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+		g.Expect(validationErr.Objects).To(HaveLen(2))
+		g.Expect(validationErr.Objects[0].Object.GetName()).To(Equal("pod1"))
+		g.Expect(validationErr.Objects[1].Object.GetName()).To(Equal("pod3"))
+
+		var statusErr *apierrors.StatusError
+		g.Expect(errors.As(validationErr.Objects[0].Err, &statusErr)).To(BeTrue())
+		g.Expect(apierrors.IsInvalid(statusErr)).To(BeTrue())
+	})
+
+	t.Run("should report objects of unknown kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, applied := newDryRunClient()
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makeService()})),
+			engine.WithDryRunClient(client, newDryRunMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(meta.IsNoMatchError(err)).To(BeTrue())
+		g.Expect(applied()).To(BeEmpty())
+	})
+
+	t.Run("should skip objects depending on kinds and namespaces created by the render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		client, applied := newDryRunClient()
+
+		// Namespaces not created yet on the cluster
+		client.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			if ns := action.GetNamespace(); ns == "team-b" || ns == "team-c" {
+				return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, ns)
+			}
+
+			return false, nil, nil
+		})
+
+		mapper := newDryRunMapper()
+		mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+		mapper.Add(schema.GroupVersionKind{
+			Group:   "apiextensions.k8s.io",
+			Version: "v1",
+			Kind:    "CustomResourceDefinition",
+		}, meta.RESTScopeRoot)
+
+		crd := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]any{"name": "widgets.example.com"},
+			"spec": map[string]any{
+				"group": "example.com",
+				"names": map[string]any{"kind": "Widget", "plural": "widgets"},
+			},
+		}}
+		widget := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]any{"name": "widget1"},
+		}}
+		namespace := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]any{"name": "team-b"},
+		}}
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{
+				crd,
+				widget,
+				namespace,
+				makePodWithNamespace("pod1", "team-b"),
+				makePodWithNamespace("pod2", "team-c"),
+			})),
+			engine.WithDryRunClient(client, mapper),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(applied()).To(Equal([]string{"/widgets.example.com", "/team-b"}))
+
+		// Only the Pod in a namespace missing from both the cluster and the render is rejected
+		var validationErr *engine.ValidationError
+		g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+		g.Expect(validationErr.Objects).To(HaveLen(1))
+		g.Expect(validationErr.Objects[0].Object.GetName()).To(Equal("pod2"))
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("should require a client and a mapper", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(
+			engine.WithDryRunClient(nil, newDryRunMapper()),
+		)
+		g.Expect(err).To(MatchError(engine.ErrDryRunClientRequired))
+	})
+}
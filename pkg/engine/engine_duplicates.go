@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// ErrDuplicateObject is returned by the DuplicatePolicyFail policy for objects rendered more than once.
+var ErrDuplicateObject = errors.New("duplicate object")
+
+// objectKey identifies an object by its GroupVersionKind, namespace and name.
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s %s", k.gvk, k.name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", k.gvk, k.namespace, k.name)
+}
+
+func keyOf(obj unstructured.Unstructured) objectKey {
+	return objectKey{
+		gvk:       obj.GroupVersionKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+// Conflict is an object rendered more than once, reported by the DuplicatePolicyFail policy.
+type Conflict struct {
+	// GroupVersionKind is the GroupVersionKind of the object.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Namespace is the namespace of the object, empty for cluster-scoped objects.
+	Namespace string
+
+	// Name is the name of the object.
+	Name string
+
+	// Renderers are the names of the renderers of each occurrence, in rendered order.
+	Renderers []string
+}
+
+func (c Conflict) String() string {
+	key := objectKey{gvk: c.GroupVersionKind, namespace: c.Namespace, name: c.Name}
+
+	return fmt.Sprintf("%s (rendered by %s)", key, strings.Join(c.Renderers, ", "))
+}
+
+// ConflictError is returned by the DuplicatePolicyFail policy, reporting all the objects rendered
+// more than once. It matches ErrDuplicateObject with errors.Is.
+type ConflictError struct {
+	// Conflicts are the objects rendered more than once, in order of first occurrence.
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	msgs := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		msgs = append(msgs, c.String())
+	}
+
+	return fmt.Sprintf("%s: %d conflict(s): %s", ErrDuplicateObject, len(e.Conflicts), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns ErrDuplicateObject.
+func (e *ConflictError) Unwrap() error {
+	return ErrDuplicateObject
+}
+
+// deduplicate applies the policy to objects sharing the same GroupVersionKind, namespace and name.
+// Objects without a name, e.g. relying on generateName, are distinct objects and always kept.
+// The origins are the names of the renderers of the objects, and preferred the renderer names in
+// order of precedence for DuplicatePolicyPrefer.
+// The kept objects retain their position in the rendered output, merged objects the position of
+// the first occurrence; the keys of the dropped occurrences are returned along with them.
+func deduplicate(
+	objects []unstructured.Unstructured,
+	origins []string,
+	policy DuplicatePolicy,
+	preferred []string,
+) ([]unstructured.Unstructured, []objectKey, error) {
+	if policy == DuplicatePolicyAllow {
+		return objects, nil, nil
+	}
+
+	// Indexes of the occurrences of each object, in rendered order
+	occurrences := make(map[objectKey][]int, len(objects))
+
+	for i := range objects {
+		if objects[i].GetName() == "" {
+			continue
+		}
+
+		key := keyOf(objects[i])
+		occurrences[key] = append(occurrences[key], i)
+	}
+
+	if policy == DuplicatePolicyFail {
+		if err := conflicts(objects, origins, occurrences); err != nil {
+			return nil, nil, err
+		}
+
+		return objects, nil, nil
+	}
+
+	results := make([]unstructured.Unstructured, 0, len(occurrences))
+
+	var dropped []objectKey
+
+	for i := range objects {
+		key := keyOf(objects[i])
+		indexes := occurrences[key]
+
+		if len(indexes) <= 1 {
+			results = append(results, objects[i])
+
+			continue
+		}
+
+		switch policy {
+		case DuplicatePolicyKeepFirst:
+			if indexes[0] != i {
+				dropped = append(dropped, key)
+
+				continue
+			}
+
+			results = append(results, objects[i])
+		case DuplicatePolicyKeepLast:
+			if indexes[len(indexes)-1] != i {
+				dropped = append(dropped, key)
+
+				continue
+			}
+
+			results = append(results, objects[i])
+		case DuplicatePolicyPrefer:
+			if prefer(indexes, origins, preferred) != i {
+				dropped = append(dropped, key)
+
+				continue
+			}
+
+			results = append(results, objects[i])
+		case DuplicatePolicyMerge:
+			if indexes[0] != i {
+				dropped = append(dropped, key)
+
+				continue
+			}
+
+			results = append(results, merge(objects, indexes))
+		}
+	}
+
+	return results, dropped, nil
+}
+
+// conflicts returns a *ConflictError reporting the objects with more than one occurrence, if any.
+func conflicts(objects []unstructured.Unstructured, origins []string, occurrences map[objectKey][]int) error {
+	var found []Conflict
+
+	for i := range objects {
+		key := keyOf(objects[i])
+		indexes := occurrences[key]
+
+		if len(indexes) <= 1 || indexes[0] != i {
+			continue
+		}
+
+		renderers := make([]string, 0, len(indexes))
+		for _, idx := range indexes {
+			renderers = append(renderers, origins[idx])
+		}
+
+		found = append(found, Conflict{
+			GroupVersionKind: key.gvk,
+			Namespace:        key.namespace,
+			Name:             key.name,
+			Renderers:        renderers,
+		})
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+
+	return &ConflictError{Conflicts: found}
+}
+
+// prefer returns the index of the occurrence rendered by the renderer coming first in preferred,
+// the first occurrence if none of their renderers is listed.
+func prefer(indexes []int, origins []string, preferred []string) int {
+	best := indexes[0]
+	rank := rankOf(origins[best], preferred)
+
+	for _, idx := range indexes[1:] {
+		if r := rankOf(origins[idx], preferred); r < rank {
+			best, rank = idx, r
+		}
+	}
+
+	return best
+}
+
+// rankOf returns the position of name in preferred, len(preferred) if it is not listed.
+func rankOf(name string, preferred []string) int {
+	if i := slices.Index(preferred, name); i >= 0 {
+		return i
+	}
+
+	return len(preferred)
+}
+
+// merge deep merges the occurrences of an object in rendered order, so later renderers override
+// the conflicting fields of earlier ones. Lists are replaced, not merged.
+func merge(objects []unstructured.Unstructured, indexes []int) unstructured.Unstructured {
+	merged := objects[indexes[0]].Object
+
+	for _, idx := range indexes[1:] {
+		merged = util.DeepMerge(merged, objects[idx].Object)
+	}
+
+	return unstructured.Unstructured{Object: merged}
+}
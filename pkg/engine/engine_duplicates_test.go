@@ -0,0 +1,255 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDuplicatePolicy(t *testing.T) {
+	withLabel := func(obj unstructured.Unstructured, value string) unstructured.Unstructured {
+		obj.SetLabels(map[string]string{"source": value})
+
+		return obj
+	}
+
+	first := newMockRenderer([]unstructured.Unstructured{
+		withLabel(makePod("pod1"), "first"),
+		withLabel(makePodWithNamespace("pod1", systemNamespace), "first"),
+		withLabel(makeService(), "first"),
+	})
+	second := newMockRenderer([]unstructured.Unstructured{
+		withLabel(makePod("pod2"), "second"),
+		withLabel(makePod("pod1"), "second"),
+	})
+
+	tests := []struct {
+		name     string
+		policy   engine.DuplicatePolicy
+		expected []string
+	}{
+		{
+			name:     "should keep duplicates by default",
+			expected: []string{"pod1/first", "pod1/first", "svc1/first", "pod2/second", "pod1/second"},
+		},
+		{
+			name:     "should keep duplicates when allowed",
+			policy:   engine.DuplicatePolicyAllow,
+			expected: []string{"pod1/first", "pod1/first", "svc1/first", "pod2/second", "pod1/second"},
+		},
+		{
+			name:     "should keep the first occurrence",
+			policy:   engine.DuplicatePolicyKeepFirst,
+			expected: []string{"pod1/first", "pod1/first", "svc1/first", "pod2/second"},
+		},
+		{
+			name:     "should keep the last occurrence",
+			policy:   engine.DuplicatePolicyKeepLast,
+			expected: []string{"pod1/first", "svc1/first", "pod2/second", "pod1/second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := []engine.Option{
+				engine.WithRenderer(first),
+				engine.WithRenderer(second),
+			}
+
+			if tt.policy != "" {
+				opts = append(opts, engine.WithDuplicatePolicy(tt.policy))
+			}
+
+			e, err := engine.New(opts...)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			names := make([]string, 0, len(objects))
+			for _, obj := range objects {
+				names = append(names, obj.GetName()+"/"+obj.GetLabels()["source"])
+			}
+
+			g.Expect(names).To(Equal(tt.expected))
+		})
+	}
+
+	t.Run("should fail on duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(first),
+			engine.WithRenderer(second),
+			engine.WithDuplicatePolicy(engine.DuplicatePolicyFail),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrDuplicateObject))
+		g.Expect(err.Error()).To(ContainSubstring("pod1"))
+	})
+
+	t.Run("should not fail on objects differing by namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(first),
+			engine.WithDuplicatePolicy(engine.DuplicatePolicyFail),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+	})
+
+	t.Run("should keep objects without a name", func(t *testing.T) {
+		generated := func() unstructured.Unstructured {
+			obj := makePod("")
+			obj.SetGenerateName("job-")
+
+			return obj
+		}
+
+		for _, policy := range []engine.DuplicatePolicy{
+			engine.DuplicatePolicyFail,
+			engine.DuplicatePolicyKeepFirst,
+			engine.DuplicatePolicyKeepLast,
+			engine.DuplicatePolicyPrefer,
+			engine.DuplicatePolicyMerge,
+		} {
+			g := NewWithT(t)
+
+			e, err := engine.New(
+				engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{generated(), generated()})),
+				engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{generated()})),
+				engine.WithDuplicatePolicy(policy),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := e.Render(t.Context())
+			g.Expect(err).ToNot(HaveOccurred(), string(policy))
+			g.Expect(objects).To(HaveLen(3), string(policy))
+		}
+	})
+
+	t.Run("should reject unknown policies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithDuplicatePolicy("Ignore"))
+		g.Expect(err).To(MatchError(engine.ErrInvalidDuplicatePolicy))
+	})
+}
+
+func TestDuplicateConflicts(t *testing.T) {
+	withLabels := func(obj unstructured.Unstructured, lbls map[string]string) unstructured.Unstructured {
+		obj.SetLabels(lbls)
+
+		return obj
+	}
+
+	newEngine := func(g *WithT, opts ...engine.Option) *engine.Engine {
+		opts = append(
+			opts,
+			engine.WithNamedRenderer("platform", newMockRenderer([]unstructured.Unstructured{
+				withLabels(makePod("pod1"), map[string]string{"source": "platform", "tier": "system"}),
+				makeService(),
+			})),
+			engine.WithNamedRenderer("app", newMockRenderer([]unstructured.Unstructured{
+				withLabels(makePod("pod1"), map[string]string{"source": "app", "app": "web"}),
+				makePod("pod2"),
+				makeService(),
+			})),
+		)
+
+		e, err := engine.New(opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		return e
+	}
+
+	t.Run("should prefer the configured renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g,
+			engine.WithDuplicatePolicy(engine.DuplicatePolicyPrefer),
+			engine.WithPreferredRenderers("app"),
+		)
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetName()).To(Equal("pod1"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("source", "app"))
+		g.Expect(objects[1].GetName()).To(Equal("pod2"))
+		g.Expect(objects[2].GetName()).To(Equal("svc1"))
+	})
+
+	t.Run("should keep the first occurrence without preferred renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, engine.WithDuplicatePolicy(engine.DuplicatePolicyPrefer))
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("source", "platform"))
+	})
+
+	t.Run("should merge the occurrences", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, engine.WithDuplicatePolicy(engine.DuplicatePolicyMerge))
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Objects).To(HaveLen(3))
+		g.Expect(result.Objects[0].GetName()).To(Equal("pod1"))
+		g.Expect(result.Objects[0].GetLabels()).To(Equal(map[string]string{
+			"source": "app",
+			"tier":   "system",
+			"app":    "web",
+		}))
+		g.Expect(result.Objects[1].GetName()).To(Equal("svc1"))
+		g.Expect(result.Objects[2].GetName()).To(Equal("pod2"))
+		g.Expect(result.Stats.Duplicates).To(Equal(2))
+		g.Expect(result.Warnings).To(ContainElement(ContainSubstring("merged by policy Merge")))
+	})
+
+	t.Run("should report all conflicts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e := newEngine(g, engine.WithDuplicatePolicy(engine.DuplicatePolicyFail))
+
+		_, err := e.Render(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrDuplicateObject))
+		g.Expect(err.Error()).To(ContainSubstring("2 conflict(s)"))
+		g.Expect(err.Error()).To(ContainSubstring("pod1 (rendered by platform, app)"))
+
+		var conflictErr *engine.ConflictError
+		g.Expect(errors.As(err, &conflictErr)).To(BeTrue())
+		g.Expect(conflictErr.Conflicts).To(HaveLen(2))
+		g.Expect(conflictErr.Conflicts[0].Name).To(Equal("pod1"))
+		g.Expect(conflictErr.Conflicts[0].Renderers).To(Equal([]string{"platform", "app"}))
+		g.Expect(conflictErr.Conflicts[1].Name).To(Equal("svc1"))
+		g.Expect(conflictErr.Conflicts[1].GroupVersionKind.Kind).To(Equal("Service"))
+	})
+
+	t.Run("should reject unknown preferred renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(
+			engine.WithNamedRenderer("app", newMockRenderer(nil)),
+			engine.WithPreferredRenderers("platform"),
+		)
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+	})
+}
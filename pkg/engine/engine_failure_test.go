@@ -0,0 +1,103 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFailurePolicy(t *testing.T) {
+	errFailed := errors.New("renderer failed")
+
+	failing := func(name string) *mockRenderer {
+		return &mockRenderer{
+			name: name,
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				return nil, errFailed
+			},
+		}
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("should return the objects of the successful renderers (parallel: %t)", parallel), func(t *testing.T) {
+			g := NewWithT(t)
+
+			e, err := engine.New(
+				engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+				engine.WithRenderer(failing("first")),
+				engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod2")})),
+				engine.WithRenderer(failing("second")),
+				engine.WithFailurePolicy(engine.FailurePolicyContinueOnError),
+				engine.WithParallel(parallel),
+				engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := e.Render(t.Context())
+			g.Expect(err).To(MatchError(errFailed))
+
+			var renderErr *engine.RenderError
+			g.Expect(errors.As(err, &renderErr)).To(BeTrue())
+			g.Expect(renderErr.Renderers).To(HaveLen(2))
+			g.Expect(renderErr.Renderers[0].Index).To(Equal(1))
+			g.Expect(renderErr.Renderers[0].Name).To(Equal("first"))
+			g.Expect(renderErr.Renderers[1].Index).To(Equal(3))
+			g.Expect(renderErr.Renderers[1].Name).To(Equal("second"))
+			g.Expect(err.Error()).To(ContainSubstring("2 renderer(s) failed"))
+
+			g.Expect(objects).To(HaveLen(2))
+			g.Expect(objects[0].GetName()).To(Equal("pod1"))
+			g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("env", "prod"))
+			g.Expect(objects[1].GetName()).To(Equal("pod2"))
+		})
+	}
+
+	t.Run("should report failures in the render result", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(failing("failing")),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithFailurePolicy(engine.FailurePolicyContinueOnError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).To(MatchError(errFailed))
+		g.Expect(result.Objects).To(HaveLen(1))
+		g.Expect(result.Renderers).To(HaveLen(2))
+		g.Expect(result.Renderers[0].Err).To(MatchError(errFailed))
+		g.Expect(result.Warnings).To(BeEmpty())
+	})
+
+	t.Run("should fail fast by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(failing("failing")),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).To(MatchError(errFailed))
+		g.Expect(objects).To(BeNil())
+
+		var renderErr *engine.RenderError
+		g.Expect(errors.As(err, &renderErr)).To(BeFalse())
+	})
+
+	t.Run("should reject unknown policies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithFailurePolicy("Ignore"))
+		g.Expect(err).To(MatchError(engine.ErrInvalidFailurePolicy))
+	})
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Hooks are callbacks invoked by the engine at the stages of a render, e.g. for progress
+// reporting, auditing or custom metrics. All of them are optional. Hooks must not modify the
+// objects and results they receive, and must be safe for concurrent use as renderers may run in
+// parallel and renders may occur concurrently.
+type Hooks struct {
+	// OnRenderStart is called at the start of each render.
+	OnRenderStart func(ctx context.Context)
+
+	// OnRendererComplete is called after each renderer run, whether it succeeded or not.
+	OnRendererComplete func(ctx context.Context, result RendererResult)
+
+	// OnObjectEmitted is called for each final object of a render, in output order.
+	OnObjectEmitted func(ctx context.Context, object unstructured.Unstructured)
+
+	// OnRenderComplete is called at the end of each render producing objects, with its result.
+	OnRenderComplete func(ctx context.Context, result *RenderResult)
+
+	// OnError is called with the error of a failed render, or with the *RenderError reporting the
+	// failed renderers with FailurePolicyContinueOnError.
+	OnError func(ctx context.Context, err error)
+}
+
+// hooks invokes the callbacks of all the registered Hooks, in registration order.
+type hooks []Hooks
+
+func (h hooks) renderStart(ctx context.Context) {
+	for _, hook := range h {
+		if hook.OnRenderStart != nil {
+			hook.OnRenderStart(ctx)
+		}
+	}
+}
+
+func (h hooks) rendererComplete(ctx context.Context, result RendererResult) {
+	for _, hook := range h {
+		if hook.OnRendererComplete != nil {
+			hook.OnRendererComplete(ctx, result)
+		}
+	}
+}
+
+func (h hooks) renderComplete(ctx context.Context, result *RenderResult) {
+	for _, hook := range h {
+		if hook.OnObjectEmitted != nil {
+			for _, obj := range result.Objects {
+				hook.OnObjectEmitted(ctx, obj)
+			}
+		}
+
+		if hook.OnRenderComplete != nil {
+			hook.OnRenderComplete(ctx, result)
+		}
+	}
+}
+
+func (h hooks) error(ctx context.Context, err error) {
+	for _, hook := range h {
+		if hook.OnError != nil {
+			hook.OnError(ctx, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+// hookRecorder records the hook invocations as events.
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *hookRecorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *hookRecorder) hooks() engine.Hooks {
+	return engine.Hooks{
+		OnRenderStart: func(_ context.Context) {
+			r.record("start")
+		},
+		OnRendererComplete: func(_ context.Context, result engine.RendererResult) {
+			if result.Err != nil {
+				r.record("renderer-failed:" + result.Name)
+			} else {
+				r.record("renderer:" + result.Name)
+			}
+		},
+		OnObjectEmitted: func(_ context.Context, obj unstructured.Unstructured) {
+			r.record("object:" + obj.GetName())
+		},
+		OnRenderComplete: func(_ context.Context, _ *engine.RenderResult) {
+			r.record("complete")
+		},
+		OnError: func(_ context.Context, _ error) {
+			r.record("error")
+		},
+	}
+}
+
+func TestHooks(t *testing.T) {
+
+	t.Run("should invoke hooks at each stage", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := &hookRecorder{}
+
+		e, err := engine.New(
+			engine.WithNamedRenderer("first", newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithNamedRenderer("second", newMockRenderer([]unstructured.Unstructured{makePod("pod2")})),
+			engine.WithHooks(recorder.hooks()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(recorder.events).To(Equal([]string{
+			"start",
+			"renderer:first",
+			"renderer:second",
+			"object:pod1",
+			"object:pod2",
+			"complete",
+		}))
+	})
+
+	t.Run("should report errors", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := &hookRecorder{}
+
+		failing := &mockRenderer{
+			name: "failing",
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				return nil, errors.New("renderer failed")
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(failing),
+			engine.WithHooks(recorder.hooks()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(HaveOccurred())
+
+		g.Expect(recorder.events).To(Equal([]string{
+			"start",
+			"renderer-failed:failing",
+			"error",
+		}))
+	})
+
+	t.Run("should report partial failures after the emitted objects", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := &hookRecorder{}
+
+		var reported error
+
+		failing := &mockRenderer{
+			name: "failing",
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				return nil, errors.New("renderer failed")
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(failing),
+			engine.WithFailurePolicy(engine.FailurePolicyContinueOnError),
+			engine.WithHooks(recorder.hooks()),
+			engine.WithHooks(engine.Hooks{
+				OnError: func(_ context.Context, err error) {
+					reported = err
+				},
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(reported).To(Equal(err))
+
+		g.Expect(recorder.events).To(Equal([]string{
+			"start",
+			"renderer:mock",
+			"renderer-failed:failing",
+			"object:pod1",
+			"complete",
+			"error",
+		}))
+	})
+
+	t.Run("should invoke hooks of parallel renderers", func(t *testing.T) {
+		g := NewWithT(t)
+		recorder := &hookRecorder{}
+
+		e, err := engine.New(
+			engine.WithNamedRenderer("first", newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithNamedRenderer("second", newMockRenderer([]unstructured.Unstructured{makePod("pod2")})),
+			engine.WithParallel(true),
+			engine.WithHooks(recorder.hooks()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(recorder.events).To(HaveLen(6))
+		g.Expect(recorder.events[1:3]).To(ConsistOf("renderer:first", "renderer:second"))
+	})
+}
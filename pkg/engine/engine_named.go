@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+var (
+	// ErrDuplicateRendererName is returned by New when a name given with WithNamedRenderer is used
+	// by another renderer.
+	ErrDuplicateRendererName = errors.New("duplicate renderer name")
+
+	// ErrUnknownRenderer is returned by New for renderer-scoped options naming no registered renderer.
+	ErrUnknownRenderer = errors.New("unknown renderer")
+)
+
+// namedRenderer overrides the name of a renderer registered with WithNamedRenderer.
+type namedRenderer struct {
+	types.Renderer
+
+	name string
+}
+
+func (r *namedRenderer) Name() string {
+	return r.name
+}
+
+// unwrapRenderer returns the renderer wrapped by WithNamedRenderer, if any.
+func unwrapRenderer(r types.Renderer) types.Renderer {
+	if named, ok := r.(*namedRenderer); ok {
+		return named.Renderer
+	}
+
+	return r
+}
+
+// validateNames checks that the names given with WithNamedRenderer are unique and that the
+// renderer-scoped options refer to registered renderers.
+func validateNames(options Options) error {
+	count := make(map[string]int, len(options.Renderers))
+	for _, r := range options.Renderers {
+		count[r.Name()]++
+	}
+
+	for _, r := range options.Renderers {
+		if _, ok := r.(*namedRenderer); ok && count[r.Name()] > 1 {
+			return fmt.Errorf("%w: %q", ErrDuplicateRendererName, r.Name())
+		}
+	}
+
+	for name := range options.RendererFilters {
+		if count[name] == 0 {
+			return fmt.Errorf("%w: filters for %q", ErrUnknownRenderer, name)
+		}
+	}
+
+	for name := range options.RendererTransformers {
+		if count[name] == 0 {
+			return fmt.Errorf("%w: transformers for %q", ErrUnknownRenderer, name)
+		}
+	}
+
+	for name := range options.Weights {
+		if count[name] == 0 {
+			return fmt.Errorf("%w: weight for %q", ErrUnknownRenderer, name)
+		}
+	}
+
+	for name := range options.Timeouts {
+		if count[name] == 0 {
+			return fmt.Errorf("%w: timeout for %q", ErrUnknownRenderer, name)
+		}
+	}
+
+	for _, name := range options.Preferred {
+		if count[name] == 0 {
+			return fmt.Errorf("%w: preferred renderer %q", ErrUnknownRenderer, name)
+		}
+	}
+
+	return nil
+}
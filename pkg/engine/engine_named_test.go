@@ -0,0 +1,114 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNamedRenderers(t *testing.T) {
+
+	t.Run("should scope filters and transformers to a named renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithNamedRenderer("dapr", newMockRenderer([]unstructured.Unstructured{makePod("dapr"), makeService()})),
+			engine.WithNamedRenderer("redis", newMockRenderer([]unstructured.Unstructured{makePod("redis"), makeService()})),
+			engine.WithRendererFilter("dapr", podFilter()),
+			engine.WithRendererTransformer("dapr", addLabels(map[string]string{"app": "dapr"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(result.Renderers).To(HaveLen(2))
+		g.Expect(result.Renderers[0].Name).To(Equal("dapr"))
+		g.Expect(result.Renderers[1].Name).To(Equal("redis"))
+
+		g.Expect(result.Objects).To(HaveLen(3))
+		g.Expect(result.Objects[0].GetName()).To(Equal("dapr"))
+		g.Expect(result.Objects[0].GetLabels()).To(HaveKeyWithValue("app", "dapr"))
+		g.Expect(result.Objects[1].GetName()).To(Equal("redis"))
+		g.Expect(result.Objects[1].GetLabels()).To(BeEmpty())
+		g.Expect(result.Objects[2].GetKind()).To(Equal("Service"))
+	})
+
+	t.Run("should target renderers by their default name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1"), makeService()})),
+			engine.WithRendererFilter("mock", podFilter()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should report scoped transformer failures as renderer failures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errFailed := errors.New("transformer failed")
+
+		e, err := engine.New(
+			engine.WithNamedRenderer("dapr", newMockRenderer([]unstructured.Unstructured{makePod("dapr")})),
+			engine.WithRendererTransformer("dapr", func(_ context.Context, _ unstructured.Unstructured) (unstructured.Unstructured, error) {
+				return unstructured.Unstructured{}, errFailed
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(errFailed))
+		g.Expect(err.Error()).To(ContainSubstring(`renderer "dapr" (*engine_test.mockRenderer)`))
+	})
+
+	t.Run("should reject duplicate names", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(
+			engine.WithNamedRenderer("mock", newMockRenderer(nil)),
+			engine.WithRenderer(newMockRenderer(nil)),
+		)
+		g.Expect(err).To(MatchError(engine.ErrDuplicateRendererName))
+	})
+
+	t.Run("should reject options for unknown renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(
+			engine.WithNamedRenderer("dapr", newMockRenderer(nil)),
+			engine.WithRendererFilter("drap", podFilter()),
+		)
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+
+		_, err = engine.New(
+			engine.WithNamedRenderer("dapr", newMockRenderer(nil)),
+			engine.WithRendererWeight("drap", 2),
+		)
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+
+		_, err = engine.New(
+			engine.WithNamedRenderer("dapr", newMockRenderer(nil)),
+			engine.WithRendererTimeout("drap", time.Minute),
+		)
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+	})
+
+	t.Run("should reject nil named renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithNamedRenderer("dapr", nil))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
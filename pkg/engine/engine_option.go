@@ -1,35 +1,136 @@
 package engine
 
 import (
+	"errors"
 	"maps"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
 )
 
+// DuplicatePolicy defines how the engine handles objects rendered more than once, i.e. objects
+// with the same GroupVersionKind, namespace and name, such as a ServiceAccount shipped by
+// several charts.
+type DuplicatePolicy string
+
+const (
+	// DuplicatePolicyAllow keeps all the objects, including duplicates.
+	DuplicatePolicyAllow DuplicatePolicy = "Allow"
+
+	// DuplicatePolicyKeepFirst keeps the first rendered occurrence of each object.
+	DuplicatePolicyKeepFirst DuplicatePolicy = "KeepFirst"
+
+	// DuplicatePolicyKeepLast keeps the last rendered occurrence of each object, so later
+	// renderers override earlier ones.
+	DuplicatePolicyKeepLast DuplicatePolicy = "KeepLast"
+
+	// DuplicatePolicyPrefer keeps the occurrence of each object rendered by the renderer coming
+	// first among the preferred ones, see WithPreferredRenderers, or the first occurrence if none
+	// of them rendered it.
+	DuplicatePolicyPrefer DuplicatePolicy = "Prefer"
+
+	// DuplicatePolicyMerge deep merges the occurrences of each object in rendered order, so later
+	// renderers override the conflicting fields of earlier ones.
+	DuplicatePolicyMerge DuplicatePolicy = "Merge"
+
+	// DuplicatePolicyFail fails the rendering with a *ConflictError reporting all the objects
+	// rendered more than once and their renderers. It matches ErrDuplicateObject.
+	DuplicatePolicyFail DuplicatePolicy = "Fail"
+)
+
+// FailurePolicy defines how the engine handles renderers failing during a render.
+type FailurePolicy string
+
+const (
+	// FailurePolicyFailFast fails the render on the first renderer error, discarding all objects.
+	FailurePolicyFailFast FailurePolicy = "FailFast"
+
+	// FailurePolicyContinueOnError runs all the renderers and processes the objects of the
+	// successful ones, returning them together with a *RenderError reporting the failed ones.
+	FailurePolicyContinueOnError FailurePolicy = "ContinueOnError"
+)
+
+var (
+	// ErrInvalidFailurePolicy is returned by New for unknown failure policies.
+	ErrInvalidFailurePolicy = errors.New("invalid failure policy")
+
+	// ErrInvalidDuplicatePolicy is returned by New for unknown duplicate policies.
+	ErrInvalidDuplicatePolicy = errors.New("invalid duplicate policy")
+
+	// ErrInvalidTimeout is returned by New for negative timeouts.
+	ErrInvalidTimeout = errors.New("invalid timeout")
+
+	// ErrRendererTimeout is returned, wrapped with the renderer name, for renderers exceeding their
+	// timeout or the render timeout.
+	ErrRendererTimeout = errors.New("renderer timed out")
+
+	// ErrInvalidRetryPolicy is returned by New for retry policies with negative values.
+	ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+
+	// ErrInvalidConcurrency is returned by New for negative concurrency limits or non-positive
+	// renderer weights.
+	ErrInvalidConcurrency = errors.New("invalid concurrency")
+)
+
 // RenderOptions represents the processing options for rendering.
 type RenderOptions struct {
 	// Filters are render-time filters applied only to this specific Render() call.
 	// These are merged with (appended to) engine-level filters.
 	Filters []types.Filter
 
+	// CollectionFilters are render-time collection filters applied only to this specific Render() call.
+	// These are merged with (appended to) engine-level collection filters.
+	CollectionFilters []types.CollectionFilter
+
 	// Transformers are render-time transformers applied only to this specific Render() call.
 	// These are merged with (appended to) engine-level transformers.
 	Transformers []types.Transformer
 
+	// CollectionTransformers are render-time collection transformers applied only to this specific
+	// Render() call. These are merged with (appended to) engine-level collection transformers.
+	CollectionTransformers []types.CollectionTransformer
+
 	// Values are render-time values passed to all renderers during this specific Render() call.
 	// These values are deep merged with Source-level values, with render-time values taking precedence.
 	Values map[string]any
+
+	// RendererValues are render-time values passed only to the renderers with the given name
+	// during this specific Render() call. They are deep merged over Values, taking precedence.
+	RendererValues map[string]map[string]any
+
+	// Timeout bounds the duration of this specific Render() call, overriding the engine-level
+	// timeout. Zero means the engine-level timeout is used.
+	Timeout time.Duration
 }
 
 // ApplyTo implements the Option interface for RenderOptions.
 func (opts RenderOptions) ApplyTo(target *RenderOptions) {
 	target.Filters = append(target.Filters, opts.Filters...)
+	target.CollectionFilters = append(target.CollectionFilters, opts.CollectionFilters...)
 	target.Transformers = append(target.Transformers, opts.Transformers...)
+	target.CollectionTransformers = append(target.CollectionTransformers, opts.CollectionTransformers...)
 
 	if opts.Values != nil {
 		target.Values = maps.Clone(opts.Values)
 	}
+
+	for name, values := range opts.RendererValues {
+		if target.RendererValues == nil {
+			target.RendererValues = make(map[string]map[string]any, len(opts.RendererValues))
+		}
+
+		target.RendererValues[name] = maps.Clone(values)
+	}
+
+	if opts.Timeout != 0 {
+		target.Timeout = opts.Timeout
+	}
 }
 
 // Options represents the processing options for the engine.
@@ -37,31 +138,210 @@ type Options struct {
 	// Filters are engine-level filters applied to all renders.
 	Filters []types.Filter
 
+	// CollectionFilters are engine-level filters deciding on each object given the whole
+	// rendered set, applied to all renders after Filters.
+	CollectionFilters []types.CollectionFilter
+
 	// Transformers are engine-level transformers applied to all renders.
 	Transformers []types.Transformer
 
+	// CollectionTransformers are engine-level transformers processing the whole rendered set,
+	// applied to all renders after Transformers.
+	CollectionTransformers []types.CollectionTransformer
+
 	// Values are values passed to renderers (used internally during rendering).
 	Values map[string]any
 
 	// Renderers are the manifest sources to process (e.g., Helm, Kustomize, YAML).
 	Renderers []types.Renderer
 
+	// RendererFilters are engine-level filters applied only to the output of the renderers with
+	// the given name, before it is aggregated with the output of the others.
+	RendererFilters map[string][]types.Filter
+
+	// RendererTransformers are engine-level transformers applied only to the output of the
+	// renderers with the given name, after RendererFilters.
+	RendererTransformers map[string][]types.Transformer
+
 	// Parallel enables parallel execution of renderers.
+	// Applied as a struct option it can only enable it; use WithParallel(false) to disable it.
 	Parallel bool
+
+	// MaxConcurrency bounds the total weight of the renderers running concurrently in parallel
+	// mode. Zero means unbounded.
+	MaxConcurrency int
+
+	// Weights are the weights of renderers by name, counted against MaxConcurrency.
+	// Renderers without a weight have weight 1.
+	Weights map[string]int
+
+	// ExpandLists enables unwrapping of List objects into their items before
+	// engine-level filters and transformers are applied.
+	// Applied as a struct option it can only enable it; use WithExpandLists(false) to disable it.
+	ExpandLists bool
+
+	// Duplicates is the policy for objects rendered more than once.
+	Duplicates DuplicatePolicy
+
+	// Preferred are the renderer names in order of precedence for DuplicatePolicyPrefer.
+	Preferred []string
+
+	// Failures is the policy for failing renderers.
+	Failures FailurePolicy
+
+	// Timeout bounds the duration of every Render() call. Zero means no timeout.
+	Timeout time.Duration
+
+	// Timeouts bound the duration of the renderers by name.
+	Timeouts map[string]time.Duration
+
+	// Retry is the policy for retrying failed renderer runs. Nil disables retries.
+	Retry *RetryPolicy
+
+	// Hooks are the callbacks invoked at the stages of a render.
+	Hooks []Hooks
+
+	// DryRunConfig is the REST configuration of the cluster validating the rendered objects with
+	// a server-side dry-run. Nil disables the validation unless DryRun is set.
+	DryRunConfig *rest.Config
+
+	// DryRun are the clients of the dry-run validation, taking precedence over DryRunConfig.
+	DryRun *DryRun
+
+	// WatchPaths are the glob patterns of the local files and directories watched by Watch,
+	// replacing the files read by the renderers. Empty means watch the files of the renderers.
+	WatchPaths []string
+
+	// WatchInterval is the interval between two scans of the watched files by Watch.
+	WatchInterval time.Duration
 }
 
 // ApplyTo implements the Option interface for Options.
 func (opts Options) ApplyTo(target *Options) {
 	target.Renderers = append(target.Renderers, opts.Renderers...)
+
+	for name, filters := range opts.RendererFilters {
+		if target.RendererFilters == nil {
+			target.RendererFilters = make(map[string][]types.Filter)
+		}
+
+		target.RendererFilters[name] = append(target.RendererFilters[name], filters...)
+	}
+
+	for name, transformers := range opts.RendererTransformers {
+		if target.RendererTransformers == nil {
+			target.RendererTransformers = make(map[string][]types.Transformer)
+		}
+
+		target.RendererTransformers[name] = append(target.RendererTransformers[name], transformers...)
+	}
 	target.Filters = append(target.Filters, opts.Filters...)
+	target.CollectionFilters = append(target.CollectionFilters, opts.CollectionFilters...)
 	target.Transformers = append(target.Transformers, opts.Transformers...)
-	target.Parallel = opts.Parallel
+	target.CollectionTransformers = append(target.CollectionTransformers, opts.CollectionTransformers...)
+
+	// Only set flags are applied, so that partial options do not reset the flags of a base engine
+	if opts.Parallel {
+		target.Parallel = true
+	}
+
+	if opts.ExpandLists {
+		target.ExpandLists = true
+	}
+
+	if opts.MaxConcurrency != 0 {
+		target.MaxConcurrency = opts.MaxConcurrency
+	}
+
+	if len(opts.Weights) > 0 {
+		if target.Weights == nil {
+			target.Weights = make(map[string]int, len(opts.Weights))
+		}
+
+		maps.Copy(target.Weights, opts.Weights)
+	}
+
+	if opts.Duplicates != "" {
+		target.Duplicates = opts.Duplicates
+	}
+
+	target.Preferred = append(target.Preferred, opts.Preferred...)
+
+	if opts.Failures != "" {
+		target.Failures = opts.Failures
+	}
+
+	if opts.Timeout != 0 {
+		target.Timeout = opts.Timeout
+	}
+
+	if opts.Retry != nil {
+		target.Retry = opts.Retry
+	}
+
+	target.Hooks = append(target.Hooks, opts.Hooks...)
+
+	if opts.DryRunConfig != nil {
+		target.DryRunConfig = opts.DryRunConfig
+	}
+
+	if opts.DryRun != nil {
+		target.DryRun = opts.DryRun
+	}
+
+	target.WatchPaths = append(target.WatchPaths, opts.WatchPaths...)
+
+	if opts.WatchInterval != 0 {
+		target.WatchInterval = opts.WatchInterval
+	}
+
+	if len(opts.Timeouts) > 0 {
+		if target.Timeouts == nil {
+			target.Timeouts = make(map[string]time.Duration, len(opts.Timeouts))
+		}
+
+		maps.Copy(target.Timeouts, opts.Timeouts)
+	}
 
 	if opts.Values != nil {
 		target.Values = maps.Clone(opts.Values)
 	}
 }
 
+// clone returns a copy of the options sharing no slices or maps with them, so options applied to
+// the copy don't affect the original. Renderers, filters and transformers are shared.
+func (opts Options) clone() Options {
+	result := opts
+
+	result.Filters = slices.Clone(opts.Filters)
+	result.CollectionFilters = slices.Clone(opts.CollectionFilters)
+	result.Transformers = slices.Clone(opts.Transformers)
+	result.CollectionTransformers = slices.Clone(opts.CollectionTransformers)
+	result.Values = maps.Clone(opts.Values)
+	result.Renderers = slices.Clone(opts.Renderers)
+	result.Weights = maps.Clone(opts.Weights)
+	result.Preferred = slices.Clone(opts.Preferred)
+	result.Timeouts = maps.Clone(opts.Timeouts)
+	result.Hooks = slices.Clone(opts.Hooks)
+	result.WatchPaths = slices.Clone(opts.WatchPaths)
+
+	if opts.RendererFilters != nil {
+		result.RendererFilters = make(map[string][]types.Filter, len(opts.RendererFilters))
+		for name, filters := range opts.RendererFilters {
+			result.RendererFilters[name] = slices.Clone(filters)
+		}
+	}
+
+	if opts.RendererTransformers != nil {
+		result.RendererTransformers = make(map[string][]types.Transformer, len(opts.RendererTransformers))
+		for name, transformers := range opts.RendererTransformers {
+			result.RendererTransformers[name] = slices.Clone(transformers)
+		}
+	}
+
+	return result
+}
+
 // Option is a generic option for Options.
 type Option = util.Option[Options]
 
@@ -76,6 +356,49 @@ func WithRenderer(r types.Renderer) Option {
 	})
 }
 
+// WithNamedRenderer adds a configured renderer to the engine under the given name, e.g.
+// "helm-dapr", instead of its default name, e.g. "helm". The name identifies the renderer in
+// errors, render results and metrics, and can be targeted by the renderer-scoped options such as
+// WithRendererFilter, WithRendererTransformer, WithRendererWeight and WithRendererTimeout.
+// Names must be unique among the renderers of the engine.
+// Can only be used during engine creation.
+func WithNamedRenderer(name string, r types.Renderer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if r == nil {
+			o.Renderers = append(o.Renderers, nil)
+
+			return
+		}
+
+		o.Renderers = append(o.Renderers, &namedRenderer{Renderer: r, name: name})
+	})
+}
+
+// WithRendererFilter adds an engine-level filter applied only to the output of the renderers with
+// the given name, before it is aggregated with the output of the other renderers. This targets a
+// single source, e.g. a renderer added with WithNamedRenderer, without reconfiguring the renderer.
+func WithRendererFilter(name string, f types.Filter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.RendererFilters == nil {
+			o.RendererFilters = make(map[string][]types.Filter)
+		}
+
+		o.RendererFilters[name] = append(o.RendererFilters[name], f)
+	})
+}
+
+// WithRendererTransformer adds an engine-level transformer applied only to the output of the
+// renderers with the given name, after the filters added with WithRendererFilter.
+func WithRendererTransformer(name string, t types.Transformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.RendererTransformers == nil {
+			o.RendererTransformers = make(map[string][]types.Transformer)
+		}
+
+		o.RendererTransformers[name] = append(o.RendererTransformers[name], t)
+	})
+}
+
 // WithFilter adds an engine-level filter function to the processing chain.
 // Engine-level filters are applied to aggregated results from all renderers on every Render() call.
 // For renderer-specific filtering, use the renderer's WithFilter option (e.g., helm.WithFilter).
@@ -86,6 +409,17 @@ func WithFilter(f types.Filter) Option {
 	})
 }
 
+// WithCollectionFilter adds an engine-level collection filter to the processing chain.
+// Collection filters decide on each object given the whole set of objects aggregated from all
+// renderers, e.g. to keep Services only if a matching Deployment exists. They are applied after
+// the per-object filters, on the objects those kept.
+// For one-time filtering on a single Render() call, use WithRenderCollectionFilter.
+func WithCollectionFilter(f types.CollectionFilter) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.CollectionFilters = append(o.CollectionFilters, f)
+	})
+}
+
 // WithTransformer adds an engine-level transformer function to the processing chain.
 // Engine-level transformers are applied to aggregated results from all renderers on every Render() call.
 // For renderer-specific transformation, use the renderer's WithTransformer option (e.g., helm.WithTransformer).
@@ -96,6 +430,17 @@ func WithTransformer(t types.Transformer) Option {
 	})
 }
 
+// WithCollectionTransformer adds an engine-level collection transformer to the processing chain.
+// Collection transformers process the whole set of objects aggregated from all renderers, e.g. to
+// generate objects derived from the others or to reorder them. They are applied after the
+// per-object transformers, each one on the set returned by the previous one.
+// For one-time transformation on a single Render() call, use WithRenderCollectionTransformer.
+func WithCollectionTransformer(t types.CollectionTransformer) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.CollectionTransformers = append(o.CollectionTransformers, t)
+	})
+}
+
 // WithRenderFilter adds a render-time filter function for a single Render() call.
 // Render-time filters are merged with (appended to) engine-level filters.
 // Use this for one-off filtering that doesn't apply to all renders.
@@ -105,6 +450,14 @@ func WithRenderFilter(f types.Filter) RenderOption {
 	})
 }
 
+// WithRenderCollectionFilter adds a render-time collection filter for a single Render() call.
+// Render-time collection filters are merged with (appended to) engine-level collection filters.
+func WithRenderCollectionFilter(f types.CollectionFilter) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.CollectionFilters = append(o.CollectionFilters, f)
+	})
+}
+
 // WithRenderTransformer adds a render-time transformer function for a single Render() call.
 // Render-time transformers are merged with (appended to) engine-level transformers.
 // Use this for one-off transformation that doesn't apply to all renders.
@@ -114,6 +467,14 @@ func WithRenderTransformer(t types.Transformer) RenderOption {
 	})
 }
 
+// WithRenderCollectionTransformer adds a render-time collection transformer for a single Render() call.
+// Render-time collection transformers are merged with (appended to) engine-level collection transformers.
+func WithRenderCollectionTransformer(t types.CollectionTransformer) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.CollectionTransformers = append(o.CollectionTransformers, t)
+	})
+}
+
 // WithParallel enables or disables parallel execution of renderers.
 // When enabled, all renderers execute concurrently using goroutines.
 // When disabled (default), renderers execute sequentially.
@@ -124,6 +485,164 @@ func WithParallel(enabled bool) Option {
 	})
 }
 
+// WithMaxConcurrency bounds the number of renderers running concurrently in parallel mode, e.g.
+// so that many Helm renderers don't pull charts all at once and exhaust network connections or
+// file descriptors. Renderers count against the limit with their weight, see WithRendererWeight.
+// Has no effect unless parallel execution is enabled with WithParallel.
+// Default: 0 (unbounded).
+func WithMaxConcurrency(n int) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.MaxConcurrency = n
+	})
+}
+
+// WithRendererWeight sets the weight counted against the WithMaxConcurrency limit by the
+// renderers with the given name, e.g. a higher weight for "helm" renderers pulling remote charts
+// than for "yaml" renderers reading local files. Weights larger than the limit are capped to it,
+// so such renderers run alone.
+// Default: 1.
+func WithRendererWeight(name string, weight int) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Weights == nil {
+			o.Weights = make(map[string]int)
+		}
+
+		o.Weights[name] = weight
+	})
+}
+
+// WithExpandLists enables or disables unwrapping of List objects (kind List or any *List kind)
+// into their individual items. When enabled, the items are expanded after all renderers have
+// run and before engine-level filters and transformers are applied, so they operate on the
+// contained objects rather than on the wrapper.
+// Default: false (disabled).
+func WithExpandLists(enabled bool) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.ExpandLists = enabled
+	})
+}
+
+// WithDuplicatePolicy sets how objects with the same GroupVersionKind, namespace and name are
+// handled. Duplicates are detected across all renderers after lists are expanded and before
+// engine-level filters and transformers are applied.
+// Default: DuplicatePolicyAllow (duplicates are kept).
+func WithDuplicatePolicy(policy DuplicatePolicy) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Duplicates = policy
+	})
+}
+
+// WithPreferredRenderers sets the renderer names, in order of precedence, deciding which occurrence
+// of an object rendered more than once is kept by DuplicatePolicyPrefer, e.g. a platform chart
+// owning the shared ServiceAccounts over the application charts also shipping them. Names of no
+// registered renderer fail New with ErrUnknownRenderer.
+func WithPreferredRenderers(names ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Preferred = append(o.Preferred, names...)
+	})
+}
+
+// WithFailurePolicy sets how renderer failures are handled. With FailurePolicyContinueOnError a
+// failing renderer doesn't discard the objects of the others: Render returns the processed objects
+// of the successful renderers together with a *RenderError attributing each failure to its
+// renderer. Failures of the engine-level filters and transformers still fail the render.
+// Default: FailurePolicyFailFast.
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Failures = policy
+	})
+}
+
+// WithTimeout bounds the duration of every Render() call, so slow renderers, e.g. pulling charts
+// from OCI registries or fetching remote Kustomize bases, can't hang the pipeline. Renderers still
+// running when the timeout expires fail with ErrRendererTimeout, wrapped with their name.
+// For a single Render() call, use WithRenderTimeout.
+// Default: 0 (no timeout).
+func WithTimeout(d time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Timeout = d
+	})
+}
+
+// WithRendererTimeout bounds the duration of each run of the renderers with the given name.
+// Renderers exceeding it fail with ErrRendererTimeout, wrapped with their name. Renderers not
+// honoring the context cancellation are abandoned and their results discarded.
+// Default: no timeout.
+func WithRendererTimeout(name string, d time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		if o.Timeouts == nil {
+			o.Timeouts = make(map[string]time.Duration)
+		}
+
+		o.Timeouts[name] = d
+	})
+}
+
+// WithRenderTimeout bounds the duration of a single Render() call, overriding the engine-level
+// timeout set with WithTimeout.
+func WithRenderTimeout(d time.Duration) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		o.Timeout = d
+	})
+}
+
+// WithRetryPolicy enables retrying failed renderer runs with exponential backoff. Each attempt is
+// bounded by the renderer timeout, see WithRendererTimeout, while the render timeout bounds all of
+// them. Retries are reported to the RetryMetric of the metrics in the context, if any.
+// Default: no retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Retry = &policy
+	})
+}
+
+// WithHooks registers callbacks invoked at the stages of every render, so embedders can report
+// progress, audit the rendered objects or record custom metrics without wrapping the engine.
+// Multiple Hooks are invoked in registration order.
+func WithHooks(h Hooks) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.Hooks = append(o.Hooks, h)
+	})
+}
+
+// WithDryRunValidation enables the validation of the rendered objects against a live cluster. After
+// the engine-level pipeline, each object is sent to the API server with a server-side dry-run
+// apply, so admission and validation errors surface before anything is applied. The objects
+// rejected by the API server fail the render with a *ValidationError reporting the error of each.
+// Default: no validation.
+func WithDryRunValidation(cfg *rest.Config) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DryRunConfig = cfg
+	})
+}
+
+// WithDryRunClient enables the dry-run validation like WithDryRunValidation, using the given client
+// and REST mapper instead of creating them from a REST configuration.
+func WithDryRunClient(client dynamic.Interface, mapper meta.RESTMapper) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.DryRun = &DryRun{Client: client, Mapper: mapper}
+	})
+}
+
+// WithWatchPaths adds the glob patterns of the local files and directories watched by Watch.
+// By default Watch derives the watched files from the sources of the renderers, e.g. their
+// kustomization directories, YAML manifests and template directories; when set, the patterns
+// replace them, e.g. to also watch files referenced from outside those directories.
+// Matched directories are watched recursively.
+func WithWatchPaths(patterns ...string) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.WatchPaths = append(o.WatchPaths, patterns...)
+	})
+}
+
+// WithWatchInterval sets the interval between two scans of the watched paths by Watch.
+// Default: 1s.
+func WithWatchInterval(d time.Duration) Option {
+	return util.FunctionalOption[Options](func(o *Options) {
+		o.WatchInterval = d
+	})
+}
+
 // WithValues adds render-time values for a single Render() call.
 // These values are passed to all renderers and deep merged with Source-level values,
 // with render-time values taking precedence for conflicting keys.
@@ -134,3 +653,18 @@ func WithValues(values map[string]any) RenderOption {
 		o.Values = values
 	})
 }
+
+// WithValuesFor adds render-time values for a single Render() call passed only to the renderers
+// with the given name, e.g. a renderer added with WithNamedRenderer, so different charts can get
+// different overrides in the same call. They are deep merged over the values set with WithValues,
+// taking precedence for conflicting keys. Names of no registered renderer fail the render with
+// ErrUnknownRenderer.
+func WithValuesFor(name string, values map[string]any) RenderOption {
+	return util.FunctionalOption[RenderOptions](func(o *RenderOptions) {
+		if o.RendererValues == nil {
+			o.RendererValues = make(map[string]map[string]any)
+		}
+
+		o.RendererValues[name] = values
+	})
+}
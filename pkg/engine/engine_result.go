@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RenderResult is the detailed outcome of a RenderResult call.
+type RenderResult struct {
+	// Objects are the final objects, as returned by Render.
+	Objects []unstructured.Unstructured
+
+	// Renderers are the results of the renderers, in registration order. Renderers not run
+	// because an earlier one failed are not included.
+	Renderers []RendererResult
+
+	// Duration is the time spent rendering and processing the objects.
+	Duration time.Duration
+
+	// Stats are the object counts of the processing stages.
+	Stats RenderStats
+
+	// Warnings report conditions that did not fail the render but may be unexpected, e.g.
+	// renderers returning no objects or duplicate objects dropped by the duplicate policy.
+	Warnings []string
+}
+
+// RendererResult is the outcome of a single renderer.
+type RendererResult struct {
+	// Name is the renderer name.
+	Name string
+
+	// Objects are the objects returned by the renderer, after the filters and transformers scoped
+	// to it and before List expansion and the engine-level filters and transformers.
+	Objects []unstructured.Unstructured
+
+	// Duration is the time spent in the renderer, retries included.
+	Duration time.Duration
+
+	// Retries is the number of times the renderer was retried, see WithRetryPolicy.
+	Retries int
+
+	// Err is the error returned by the renderer, wrapped with the renderer name, if any.
+	Err error
+}
+
+// RenderStats are the object counts of the processing stages of a render.
+type RenderStats struct {
+	// Rendered is the number of objects returned by the renderers, after List expansion.
+	Rendered int
+
+	// Duplicates is the number of objects dropped by the duplicate policy.
+	Duplicates int
+
+	// Filtered is the number of objects removed by the filters and collection filters.
+	Filtered int
+}
+
+// RendererError is the failure of a single renderer within a RenderError.
+type RendererError struct {
+	// Index is the position of the renderer in registration order.
+	Index int
+
+	// Name is the renderer name.
+	Name string
+
+	// Err is the error returned by the renderer, wrapped with the renderer name.
+	Err error
+}
+
+// RenderError is returned by Render and RenderResult with FailurePolicyContinueOnError when some
+// renderers failed, together with the objects of the successful ones.
+type RenderError struct {
+	// Renderers are the failed renderers, in registration order.
+	Renderers []RendererError
+}
+
+func (e *RenderError) Error() string {
+	msgs := make([]string, 0, len(e.Renderers))
+	for _, r := range e.Renderers {
+		msgs = append(msgs, r.Err.Error())
+	}
+
+	return fmt.Sprintf("%d renderer(s) failed: %s", len(e.Renderers), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed renderers, so errors.Is and errors.As match any of them.
+func (e *RenderError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Renderers))
+	for _, r := range e.Renderers {
+		errs = append(errs, r.Err)
+	}
+
+	return errs
+}
+
+// newRenderError returns a RenderError for the failed renderers, or nil if none failed.
+func newRenderError(results []RendererResult) *RenderError {
+	var failed []RendererError
+
+	for i, r := range results {
+		if r.Err != nil {
+			failed = append(failed, RendererError{
+				Index: i,
+				Name:  r.Name,
+				Err:   r.Err,
+			})
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &RenderError{Renderers: failed}
+}
@@ -0,0 +1,121 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderResult(t *testing.T) {
+
+	t.Run("should report the objects of each renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pods := newMockRenderer([]unstructured.Unstructured{makePod("pod1"), makePod("pod2")})
+		pods.name = "pods"
+
+		services := newMockRenderer([]unstructured.Unstructured{makeService()})
+		services.name = "services"
+
+		e, err := engine.New(
+			engine.WithRenderer(pods),
+			engine.WithRenderer(services),
+			engine.WithFilter(podFilter()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(result.Objects).To(HaveLen(2))
+		g.Expect(result.Duration).To(BeNumerically(">", 0))
+		g.Expect(result.Warnings).To(BeEmpty())
+		g.Expect(result.Stats).To(Equal(engine.RenderStats{
+			Rendered: 3,
+			Filtered: 1,
+		}))
+
+		g.Expect(result.Renderers).To(HaveLen(2))
+		g.Expect(result.Renderers[0].Name).To(Equal("pods"))
+		g.Expect(result.Renderers[0].Objects).To(HaveLen(2))
+		g.Expect(result.Renderers[0].Err).ToNot(HaveOccurred())
+		g.Expect(result.Renderers[1].Name).To(Equal("services"))
+		g.Expect(result.Renderers[1].Objects).To(HaveLen(1))
+	})
+
+	t.Run("should warn about empty renderers and dropped duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		empty := newMockRenderer(nil)
+		empty.name = "empty"
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(empty),
+			engine.WithDuplicatePolicy(engine.DuplicatePolicyKeepLast),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(result.Objects).To(HaveLen(1))
+		g.Expect(result.Stats.Rendered).To(Equal(2))
+		g.Expect(result.Stats.Duplicates).To(Equal(1))
+		g.Expect(result.Warnings).To(ConsistOf(
+			ContainSubstring(`renderer "empty" returned no objects`),
+			ContainSubstring("duplicate object /v1, Kind=Pod pod1 dropped by policy KeepLast"),
+		))
+	})
+
+	t.Run("should report the failing renderer", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failing := &mockRenderer{
+			name: "failing",
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				return nil, errors.New("renderer failed")
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(failing),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod2")})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).To(MatchError(ContainSubstring("renderer failed")))
+
+		g.Expect(result).ToNot(BeNil())
+		g.Expect(result.Objects).To(BeNil())
+		g.Expect(result.Renderers).To(HaveLen(2))
+		g.Expect(result.Renderers[0].Err).ToNot(HaveOccurred())
+		g.Expect(result.Renderers[1].Name).To(Equal("failing"))
+		g.Expect(result.Renderers[1].Err).To(MatchError(ContainSubstring(`renderer "failing"`)))
+	})
+
+	t.Run("should report all renderers in parallel mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod2")})),
+			engine.WithParallel(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Renderers).To(HaveLen(2))
+		g.Expect(result.Objects).To(HaveLen(2))
+	})
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// RetryPolicy configures the retry of failed renderer runs, e.g. for flaky registries and
+// repositories.
+type RetryPolicy struct {
+	// Attempts is the maximum number of runs of a renderer, the first one included.
+	// Values below 2 disable retries.
+	Attempts int
+
+	// Backoff is the delay before the first retry, doubled before each following one.
+	Backoff time.Duration
+
+	// MaxBackoff caps the delay between retries. Zero means uncapped.
+	MaxBackoff time.Duration
+
+	// Retryable reports whether a renderer error is worth retrying. When nil, all errors are
+	// retried except context cancellation. Runs abandoned once their timeout expires are never
+	// retried, as they may still be executing.
+	Retryable func(err error) bool
+}
+
+// retryable reports whether a failed run should be retried.
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return !errors.Is(err, context.Canceled)
+}
+
+// delay returns the backoff before retrying the given failed attempt, starting at 1.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+
+	for i := 1; i < attempt && d > 0; i++ {
+		// Stop doubling before overflowing
+		if d > math.MaxInt64/2 {
+			d = math.MaxInt64
+
+			break
+		}
+
+		d *= 2
+
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 {
+		d = min(d, p.MaxBackoff)
+	}
+
+	return d
+}
+
+// wait waits for d, returning the context error if it's done first.
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,169 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/metrics"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/metrics/memory"
+
+	. "github.com/onsi/gomega"
+)
+
+// flakyRenderer returns a renderer failing with err the given number of times before succeeding.
+func flakyRenderer(failures int32, err error) (*mockRenderer, *atomic.Int32) {
+	calls := &atomic.Int32{}
+
+	return &mockRenderer{
+		name: "flaky",
+		processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+			if calls.Add(1) <= failures {
+				return nil, err
+			}
+
+			return []unstructured.Unstructured{makePod("pod1")}, nil
+		},
+	}, calls
+}
+
+func TestRetryPolicy(t *testing.T) {
+	errUnavailable := errors.New("registry unavailable")
+
+	t.Run("should retry failed renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, calls := flakyRenderer(2, errUnavailable)
+		retries := memory.NewRetryMetric()
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRetryPolicy(engine.RetryPolicy{
+				Attempts: 3,
+				Backoff:  time.Millisecond,
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx := metrics.WithMetrics(t.Context(), &metrics.Metrics{RetryMetric: retries})
+
+		result, err := e.RenderResult(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Objects).To(HaveLen(1))
+		g.Expect(result.Renderers[0].Retries).To(Equal(2))
+		g.Expect(calls.Load()).To(BeNumerically("==", 3))
+		g.Expect(retries.Summary()).To(HaveKeyWithValue("flaky", 2))
+	})
+
+	t.Run("should give up after the last attempt", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, calls := flakyRenderer(5, errUnavailable)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRetryPolicy(engine.RetryPolicy{Attempts: 2}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(errUnavailable))
+		g.Expect(calls.Load()).To(BeNumerically("==", 2))
+	})
+
+	t.Run("should only retry retryable errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		errInvalid := errors.New("invalid chart")
+		renderer, calls := flakyRenderer(5, errInvalid)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRetryPolicy(engine.RetryPolicy{
+				Attempts: 3,
+				Retryable: func(err error) bool {
+					return errors.Is(err, errUnavailable)
+				},
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(errInvalid))
+		g.Expect(calls.Load()).To(BeNumerically("==", 1))
+	})
+
+	t.Run("should stop waiting when the render times out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, calls := flakyRenderer(5, errUnavailable)
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRetryPolicy(engine.RetryPolicy{
+				Attempts: 3,
+				Backoff:  time.Hour,
+			}),
+			engine.WithTimeout(20*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(errUnavailable))
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+		g.Expect(calls.Load()).To(BeNumerically("==", 1))
+	})
+
+	t.Run("should not retry runs abandoned after the renderer timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls atomic.Int32
+		var running atomic.Int32
+		var overlapped atomic.Bool
+
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		// Ignores the context cancellation, so the timed out run keeps executing
+		renderer := &mockRenderer{
+			name: "stuck",
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				calls.Add(1)
+
+				if running.Add(1) > 1 {
+					overlapped.Store(true)
+				}
+				defer running.Add(-1)
+
+				<-release
+
+				return []unstructured.Unstructured{}, nil
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithRendererTimeout("stuck", 20*time.Millisecond),
+			engine.WithRetryPolicy(engine.RetryPolicy{Attempts: 3}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+		g.Expect(result.Renderers[0].Retries).To(BeZero())
+		g.Expect(calls.Load()).To(BeNumerically("==", 1))
+		g.Expect(overlapped.Load()).To(BeFalse())
+	})
+
+	t.Run("should reject invalid policies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithRetryPolicy(engine.RetryPolicy{Attempts: 3, Backoff: -time.Second}))
+		g.Expect(err).To(MatchError(engine.ErrInvalidRetryPolicy))
+	})
+}
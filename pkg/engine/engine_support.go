@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/gotemplate"
@@ -8,8 +9,12 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/mem"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 )
 
+// ErrSourceNil is returned by FromSources for nil sources.
+var ErrSourceNil = errors.New("source cannot be nil")
+
 // Helm creates an Engine configured with a single Helm renderer.
 // This is a convenience function for simple Helm-only rendering scenarios.
 //
@@ -107,3 +112,39 @@ func Mem(source mem.Source, opts ...mem.RendererOption) (*Engine, error) {
 
 	return New(WithRenderer(renderer))
 }
+
+// FromSources creates an Engine with a renderer for each of the given sources, in order, and the
+// given engine options. Sources are the Source values of the renderer packages, e.g. helm.Source,
+// kustomize.Source, yaml.Source or git.Source, all implementing types.Source. Renderers are created
+// with their default options; to configure them, e.g. to enable caching, or for renderers requiring
+// options, such as the cluster renderer, create them and use New with WithRenderer.
+//
+// Example:
+//
+//	e, _ := engine.FromSources(
+//	    []types.Source{
+//	        helm.Source{Chart: "oci://registry/chart:1.0.0", ReleaseName: "my-release"},
+//	        kustomize.Source{Path: "/path/to/kustomization"},
+//	        yaml.Source{FS: os.DirFS("/path/to/manifests"), Path: "*.yaml"},
+//	    },
+//	    engine.WithParallel(true),
+//	)
+//	objects, _ := e.Render(ctx)
+func FromSources(sources []types.Source, opts ...Option) (*Engine, error) {
+	options := make([]Option, 0, len(sources)+len(opts))
+
+	for i, source := range sources {
+		if source == nil {
+			return nil, fmt.Errorf("%w: position %d", ErrSourceNil, i)
+		}
+
+		renderer, err := source.NewRenderer()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create renderer for %T at position %d: %w", source, i, err)
+		}
+
+		options = append(options, WithRenderer(renderer))
+	}
+
+	return New(append(options, opts...)...)
+}
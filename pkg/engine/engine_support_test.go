@@ -9,11 +9,16 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/git"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/gotemplate"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/json"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/jsonnet"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/mem"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/remote"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 
 	. "github.com/onsi/gomega"
 )
@@ -169,6 +174,77 @@ func TestMem(t *testing.T) {
 	})
 }
 
+func TestFromSources(t *testing.T) {
+	t.Run("should create engine with a renderer per source", func(t *testing.T) {
+		g := NewWithT(t)
+		e, err := engine.FromSources(
+			[]types.Source{
+				mem.Source{Objects: []unstructured.Unstructured{makePod("pod1")}},
+				yaml.Source{Data: []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: svc1\n")},
+				&mem.Source{Objects: []unstructured.Unstructured{makePod("pod2")}},
+			},
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(e).ShouldNot(BeNil())
+
+		result, err := e.RenderResult(t.Context())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Renderers).To(HaveLen(3))
+		g.Expect(result.Renderers[0].Name).To(Equal("mem"))
+		g.Expect(result.Renderers[1].Name).To(Equal("yaml"))
+		g.Expect(result.Renderers[2].Name).To(Equal("mem"))
+
+		g.Expect(result.Objects).To(HaveLen(3))
+		g.Expect(result.Objects[0].GetName()).To(Equal("pod1"))
+		g.Expect(result.Objects[1].GetName()).To(Equal("svc1"))
+		g.Expect(result.Objects[2].GetName()).To(Equal("pod2"))
+
+		for _, obj := range result.Objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("env", "prod"))
+		}
+	})
+
+	t.Run("should accept the sources of the renderers", func(t *testing.T) {
+		g := NewWithT(t)
+		e, err := engine.FromSources([]types.Source{
+			helm.Source{Chart: "oci://registry-1.docker.io/bitnamicharts/nginx", ReleaseName: "test-release"},
+			kustomize.Source{Path: "/some/path"},
+			yaml.Source{FS: os.DirFS("."), Path: "*.go"},
+			gotemplate.Source{FS: os.DirFS("."), Path: "*.go"},
+			mem.Source{},
+			json.Source{FS: os.DirFS("."), Path: "*.json"},
+			git.Source{Repository: "https://github.com/org/repo", Path: "deploy/*.yaml"},
+			remote.Source{URL: "https://example.com/manifests.yaml"},
+			jsonnet.Source{Path: "main.jsonnet"},
+		})
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(e).ShouldNot(BeNil())
+	})
+
+	t.Run("should return error for invalid source", func(t *testing.T) {
+		g := NewWithT(t)
+		e, err := engine.FromSources([]types.Source{
+			mem.Source{},
+			kustomize.Source{}, // Missing path
+		})
+
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("kustomize.Source at position 1"))
+		g.Expect(e).Should(BeNil())
+	})
+
+	t.Run("should return error for nil source", func(t *testing.T) {
+		g := NewWithT(t)
+		e, err := engine.FromSources([]types.Source{mem.Source{}, nil})
+
+		g.Expect(err).Should(MatchError(engine.ErrSourceNil))
+		g.Expect(e).Should(BeNil())
+	})
+}
+
 func benchmarkEngineRender(b *testing.B, parallel bool) {
 	b.Helper()
 	ctx := b.Context()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"maps"
+	"slices"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -11,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/mem"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
@@ -114,6 +116,105 @@ func TestEngineRender(t *testing.T) {
 		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
 	})
 
+	t.Run("should expand lists before engine-level filters", func(t *testing.T) {
+		g := NewWithT(t)
+		pod := makePod("pod1")
+		svc := makeService()
+
+		list := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "List",
+				"items":      []any{pod.Object, svc.Object},
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{list})),
+			engine.WithFilter(podFilter()),
+			engine.WithExpandLists(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("pod1"))
+	})
+
+	t.Run("should apply collection filters after filters", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := newMockRenderer([]unstructured.Unstructured{
+			makePod("pod1"),
+			makePodWithNamespace("pod1", systemNamespace),
+			makePod("pod2"),
+			makeService(),
+		})
+
+		// keep pods sharing their name with another pod
+		samePodName := filter.Any(func(obj unstructured.Unstructured) types.Filter {
+			return func(_ context.Context, other unstructured.Unstructured) (bool, error) {
+				return other.GetName() == obj.GetName(), nil
+			}
+		})
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithFilter(podFilter()),
+			engine.WithCollectionFilter(samePodName),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("pod1"))
+		g.Expect(objects[1].GetName()).To(Equal("pod1"))
+
+		objects, err = e.Render(t.Context(), engine.WithRenderCollectionFilter(
+			func(_ context.Context, obj unstructured.Unstructured, _ []unstructured.Unstructured) (bool, error) {
+				return obj.GetNamespace() == systemNamespace, nil
+			},
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetNamespace()).To(Equal(systemNamespace))
+	})
+
+	t.Run("should apply collection transformers after transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer := newMockRenderer([]unstructured.Unstructured{makePod("pod1"), makePod("pod2")})
+
+		reverse := func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+			result := slices.Clone(objects)
+			slices.Reverse(result)
+
+			return result, nil
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithTransformer(addLabels(map[string]string{"managed-by": "engine"})),
+			engine.WithCollectionTransformer(reverse),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("pod2"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("managed-by", "engine"))
+
+		objects, err = e.Render(t.Context(), engine.WithRenderCollectionTransformer(
+			func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+				return objects[:1], nil
+			},
+		))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("pod2"))
+	})
+
 	t.Run("should apply engine-level transformer", func(t *testing.T) {
 		g := NewWithT(t)
 		renderer := newMockRenderer([]unstructured.Unstructured{makePod("pod1")})
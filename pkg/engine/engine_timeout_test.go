@@ -0,0 +1,127 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+// blockingRenderer returns a renderer waiting for its context to be done.
+func blockingRenderer(name string) *mockRenderer {
+	return &mockRenderer{
+		name: name,
+		processFunc: func(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		},
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+
+	t.Run("should fail renderers exceeding the render timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRenderer(blockingRenderer("slow")),
+			engine.WithTimeout(time.Hour),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithRenderTimeout(20*time.Millisecond))
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+		g.Expect(err.Error()).To(ContainSubstring(`renderer "slow"`))
+	})
+
+	t.Run("should apply the engine-level timeout", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(blockingRenderer("slow")),
+			engine.WithTimeout(20*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+	})
+
+	t.Run("should bound renderers by name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(blockingRenderer("slow")),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRendererTimeout("slow", 20*time.Millisecond),
+			engine.WithFailurePolicy(engine.FailurePolicyContinueOnError),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+		g.Expect(objects).To(HaveLen(1))
+
+		var renderErr *engine.RenderError
+		g.Expect(err).To(BeAssignableToTypeOf(renderErr))
+	})
+
+	t.Run("should abandon renderers ignoring cancellation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+
+		stuck := &mockRenderer{
+			name: "stuck",
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				<-release
+
+				return nil, nil
+			},
+		}
+
+		e, err := engine.New(
+			engine.WithRenderer(stuck),
+			engine.WithRendererTimeout("stuck", 20*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context())
+		g.Expect(err).To(MatchError(engine.ErrRendererTimeout))
+		g.Expect(err.Error()).To(ContainSubstring(`renderer "stuck"`))
+	})
+
+	t.Run("should not fail renderers completing in time", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithRendererTimeout("mock", time.Minute),
+			engine.WithTimeout(time.Minute),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should reject negative timeouts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithTimeout(-time.Second))
+		g.Expect(err).To(MatchError(engine.ErrInvalidTimeout))
+
+		_, err = engine.New(engine.WithRendererTimeout("helm", -time.Second))
+		g.Expect(err).To(MatchError(engine.ErrInvalidTimeout))
+	})
+}
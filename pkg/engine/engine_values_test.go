@@ -0,0 +1,87 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+
+	. "github.com/onsi/gomega"
+)
+
+// capturingRenderer returns a renderer storing the values it is called with.
+func capturingRenderer(name string, captured *map[string]any) *mockRenderer {
+	return &mockRenderer{
+		name: name,
+		processFunc: func(_ context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+			*captured = values
+
+			return []unstructured.Unstructured{makePod(name)}, nil
+		},
+	}
+}
+
+func TestRendererValues(t *testing.T) {
+
+	t.Run("should merge renderer values over render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var daprValues, redisValues map[string]any
+
+		e, err := engine.New(
+			engine.WithNamedRenderer("dapr", capturingRenderer("dapr", &daprValues)),
+			engine.WithNamedRenderer("redis", capturingRenderer("redis", &redisValues)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := e.Render(t.Context(),
+			engine.WithValues(map[string]any{
+				"image": map[string]any{"registry": "mirror.example.com", "tag": "latest"},
+			}),
+			engine.WithValuesFor("dapr", map[string]any{
+				"image": map[string]any{"tag": "1.14"},
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(daprValues).To(Equal(map[string]any{
+			"image": map[string]any{"registry": "mirror.example.com", "tag": "1.14"},
+		}))
+		g.Expect(redisValues).To(Equal(map[string]any{
+			"image": map[string]any{"registry": "mirror.example.com", "tag": "latest"},
+		}))
+	})
+
+	t.Run("should support struct-based RenderOptions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var values map[string]any
+
+		e, err := engine.New(engine.WithNamedRenderer("dapr", capturingRenderer("dapr", &values)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.RenderOptions{
+			RendererValues: map[string]map[string]any{
+				"dapr": {"replicaCount": 2},
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(values).To(Equal(map[string]any{"replicaCount": 2}))
+	})
+
+	t.Run("should fail on values for unknown renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var values map[string]any
+
+		e, err := engine.New(engine.WithNamedRenderer("dapr", capturingRenderer("dapr", &values)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = e.Render(t.Context(), engine.WithValuesFor("drap", map[string]any{"replicaCount": 2}))
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+		g.Expect(values).To(BeNil())
+	})
+}
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// defaultWatchInterval is the default interval between two scans of the watched paths.
+const defaultWatchInterval = time.Second
+
+var (
+	// ErrNoWatchPaths is returned by Watch when neither the renderers nor WithWatchPaths provide
+	// paths to watch.
+	ErrNoWatchPaths = errors.New("no paths to watch")
+
+	// ErrInvalidWatchInterval is returned by New for non-positive watch intervals.
+	ErrInvalidWatchInterval = errors.New("invalid watch interval")
+)
+
+// WatchHandler is invoked by Watch with the outcome of each render, as returned by Render.
+type WatchHandler func(ctx context.Context, objects []unstructured.Unstructured, err error)
+
+// fileState is the state of a watched file, compared between two scans to detect changes.
+type fileState struct {
+	modTime int64
+	size    int64
+}
+
+// Watch renders with the given render-time options and invokes handler with the outcome, then
+// re-renders whenever the local files read by the renderers change, until ctx is done. The files
+// are those returned by the renderers implementing types.Watchable, e.g. kustomize directories,
+// yaml globs and template directories, unless paths are set with WithWatchPaths, which then
+// replace them. Changes are detected by scanning the files at the interval set with
+// WithWatchInterval, so several changes between two scans trigger a single render. Before
+// re-rendering, the cached results of the renderers implementing types.Invalidator are discarded.
+//
+// Render errors and scan errors are passed to handler and don't stop the watch. Watch returns nil
+// once ctx is done, or an error if the files can't be scanned when it starts.
+func (e *Engine) Watch(ctx context.Context, handler WatchHandler, opts ...RenderOption) error {
+	sources := e.watchSources()
+	if len(sources) == 0 {
+		return ErrNoWatchPaths
+	}
+
+	state, err := scan(sources)
+	if err != nil {
+		return fmt.Errorf("failed to scan watched paths: %w", err)
+	}
+
+	objects, err := e.Render(ctx, opts...)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	handler(ctx, objects, err)
+
+	ticker := time.NewTicker(e.options.WatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := scan(sources)
+		if err != nil {
+			handler(ctx, nil, fmt.Errorf("failed to scan watched paths: %w", err))
+
+			continue
+		}
+
+		if maps.Equal(state, current) {
+			continue
+		}
+
+		state = current
+
+		e.invalidate()
+
+		objects, err := e.Render(ctx, opts...)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		handler(ctx, objects, err)
+	}
+}
+
+// invalidate discards the cached results of the renderers implementing types.Invalidator.
+func (e *Engine) invalidate() {
+	for _, r := range e.options.Renderers {
+		if invalidator, ok := unwrapRenderer(r).(types.Invalidator); ok {
+			invalidator.Invalidate()
+		}
+	}
+}
+
+// watchSources returns the files watched by Watch: the paths set with WithWatchPaths if any,
+// otherwise the local files read by the renderers implementing types.Watchable.
+func (e *Engine) watchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0)
+
+	if len(e.options.WatchPaths) > 0 {
+		for _, pattern := range e.options.WatchPaths {
+			sources = append(sources, types.WatchSource{Pattern: pattern})
+		}
+
+		return sources
+	}
+
+	for _, r := range e.options.Renderers {
+		if watchable, ok := unwrapRenderer(r).(types.Watchable); ok {
+			sources = append(sources, watchable.WatchSources()...)
+		}
+	}
+
+	return sources
+}
+
+// scan returns the state of the files matched by the sources, descending into the matched
+// directories. Files are keyed by the index of their source, as sources may be read from
+// different filesystems.
+func scan(sources []types.WatchSource) (map[string]fileState, error) {
+	state := make(map[string]fileState)
+
+	for i, source := range sources {
+		glob, walk := filepath.Glob, filepath.WalkDir
+		if source.FS != nil {
+			glob = func(pattern string) ([]string, error) {
+				return fs.Glob(source.FS, pattern)
+			}
+			walk = func(root string, fn fs.WalkDirFunc) error {
+				return fs.WalkDir(source.FS, root, fn)
+			}
+		}
+
+		matches, err := glob(source.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", source.Pattern, err)
+		}
+
+		for _, match := range matches {
+			err := walk(match, func(path string, d fs.DirEntry, err error) error {
+				switch {
+				case errors.Is(err, fs.ErrNotExist):
+					// Removed while scanning, the next scan reports it
+					return nil
+				case err != nil:
+					return err
+				case d.IsDir():
+					return nil
+				}
+
+				info, err := d.Info()
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+
+				if err != nil {
+					return err
+				}
+
+				state[fmt.Sprintf("%d:%s", i, path)] = fileState{
+					modTime: info.ModTime().UnixNano(),
+					size:    info.Size(),
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan %q: %w", match, err)
+			}
+		}
+	}
+
+	return state, nil
+}
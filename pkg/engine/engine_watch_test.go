@@ -0,0 +1,183 @@
+package engine_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
+
+	. "github.com/onsi/gomega"
+)
+
+const watchPodYAML = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod1
+`
+
+const watchConfigMapYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+func TestWatch(t *testing.T) {
+	t.Run("should re-render when the files of the renderers change", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(dir, "pod.yaml"), []byte(watchPodYAML), 0o600)).To(Succeed())
+
+		// The cache would hide the new file without invalidation
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: os.DirFS(dir), Path: "*.yaml"}},
+			yaml.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The watched files are derived from the renderer sources
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithWatchInterval(10*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		renders := make(chan []unstructured.Unstructured, 16)
+		done := make(chan error, 1)
+
+		go func() {
+			done <- e.Watch(ctx, func(_ context.Context, objects []unstructured.Unstructured, err error) {
+				if err == nil {
+					renders <- objects
+				}
+			})
+		}()
+
+		g.Eventually(renders).Should(Receive(HaveLen(1)))
+
+		g.Expect(os.WriteFile(filepath.Join(dir, "configmap.yaml"), []byte(watchConfigMapYAML), 0o600)).To(Succeed())
+		g.Eventually(renders).Should(Receive(HaveLen(2)))
+
+		g.Expect(os.Remove(filepath.Join(dir, "pod.yaml"))).To(Succeed())
+		g.Eventually(renders).Should(Receive(HaveLen(1)))
+
+		cancel()
+		g.Eventually(done).Should(Receive(BeNil()))
+	})
+
+	t.Run("should not re-render without changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(dir, "pod.yaml"), []byte(watchPodYAML), 0o600)).To(Succeed())
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{makePod("pod1")})),
+			engine.WithWatchPaths(filepath.Join(dir, "*.yaml")),
+			engine.WithWatchInterval(10*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		renders := make(chan []unstructured.Unstructured, 16)
+		done := make(chan error, 1)
+
+		go func() {
+			done <- e.Watch(ctx, func(_ context.Context, objects []unstructured.Unstructured, _ error) {
+				renders <- objects
+			})
+		}()
+
+		g.Eventually(renders).Should(Receive())
+		g.Consistently(renders, 100*time.Millisecond).ShouldNot(Receive())
+
+		cancel()
+		g.Eventually(done).Should(Receive(BeNil()))
+	})
+
+	t.Run("should watch the given paths instead of the renderer files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sources := t.TempDir()
+		watched := t.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(sources, "pod.yaml"), []byte(watchPodYAML), 0o600)).To(Succeed())
+
+		renderer, err := yaml.New([]yaml.Source{{FS: os.DirFS(sources), Path: "*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		e, err := engine.New(
+			engine.WithRenderer(renderer),
+			engine.WithWatchPaths(watched),
+			engine.WithWatchInterval(10*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		renders := make(chan []unstructured.Unstructured, 16)
+		done := make(chan error, 1)
+
+		go func() {
+			done <- e.Watch(ctx, func(_ context.Context, objects []unstructured.Unstructured, err error) {
+				if err == nil {
+					renders <- objects
+				}
+			})
+		}()
+
+		g.Eventually(renders).Should(Receive(HaveLen(1)))
+
+		g.Expect(os.WriteFile(filepath.Join(sources, "configmap.yaml"), []byte(watchConfigMapYAML), 0o600)).To(Succeed())
+		g.Consistently(renders, 100*time.Millisecond).ShouldNot(Receive())
+
+		g.Expect(os.WriteFile(filepath.Join(watched, "trigger"), []byte("1"), 0o600)).To(Succeed())
+		g.Eventually(renders).Should(Receive(HaveLen(2)))
+
+		cancel()
+		g.Eventually(done).Should(Receive(BeNil()))
+	})
+
+	t.Run("should require watch paths without watchable renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(engine.WithRenderer(newMockRenderer(nil)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = e.Watch(t.Context(), func(context.Context, []unstructured.Unstructured, error) {})
+		g.Expect(err).To(MatchError(engine.ErrNoWatchPaths))
+	})
+
+	t.Run("should reject invalid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		e, err := engine.New(
+			engine.WithRenderer(newMockRenderer(nil)),
+			engine.WithWatchPaths("[invalid"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		err = e.Watch(t.Context(), func(context.Context, []unstructured.Unstructured, error) {})
+		g.Expect(err).To(MatchError(filepath.ErrBadPattern))
+	})
+
+	t.Run("should reject negative intervals", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := engine.New(engine.WithWatchInterval(-time.Second))
+		g.Expect(err).To(MatchError(engine.ErrInvalidWatchInterval))
+	})
+}
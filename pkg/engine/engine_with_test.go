@@ -0,0 +1,188 @@
+package engine_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/engine"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWith(t *testing.T) {
+	newCountingRenderer := func(name string, calls *atomic.Int32, objects ...unstructured.Unstructured) *mockRenderer {
+		return &mockRenderer{
+			name: name,
+			processFunc: func(_ context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+				calls.Add(1)
+
+				// Fresh objects on every call, as real renderers return
+				result := make([]unstructured.Unstructured, 0, len(objects))
+				for _, obj := range objects {
+					result = append(result, *obj.DeepCopy())
+				}
+
+				return result, nil
+			},
+		}
+	}
+
+	t.Run("should share the renderers with the base engine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls atomic.Int32
+
+		base, err := engine.New(
+			engine.WithRenderer(newCountingRenderer("app", &calls, makePod("pod1"), makeService())),
+			engine.WithTransformer(addLabels(map[string]string{"team": "a"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		prod, err := base.With(
+			engine.WithFilter(podFilter()),
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := prod.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("pod1"))
+		g.Expect(objects[0].GetLabels()).To(Equal(map[string]string{"team": "a", "env": "prod"}))
+
+		objects, err = base.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetLabels()).To(Equal(map[string]string{"team": "a"}))
+
+		g.Expect(calls.Load()).To(Equal(int32(2)))
+	})
+
+	t.Run("should not share added options between derived engines", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls atomic.Int32
+
+		base, err := engine.New(
+			engine.WithNamedRenderer("app", newCountingRenderer("app", &calls, makePod("pod1"))),
+			engine.WithTransformer(addLabels(map[string]string{"team": "a"})),
+			engine.WithRendererTransformer("app", addLabels(map[string]string{"source": "app"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		dev, err := base.With(
+			engine.WithTransformer(addLabels(map[string]string{"env": "dev"})),
+			engine.WithRendererTransformer("app", addLabels(map[string]string{"debug": "true"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		prod, err := base.With(
+			engine.WithTransformer(addLabels(map[string]string{"env": "prod"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := dev.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(Equal(map[string]string{
+			"team":   "a",
+			"source": "app",
+			"debug":  "true",
+			"env":    "dev",
+		}))
+
+		objects, err = prod.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(Equal(map[string]string{
+			"team":   "a",
+			"source": "app",
+			"env":    "prod",
+		}))
+	})
+
+	t.Run("should add renderers and override settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var calls atomic.Int32
+
+		base, err := engine.New(
+			engine.WithRenderer(newCountingRenderer("base", &calls, makePod("pod1"))),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		derived, err := base.With(
+			engine.WithRenderer(newCountingRenderer("extra", &calls, makePod("pod1"), makePod("pod2"))),
+			engine.WithDuplicatePolicy(engine.DuplicatePolicyKeepFirst),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := derived.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		objects, err = base.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should keep the flags of the base engine", func(t *testing.T) {
+		g := NewWithT(t)
+		tracker := &concurrencyTracker{}
+
+		pod := makePod("pod2")
+		svc := makeService()
+		list := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "List",
+				"items":      []any{pod.Object, svc.Object},
+			},
+		}
+
+		base, err := engine.New(
+			engine.WithParallel(true),
+			engine.WithExpandLists(true),
+			engine.WithRenderer(tracker.renderer("first")),
+			engine.WithRenderer(tracker.renderer("second")),
+			engine.WithRenderer(newMockRenderer([]unstructured.Unstructured{list})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		derived, err := base.With(&engine.Options{
+			Filters: []types.Filter{podFilter()},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := derived.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(tracker.peak.Load()).To(BeNumerically("==", 2))
+
+		sequential, err := base.With(engine.WithParallel(false))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		tracker.peak.Store(0)
+
+		_, err = sequential.Render(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(tracker.peak.Load()).To(BeNumerically("==", 1))
+	})
+
+	t.Run("should validate the derived options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		base, err := engine.New(engine.WithRenderer(newMockRenderer(nil)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = base.With(engine.WithDuplicatePolicy("Ignore"))
+		g.Expect(err).To(MatchError(engine.ErrInvalidDuplicatePolicy))
+
+		_, err = base.With(engine.WithRendererFilter("unknown", podFilter()))
+		g.Expect(err).To(MatchError(engine.ErrUnknownRenderer))
+	})
+}
@@ -0,0 +1,49 @@
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cel"
+)
+
+var (
+	// ErrCelMustReturnBoolean is returned when a CEL expression doesn't return a boolean.
+	ErrCelMustReturnBoolean = errors.New("cel expression must return a boolean")
+)
+
+// Filter creates a new CEL filter with the given expression and options.
+// The object being filtered is available as the object variable.
+func Filter(expression string, opts ...cel.Option) (types.Filter, error) {
+	// Create a new CEL engine
+	engine, err := cel.NewEngine(expression, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cel engine: %w", err)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		// Evaluate the CEL program against the object
+		v, err := engine.Run(ctx, obj.Object)
+		if err != nil {
+			return false, &filter.Error{
+				Object: obj,
+				Err:    fmt.Errorf("error executing cel expression: %w", err),
+			}
+		}
+
+		// Convert the result to a boolean
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+
+		return false, &filter.Error{
+			Object: obj,
+			Err:    fmt.Errorf("%w, got %T", ErrCelMustReturnBoolean, v),
+		}
+	}, nil
+}
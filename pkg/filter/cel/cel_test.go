@@ -0,0 +1,216 @@
+package cel_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/cel"
+	utilcel "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cel"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFilter(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should filter by kind", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.kind == "Pod"`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		pod := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name": "test-pod",
+				},
+			},
+		}
+
+		result, err := filter(ctx, pod)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		service := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]any{
+					"name": "test-service",
+				},
+			},
+		}
+
+		result, err = filter(ctx, service)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should filter by complex expression", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.kind == "Deployment" && object.spec.replicas > 1`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		matching := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec": map[string]any{
+					"replicas": int64(3),
+				},
+			},
+		}
+
+		result, err := filter(ctx, matching)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		notMatching := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"spec": map[string]any{
+					"replicas": int64(1),
+				},
+			},
+		}
+
+		result, err = filter(ctx, notMatching)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should filter with has macro", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`has(object.metadata.labels) && object.metadata.labels.app == "nginx"`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		withLabel := unstructured.Unstructured{
+			Object: map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]any{
+						"app": "nginx",
+					},
+				},
+			},
+		}
+
+		result, err := filter(ctx, withLabel)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		withoutLabel := unstructured.Unstructured{
+			Object: map[string]any{
+				"metadata": map[string]any{
+					"name": "test-pod",
+				},
+			},
+		}
+
+		result, err = filter(ctx, withoutLabel)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should filter with optional fields", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.?metadata.?annotations.?special.orValue("") == ""`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj := unstructured.Unstructured{
+			Object: map[string]any{
+				"metadata": map[string]any{
+					"name": "test",
+				},
+			},
+		}
+
+		result, err := filter(ctx, obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+	})
+
+	t.Run("should filter with list macros", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.spec.containers.all(c, c.image.endsWith(":1.25"))`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj := unstructured.Unstructured{
+			Object: map[string]any{
+				"kind": "Pod",
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{"name": "app", "image": "nginx:1.25"},
+						map[string]any{"name": "proxy", "image": "envoy:1.30"},
+					},
+				},
+			},
+		}
+
+		result, err := filter(ctx, obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should filter with variable", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(
+			`object.kind == expectedKind`,
+			utilcel.WithVariable("expectedKind", "Pod"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		pod := unstructured.Unstructured{
+			Object: map[string]any{
+				"kind": "Pod",
+			},
+		}
+
+		result, err := filter(ctx, pod)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+	})
+
+	t.Run("should return error for invalid expression", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.kind ==`)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("error creating cel engine"))
+		g.Expect(filter).To(BeNil())
+	})
+
+	t.Run("should return error for non-boolean result", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.kind`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj := unstructured.Unstructured{
+			Object: map[string]any{
+				"kind": "Pod",
+			},
+		}
+
+		result, err := filter(ctx, obj)
+		g.Expect(err).To(MatchError(cel.ErrCelMustReturnBoolean))
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should return error for missing field", func(t *testing.T) {
+		g := NewWithT(t)
+		filter, err := cel.Filter(`object.spec.replicas > 1`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj := unstructured.Unstructured{
+			Object: map[string]any{
+				"kind": "Pod",
+			},
+		}
+
+		result, err := filter(ctx, obj)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("error executing cel expression"))
+		g.Expect(result).To(BeFalse())
+	})
+}
@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// Any returns a collection filter that keeps an object if ANY of the other objects of the set
+// passes the filter returned by related for that object.
+// If any filter returns an error, the error is returned immediately.
+func Any(related func(object unstructured.Unstructured) types.Filter) types.CollectionFilter {
+	return func(ctx context.Context, obj unstructured.Unstructured, objects []unstructured.Unstructured) (bool, error) {
+		return exists(ctx, related(obj), obj, objects)
+	}
+}
+
+// All returns a collection filter that keeps an object if ALL the other objects of the set pass
+// the filter returned by related for that object. Objects alone in the set are kept.
+// If any filter returns an error, the error is returned immediately.
+func All(related func(object unstructured.Unstructured) types.Filter) types.CollectionFilter {
+	return func(ctx context.Context, obj unstructured.Unstructured, objects []unstructured.Unstructured) (bool, error) {
+		ok, err := exists(ctx, Not(related(obj)), obj, objects)
+		if err != nil {
+			return false, err
+		}
+
+		return !ok, nil
+	}
+}
+
+// None returns a collection filter that keeps an object if NONE of the other objects of the set
+// passes the filter returned by related for that object.
+// If any filter returns an error, the error is returned immediately.
+func None(related func(object unstructured.Unstructured) types.Filter) types.CollectionFilter {
+	return func(ctx context.Context, obj unstructured.Unstructured, objects []unstructured.Unstructured) (bool, error) {
+		ok, err := exists(ctx, related(obj), obj, objects)
+		if err != nil {
+			return false, err
+		}
+
+		return !ok, nil
+	}
+}
+
+// IfCollection applies a collection filter conditionally.
+// If the condition passes, the then collection filter is applied.
+// If the condition fails, the object passes through (returns true).
+func IfCollection(condition types.Filter, then types.CollectionFilter) types.CollectionFilter {
+	return func(ctx context.Context, obj unstructured.Unstructured, objects []unstructured.Unstructured) (bool, error) {
+		ok, err := condition(ctx, obj)
+		if err != nil {
+			return false, err
+		}
+
+		if !ok {
+			return true, nil
+		}
+
+		return then(ctx, obj, objects)
+	}
+}
+
+// exists reports whether any object of the set, other than obj, passes the filter.
+func exists(
+	ctx context.Context,
+	filter types.Filter,
+	obj unstructured.Unstructured,
+	objects []unstructured.Unstructured,
+) (bool, error) {
+	for i := range objects {
+		if sameObject(obj, objects[i]) {
+			continue
+		}
+
+		ok, err := filter(ctx, objects[i])
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sameObject reports whether both objects share the same content map, i.e. they are copies of
+// the same element of the set.
+func sameObject(a unstructured.Unstructured, b unstructured.Unstructured) bool {
+	if a.Object == nil || b.Object == nil {
+		return false
+	}
+
+	return reflect.ValueOf(a.Object).UnsafePointer() == reflect.ValueOf(b.Object).UnsafePointer()
+}
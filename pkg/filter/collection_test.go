@@ -0,0 +1,122 @@
+package filter_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCollection(t *testing.T) {
+	// selects the Deployment sharing the name of the object
+	sameNameDeployment := func(obj unstructured.Unstructured) types.Filter {
+		return func(_ context.Context, other unstructured.Unstructured) (bool, error) {
+			return other.GetKind() == "Deployment" && other.GetName() == obj.GetName(), nil
+		}
+	}
+
+	isService := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return obj.GetKind() == "Service", nil
+	}
+
+	objects := []unstructured.Unstructured{
+		makeObject("Deployment", "app"),
+		makeObject("Service", "app"),
+		makeObject("Service", "orphan"),
+	}
+
+	keep := func(g *WithT, f types.CollectionFilter) []string {
+		kept := make([]string, 0)
+
+		for _, obj := range objects {
+			ok, err := f(t.Context(), obj, objects)
+			g.Expect(err).ShouldNot(HaveOccurred())
+
+			if ok {
+				kept = append(kept, obj.GetKind()+"/"+obj.GetName())
+			}
+		}
+
+		return kept
+	}
+
+	t.Run("Any should keep objects with a related object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(keep(g, filter.Any(sameNameDeployment))).Should(Equal([]string{"Service/app"}))
+	})
+
+	t.Run("None should keep objects without related objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(keep(g, filter.None(sameNameDeployment))).Should(Equal([]string{"Deployment/app", "Service/orphan"}))
+	})
+
+	t.Run("All should keep objects related to every other object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sameName := func(obj unstructured.Unstructured) types.Filter {
+			return func(_ context.Context, other unstructured.Unstructured) (bool, error) {
+				return other.GetName() == obj.GetName(), nil
+			}
+		}
+
+		g.Expect(keep(g, filter.All(sameName))).Should(BeEmpty())
+
+		notSelf := func(obj unstructured.Unstructured) types.Filter {
+			return func(_ context.Context, other unstructured.Unstructured) (bool, error) {
+				return other.GetKind() != obj.GetKind() || other.GetName() != obj.GetName(), nil
+			}
+		}
+
+		g.Expect(keep(g, filter.All(notSelf))).Should(HaveLen(3))
+	})
+
+	t.Run("IfCollection should only apply to matching objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := filter.IfCollection(isService, filter.Any(sameNameDeployment))
+
+		g.Expect(keep(g, f)).Should(Equal([]string{"Deployment/app", "Service/app"}))
+	})
+
+	t.Run("should not relate an object to itself", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := filter.Any(func(obj unstructured.Unstructured) types.Filter {
+			return func(_ context.Context, other unstructured.Unstructured) (bool, error) {
+				return other.GetName() == obj.GetName() && other.GetKind() == obj.GetKind(), nil
+			}
+		})
+
+		g.Expect(keep(g, f)).Should(BeEmpty())
+	})
+
+	t.Run("should return errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := filter.Any(func(unstructured.Unstructured) types.Filter {
+			return alwaysError()
+		})
+
+		_, err := f(t.Context(), objects[0], objects)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func makeObject(kind string, name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]any{
+				"name": name,
+			},
+		},
+	}
+}
@@ -0,0 +1,240 @@
+package field
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+)
+
+var (
+	// ErrNotComparable is returned for values that do not support ordered comparisons.
+	ErrNotComparable = errors.New("value is not comparable")
+
+	// ErrInvalidPath is returned for malformed field paths.
+	ErrInvalidPath = fieldpath.ErrInvalidPath
+)
+
+// Match returns a filter that keeps objects for which any value selected by the field path
+// satisfies the predicate. Paths are dotted, e.g. "spec.template.spec.containers[*].image";
+// see fieldpath.Parse for the supported syntax. Missing fields and null values are
+// never passed to the predicate.
+func Match(path string, predicate func(value any) bool) (types.Filter, error) {
+	p, err := fieldpath.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		for _, v := range p.Lookup(obj.Object) {
+			if predicate(v) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}, nil
+}
+
+// Exists returns a filter that keeps objects for which the field path selects a non-null value.
+func Exists(path string) (types.Filter, error) {
+	return Match(path, func(any) bool {
+		return true
+	})
+}
+
+// Equals returns a filter that keeps objects for which any value selected by the field path
+// equals value. Numbers are compared by value regardless of their Go type, and a
+// resource.Quantity matches quantities written in any notation, e.g. "1Gi" and "1024Mi".
+func Equals(path string, value any) (types.Filter, error) {
+	return Match(path, func(v any) bool {
+		return equal(v, value)
+	})
+}
+
+// In returns a filter that keeps objects for which any value selected by the field path equals
+// one of values.
+func In(path string, values ...any) (types.Filter, error) {
+	return Match(path, func(v any) bool {
+		for _, value := range values {
+			if equal(v, value) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// GreaterThan returns a filter that keeps objects for which any value selected by the field
+// path is greater than value. Value must be a number, a string or a resource.Quantity.
+func GreaterThan(path string, value any) (types.Filter, error) {
+	return ordered(path, value, func(c int) bool { return c > 0 })
+}
+
+// GreaterOrEqual returns a filter that keeps objects for which any value selected by the field
+// path is greater than or equal to value. Value must be a number, a string or a resource.Quantity.
+func GreaterOrEqual(path string, value any) (types.Filter, error) {
+	return ordered(path, value, func(c int) bool { return c >= 0 })
+}
+
+// LessThan returns a filter that keeps objects for which any value selected by the field path
+// is less than value. Value must be a number, a string or a resource.Quantity.
+func LessThan(path string, value any) (types.Filter, error) {
+	return ordered(path, value, func(c int) bool { return c < 0 })
+}
+
+// LessOrEqual returns a filter that keeps objects for which any value selected by the field
+// path is less than or equal to value. Value must be a number, a string or a resource.Quantity.
+func LessOrEqual(path string, value any) (types.Filter, error) {
+	return ordered(path, value, func(c int) bool { return c <= 0 })
+}
+
+// StartsWith returns a filter that keeps objects for which any string selected by the field
+// path starts with prefix.
+func StartsWith(path string, prefix string) (types.Filter, error) {
+	return matchString(path, func(s string) bool { return strings.HasPrefix(s, prefix) })
+}
+
+// EndsWith returns a filter that keeps objects for which any string selected by the field path
+// ends with suffix.
+func EndsWith(path string, suffix string) (types.Filter, error) {
+	return matchString(path, func(s string) bool { return strings.HasSuffix(s, suffix) })
+}
+
+// Contains returns a filter that keeps objects for which any string selected by the field path
+// contains substr.
+func Contains(path string, substr string) (types.Filter, error) {
+	return matchString(path, func(s string) bool { return strings.Contains(s, substr) })
+}
+
+// Regex returns a filter that keeps objects for which any string selected by the field path
+// matches the given regex pattern.
+func Regex(path string, pattern string) (types.Filter, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	return matchString(path, re.MatchString)
+}
+
+// ordered returns a filter keeping objects for which the comparison of any selected value with
+// value satisfies check. Values of a different type than value never match.
+func ordered(path string, value any, check func(int) bool) (types.Filter, error) {
+	if _, ok := compare(value, value); !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotComparable, value)
+	}
+
+	return Match(path, func(v any) bool {
+		c, ok := compare(v, value)
+
+		return ok && check(c)
+	})
+}
+
+// matchString returns a filter keeping objects for which any selected string satisfies check.
+func matchString(path string, check func(string) bool) (types.Filter, error) {
+	return Match(path, func(v any) bool {
+		s, ok := v.(string)
+
+		return ok && check(s)
+	})
+}
+
+// equal reports whether a field value equals a reference value, comparing comparable values
+// with compare and any other value deeply.
+func equal(v any, value any) bool {
+	if c, ok := compare(v, value); ok {
+		return c == 0
+	}
+
+	return reflect.DeepEqual(v, value)
+}
+
+// compare compares a field value with a reference value, returning false if they are not
+// comparable: numbers are compared numerically, strings lexically, and quantities by amount
+// when the reference value is a resource.Quantity.
+func compare(v any, value any) (int, bool) {
+	switch ref := value.(type) {
+	case resource.Quantity:
+		q, ok := toQuantity(v)
+		if !ok {
+			return 0, false
+		}
+
+		return q.Cmp(ref), true
+	case *resource.Quantity:
+		if ref == nil {
+			return 0, false
+		}
+
+		return compare(v, *ref)
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return 0, false
+		}
+
+		return strings.Compare(s, ref), true
+	}
+
+	a, ok := toFloat(v)
+	if !ok {
+		return 0, false
+	}
+
+	b, ok := toFloat(value)
+	if !ok {
+		return 0, false
+	}
+
+	return cmp.Compare(a, b), true
+}
+
+// toQuantity converts a field value to a quantity, parsing strings such as "500m" or "1Gi".
+func toQuantity(v any) (resource.Quantity, bool) {
+	switch value := v.(type) {
+	case resource.Quantity:
+		return value, true
+	case string:
+		q, err := resource.ParseQuantity(value)
+
+		return q, err == nil
+	}
+
+	f, ok := toFloat(v)
+	if !ok {
+		return resource.Quantity{}, false
+	}
+
+	q, err := resource.ParseQuantity(strconv.FormatFloat(f, 'f', -1, 64))
+
+	return q, err == nil
+}
+
+// toFloat converts any numeric value to a float64.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,173 @@
+package field_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/field"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "app",
+				"labels": map[string]any{
+					"app.kubernetes.io/name": "app",
+				},
+			},
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"paused":   false,
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"name":  "app",
+								"image": "registry.internal/app:1.0",
+								"resources": map[string]any{
+									"limits": map[string]any{
+										"cpu":    "500m",
+										"memory": "1Gi",
+									},
+								},
+							},
+							map[string]any{
+								"name":  "proxy",
+								"image": "docker.io/envoyproxy/envoy:v1.30",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestFilters(t *testing.T) {
+	must := func(f types.Filter, err error) types.Filter {
+		t.Helper()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return f
+	}
+
+	tests := []struct {
+		name     string
+		filter   types.Filter
+		expected bool
+	}{
+		{"exists", must(field.Exists("spec.replicas")), true},
+		{"exists with leading dot", must(field.Exists(".spec.template.spec.containers[1].name")), true},
+		{"exists on missing field", must(field.Exists("spec.strategy")), false},
+		{"exists on out of range index", must(field.Exists("spec.template.spec.containers[2]")), false},
+		{"exists on quoted key", must(field.Exists("metadata.labels['app.kubernetes.io/name']")), true},
+		{"equals number", must(field.Equals("spec.replicas", 3)), true},
+		{"equals float", must(field.Equals("spec.replicas", 3.0)), true},
+		{"equals different number", must(field.Equals("spec.replicas", 2)), false},
+		{"equals string", must(field.Equals("kind", "Deployment")), true},
+		{"equals does not convert types", must(field.Equals("spec.replicas", "3")), false},
+		{"equals bool", must(field.Equals("spec.paused", false)), true},
+		{"equals quantity", must(field.Equals(
+			"spec.template.spec.containers[*].resources.limits.memory", resource.MustParse("1024Mi"),
+		)), true},
+		{"in", must(field.In("spec.template.spec.containers[*].name", "sidecar", "proxy")), true},
+		{"in without match", must(field.In("kind", "StatefulSet", "DaemonSet")), false},
+		{"greater than", must(field.GreaterThan("spec.replicas", 2)), true},
+		{"greater than equal value", must(field.GreaterThan("spec.replicas", 3)), false},
+		{"greater or equal", must(field.GreaterOrEqual("spec.replicas", 3)), true},
+		{"less than", must(field.LessThan("spec.replicas", 3)), false},
+		{"less or equal", must(field.LessOrEqual("spec.replicas", 3)), true},
+		{"less than quantity", must(field.LessThan(
+			"spec.template.spec.containers[*].resources.limits.cpu", resource.MustParse("1"),
+		)), true},
+		{"greater than quantity", must(field.GreaterThan(
+			"spec.template.spec.containers[*].resources.limits.memory", resource.MustParse("2Gi"),
+		)), false},
+		{"ordered comparison of different types", must(field.GreaterThan("kind", 1)), false},
+		{"starts with any element", must(field.StartsWith(
+			"spec.template.spec.containers[*].image", "registry.internal/",
+		)), true},
+		{"starts with indexed element", must(field.StartsWith(
+			"spec.template.spec.containers[1].image", "registry.internal/",
+		)), false},
+		{"ends with", must(field.EndsWith("spec.template.spec.containers[0].image", ":1.0")), true},
+		{"contains", must(field.Contains("spec.template.spec.containers[*].image", "envoy")), true},
+		{"regex", must(field.Regex("spec.template.spec.containers[*].image", `:v\d+\.\d+$`)), true},
+		{"wildcard on maps", must(field.Equals("metadata.labels[*]", "app")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run("should evaluate "+tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := tt.filter(t.Context(), makeDeployment())
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expected))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Run("should pass selected values to the predicate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var values []any
+
+		f, err := field.Match("spec.template.spec.containers[*].name", func(v any) bool {
+			values = append(values, v)
+
+			return false
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := f(t.Context(), makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+		g.Expect(values).To(Equal([]any{"app", "proxy"}))
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Run("should reject invalid paths", func(t *testing.T) {
+		for _, path := range []string{
+			"",
+			".",
+			"spec..replicas",
+			"spec.",
+			"spec.containers[",
+			"spec.containers[-1]",
+			"spec.containers[a]",
+			"spec.containers[0]image",
+		} {
+			g := NewWithT(t)
+
+			_, err := field.Exists(path)
+			g.Expect(err).To(MatchError(field.ErrInvalidPath), path)
+		}
+	})
+
+	t.Run("should reject values without ordering", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := field.GreaterThan("spec.paused", true)
+		g.Expect(err).To(MatchError(field.ErrNotComparable))
+	})
+
+	t.Run("should reject invalid regex patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := field.Regex("metadata.name", "[")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
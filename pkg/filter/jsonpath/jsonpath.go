@@ -0,0 +1,105 @@
+package jsonpath
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// Exists returns a filter that keeps objects for which the kubectl-style JSONPath expression
+// selects at least one non-null value, e.g. "{.spec.template.spec.tolerations}" or
+// "{.spec.containers[?(@.name=='nginx')]}". Braces are optional.
+func Exists(expression string) (types.Filter, error) {
+	jp, err := parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		values, err := find(jp, obj)
+		if err != nil {
+			return false, err
+		}
+
+		return len(values) > 0, nil
+	}, nil
+}
+
+// Equals returns a filter that keeps objects for which any value selected by the kubectl-style
+// JSONPath expression equals value. Values are compared in their string form, so numbers and
+// booleans match regardless of their Go type, e.g. Equals("{.spec.replicas}", 3).
+func Equals(expression string, value any) (types.Filter, error) {
+	jp, err := parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := fmt.Sprint(value)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		values, err := find(jp, obj)
+		if err != nil {
+			return false, err
+		}
+
+		for _, v := range values {
+			if fmt.Sprint(v) == expected {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}, nil
+}
+
+// parse parses a JSONPath expression, wrapping it in braces if needed like kubectl does.
+func parse(expression string) (*jsonpath.JSONPath, error) {
+	expr := strings.TrimSpace(expression)
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	jp := jsonpath.New("filter").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath expression %q: %w", expression, err)
+	}
+
+	return jp, nil
+}
+
+// find returns the non-null values selected by the JSONPath expression.
+func find(jp *jsonpath.JSONPath, obj unstructured.Unstructured) ([]any, error) {
+	results, err := jp.FindResults(obj.Object)
+	if err != nil {
+		return nil, &filter.Error{
+			Object: obj,
+			Err:    fmt.Errorf("error executing jsonpath expression: %w", err),
+		}
+	}
+
+	values := make([]any, 0)
+
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() {
+				continue
+			}
+
+			if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer || v.Kind() == reflect.Map ||
+				v.Kind() == reflect.Slice) && v.IsNil() {
+				continue
+			}
+
+			values = append(values, v.Interface())
+		}
+	}
+
+	return values, nil
+}
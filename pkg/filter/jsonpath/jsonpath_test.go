@@ -0,0 +1,187 @@
+package jsonpath_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/jsonpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func deployment(replicas int64, containers ...string) unstructured.Unstructured {
+	items := make([]any, 0, len(containers))
+	for _, name := range containers {
+		items = append(items, map[string]any{
+			"name":  name,
+			"image": name + ":latest",
+		})
+	}
+
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "test-deployment",
+				"labels": map[string]any{
+					"app.kubernetes.io/name": "test",
+				},
+			},
+			"spec": map[string]any{
+				"replicas": replicas,
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": items,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExists(t *testing.T) {
+	ctx := t.Context()
+
+	tests := []struct {
+		name       string
+		expression string
+		object     unstructured.Unstructured
+		expected   bool
+	}{
+		{
+			name:       "should match existing fields",
+			expression: "{.spec.replicas}",
+			object:     deployment(3, "nginx"),
+			expected:   true,
+		},
+		{
+			name:       "should accept expressions without braces",
+			expression: ".spec.template.spec.containers[0].image",
+			object:     deployment(3, "nginx"),
+			expected:   true,
+		},
+		{
+			name:       "should not match missing fields",
+			expression: "{.spec.strategy.type}",
+			object:     deployment(3, "nginx"),
+			expected:   false,
+		},
+		{
+			name:       "should match filter expressions",
+			expression: "{.spec.template.spec.containers[?(@.name=='sidecar')]}",
+			object:     deployment(3, "nginx", "sidecar"),
+			expected:   true,
+		},
+		{
+			name:       "should not match empty filter results",
+			expression: "{.spec.template.spec.containers[?(@.name=='sidecar')]}",
+			object:     deployment(3, "nginx"),
+			expected:   false,
+		},
+		{
+			name:       "should match escaped keys",
+			expression: `{.metadata.labels.app\.kubernetes\.io/name}`,
+			object:     deployment(3, "nginx"),
+			expected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f, err := jsonpath.Exists(tt.expression)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			result, err := f(ctx, tt.object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expected))
+		})
+	}
+
+	t.Run("should fail on invalid expressions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := jsonpath.Exists("{.spec.replicas")
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestEquals(t *testing.T) {
+	ctx := t.Context()
+
+	tests := []struct {
+		name       string
+		expression string
+		value      any
+		object     unstructured.Unstructured
+		expected   bool
+	}{
+		{
+			name:       "should match strings",
+			expression: "{.kind}",
+			value:      "Deployment",
+			object:     deployment(3, "nginx"),
+			expected:   true,
+		},
+		{
+			name:       "should not match different strings",
+			expression: "{.kind}",
+			value:      "StatefulSet",
+			object:     deployment(3, "nginx"),
+			expected:   false,
+		},
+		{
+			name:       "should match numbers regardless of their type",
+			expression: "{.spec.replicas}",
+			value:      3,
+			object:     deployment(3, "nginx"),
+			expected:   true,
+		},
+		{
+			name:       "should match any of multiple results",
+			expression: "{.spec.template.spec.containers[*].image}",
+			value:      "sidecar:latest",
+			object:     deployment(3, "nginx", "sidecar"),
+			expected:   true,
+		},
+		{
+			name:       "should not match missing fields",
+			expression: "{.spec.strategy.type}",
+			value:      "",
+			object:     deployment(3, "nginx"),
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f, err := jsonpath.Equals(tt.expression, tt.value)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			result, err := f(ctx, tt.object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expected))
+		})
+	}
+
+	t.Run("should return a filter error on execution failures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := jsonpath.Equals("{.spec.replicas[0]}", 3)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = f(ctx, deployment(3, "nginx"))
+		g.Expect(err).To(HaveOccurred())
+
+		var filterErr *filter.Error
+		g.Expect(errors.As(err, &filterErr)).To(BeTrue())
+		g.Expect(filterErr.Object.GetName()).To(Equal("test-deployment"))
+	})
+}
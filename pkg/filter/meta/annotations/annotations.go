@@ -45,3 +45,17 @@ func MatchAnnotations(matchAnnotations map[string]string) types.Filter {
 		return true, nil
 	}
 }
+
+// MatchIf returns a filter that keeps objects that have at least one annotation for which the
+// predicate returns true.
+func MatchIf(predicate func(key string, value string) bool) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		for key, value := range obj.GetAnnotations() {
+			if predicate(key, value) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
@@ -1,12 +1,14 @@
 package annotations_test
 
 import (
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/annotations"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 
 	. "github.com/onsi/gomega"
 )
@@ -97,6 +99,50 @@ func TestMatchAnnotations(t *testing.T) {
 		g.Expect(err).ShouldNot(HaveOccurred())
 		g.Expect(ok).Should(BeFalse())
 	})
+
+	t.Run("should match source annotations", func(t *testing.T) {
+		filter := annotations.MatchAnnotations(map[string]string{
+			types.AnnotationSourceType: "helm",
+		})
+
+		ok, err := filter(t.Context(), makePodWithAnnotations(map[string]string{
+			types.AnnotationSourceType: "helm",
+			types.AnnotationSourcePath: "oci://registry.example.com/charts/app",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func TestMatchIf(t *testing.T) {
+	g := NewWithT(t)
+
+	fromTemplates := annotations.MatchIf(func(key string, value string) bool {
+		return key == types.AnnotationSourceFile && strings.HasPrefix(value, "templates/")
+	})
+
+	t.Run("should keep objects with a matching annotation", func(t *testing.T) {
+		ok, err := fromTemplates(t.Context(), makePodWithAnnotations(map[string]string{
+			types.AnnotationSourceType: "helm",
+			types.AnnotationSourceFile: "templates/pod.yaml",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should exclude objects without a matching annotation", func(t *testing.T) {
+		ok, err := fromTemplates(t.Context(), makePodWithAnnotations(map[string]string{
+			types.AnnotationSourceFile: "crds/crd.yaml",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should handle objects with no annotations", func(t *testing.T) {
+		ok, err := fromTemplates(t.Context(), makePodWithAnnotations(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
 }
 
 // Helper function
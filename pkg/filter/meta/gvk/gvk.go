@@ -2,6 +2,8 @@ package gvk
 
 import (
 	"context"
+	"fmt"
+	"path"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -19,3 +21,68 @@ func Filter(gvks ...schema.GroupVersionKind) types.Filter {
 		return s.Has(object.GetObjectKind().GroupVersionKind()), nil
 	}
 }
+
+// Group creates a new filter function that keeps objects whose API group is any of the provided
+// groups. The core group is the empty string.
+func Group(groups ...string) types.Filter {
+	s := sets.New(groups...)
+
+	return func(_ context.Context, object unstructured.Unstructured) (bool, error) {
+		return s.Has(object.GetObjectKind().GroupVersionKind().Group), nil
+	}
+}
+
+// Version creates a new filter function that keeps objects whose API version, without the group,
+// is any of the provided versions.
+func Version(versions ...string) types.Filter {
+	s := sets.New(versions...)
+
+	return func(_ context.Context, object unstructured.Unstructured) (bool, error) {
+		return s.Has(object.GetObjectKind().GroupVersionKind().Version), nil
+	}
+}
+
+// Kind creates a new filter function that keeps objects whose Kind is any of the provided kinds,
+// regardless of their group and version.
+func Kind(kinds ...string) types.Filter {
+	s := sets.New(kinds...)
+
+	return func(_ context.Context, object unstructured.Unstructured) (bool, error) {
+		return s.Has(object.GetObjectKind().GroupVersionKind().Kind), nil
+	}
+}
+
+// Match creates a new filter function that keeps objects whose GroupVersionKind matches any of the
+// provided patterns. Each field of a pattern is a glob as supported by path.Match, e.g.
+// {Group: "apps", Version: "*", Kind: "*"} or {Group: "*.example.com", Version: "v1*", Kind: "*"}.
+// A "*" group also matches the core group.
+func Match(patterns ...schema.GroupVersionKind) (types.Filter, error) {
+	for _, p := range patterns {
+		for _, field := range []string{p.Group, p.Version, p.Kind} {
+			if _, err := path.Match(field, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", field, err)
+			}
+		}
+	}
+
+	f := func(_ context.Context, object unstructured.Unstructured) (bool, error) {
+		objGVK := object.GetObjectKind().GroupVersionKind()
+
+		for _, p := range patterns {
+			if glob(p.Group, objGVK.Group) && glob(p.Version, objGVK.Version) && glob(p.Kind, objGVK.Kind) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return f, nil
+}
+
+// glob reports whether value matches the validated pattern.
+func glob(pattern string, value string) bool {
+	ok, _ := path.Match(pattern, value)
+
+	return ok
+}
@@ -1,6 +1,7 @@
 package gvk_test
 
 import (
+	"path"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -9,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 
 	. "github.com/onsi/gomega"
 )
@@ -207,6 +209,94 @@ func TestFilter(t *testing.T) {
 	})
 }
 
+func TestGroupVersionKind(t *testing.T) {
+	ctx := t.Context()
+
+	deployment := makeObject("apps/v1", "Deployment", "test-deployment")
+	pod := makeObject("v1", "Pod", "test-pod")
+	cr := makeObject("example.com/v1beta1", "Deployment", "test-cr")
+
+	tests := []struct {
+		name     string
+		filter   func(g *WithT) types.Filter
+		expected []bool
+	}{
+		{
+			name:     "should filter by group",
+			filter:   func(*WithT) types.Filter { return gvk.Group("apps") },
+			expected: []bool{true, false, false},
+		},
+		{
+			name:     "should filter by core group",
+			filter:   func(*WithT) types.Filter { return gvk.Group("") },
+			expected: []bool{false, true, false},
+		},
+		{
+			name:     "should filter by version",
+			filter:   func(*WithT) types.Filter { return gvk.Version("v1") },
+			expected: []bool{true, true, false},
+		},
+		{
+			name:     "should filter by kind regardless of group and version",
+			filter:   func(*WithT) types.Filter { return gvk.Kind("Deployment") },
+			expected: []bool{true, false, true},
+		},
+		{
+			name: "should match everything in a group",
+			filter: func(g *WithT) types.Filter {
+				f, err := gvk.Match(schema.GroupVersionKind{Group: "apps", Version: "*", Kind: "*"})
+				g.Expect(err).ToNot(HaveOccurred())
+
+				return f
+			},
+			expected: []bool{true, false, false},
+		},
+		{
+			name: "should match the core group with a wildcard",
+			filter: func(g *WithT) types.Filter {
+				f, err := gvk.Match(schema.GroupVersionKind{Group: "*", Version: "v1", Kind: "*"})
+				g.Expect(err).ToNot(HaveOccurred())
+
+				return f
+			},
+			expected: []bool{true, true, false},
+		},
+		{
+			name: "should match glob patterns",
+			filter: func(g *WithT) types.Filter {
+				f, err := gvk.Match(
+					schema.GroupVersionKind{Group: "*.com", Version: "v1beta*", Kind: "*"},
+					schema.GroupVersionKind{Group: "", Version: "v1", Kind: "P?d"},
+				)
+				g.Expect(err).ToNot(HaveOccurred())
+
+				return f
+			},
+			expected: []bool{false, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			filter := tt.filter(g)
+
+			for i, obj := range []unstructured.Unstructured{deployment, pod, cr} {
+				result, err := filter(ctx, obj)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(result).To(Equal(tt.expected[i]), obj.GetName())
+			}
+		})
+	}
+
+	t.Run("should reject invalid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := gvk.Match(schema.GroupVersionKind{Group: "apps", Version: "*", Kind: "[Deploy"})
+		g.Expect(err).To(MatchError(path.ErrBadPattern))
+	})
+}
+
 func makeObject(apiVersion string, kind string, name string) unstructured.Unstructured {
 	obj := unstructured.Unstructured{
 		Object: map[string]any{
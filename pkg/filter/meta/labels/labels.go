@@ -3,6 +3,7 @@ package labels
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -10,6 +11,14 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 )
 
+const (
+	// partOfLabel is the recommended label holding the name of the higher level application.
+	partOfLabel = "app.kubernetes.io/part-of"
+
+	// instanceLabel is the recommended label holding the unique name of the application instance.
+	instanceLabel = "app.kubernetes.io/instance"
+)
+
 // HasLabel returns a filter that keeps objects that have the specified label key.
 func HasLabel(key string) types.Filter {
 	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
@@ -62,3 +71,24 @@ func Selector(selector string) (types.Filter, error) {
 
 	return f, nil
 }
+
+// PartOf returns a filter that keeps objects whose app.kubernetes.io/part-of label is any of the
+// given applications, to slice the output of charts shipping several applications.
+func PartOf(applications ...string) types.Filter {
+	return oneOf(partOfLabel, applications)
+}
+
+// Instance returns a filter that keeps objects whose app.kubernetes.io/instance label is any of
+// the given instances, e.g. a Helm release name.
+func Instance(instances ...string) types.Filter {
+	return oneOf(instanceLabel, instances)
+}
+
+// oneOf returns a filter that keeps objects whose label value is any of values.
+func oneOf(key string, values []string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		value, ok := obj.GetLabels()[key]
+
+		return ok && slices.Contains(values, value), nil
+	}
+}
@@ -201,6 +201,55 @@ func TestSelector(t *testing.T) {
 	})
 }
 
+func TestPartOf(t *testing.T) {
+
+	t.Run("should keep objects part of any of the applications", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := labels.PartOf("shop", "billing")
+
+		ok, err := filter(t.Context(), makePodWithLabels(map[string]string{
+			"app.kubernetes.io/part-of": "billing",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should exclude objects of other or no applications", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := labels.PartOf("shop")
+
+		ok, err := filter(t.Context(), makePodWithLabels(map[string]string{
+			"app.kubernetes.io/part-of": "billing",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		ok, err = filter(t.Context(), makePodWithLabels(nil))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
+func TestInstance(t *testing.T) {
+
+	t.Run("should keep objects of the instance", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := labels.Instance("shop-prod")
+
+		ok, err := filter(t.Context(), makePodWithLabels(map[string]string{
+			"app.kubernetes.io/instance": "shop-prod",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePodWithLabels(map[string]string{
+			"app.kubernetes.io/instance": "shop-staging",
+		}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
 // Helper functions
 
 func makePodWithLabels(lbls map[string]string) unstructured.Unstructured {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 )
@@ -50,3 +51,16 @@ func Regex(pattern string) (types.Filter, error) {
 
 	return f, nil
 }
+
+// NamespacedName returns a filter that keeps objects matching any of the given namespace and name
+// pairs. Cluster-scoped objects are matched with an empty namespace.
+func NamespacedName(names ...k8stypes.NamespacedName) types.Filter {
+	nameSet := make(map[k8stypes.NamespacedName]bool, len(names))
+	for _, name := range names {
+		nameSet[name] = true
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return nameSet[k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}], nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/name"
 
@@ -119,6 +120,48 @@ func TestRegex(t *testing.T) {
 	})
 }
 
+func TestNamespacedName(t *testing.T) {
+
+	filter := name.NamespacedName(
+		k8stypes.NamespacedName{Namespace: "default", Name: "nginx-pod"},
+		k8stypes.NamespacedName{Name: "cluster-role"},
+	)
+
+	t.Run("should keep objects with matching namespace and name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := makePod("nginx-pod")
+		pod.SetNamespace("default")
+
+		ok, err := filter(t.Context(), pod)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should exclude objects in other namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := makePod("nginx-pod")
+		pod.SetNamespace("kube-system")
+
+		ok, err := filter(t.Context(), pod)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should match cluster-scoped objects with an empty namespace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := filter(t.Context(), makePod("cluster-role"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePod("nginx-pod"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
 // Helper function
 
 func makePod(podName string) unstructured.Unstructured {
@@ -0,0 +1,45 @@
+package owner
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// HasOwner returns a filter that keeps objects with at least one owner reference.
+func HasOwner() types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return len(obj.GetOwnerReferences()) > 0, nil
+	}
+}
+
+// OwnedBy returns a filter that keeps objects with an owner reference to the object of the given
+// kind and name, regardless of its API version. An empty name matches any owner of the kind.
+func OwnedBy(kind string, name string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.Kind == kind && (name == "" || ref.Name == name) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// ControlledBy returns a filter that keeps objects whose controller owner reference is the object
+// of the given kind and name, regardless of its API version. An empty name matches any
+// controller of the kind.
+func ControlledBy(kind string, name string) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		for _, ref := range obj.GetOwnerReferences() {
+			if ref.Controller != nil && *ref.Controller && ref.Kind == kind && (name == "" || ref.Name == name) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
@@ -0,0 +1,108 @@
+package owner_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/owner"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestHasOwner(t *testing.T) {
+
+	t.Run("should keep objects with owner references", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := owner.HasOwner()
+
+		ok, err := filter(t.Context(), makePodWithOwners(ownerRef("ReplicaSet", "app-1234", false)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePodWithOwners())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
+func TestOwnedBy(t *testing.T) {
+
+	t.Run("should keep objects owned by the object", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := owner.OwnedBy("MyApp", "shop")
+
+		ok, err := filter(t.Context(), makePodWithOwners(
+			ownerRef("ConfigMap", "config", false),
+			ownerRef("MyApp", "shop", false),
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+
+	t.Run("should exclude objects owned by other objects", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := owner.OwnedBy("MyApp", "shop")
+
+		ok, err := filter(t.Context(), makePodWithOwners(ownerRef("MyApp", "billing", false)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+
+	t.Run("should match any owner of the kind with an empty name", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := owner.OwnedBy("MyApp", "")
+
+		ok, err := filter(t.Context(), makePodWithOwners(ownerRef("MyApp", "billing", false)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
+
+func TestControlledBy(t *testing.T) {
+
+	t.Run("should only match controller references", func(t *testing.T) {
+		g := NewWithT(t)
+		filter := owner.ControlledBy("MyApp", "shop")
+
+		ok, err := filter(t.Context(), makePodWithOwners(ownerRef("MyApp", "shop", true)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		ok, err = filter(t.Context(), makePodWithOwners(ownerRef("MyApp", "shop", false)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+	})
+}
+
+// Helper functions
+
+func ownerRef(kind string, name string, controller bool) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: "example.com/v1",
+		Kind:       kind,
+		Name:       name,
+		UID:        "uid",
+		Controller: &controller,
+	}
+}
+
+func makePodWithOwners(refs ...metav1.OwnerReference) unstructured.Unstructured {
+	obj := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]any{
+				"name": "test",
+			},
+		},
+	}
+	obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+	if len(refs) > 0 {
+		obj.SetOwnerReferences(refs)
+	}
+
+	return obj
+}
@@ -0,0 +1,103 @@
+package scope
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the scope filters.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple scope options at once.
+type Options struct {
+	// RESTMapper resolves the scope of the kinds it knows, e.g. one backed by a cluster discovery client.
+	RESTMapper meta.RESTMapper
+
+	// CRDs are CustomResourceDefinitions used to resolve the scope of custom kinds.
+	CRDs []unstructured.Unstructured
+}
+
+// ApplyTo applies the scope options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.RESTMapper != nil {
+		target.RESTMapper = opts.RESTMapper
+	}
+
+	target.CRDs = append(target.CRDs, opts.CRDs...)
+}
+
+// WithRESTMapper sets the RESTMapper used to resolve the scope of kinds. Kinds unknown to the
+// mapper fall back to the built-in table and the CRDs.
+// Default: none, only the built-in table of Kubernetes kinds and the CRDs are used.
+func WithRESTMapper(mapper meta.RESTMapper) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.RESTMapper = mapper
+	})
+}
+
+// WithCRDs adds CustomResourceDefinitions used to resolve the scope of custom kinds, for
+// instance the ones rendered alongside the objects.
+func WithCRDs(crds ...unstructured.Unstructured) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.CRDs = append(opts.CRDs, crds...)
+	})
+}
+
+// ClusterScoped returns a filter that keeps cluster-scoped objects, such as Namespaces,
+// ClusterRoles or CustomResourceDefinitions. Kinds of unknown scope are assumed to be namespaced.
+func ClusterScoped(opts ...Option) types.Filter {
+	return scoped(true, opts)
+}
+
+// Namespaced returns a filter that keeps namespaced objects. Kinds of unknown scope are assumed
+// to be namespaced.
+func Namespaced(opts ...Option) types.Filter {
+	return scoped(false, opts)
+}
+
+func scoped(cluster bool, opts []Option) types.Filter {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		clusterScoped, err := isClusterScoped(options, obj)
+		if err != nil {
+			return false, &filter.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return clusterScoped == cluster, nil
+	}
+}
+
+// isClusterScoped resolves the scope of the object kind with the RESTMapper, if any, then with
+// the built-in table and the CRDs.
+func isClusterScoped(options Options, obj unstructured.Unstructured) (bool, error) {
+	gvk := obj.GroupVersionKind()
+
+	if options.RESTMapper != nil {
+		mapping, err := options.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+		switch {
+		case err == nil:
+			return mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+		case !meta.IsNoMatchError(err):
+			return false, fmt.Errorf("unable to resolve the scope of %s: %w", gvk, err)
+		}
+	}
+
+	return k8s.IsClusterScoped(gvk.GroupKind(), options.CRDs...), nil
+}
@@ -0,0 +1,131 @@
+package scope_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/scope"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterScoped(t *testing.T) {
+	ctx := t.Context()
+
+	tests := []struct {
+		name     string
+		object   unstructured.Unstructured
+		opts     []scope.Option
+		expected bool
+	}{
+		{
+			name:     "should keep built-in cluster-scoped kinds",
+			object:   makeObject("rbac.authorization.k8s.io/v1", "ClusterRole", "admin"),
+			expected: true,
+		},
+		{
+			name:     "should exclude built-in namespaced kinds",
+			object:   makeObject("apps/v1", "Deployment", "app"),
+			expected: false,
+		},
+		{
+			name:     "should assume unknown kinds are namespaced",
+			object:   makeObject("example.com/v1", "Widget", "widget"),
+			expected: false,
+		},
+		{
+			name:     "should resolve custom kinds from CRDs",
+			object:   makeObject("example.com/v1", "Widget", "widget"),
+			opts:     []scope.Option{scope.WithCRDs(makeCRD("example.com", "Widget", "Cluster"))},
+			expected: true,
+		},
+		{
+			name:   "should resolve kinds with the RESTMapper",
+			object: makeObject("example.com/v1", "Widget", "widget"),
+			opts: []scope.Option{scope.WithRESTMapper(makeMapper(
+				schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeRoot,
+			))},
+			expected: true,
+		},
+		{
+			name:   "should fall back to the built-in table for kinds unknown to the RESTMapper",
+			object: makeObject("v1", "Namespace", "team"),
+			opts: []scope.Option{scope.WithRESTMapper(makeMapper(
+				schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeRoot,
+			))},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result, err := scope.ClusterScoped(tt.opts...)(ctx, tt.object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(tt.expected))
+
+			result, err = scope.Namespaced(tt.opts...)(ctx, tt.object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(!tt.expected))
+		})
+	}
+
+	t.Run("should return a filter error on RESTMapper failures", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f := scope.ClusterScoped(scope.WithRESTMapper(failingMapper{}))
+
+		_, err := f(ctx, makeObject("v1", "Namespace", "team"))
+		g.Expect(err).To(HaveOccurred())
+
+		var filterErr *filter.Error
+		g.Expect(errors.As(err, &filterErr)).To(BeTrue())
+	})
+}
+
+func makeObject(apiVersion string, kind string, name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+
+	return obj
+}
+
+func makeCRD(group string, kind string, crdScope string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"spec": map[string]any{
+				"group": group,
+				"scope": crdScope,
+				"names": map[string]any{
+					"kind": kind,
+				},
+			},
+		},
+	}
+}
+
+func makeMapper(gvk schema.GroupVersionKind, s meta.RESTScope) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{gvk.GroupVersion()})
+	mapper.Add(gvk, s)
+
+	return mapper
+}
+
+// failingMapper is a RESTMapper failing every mapping with a non "no match" error.
+type failingMapper struct {
+	meta.RESTMapper
+}
+
+func (failingMapper) RESTMapping(_ schema.GroupKind, _ ...string) (*meta.RESTMapping, error) {
+	return nil, errors.New("discovery unavailable")
+}
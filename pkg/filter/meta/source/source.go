@@ -0,0 +1,93 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// Source holds patterns for the source annotations added by renderers when source annotations
+// are enabled. Each field is a glob as supported by path.Match; empty fields match any value,
+// including a missing annotation.
+type Source struct {
+	// Type matches the renderer type, e.g. "helm" or "kustomize".
+	Type string
+
+	// Path matches the source path or chart identifier.
+	Path string
+
+	// File matches the template or manifest file within the source.
+	File string
+}
+
+// Type returns a filter that keeps objects rendered by any of the given renderer types,
+// e.g. "helm" or "kustomize".
+func Type(rendererTypes ...string) types.Filter {
+	return annotation(types.AnnotationSourceType, rendererTypes)
+}
+
+// Path returns a filter that keeps objects rendered from any of the given source paths or
+// chart identifiers.
+func Path(paths ...string) types.Filter {
+	return annotation(types.AnnotationSourcePath, paths)
+}
+
+// File returns a filter that keeps objects rendered from any of the given files.
+func File(files ...string) types.Filter {
+	return annotation(types.AnnotationSourceFile, files)
+}
+
+// Match returns a filter that keeps objects whose source annotations match any of the given
+// patterns, e.g. Source{Type: "helm", File: "templates/*.yaml"}.
+func Match(patterns ...Source) (types.Filter, error) {
+	for _, p := range patterns {
+		for _, field := range []string{p.Type, p.Path, p.File} {
+			if _, err := path.Match(field, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", field, err)
+			}
+		}
+	}
+
+	f := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		objAnnotations := obj.GetAnnotations()
+
+		for _, p := range patterns {
+			if glob(p.Type, objAnnotations[types.AnnotationSourceType]) &&
+				glob(p.Path, objAnnotations[types.AnnotationSourcePath]) &&
+				glob(p.File, objAnnotations[types.AnnotationSourceFile]) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return f, nil
+}
+
+// annotation returns a filter keeping objects whose annotation value is any of values.
+func annotation(key string, values []string) types.Filter {
+	s := sets.New(values...)
+
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		value, ok := obj.GetAnnotations()[key]
+
+		return ok && s.Has(value), nil
+	}
+}
+
+// glob reports whether value matches the validated pattern; empty patterns match any value.
+func glob(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	ok, _ := path.Match(pattern, value)
+
+	return ok
+}
@@ -0,0 +1,122 @@
+package source_test
+
+import (
+	"path"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/source"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFilters(t *testing.T) {
+	ctx := t.Context()
+
+	helmPod := makeObject("helm", "oci://registry.example.com/charts/app", "templates/pod.yaml")
+	helmCRD := makeObject("helm", "oci://registry.example.com/charts/app", "crds/crd.yaml")
+	kustomizeSvc := makeObject("kustomize", "overlays/prod", "")
+	plain := makeObject("", "", "")
+
+	objects := []unstructured.Unstructured{helmPod, helmCRD, kustomizeSvc, plain}
+
+	mustMatch := func(g *WithT, patterns ...source.Source) types.Filter {
+		f, err := source.Match(patterns...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		return f
+	}
+
+	tests := []struct {
+		name     string
+		filter   func(g *WithT) types.Filter
+		expected []bool
+	}{
+		{
+			name:     "should filter by type",
+			filter:   func(*WithT) types.Filter { return source.Type("helm") },
+			expected: []bool{true, true, false, false},
+		},
+		{
+			name:     "should filter by path",
+			filter:   func(*WithT) types.Filter { return source.Path("overlays/prod", "overlays/dev") },
+			expected: []bool{false, false, true, false},
+		},
+		{
+			name:     "should filter by file",
+			filter:   func(*WithT) types.Filter { return source.File("crds/crd.yaml") },
+			expected: []bool{false, true, false, false},
+		},
+		{
+			name: "should match glob patterns",
+			filter: func(g *WithT) types.Filter {
+				return mustMatch(g, source.Source{Type: "helm", File: "templates/*.yaml"})
+			},
+			expected: []bool{true, false, false, false},
+		},
+		{
+			name: "should match any of the patterns",
+			filter: func(g *WithT) types.Filter {
+				return mustMatch(g,
+					source.Source{Path: "oci://registry.example.com/charts/*", File: "crds/*"},
+					source.Source{Type: "kustomize"},
+				)
+			},
+			expected: []bool{false, true, true, false},
+		},
+		{
+			name: "should match any object with an empty pattern",
+			filter: func(g *WithT) types.Filter {
+				return mustMatch(g, source.Source{})
+			},
+			expected: []bool{true, true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			filter := tt.filter(g)
+
+			for i, obj := range objects {
+				result, err := filter(ctx, obj)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(result).To(Equal(tt.expected[i]), "object %d", i)
+			}
+		})
+	}
+
+	t.Run("should reject invalid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := source.Match(source.Source{File: "templates/[pod"})
+		g.Expect(err).To(MatchError(path.ErrBadPattern))
+	})
+}
+
+func makeObject(sourceType string, sourcePath string, sourceFile string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("test")
+
+	annotations := make(map[string]string)
+
+	for key, value := range map[string]string{
+		types.AnnotationSourceType: sourceType,
+		types.AnnotationSourcePath: sourcePath,
+		types.AnnotationSourceFile: sourceFile,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+
+	return obj
+}
@@ -0,0 +1,349 @@
+// Package openapi provides a filter validating objects against Kubernetes OpenAPI schemas and
+// CustomResourceDefinition schemas.
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+var (
+	// ErrSchemaNotFound is returned for objects whose kind has no schema when schemas are required.
+	ErrSchemaNotFound = errors.New("schema not found")
+
+	// ErrInvalidMode is returned by New for unknown modes.
+	ErrInvalidMode = errors.New("invalid mode")
+)
+
+// Mode defines what the filter does with invalid objects.
+type Mode string
+
+const (
+	// ModeFail fails the pipeline with a ValidationError.
+	ModeFail Mode = "Fail"
+
+	// ModeDrop drops invalid objects.
+	ModeDrop Mode = "Drop"
+)
+
+// Violation is a single schema violation of an object.
+type Violation struct {
+	// Field is the path of the offending field, e.g. "spec.replicas".
+	Field string
+
+	// Message describes the violation.
+	Message string
+}
+
+// ValidationError is returned for objects violating their schema.
+type ValidationError struct {
+	// GroupVersionKind is the kind of the object.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Violations are the schema violations of the object.
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+
+	return fmt.Sprintf("invalid %s: %s", e.GroupVersionKind.Kind, strings.Join(messages, "; "))
+}
+
+// Option is a generic option for Validator.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple validator options at once.
+type Options struct {
+	// Documents are OpenAPI v2 or v3 documents holding the schemas of the kinds, such as the
+	// ones served by the API server at /openapi/v2 or /openapi/v3/apis/<group>/<version>.
+	Documents [][]byte
+
+	// CRDs are CustomResourceDefinitions holding the schemas of custom kinds.
+	CRDs []unstructured.Unstructured
+
+	// Mode defines what the filter does with invalid objects.
+	Mode Mode
+
+	// RejectUnknownFields reports fields not declared by the schemas, unless the schema preserves
+	// unknown fields.
+	RejectUnknownFields bool
+
+	// RequireSchema reports objects whose kind has no schema with ErrSchemaNotFound, instead of
+	// keeping them.
+	RequireSchema bool
+
+	// Reporter is called with every invalid object and its violations, in both modes.
+	Reporter func(object unstructured.Unstructured, err *ValidationError)
+}
+
+// ApplyTo applies the validator options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Documents = append(target.Documents, opts.Documents...)
+	target.CRDs = append(target.CRDs, opts.CRDs...)
+	target.RejectUnknownFields = opts.RejectUnknownFields
+	target.RequireSchema = opts.RequireSchema
+
+	if opts.Mode != "" {
+		target.Mode = opts.Mode
+	}
+
+	if opts.Reporter != nil {
+		target.Reporter = opts.Reporter
+	}
+}
+
+// WithDocument adds an OpenAPI v2 or v3 document, in JSON, holding the schemas of the kinds
+// listed by their x-kubernetes-group-version-kind extension. Documents added later take
+// precedence for the same kind.
+func WithDocument(data []byte) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Documents = append(opts.Documents, data)
+	})
+}
+
+// WithCRDs adds CustomResourceDefinitions holding the schemas of custom kinds, for instance the
+// ones rendered alongside the objects. CRD schemas take precedence over documents.
+func WithCRDs(crds ...unstructured.Unstructured) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.CRDs = append(opts.CRDs, crds...)
+	})
+}
+
+// WithMode sets what the filter does with invalid objects.
+// Default: ModeFail.
+func WithMode(mode Mode) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Mode = mode
+	})
+}
+
+// WithRejectUnknownFields enables reporting fields not declared by the schemas, such as typos.
+// Schemas marked with x-kubernetes-preserve-unknown-fields still accept any field.
+// Default: false.
+func WithRejectUnknownFields(enabled bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.RejectUnknownFields = enabled
+	})
+}
+
+// WithRequireSchema enables reporting objects whose kind has no schema with ErrSchemaNotFound.
+// Default: false (objects without schema are kept).
+func WithRequireSchema(enabled bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.RequireSchema = enabled
+	})
+}
+
+// WithReporter sets a function called with every invalid object and its violations, e.g. to
+// collect a report of the objects dropped in ModeDrop.
+func WithReporter(reporter func(object unstructured.Unstructured, err *ValidationError)) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Reporter = reporter
+	})
+}
+
+// Validator validates objects against the schemas of their kind.
+// Schemas are resolved lazily on first use of each kind.
+//
+// Thread-safety: Validator is safe for concurrent use.
+type Validator struct {
+	options  Options
+	resolver resolver
+	sources  map[schema.GroupVersionKind]spec.Schema
+
+	mu      sync.Mutex
+	schemas map[schema.GroupVersionKind]*spec.Schema
+}
+
+// New creates a new Validator with the given options.
+func New(opts ...Option) (*Validator, error) {
+	options := Options{
+		Mode: ModeFail,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.Mode != ModeFail && options.Mode != ModeDrop {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMode, options.Mode)
+	}
+
+	v := Validator{
+		options: options,
+		resolver: resolver{
+			defs:                make(map[string]spec.Schema),
+			rejectUnknownFields: options.RejectUnknownFields,
+		},
+		sources: make(map[schema.GroupVersionKind]spec.Schema),
+		schemas: make(map[schema.GroupVersionKind]*spec.Schema),
+	}
+
+	for _, data := range options.Documents {
+		defs, err := parseDocument(data)
+		if err != nil {
+			return nil, err
+		}
+
+		for gvk, name := range documentKinds(defs) {
+			v.sources[gvk] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/" + name)}}
+		}
+
+		for name, def := range defs {
+			v.resolver.defs[name] = def
+		}
+	}
+
+	for _, crd := range options.CRDs {
+		if crd.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+
+		schemas, err := crdSchemas(crd)
+		if err != nil {
+			return nil, err
+		}
+
+		for gvk, s := range schemas {
+			v.sources[gvk] = s
+		}
+	}
+
+	return &v, nil
+}
+
+// Filter creates a new Validator with the given options and returns its filter.
+func Filter(opts ...Option) (types.Filter, error) {
+	v, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Filter(), nil
+}
+
+// Validate validates the object against the schema of its kind. It returns a *ValidationError
+// for invalid objects, and an error wrapping ErrSchemaNotFound for kinds without schema if
+// schemas are required.
+func (v *Validator) Validate(obj unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+
+	s, err := v.schema(gvk)
+	if err != nil {
+		return err
+	}
+
+	if s == nil {
+		if v.options.RequireSchema {
+			return fmt.Errorf("%w: %s", ErrSchemaNotFound, gvk)
+		}
+
+		return nil
+	}
+
+	result := validate.NewSchemaValidator(s, nil, "", strfmt.Default).Validate(obj.Object)
+	if result.IsValid() {
+		return nil
+	}
+
+	return &ValidationError{
+		GroupVersionKind: gvk,
+		Violations:       violations(result.Errors),
+	}
+}
+
+// Filter returns a filter validating objects: invalid objects are dropped in ModeDrop, or fail
+// the filter with a *ValidationError in ModeFail.
+func (v *Validator) Filter() types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		err := v.Validate(obj)
+
+		var validationErr *ValidationError
+
+		switch {
+		case err == nil:
+			return true, nil
+		case !errors.As(err, &validationErr):
+			return false, &filter.Error{Object: obj, Err: err}
+		}
+
+		if v.options.Reporter != nil {
+			v.options.Reporter(obj, validationErr)
+		}
+
+		if v.options.Mode == ModeDrop {
+			return false, nil
+		}
+
+		return false, &filter.Error{Object: obj, Err: validationErr}
+	}
+}
+
+// schema returns the resolved schema of a kind, or nil if the kind has no schema.
+func (v *Validator) schema(gvk schema.GroupVersionKind) (*spec.Schema, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if s, ok := v.schemas[gvk]; ok {
+		return s, nil
+	}
+
+	source, ok := v.sources[gvk]
+	if !ok {
+		return nil, nil //nolint:nilnil // no schema for the kind
+	}
+
+	s, err := v.resolver.root(source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve schema of %s: %w", gvk, err)
+	}
+
+	v.schemas[gvk] = s
+
+	return s, nil
+}
+
+// violations flattens the validation errors.
+func violations(errs []error) []Violation {
+	result := make([]Violation, 0, len(errs))
+
+	for _, err := range errs {
+		var composite *openapierrors.CompositeError
+		if errors.As(err, &composite) {
+			result = append(result, violations(composite.Errors)...)
+
+			continue
+		}
+
+		violation := Violation{
+			Message: strings.Replace(err.Error(), " in body", "", 1),
+		}
+
+		var validation *openapierrors.Validation
+		if errors.As(err, &validation) {
+			violation.Field = validation.Name
+		}
+
+		result = append(result, violation)
+	}
+
+	return result
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	extensionGVK                   = "x-kubernetes-group-version-kind"
+	extensionIntOrString           = "x-kubernetes-int-or-string"
+	extensionPreserveUnknownFields = "x-kubernetes-preserve-unknown-fields"
+	extensionEmbeddedResource      = "x-kubernetes-embedded-resource"
+	formatIntOrString              = "int-or-string"
+	formatQuantity                 = "quantity"
+
+	// quantityDefinition is the definition of resource quantities, serialized as strings but
+	// accepted as numbers as well.
+	quantityDefinition = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+)
+
+// document holds the schemas of an OpenAPI v2 or v3 document.
+type document struct {
+	Definitions map[string]spec.Schema `json:"definitions"`
+	Components  struct {
+		Schemas map[string]spec.Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// parseDocument returns the named schemas of an OpenAPI v2 (definitions) or v3
+// (components.schemas) document.
+func parseDocument(data []byte) (map[string]spec.Schema, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse OpenAPI document: %w", err)
+	}
+
+	if len(doc.Definitions) > 0 {
+		return doc.Definitions, nil
+	}
+
+	return doc.Components.Schemas, nil
+}
+
+// documentKinds returns the name of the definition of each GroupVersionKind listed in the
+// x-kubernetes-group-version-kind extension of the definitions.
+func documentKinds(defs map[string]spec.Schema) map[schema.GroupVersionKind]string {
+	kinds := make(map[schema.GroupVersionKind]string)
+
+	for name, def := range defs {
+		gvks, ok := def.Extensions[extensionGVK].([]any)
+		if !ok {
+			continue
+		}
+
+		for _, item := range gvks {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			group, _ := m["group"].(string)
+			version, _ := m["version"].(string)
+			kind, _ := m["kind"].(string)
+
+			kinds[schema.GroupVersionKind{Group: group, Version: version, Kind: kind}] = name
+		}
+	}
+
+	return kinds
+}
+
+// crdSchemas returns the schema of every served version of a CustomResourceDefinition.
+func crdSchemas(crd unstructured.Unstructured) (map[schema.GroupVersionKind]spec.Schema, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+
+	// Schema shared by all versions, as in apiextensions.k8s.io/v1beta1
+	legacy, _, _ := unstructured.NestedMap(crd.Object, "spec", "validation", "openAPIV3Schema")
+
+	result := make(map[schema.GroupVersionKind]spec.Schema)
+
+	for _, item := range versions {
+		version, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(version, "name")
+
+		content, found, _ := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if !found {
+			content = legacy
+		}
+
+		if content == nil {
+			continue
+		}
+
+		data, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode schema of %s: %w", crd.GetName(), err)
+		}
+
+		var s spec.Schema
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("unable to parse schema of %s: %w", crd.GetName(), err)
+		}
+
+		result[schema.GroupVersionKind{Group: group, Version: name, Kind: kind}] = s
+	}
+
+	return result, nil
+}
+
+// resolver inlines the references of schemas and adapts the Kubernetes specific extensions to
+// the JSON schema validator.
+type resolver struct {
+	defs                map[string]spec.Schema
+	rejectUnknownFields bool
+}
+
+// root resolves the schema of a kind, implicitly allowing apiVersion, kind and metadata as the
+// API server does.
+func (r *resolver) root(s spec.Schema) (*spec.Schema, error) {
+	resolved, err := r.resolve(s, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resolved.Properties) > 0 {
+		for _, name := range []string{"apiVersion", "kind"} {
+			if _, ok := resolved.Properties[name]; !ok {
+				resolved.Properties[name] = *spec.StringProperty()
+			}
+		}
+
+		if _, ok := resolved.Properties["metadata"]; !ok {
+			resolved.Properties["metadata"] = spec.Schema{}
+		}
+	}
+
+	return &resolved, nil
+}
+
+// resolve returns a copy of the schema with its references inlined. Recursive references are
+// replaced with a schema accepting any value.
+func (r *resolver) resolve(s spec.Schema, visiting map[string]bool) (spec.Schema, error) {
+	if ref := s.Ref.String(); ref != "" {
+		name := ref[strings.LastIndex(ref, "/")+1:]
+
+		if visiting[name] {
+			return spec.Schema{}, nil
+		}
+
+		def, ok := r.defs[name]
+		if !ok {
+			return spec.Schema{}, fmt.Errorf("unresolved schema reference %q", ref)
+		}
+
+		if name == quantityDefinition {
+			def.Format = formatQuantity
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		return r.resolve(def, visiting)
+	}
+
+	var err error
+
+	resolveAll := func(schemas []spec.Schema) []spec.Schema {
+		if schemas == nil || err != nil {
+			return schemas
+		}
+
+		result := make([]spec.Schema, len(schemas))
+		for i := range schemas {
+			if result[i], err = r.resolve(schemas[i], visiting); err != nil {
+				break
+			}
+		}
+
+		return result
+	}
+
+	resolveMap := func(schemas map[string]spec.Schema) map[string]spec.Schema {
+		if schemas == nil || err != nil {
+			return schemas
+		}
+
+		result := make(map[string]spec.Schema, len(schemas))
+		for k, v := range schemas {
+			if result[k], err = r.resolve(v, visiting); err != nil {
+				break
+			}
+		}
+
+		return result
+	}
+
+	resolveOne := func(schema *spec.Schema) *spec.Schema {
+		if schema == nil || err != nil {
+			return schema
+		}
+
+		var resolved spec.Schema
+		resolved, err = r.resolve(*schema, visiting)
+
+		return &resolved
+	}
+
+	s.Properties = resolveMap(s.Properties)
+	s.PatternProperties = resolveMap(s.PatternProperties)
+	s.AllOf = resolveAll(s.AllOf)
+	s.AnyOf = resolveAll(s.AnyOf)
+	s.OneOf = resolveAll(s.OneOf)
+	s.Not = resolveOne(s.Not)
+
+	if s.Items != nil {
+		s.Items = &spec.SchemaOrArray{
+			Schema:  resolveOne(s.Items.Schema),
+			Schemas: resolveAll(s.Items.Schemas),
+		}
+	}
+
+	if s.AdditionalProperties != nil {
+		s.AdditionalProperties = &spec.SchemaOrBool{
+			Allows: s.AdditionalProperties.Allows,
+			Schema: resolveOne(s.AdditionalProperties.Schema),
+		}
+	}
+
+	if err != nil {
+		return spec.Schema{}, err
+	}
+
+	r.adapt(&s)
+
+	return s, nil
+}
+
+// adapt maps the Kubernetes specific schema semantics to plain JSON schema.
+func (r *resolver) adapt(s *spec.Schema) {
+	// Null values are dropped by the API server rather than rejected
+	s.Nullable = true
+
+	// Defaults are not applied and would only bloat the schema
+	s.Default = nil
+
+	intOrString, _ := s.Extensions[extensionIntOrString].(bool)
+
+	switch {
+	case intOrString || s.Format == formatIntOrString:
+		s.Type = spec.StringOrArray{"integer", "string"}
+		s.Format = ""
+	case s.Format == formatQuantity:
+		s.Type = spec.StringOrArray{"number", "string"}
+		s.Format = ""
+	}
+
+	if !r.rejectUnknownFields || len(s.Properties) == 0 || s.AdditionalProperties != nil {
+		return
+	}
+
+	preserve, _ := s.Extensions[extensionPreserveUnknownFields].(bool)
+	embedded, _ := s.Extensions[extensionEmbeddedResource].(bool)
+
+	if !preserve && !embedded {
+		s.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+	}
+}
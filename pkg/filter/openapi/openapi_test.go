@@ -0,0 +1,295 @@
+package openapi_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/openapi"
+
+	. "github.com/onsi/gomega"
+)
+
+// swaggerDocument is a minimal OpenAPI v2 document with the schema of apps/v1 Deployments.
+const swaggerDocument = `{
+  "swagger": "2.0",
+  "definitions": {
+    "io.k8s.api.apps.v1.Deployment": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "spec": {"$ref": "#/definitions/io.k8s.api.apps.v1.DeploymentSpec"}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+    },
+    "io.k8s.api.apps.v1.DeploymentSpec": {
+      "type": "object",
+      "required": ["selector"],
+      "properties": {
+        "replicas": {"type": "integer", "format": "int32"},
+        "selector": {"type": "object"},
+        "maxSurge": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.util.intstr.IntOrString"},
+        "limits": {
+          "type": "object",
+          "additionalProperties": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.api.resource.Quantity"}
+        },
+        "template": {"$ref": "#/definitions/io.k8s.api.core.v1.PodTemplateSpec"}
+      }
+    },
+    "io.k8s.api.core.v1.PodTemplateSpec": {
+      "type": "object",
+      "properties": {
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "template": {"$ref": "#/definitions/io.k8s.api.core.v1.PodTemplateSpec"}
+      }
+    },
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "namespace": {"type": "string"},
+        "creationTimestamp": {"type": "string", "format": "date-time"},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}}
+      }
+    },
+    "io.k8s.apimachinery.pkg.util.intstr.IntOrString": {"type": "string", "format": "int-or-string"},
+    "io.k8s.apimachinery.pkg.api.resource.Quantity": {"type": "string"}
+  }
+}`
+
+func makeDeployment(spec map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":              "app",
+				"creationTimestamp": nil,
+				"labels":            map[string]any{"app": "test"},
+			},
+			"spec": spec,
+		},
+	}
+}
+
+func makeWidget(size any) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]any{"name": "widget"},
+			"spec":       map[string]any{"size": size},
+		},
+	}
+}
+
+func makeCRD() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]any{"name": "widgets.example.com"},
+			"spec": map[string]any{
+				"group": "example.com",
+				"names": map[string]any{"kind": "Widget"},
+				"versions": []any{
+					map[string]any{
+						"name": "v1",
+						"schema": map[string]any{
+							"openAPIV3Schema": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"spec": map[string]any{
+										"type": "object",
+										"properties": map[string]any{
+											"size": map[string]any{
+												"type":    "integer",
+												"minimum": int64(1),
+											},
+										},
+									},
+									"status": map[string]any{
+										"type":                                 "object",
+										"x-kubernetes-preserve-unknown-fields": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validationError(g *WithT, err error) *openapi.ValidationError {
+	var validationErr *openapi.ValidationError
+	g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+
+	return validationErr
+}
+
+func TestValidate(t *testing.T) {
+	validSpec := func() map[string]any {
+		return map[string]any{
+			"replicas": int64(3),
+			"selector": map[string]any{},
+			"maxSurge": "25%",
+			"limits":   map[string]any{"cpu": "500m", "memory": int64(1024)},
+		}
+	}
+
+	t.Run("should accept valid objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v, err := openapi.New(openapi.WithDocument([]byte(swaggerDocument)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(v.Validate(makeDeployment(validSpec()))).To(Succeed())
+
+		spec := validSpec()
+		spec["maxSurge"] = int64(1)
+		g.Expect(v.Validate(makeDeployment(spec))).To(Succeed())
+	})
+
+	t.Run("should report violations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v, err := openapi.New(openapi.WithDocument([]byte(swaggerDocument)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		spec := validSpec()
+		spec["replicas"] = "three"
+		delete(spec, "selector")
+
+		validationErr := validationError(g, v.Validate(makeDeployment(spec)))
+		g.Expect(validationErr.GroupVersionKind.Kind).To(Equal("Deployment"))
+		g.Expect(validationErr.Violations).To(ConsistOf(
+			HaveField("Field", "spec.replicas"),
+			HaveField("Field", "spec.selector"),
+		))
+		g.Expect(validationErr.Error()).To(ContainSubstring("spec.replicas must be of type integer"))
+	})
+
+	t.Run("should reject unknown fields if enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		spec := validSpec()
+		spec["replica"] = int64(3)
+
+		v, err := openapi.New(openapi.WithDocument([]byte(swaggerDocument)))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.Validate(makeDeployment(spec))).To(Succeed())
+
+		v, err = openapi.New(
+			openapi.WithDocument([]byte(swaggerDocument)),
+			openapi.WithRejectUnknownFields(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		validationErr := validationError(g, v.Validate(makeDeployment(spec)))
+		g.Expect(validationErr.Violations).To(ConsistOf(
+			HaveField("Message", ContainSubstring("spec.replica is a forbidden property")),
+		))
+	})
+
+	t.Run("should validate custom resources with CRD schemas", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v, err := openapi.New(
+			openapi.WithCRDs(makeCRD()),
+			openapi.WithRejectUnknownFields(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		widget := makeWidget(int64(3))
+		widget.Object["status"] = map[string]any{"anything": true}
+		g.Expect(v.Validate(widget)).To(Succeed())
+
+		validationErr := validationError(g, v.Validate(makeWidget(int64(0))))
+		g.Expect(validationErr.Violations).To(ConsistOf(
+			HaveField("Field", "spec.size"),
+		))
+	})
+
+	t.Run("should keep kinds without schema unless required", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v, err := openapi.New(openapi.WithDocument([]byte(swaggerDocument)))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.Validate(makeWidget("large"))).To(Succeed())
+
+		v, err = openapi.New(
+			openapi.WithDocument([]byte(swaggerDocument)),
+			openapi.WithRequireSchema(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(v.Validate(makeWidget("large"))).To(MatchError(openapi.ErrSchemaNotFound))
+	})
+
+	t.Run("should fail on invalid documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := openapi.New(openapi.WithDocument([]byte("{")))
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestFilter(t *testing.T) {
+	valid := makeWidget(int64(3))
+	invalid := makeWidget("large")
+
+	t.Run("should fail on invalid objects by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		f, err := openapi.Filter(openapi.WithCRDs(makeCRD()))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ok, err := f(t.Context(), valid)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ok).To(BeTrue())
+
+		_, err = f(t.Context(), invalid)
+
+		var filterErr *filter.Error
+		g.Expect(errors.As(err, &filterErr)).To(BeTrue())
+		g.Expect(filterErr.Object.GetName()).To(Equal("widget"))
+		g.Expect(validationError(g, err).Violations).To(HaveLen(1))
+	})
+
+	t.Run("should drop and report invalid objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var reported []*openapi.ValidationError
+
+		f, err := openapi.Filter(
+			openapi.WithCRDs(makeCRD()),
+			openapi.WithMode(openapi.ModeDrop),
+			openapi.WithReporter(func(_ unstructured.Unstructured, err *openapi.ValidationError) {
+				reported = append(reported, err)
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ok, err := f(t.Context(), valid)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ok).To(BeTrue())
+
+		ok, err = f(t.Context(), invalid)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(ok).To(BeFalse())
+		g.Expect(reported).To(HaveLen(1))
+		g.Expect(reported[0].Violations[0].Field).To(Equal("spec.size"))
+	})
+
+	t.Run("should reject unknown modes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := openapi.Filter(openapi.WithMode("Warn"))
+		g.Expect(err).To(MatchError(openapi.ErrInvalidMode))
+	})
+}
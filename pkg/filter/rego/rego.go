@@ -0,0 +1,379 @@
+// Package rego provides a filter evaluating Open Policy Agent Rego policies against objects, so
+// that existing conftest and Gatekeeper policies can be reused in pipelines.
+package rego
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/loader"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+const (
+	// DefaultPackage is the package holding the rules, as used by conftest.
+	DefaultPackage = "main"
+)
+
+var (
+	// ErrNoPolicies is returned by New when neither modules nor bundles are configured.
+	ErrNoPolicies = errors.New("no policies configured")
+
+	// ErrInvalidMode is returned by New for unknown modes.
+	ErrInvalidMode = errors.New("invalid mode")
+
+	// ErrInvalidPackage is returned when the configured package is a value rather than a package.
+	ErrInvalidPackage = errors.New("invalid package")
+)
+
+// Mode defines what the filter does with denied objects.
+type Mode string
+
+const (
+	// ModeFail fails the pipeline with a PolicyError.
+	ModeFail Mode = "Fail"
+
+	// ModeDrop drops denied objects.
+	ModeDrop Mode = "Drop"
+)
+
+// PolicyError is returned for objects denied by the policies.
+type PolicyError struct {
+	// Messages are the messages of the deny and violation rules matching the object.
+	Messages []string
+}
+
+func (e *PolicyError) Error() string {
+	return "denied by policy: " + strings.Join(e.Messages, "; ")
+}
+
+// Bundle is a policy bundle, i.e. Rego modules with optional data.json/data.yaml documents and
+// .manifest file, loaded from a filesystem.
+type Bundle struct {
+	// FS is the filesystem holding the bundle.
+	FS fs.FS
+
+	// Path is the directory of the bundle within FS, "." for the root.
+	Path string
+}
+
+// Option is a generic option for Evaluator.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple evaluator options at once.
+type Options struct {
+	// Modules are Rego modules keyed by file name.
+	Modules map[string]string
+
+	// Bundles are policy bundles loaded from filesystems.
+	Bundles []Bundle
+
+	// Package is the package holding the deny, violation and allow rules.
+	Package string
+
+	// V0Compatible parses the policies with the pre-1.0 Rego syntax, as used by most existing
+	// Gatekeeper and conftest policies.
+	V0Compatible bool
+
+	// Input builds the input document of the policies from an object.
+	Input func(obj unstructured.Unstructured) any
+
+	// Mode defines what the filter does with denied objects.
+	Mode Mode
+
+	// Reporter is called with every denied object and the policy messages, in both modes.
+	Reporter func(object unstructured.Unstructured, err *PolicyError)
+}
+
+// ApplyTo applies the evaluator options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if len(opts.Modules) > 0 {
+		if target.Modules == nil {
+			target.Modules = make(map[string]string, len(opts.Modules))
+		}
+
+		maps.Copy(target.Modules, opts.Modules)
+	}
+
+	target.Bundles = append(target.Bundles, opts.Bundles...)
+	target.V0Compatible = opts.V0Compatible
+
+	if opts.Package != "" {
+		target.Package = opts.Package
+	}
+
+	if opts.Input != nil {
+		target.Input = opts.Input
+	}
+
+	if opts.Mode != "" {
+		target.Mode = opts.Mode
+	}
+
+	if opts.Reporter != nil {
+		target.Reporter = opts.Reporter
+	}
+}
+
+// WithModule adds a Rego module with the given file name and source.
+func WithModule(name string, source string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		if opts.Modules == nil {
+			opts.Modules = make(map[string]string)
+		}
+
+		opts.Modules[name] = source
+	})
+}
+
+// WithBundle adds a policy bundle loaded from the given directory of the filesystem, e.g.
+// os.DirFS("policy") and ".". Bundles loaded together must declare disjoint roots in their
+// .manifest file.
+func WithBundle(fsys fs.FS, path string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Bundles = append(opts.Bundles, Bundle{FS: fsys, Path: path})
+	})
+}
+
+// WithPackage sets the package holding the deny, violation and allow rules.
+// Default: DefaultPackage.
+func WithPackage(name string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Package = name
+	})
+}
+
+// WithV0Compatible enables parsing the policies with the pre-1.0 Rego syntax.
+// Default: false.
+func WithV0Compatible(enabled bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.V0Compatible = enabled
+	})
+}
+
+// WithInput sets the function building the input document of the policies from an object, e.g.
+// ReviewInput for Gatekeeper policies.
+// Default: the object itself, as used by conftest.
+func WithInput(input func(obj unstructured.Unstructured) any) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Input = input
+	})
+}
+
+// WithMode sets what the filter does with denied objects.
+// Default: ModeFail.
+func WithMode(mode Mode) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Mode = mode
+	})
+}
+
+// WithReporter sets a function called with every denied object and the policy messages, e.g. to
+// collect a report of the objects dropped in ModeDrop.
+func WithReporter(reporter func(object unstructured.Unstructured, err *PolicyError)) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Reporter = reporter
+	})
+}
+
+// ObjectInput returns the object as input document, as used by conftest.
+func ObjectInput(obj unstructured.Unstructured) any {
+	return obj.Object
+}
+
+// ReviewInput returns an admission review shaped input document holding the object under
+// review.object, as used by Gatekeeper constraint templates.
+func ReviewInput(obj unstructured.Unstructured) any {
+	gvk := obj.GroupVersionKind()
+
+	return map[string]any{
+		"review": map[string]any{
+			"kind": map[string]any{
+				"group":   gvk.Group,
+				"version": gvk.Version,
+				"kind":    gvk.Kind,
+			},
+			"name":      obj.GetName(),
+			"namespace": obj.GetNamespace(),
+			"operation": "CREATE",
+			"object":    obj.Object,
+		},
+	}
+}
+
+// Evaluator evaluates Rego policies against objects. An object is denied when the deny or
+// violation rules of the package produce messages for it, or when the package defines an allow
+// rule that is not true for it.
+//
+// Thread-safety: Evaluator is safe for concurrent use.
+type Evaluator struct {
+	options Options
+	query   rego.PreparedEvalQuery
+}
+
+// New creates a new Evaluator with the given options, compiling the policies.
+func New(opts ...Option) (*Evaluator, error) {
+	options := Options{
+		Package: DefaultPackage,
+		Input:   ObjectInput,
+		Mode:    ModeFail,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.Mode != ModeFail && options.Mode != ModeDrop {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMode, options.Mode)
+	}
+
+	if len(options.Modules) == 0 && len(options.Bundles) == 0 {
+		return nil, ErrNoPolicies
+	}
+
+	version := ast.RegoV1
+	if options.V0Compatible {
+		version = ast.RegoV0
+	}
+
+	regoOpts := []func(*rego.Rego){
+		rego.Query("data." + options.Package),
+		rego.SetRegoVersion(version),
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(options.Modules)) {
+		regoOpts = append(regoOpts, rego.Module(name, options.Modules[name]))
+	}
+
+	for i, b := range options.Bundles {
+		bundle, err := loader.NewFileLoader().
+			WithFS(b.FS).
+			WithRegoVersion(version).
+			AsBundle(b.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policy bundle %q: %w", b.Path, err)
+		}
+
+		regoOpts = append(regoOpts, rego.ParsedBundle(fmt.Sprintf("bundle-%d", i), bundle))
+	}
+
+	//nolint:contextcheck // policies are compiled once, independently of the callers
+	query, err := rego.New(regoOpts...).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile policies: %w", err)
+	}
+
+	return &Evaluator{
+		options: options,
+		query:   query,
+	}, nil
+}
+
+// Filter creates a new Evaluator with the given options and returns its filter.
+func Filter(opts ...Option) (types.Filter, error) {
+	e, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.Filter(), nil
+}
+
+// Evaluate evaluates the policies against the object. It returns a *PolicyError for denied
+// objects.
+func (e *Evaluator) Evaluate(ctx context.Context, obj unstructured.Unstructured) error {
+	rs, err := e.query.Eval(ctx, rego.EvalInput(e.options.Input(obj)))
+	if err != nil {
+		return fmt.Errorf("failed to evaluate policies: %w", err)
+	}
+
+	// An undefined package has no rules, hence nothing to deny
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil
+	}
+
+	document, ok := rs[0].Expressions[0].Value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: data.%s is not a package", ErrInvalidPackage, e.options.Package)
+	}
+
+	messages := append(ruleMessages(document["deny"]), ruleMessages(document["violation"])...)
+
+	if allow, ok := document["allow"]; ok && allow != true {
+		messages = append(messages, fmt.Sprintf("not allowed by data.%s.allow", e.options.Package))
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return &PolicyError{Messages: messages}
+}
+
+// Filter returns a filter evaluating the policies: denied objects are dropped in ModeDrop, or
+// fail the filter with a *PolicyError in ModeFail.
+func (e *Evaluator) Filter() types.Filter {
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		err := e.Evaluate(ctx, obj)
+
+		var policyErr *PolicyError
+
+		switch {
+		case err == nil:
+			return true, nil
+		case !errors.As(err, &policyErr):
+			return false, &filter.Error{Object: obj, Err: err}
+		}
+
+		if e.options.Reporter != nil {
+			e.options.Reporter(obj, policyErr)
+		}
+
+		if e.options.Mode == ModeDrop {
+			return false, nil
+		}
+
+		return false, &filter.Error{Object: obj, Err: policyErr}
+	}
+}
+
+// ruleMessages returns the messages of a deny or violation rule. Messages are either strings,
+// as in conftest, or objects with a msg field, as in Gatekeeper.
+func ruleMessages(rule any) []string {
+	values, ok := rule.([]any)
+	if !ok {
+		return nil
+	}
+
+	messages := make([]string, 0, len(values))
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			messages = append(messages, v)
+		case map[string]any:
+			if msg, ok := v["msg"].(string); ok {
+				messages = append(messages, msg)
+
+				continue
+			}
+
+			messages = append(messages, fmt.Sprint(v))
+		default:
+			messages = append(messages, fmt.Sprint(v))
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,211 @@
+package rego_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/rego"
+
+	. "github.com/onsi/gomega"
+)
+
+const denyLatestPolicy = `
+package main
+
+deny contains msg if {
+	some container in input.spec.template.spec.containers
+	endswith(container.image, ":latest")
+	msg := sprintf("container %s uses the latest tag", [container.name])
+}
+`
+
+const gatekeeperPolicy = `
+package k8srequiredlabels
+
+violation[{"msg": msg}] {
+	provided := {label | input.review.object.metadata.labels[label]}
+	required := {label | label := data.required[_]}
+	missing := required - provided
+	count(missing) > 0
+	msg := sprintf("missing labels: %v", [missing])
+}
+`
+
+func makeDeployment(name string, image string, labels map[string]string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": "default",
+			},
+			"spec": map[string]any{
+				"replicas": int64(2),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "app", "image": image},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	obj.SetLabels(labels)
+
+	return obj
+}
+
+func TestFilter(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should keep objects without deny messages", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter, err := rego.Filter(rego.WithModule("policy.rego", denyLatestPolicy))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("app", "nginx:1.25", nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+	})
+
+	t.Run("should fail on denied objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter, err := rego.Filter(rego.WithModule("policy.rego", denyLatestPolicy))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("app", "nginx:latest", nil))
+		g.Expect(result).To(BeFalse())
+
+		var policyErr *rego.PolicyError
+		g.Expect(errors.As(err, &policyErr)).To(BeTrue())
+		g.Expect(policyErr.Messages).To(ConsistOf("container app uses the latest tag"))
+	})
+
+	t.Run("should drop denied objects and report them", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var reported []string
+
+		filter, err := rego.Filter(
+			rego.WithModule("policy.rego", denyLatestPolicy),
+			rego.WithMode(rego.ModeDrop),
+			rego.WithReporter(func(obj unstructured.Unstructured, err *rego.PolicyError) {
+				reported = append(reported, obj.GetName())
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("latest", "nginx:latest", nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeFalse())
+
+		result, err = filter(ctx, makeDeployment("pinned", "nginx:1.25", nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		g.Expect(reported).To(Equal([]string{"latest"}))
+	})
+
+	t.Run("should evaluate allow rules", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter, err := rego.Filter(
+			rego.WithPackage("kubernetes.admission"),
+			rego.WithModule("allow.rego", `
+package kubernetes.admission
+
+default allow := false
+
+allow if input.spec.replicas > 1
+`),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("app", "nginx:1.25", nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		single := makeDeployment("single", "nginx:1.25", nil)
+		g.Expect(unstructured.SetNestedField(single.Object, int64(1), "spec", "replicas")).To(Succeed())
+
+		result, err = filter(ctx, single)
+		g.Expect(err).To(MatchError(ContainSubstring("not allowed by data.kubernetes.admission.allow")))
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should keep objects when the package is undefined", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter, err := rego.Filter(
+			rego.WithModule("policy.rego", denyLatestPolicy),
+			rego.WithPackage("other"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("app", "nginx:latest", nil))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+	})
+
+	t.Run("should load Gatekeeper bundles from a filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fsys := fstest.MapFS{
+			"policy/requiredlabels.rego": {Data: []byte(gatekeeperPolicy)},
+			"policy/data.json":           {Data: []byte(`{"required": ["team"]}`)},
+		}
+
+		filter, err := rego.Filter(
+			rego.WithBundle(fsys, "policy"),
+			rego.WithPackage("k8srequiredlabels"),
+			rego.WithV0Compatible(true),
+			rego.WithInput(rego.ReviewInput),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := filter(ctx, makeDeployment("labeled", "nginx:1.25", map[string]string{"team": "platform"}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(BeTrue())
+
+		result, err = filter(ctx, makeDeployment("unlabeled", "nginx:1.25", nil))
+		g.Expect(err).To(MatchError(ContainSubstring(`missing labels: {"team"}`)))
+		g.Expect(result).To(BeFalse())
+	})
+
+	t.Run("should reject invalid configurations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := rego.Filter()
+		g.Expect(err).To(MatchError(rego.ErrNoPolicies))
+
+		_, err = rego.Filter(
+			rego.WithModule("policy.rego", denyLatestPolicy),
+			rego.WithMode("Warn"),
+		)
+		g.Expect(err).To(MatchError(rego.ErrInvalidMode))
+
+		_, err = rego.Filter(rego.WithModule("policy.rego", "package main\n\ndeny contains msg if {"))
+		g.Expect(err).To(MatchError(ContainSubstring("failed to compile policies")))
+	})
+
+	t.Run("should reject rule values as package", func(t *testing.T) {
+		g := NewWithT(t)
+
+		filter, err := rego.Filter(
+			rego.WithModule("policy.rego", denyLatestPolicy),
+			rego.WithPackage("main.deny"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = filter(ctx, makeDeployment("app", "nginx:latest", nil))
+		g.Expect(err).To(MatchError(rego.ErrInvalidPackage))
+	})
+}
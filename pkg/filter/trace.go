@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// Evaluation is the result of a named filter for an object, along with the named filters
+// evaluated while computing it.
+type Evaluation struct {
+	// Name is the name of the filter, as given to Named.
+	Name string
+
+	// Result is the result of the filter.
+	Result bool
+
+	// Err is the error returned by the filter, if any.
+	Err error
+
+	// Children are the named filters evaluated within this filter, in evaluation order.
+	Children []*Evaluation
+}
+
+// Rejection describes why a traced filter rejected an object.
+type Rejection struct {
+	// Object is the rejected object.
+	Object unstructured.Unstructured
+
+	// Path holds the names of the filters that rejected the object, from the outermost to the
+	// innermost one: starting from the top, it follows at each level the last named filter that
+	// returned false. It is empty if no named filter returned false, e.g. when a Not inverted
+	// the result of a named filter.
+	Path []string
+
+	// Evaluations are the outermost named filters evaluated for the object.
+	Evaluations []*Evaluation
+}
+
+// RejectedBy returns the name of the innermost filter that rejected the object, or an empty
+// string if no named filter returned false.
+func (r Rejection) RejectedBy() string {
+	if len(r.Path) == 0 {
+		return ""
+	}
+
+	return r.Path[len(r.Path)-1]
+}
+
+// evaluationKey is the context key of the evaluation being traced.
+type evaluationKey struct{}
+
+// Named wraps a filter with a name, recording its result when evaluated within a traced filter.
+// Outside of Trace, the filter is called unchanged.
+func Named(name string, filter types.Filter) types.Filter {
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		parent, ok := ctx.Value(evaluationKey{}).(*Evaluation)
+		if !ok {
+			return filter(ctx, obj)
+		}
+
+		evaluation := &Evaluation{Name: name}
+		parent.Children = append(parent.Children, evaluation)
+
+		result, err := filter(context.WithValue(ctx, evaluationKey{}, evaluation), obj)
+
+		evaluation.Result = result
+		evaluation.Err = err
+
+		return result, err
+	}
+}
+
+// Trace wraps a filter so that every object it rejects is reported to fn, along with the
+// evaluation of the named filters of the filter tree. Objects are rejected when the filter
+// returns false; errors are returned unchanged and not reported.
+//
+// Example:
+//
+//	diagnostics := &filter.Diagnostics{}
+//
+//	f := filter.Trace(
+//	    filter.And(
+//	        filter.Named("production", namespace.Filter("production")),
+//	        filter.Named("deployments", gvk.Kind("Deployment")),
+//	    ),
+//	    diagnostics.Record,
+//	)
+func Trace(filter types.Filter, fn func(rejection Rejection)) types.Filter {
+	return func(ctx context.Context, obj unstructured.Unstructured) (bool, error) {
+		root := &Evaluation{}
+
+		result, err := filter(context.WithValue(ctx, evaluationKey{}, root), obj)
+		if err != nil || result {
+			return result, err
+		}
+
+		fn(Rejection{
+			Object:      obj,
+			Path:        rejectionPath(root),
+			Evaluations: root.Children,
+		})
+
+		return false, nil
+	}
+}
+
+// rejectionPath follows the last named filter that returned false at each level.
+func rejectionPath(evaluation *Evaluation) []string {
+	path := make([]string, 0)
+
+	for {
+		var rejecting *Evaluation
+
+		for _, child := range evaluation.Children {
+			if !child.Result && child.Err == nil {
+				rejecting = child
+			}
+		}
+
+		if rejecting == nil {
+			return path
+		}
+
+		path = append(path, rejecting.Name)
+		evaluation = rejecting
+	}
+}
+
+// Diagnostics collects the rejections reported by traced filters.
+//
+// Thread-safety: Diagnostics is safe for concurrent use.
+type Diagnostics struct {
+	mu         sync.Mutex
+	rejections []Rejection
+}
+
+// Record adds a rejection; it can be passed to Trace as the rejection callback.
+func (d *Diagnostics) Record(rejection Rejection) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rejections = append(d.rejections, rejection)
+}
+
+// Rejections returns the recorded rejections.
+func (d *Diagnostics) Rejections() []Rejection {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Rejection(nil), d.rejections...)
+}
@@ -0,0 +1,125 @@
+package filter_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTrace(t *testing.T) {
+	isService := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return obj.GetKind() == "Service", nil
+	}
+
+	isApp := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		return obj.GetName() == "app", nil
+	}
+
+	t.Run("should report the leaf filter rejecting the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diagnostics := &filter.Diagnostics{}
+
+		f := filter.Trace(
+			filter.And(
+				filter.Named("service", isService),
+				filter.Named("app", isApp),
+			),
+			diagnostics.Record,
+		)
+
+		for _, obj := range []unstructured.Unstructured{
+			makeObject("Service", "app"),
+			makeObject("Service", "other"),
+			makeObject("Deployment", "app"),
+		} {
+			_, err := f(t.Context(), obj)
+			g.Expect(err).ShouldNot(HaveOccurred())
+		}
+
+		rejections := diagnostics.Rejections()
+		g.Expect(rejections).Should(HaveLen(2))
+
+		g.Expect(rejections[0].Object.GetName()).Should(Equal("other"))
+		g.Expect(rejections[0].RejectedBy()).Should(Equal("app"))
+		g.Expect(rejections[0].Evaluations).Should(HaveLen(2))
+
+		g.Expect(rejections[1].Object.GetKind()).Should(Equal("Deployment"))
+		g.Expect(rejections[1].RejectedBy()).Should(Equal("service"))
+		g.Expect(rejections[1].Evaluations).Should(HaveLen(1))
+	})
+
+	t.Run("should report the path of nested filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var rejection filter.Rejection
+
+		f := filter.Trace(
+			filter.Named("root", filter.Or(
+				filter.Named("deployment", alwaysFalse()),
+				filter.Named("service-app", filter.And(
+					filter.Named("service", isService),
+					filter.Named("app", isApp),
+				)),
+			)),
+			func(r filter.Rejection) {
+				rejection = r
+			},
+		)
+
+		ok, err := f(t.Context(), makeObject("Service", "other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		g.Expect(rejection.Path).Should(Equal([]string{"root", "service-app", "app"}))
+		g.Expect(rejection.Evaluations).Should(HaveLen(1))
+		g.Expect(rejection.Evaluations[0].Children).Should(HaveLen(2))
+		g.Expect(rejection.Evaluations[0].Children[1].Children[0].Result).Should(BeTrue())
+	})
+
+	t.Run("should report an empty path for inverted filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diagnostics := &filter.Diagnostics{}
+
+		f := filter.Trace(filter.Not(filter.Named("service", isService)), diagnostics.Record)
+
+		ok, err := f(t.Context(), makeObject("Service", "app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeFalse())
+
+		rejections := diagnostics.Rejections()
+		g.Expect(rejections).Should(HaveLen(1))
+		g.Expect(rejections[0].Path).Should(BeEmpty())
+		g.Expect(rejections[0].RejectedBy()).Should(BeEmpty())
+		g.Expect(rejections[0].Evaluations[0].Result).Should(BeTrue())
+	})
+
+	t.Run("should not report accepted objects and errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		diagnostics := &filter.Diagnostics{}
+
+		ok, err := filter.Trace(filter.Named("true", alwaysTrue()), diagnostics.Record)(t.Context(), makePod("test"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+
+		_, err = filter.Trace(filter.Named("error", alwaysError()), diagnostics.Record)(t.Context(), makePod("test"))
+		g.Expect(err).Should(HaveOccurred())
+
+		g.Expect(diagnostics.Rejections()).Should(BeEmpty())
+	})
+
+	t.Run("should call named filters unchanged outside of a trace", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ok, err := filter.Named("service", isService)(t.Context(), makeObject("Service", "app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ok).Should(BeTrue())
+	})
+}
@@ -0,0 +1,180 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// ErrCycle is returned when objects depend on each other, so no apply order exists.
+var ErrCycle = errors.New("dependency cycle")
+
+// Resolver reports whether object depends on dependency, i.e. dependency has to be applied first.
+type Resolver func(object unstructured.Unstructured, dependency unstructured.Unstructured) bool
+
+// Option is a generic option for the dependency graph.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple dependency graph options at once.
+type Options struct {
+	// Resolvers infer the dependencies between objects, in addition to the built-in ones.
+	Resolvers []Resolver
+}
+
+// ApplyTo applies the dependency graph options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Resolvers = append(target.Resolvers, opts.Resolvers...)
+}
+
+// WithResolvers adds resolvers inferring dependencies the built-in resolvers don't know about,
+// e.g. between custom resources.
+// Default: only the built-in resolvers are used.
+func WithResolvers(resolvers ...Resolver) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Resolvers = append(opts.Resolvers, resolvers...)
+	})
+}
+
+// Graph is the dependency graph of a set of objects. Objects are identified by their index in
+// the set the graph is built from.
+type Graph struct {
+	objects      []unstructured.Unstructured
+	dependencies [][]int
+}
+
+// New builds the dependency graph of objects. Dependencies are inferred by the built-in
+// resolvers, Namespace, CustomResourceDefinition, PodReferences, RBAC and Webhook, and by the
+// ones added with WithResolvers. Every pair of objects is checked, so building the graph is
+// quadratic in the number of objects.
+func New(objects []unstructured.Unstructured, opts ...Option) *Graph {
+	options := Options{
+		Resolvers: []Resolver{Namespace, CustomResourceDefinition, PodReferences, RBAC, Webhook},
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	g := Graph{
+		objects:      objects,
+		dependencies: make([][]int, len(objects)),
+	}
+
+	for i := range objects {
+		for j := range objects {
+			if i == j {
+				continue
+			}
+
+			for _, resolve := range options.Resolvers {
+				if resolve(objects[i], objects[j]) {
+					g.dependencies[i] = append(g.dependencies[i], j)
+
+					break
+				}
+			}
+		}
+	}
+
+	return &g
+}
+
+// Objects returns the objects of the graph.
+func (g *Graph) Objects() []unstructured.Unstructured {
+	return g.objects
+}
+
+// Dependencies returns the indexes of the objects the object at index i depends on.
+func (g *Graph) Dependencies(i int) []int {
+	return g.dependencies[i]
+}
+
+// Waves groups the objects into waves that can be applied in sequence: every object depends only
+// on objects of previous waves. Objects of a wave keep their relative order.
+// Returns ErrCycle if some objects depend on each other.
+func (g *Graph) Waves() ([][]unstructured.Unstructured, error) {
+	wave := make([]int, len(g.objects))
+	for i := range wave {
+		wave[i] = -1
+	}
+
+	var waves [][]unstructured.Unstructured
+
+	for placed := 0; placed < len(g.objects); {
+		var current []int
+
+		for i := range g.objects {
+			if wave[i] < 0 && g.ready(i, wave) {
+				current = append(current, i)
+			}
+		}
+
+		if len(current) == 0 {
+			return nil, g.cycleError(wave)
+		}
+
+		objects := make([]unstructured.Unstructured, 0, len(current))
+
+		for _, i := range current {
+			wave[i] = len(waves)
+			objects = append(objects, g.objects[i])
+		}
+
+		waves = append(waves, objects)
+		placed += len(current)
+	}
+
+	return waves, nil
+}
+
+// Sorted returns the objects in an order that can be applied in sequence, every object after its
+// dependencies. It is the concatenation of the Waves.
+// Returns ErrCycle if some objects depend on each other.
+func (g *Graph) Sorted() ([]unstructured.Unstructured, error) {
+	waves, err := g.Waves()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]unstructured.Unstructured, 0, len(g.objects))
+	for _, w := range waves {
+		result = append(result, w...)
+	}
+
+	return result, nil
+}
+
+// ready reports whether all dependencies of the object at index i are placed in a wave.
+func (g *Graph) ready(i int, wave []int) bool {
+	for _, j := range g.dependencies[i] {
+		if wave[j] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cycleError returns an ErrCycle listing the objects that could not be placed in a wave.
+func (g *Graph) cycleError(wave []int) error {
+	var names []string
+
+	for i, obj := range g.objects {
+		if wave[i] >= 0 {
+			continue
+		}
+
+		name := obj.GetName()
+		if obj.GetNamespace() != "" {
+			name = obj.GetNamespace() + "/" + name
+		}
+
+		names = append(names, obj.GetKind()+" "+name)
+	}
+
+	return fmt.Errorf("%w: %s", ErrCycle, strings.Join(names, ", "))
+}
@@ -0,0 +1,269 @@
+package graph
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const (
+	// injectCAFromAnnotation references the cert-manager Certificate whose CA is injected into
+	// webhook configurations, APIServices and CRDs, as namespace/name.
+	injectCAFromAnnotation = "cert-manager.io/inject-ca-from"
+
+	// injectCAFromSecretAnnotation references the Secret whose CA is injected, as namespace/name.
+	injectCAFromSecretAnnotation = "cert-manager.io/inject-ca-from-secret"
+)
+
+var (
+	namespaceGK          = schema.GroupKind{Kind: "Namespace"}
+	serviceAccountGK     = schema.GroupKind{Kind: "ServiceAccount"}
+	secretGK             = schema.GroupKind{Kind: "Secret"}
+	configMapGK          = schema.GroupKind{Kind: "ConfigMap"}
+	serviceGK            = schema.GroupKind{Kind: "Service"}
+	crdGK                = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	roleGK               = schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "Role"}
+	clusterRoleGK        = schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}
+	roleBindingGK        = schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}
+	clusterRoleBindingGK = schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}
+	mutatingWebhookGK    = schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}
+	validatingWebhookGK  = schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}
+	apiServiceGK         = schema.GroupKind{Group: "apiregistration.k8s.io", Kind: "APIService"}
+	certificateGK        = schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"}
+)
+
+// Namespace is a resolver making objects depend on the Namespace they are in.
+func Namespace(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+	return groupKind(dependency) == namespaceGK &&
+		object.GetNamespace() != "" &&
+		object.GetNamespace() == dependency.GetName()
+}
+
+// CustomResourceDefinition is a resolver making custom resources depend on the
+// CustomResourceDefinition of their kind.
+func CustomResourceDefinition(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+	if groupKind(dependency) != crdGK {
+		return false
+	}
+
+	group, _, _ := unstructured.NestedString(dependency.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(dependency.Object, "spec", "names", "kind")
+
+	return groupKind(object) == schema.GroupKind{Group: group, Kind: kind}
+}
+
+// PodReferences is a resolver making objects holding pod specs, e.g. Pods and workloads, depend
+// on the ServiceAccount, image pull Secrets, and the Secrets and ConfigMaps mounted as volumes or
+// read as environment variables by their pods.
+func PodReferences(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+	gk := groupKind(dependency)
+	if gk != serviceAccountGK && gk != secretGK && gk != configMapGK {
+		return false
+	}
+
+	if object.GetNamespace() != dependency.GetNamespace() {
+		return false
+	}
+
+	name := dependency.GetName()
+	found := false
+
+	_ = k8s.VisitPodSpecs(object.Object, func(podSpec map[string]any) error {
+		found = found || podSpecReferences(podSpec, gk, name)
+
+		return nil
+	})
+
+	return found
+}
+
+// RBAC is a resolver making RoleBindings and ClusterRoleBindings depend on the Role or
+// ClusterRole they bind and on their ServiceAccount subjects.
+func RBAC(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+	gk := groupKind(object)
+	if gk != roleBindingGK && gk != clusterRoleBindingGK {
+		return false
+	}
+
+	switch groupKind(dependency) {
+	case roleGK:
+		return roleRef(object, "Role", dependency.GetName()) && object.GetNamespace() == dependency.GetNamespace()
+	case clusterRoleGK:
+		return roleRef(object, "ClusterRole", dependency.GetName())
+	case serviceAccountGK:
+		subjects, _, _ := unstructured.NestedSlice(object.Object, "subjects")
+
+		for _, s := range subjects {
+			subject, ok := s.(map[string]any)
+			if !ok || subject["kind"] != "ServiceAccount" {
+				continue
+			}
+
+			namespace, _ := subject["namespace"].(string)
+			if namespace == "" {
+				namespace = object.GetNamespace()
+			}
+
+			if subject["name"] == dependency.GetName() && namespace == dependency.GetNamespace() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Webhook is a resolver making webhook configurations, APIServices and CustomResourceDefinitions
+// with a conversion webhook depend on the Service serving them, and on the cert-manager
+// Certificate or Secret their CA is injected from.
+func Webhook(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+	var services []map[string]any
+
+	switch groupKind(object) {
+	case mutatingWebhookGK, validatingWebhookGK:
+		webhooks, _, _ := unstructured.NestedSlice(object.Object, "webhooks")
+
+		for _, w := range webhooks {
+			if webhook, ok := w.(map[string]any); ok {
+				if service, ok, _ := unstructured.NestedMap(webhook, "clientConfig", "service"); ok {
+					services = append(services, service)
+				}
+			}
+		}
+	case apiServiceGK:
+		if service, ok, _ := unstructured.NestedMap(object.Object, "spec", "service"); ok {
+			services = append(services, service)
+		}
+	case crdGK:
+		if service, ok, _ := unstructured.NestedMap(object.Object, "spec", "conversion", "webhook", "clientConfig", "service"); ok {
+			services = append(services, service)
+		}
+	default:
+		return false
+	}
+
+	reference := dependency.GetNamespace() + "/" + dependency.GetName()
+
+	switch groupKind(dependency) {
+	case serviceGK:
+		for _, service := range services {
+			if service["namespace"] == dependency.GetNamespace() && service["name"] == dependency.GetName() {
+				return true
+			}
+		}
+	case certificateGK:
+		return object.GetAnnotations()[injectCAFromAnnotation] == reference
+	case secretGK:
+		return object.GetAnnotations()[injectCAFromSecretAnnotation] == reference
+	}
+
+	return false
+}
+
+func groupKind(obj unstructured.Unstructured) schema.GroupKind {
+	return obj.GroupVersionKind().GroupKind()
+}
+
+// roleRef reports whether a binding references the role of the given kind and name.
+func roleRef(binding unstructured.Unstructured, kind string, name string) bool {
+	refKind, _, _ := unstructured.NestedString(binding.Object, "roleRef", "kind")
+	refName, _, _ := unstructured.NestedString(binding.Object, "roleRef", "name")
+
+	return refKind == kind && refName == name
+}
+
+// podSpecReferences reports whether a pod spec references the ServiceAccount, Secret or ConfigMap
+// of the given name.
+func podSpecReferences(podSpec map[string]any, gk schema.GroupKind, name string) bool {
+	switch gk {
+	case serviceAccountGK:
+		sa, _ := podSpec["serviceAccountName"].(string)
+
+		return sa == name
+	case secretGK:
+		if hasNamedItem(podSpec["imagePullSecrets"], name) {
+			return true
+		}
+
+		return volumeReferences(podSpec, "secret", "secretName", name) ||
+			containerReferences(podSpec, "secretRef", "secretKeyRef", name)
+	case configMapGK:
+		return volumeReferences(podSpec, "configMap", "name", name) ||
+			containerReferences(podSpec, "configMapRef", "configMapKeyRef", name)
+	}
+
+	return false
+}
+
+// volumeReferences reports whether the volumes of a pod spec, projected ones included, reference
+// the source of the given type and name.
+func volumeReferences(podSpec map[string]any, source string, field string, name string) bool {
+	volumes, _ := podSpec["volumes"].([]any)
+
+	for _, v := range volumes {
+		volume, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if ref, ok := volume[source].(map[string]any); ok && ref[field] == name {
+			return true
+		}
+
+		sources, _, _ := unstructured.NestedSlice(volume, "projected", "sources")
+		for _, s := range sources {
+			if ref, ok, _ := unstructured.NestedMap(asMap(s), source); ok && ref["name"] == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// containerReferences reports whether the containers of a pod spec read environment variables
+// from the Secret or ConfigMap of the given name, through envFrom or env valueFrom.
+func containerReferences(podSpec map[string]any, fromField string, keyRefField string, name string) bool {
+	found := false
+
+	_ = k8s.VisitContainers(map[string]any{"spec": podSpec}, func(container map[string]any) error {
+		envFrom, _ := container["envFrom"].([]any)
+		for _, e := range envFrom {
+			if ref, ok, _ := unstructured.NestedMap(asMap(e), fromField); ok && ref["name"] == name {
+				found = true
+			}
+		}
+
+		env, _ := container["env"].([]any)
+		for _, e := range env {
+			if ref, ok, _ := unstructured.NestedMap(asMap(e), "valueFrom", keyRefField); ok && ref["name"] == name {
+				found = true
+			}
+		}
+
+		return nil
+	})
+
+	return found
+}
+
+// hasNamedItem reports whether a list of objects holds one with the given name.
+func hasNamedItem(list any, name string) bool {
+	items, _ := list.([]any)
+
+	for _, i := range items {
+		if item, ok := i.(map[string]any); ok && item["name"] == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// asMap returns value as a map, or nil if it is not one.
+func asMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+
+	return m
+}
@@ -0,0 +1,248 @@
+package graph_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/graph"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+
+	. "github.com/onsi/gomega"
+)
+
+const operatorYAML = `
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: widgets
+  annotations:
+    cert-manager.io/inject-ca-from: operator/webhook-cert
+webhooks:
+- name: widgets.example.com
+  clientConfig:
+    service:
+      namespace: operator
+      name: webhook
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+  namespace: operator
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: operator
+  namespace: operator
+spec:
+  template:
+    spec:
+      serviceAccountName: operator
+      containers:
+      - name: manager
+        image: example.com/operator
+        envFrom:
+        - configMapRef:
+            name: operator-config
+      volumes:
+      - name: cert
+        secret:
+          secretName: webhook-cert
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: webhook
+  namespace: operator
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: webhook-cert
+  namespace: operator
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: operator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: operator
+subjects:
+- kind: ServiceAccount
+  name: operator
+  namespace: operator
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: operator
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: operator-config
+  namespace: operator
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: webhook-cert
+  namespace: operator
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: operator
+  namespace: operator
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: operator
+`
+
+func decode(t *testing.T, content string) []unstructured.Unstructured {
+	t.Helper()
+
+	objects, err := k8s.DecodeYAML([]byte(content))
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return objects
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetKind()+"/"+obj.GetName())
+	}
+
+	return result
+}
+
+func TestGraph(t *testing.T) {
+	t.Run("should infer dependencies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := decode(t, operatorYAML)
+		dependencies := func(i int) []string {
+			result := make([]string, 0)
+			for _, j := range graph.New(objects).Dependencies(i) {
+				result = append(result, objects[j].GetKind()+"/"+objects[j].GetName())
+			}
+
+			return result
+		}
+
+		g.Expect(dependencies(0)).Should(ConsistOf("Service/webhook", "Certificate/webhook-cert"))
+		g.Expect(dependencies(1)).Should(ConsistOf("Namespace/operator", "CustomResourceDefinition/widgets.example.com"))
+		g.Expect(dependencies(2)).Should(ConsistOf(
+			"Namespace/operator",
+			"ServiceAccount/operator",
+			"ConfigMap/operator-config",
+			"Secret/webhook-cert",
+		))
+		g.Expect(dependencies(5)).Should(ConsistOf("ClusterRole/operator", "ServiceAccount/operator"))
+		g.Expect(dependencies(11)).Should(BeEmpty())
+	})
+
+	t.Run("should group objects into waves", func(t *testing.T) {
+		g := NewWithT(t)
+
+		waves, err := graph.New(decode(t, operatorYAML)).Waves()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(waves).Should(HaveLen(3))
+
+		g.Expect(names(waves[0])).Should(Equal([]string{
+			"ClusterRole/operator",
+			"CustomResourceDefinition/widgets.example.com",
+			"Namespace/operator",
+		}))
+		g.Expect(names(waves[1])).Should(Equal([]string{
+			"Widget/widget",
+			"Service/webhook",
+			"Certificate/webhook-cert",
+			"ConfigMap/operator-config",
+			"Secret/webhook-cert",
+			"ServiceAccount/operator",
+		}))
+		g.Expect(names(waves[2])).Should(Equal([]string{
+			"ValidatingWebhookConfiguration/widgets",
+			"Deployment/operator",
+			"ClusterRoleBinding/operator",
+		}))
+	})
+
+	t.Run("should sort objects after their dependencies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sorted, err := graph.New(decode(t, operatorYAML)).Sorted()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(sorted).Should(HaveLen(12))
+		g.Expect(sorted[0].GetKind()).Should(Equal("ClusterRole"))
+		g.Expect(sorted[11].GetKind()).Should(Equal("ClusterRoleBinding"))
+	})
+
+	t.Run("should use custom resolvers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := decode(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+`)
+
+		byName := func(object unstructured.Unstructured, dependency unstructured.Unstructured) bool {
+			return object.GetName() > dependency.GetName()
+		}
+
+		sorted, err := graph.New(objects, graph.WithResolvers(byName)).Sorted()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(sorted)).Should(Equal([]string{"ConfigMap/a", "ConfigMap/b"}))
+	})
+
+	t.Run("should report cycles", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := decode(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: test
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: test
+`)
+
+		always := func(_ unstructured.Unstructured, _ unstructured.Unstructured) bool {
+			return true
+		}
+
+		_, err := graph.New(objects, graph.WithResolvers(always)).Waves()
+		g.Expect(err).Should(MatchError(graph.ErrCycle))
+		g.Expect(err).Should(MatchError(ContainSubstring("ConfigMap test/a, ConfigMap test/b")))
+	})
+}
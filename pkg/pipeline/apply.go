@@ -47,6 +47,34 @@ func ApplyFilters(
 	return filtered, nil
 }
 
+// ApplyCollectionFilters applies a series of collection filters to objects, returning only those that
+// match all filters. Each filter decides on the objects kept by the previous ones.
+// Returns Error with detailed context if any filter fails.
+func ApplyCollectionFilters(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	filters []types.CollectionFilter,
+) ([]unstructured.Unstructured, error) {
+	for _, f := range filters {
+		filtered := make([]unstructured.Unstructured, 0, len(objects))
+
+		for _, obj := range objects {
+			ok, err := f(ctx, obj, objects)
+			if err != nil {
+				return nil, filter.Wrap(obj, err)
+			}
+
+			if ok {
+				filtered = append(filtered, obj)
+			}
+		}
+
+		objects = filtered
+	}
+
+	return objects, nil
+}
+
 // ApplyTransformers applies a series of transformers to objects, transforming each object sequentially.
 // Returns Error with detailed context if any transformer fails.
 func ApplyTransformers(
@@ -77,6 +105,26 @@ func ApplyTransformers(
 	return transformed, nil
 }
 
+// ApplyCollectionTransformers applies a series of collection transformers to objects, each one
+// receiving the set returned by the previous one.
+// Callers should wrap returned errors with appropriate context.
+func ApplyCollectionTransformers(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+	transformers []types.CollectionTransformer,
+) ([]unstructured.Unstructured, error) {
+	for _, t := range transformers {
+		result, err := t(ctx, objects)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = result
+	}
+
+	return objects, nil
+}
+
 // Apply executes a filter and transformer pipeline on the given objects.
 // It applies filters first, then transformers, returning the transformed objects.
 // Callers should wrap returned errors with appropriate context.
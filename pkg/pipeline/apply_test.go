@@ -3,6 +3,7 @@ package pipeline_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -128,6 +129,110 @@ func TestApplyFilters(t *testing.T) {
 	})
 }
 
+func TestApplyCollectionFilters(t *testing.T) {
+
+	t.Run("should return all objects when no filters", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{makeObject(kindPod, "pod1")}
+
+		result, err := pipeline.ApplyCollectionFilters(t.Context(), objects, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(objects))
+	})
+
+	t.Run("should decide on the objects kept by previous filters", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{
+			makeObject(kindPod, "pod1"),
+			makeObject(kindPod, "pod2"),
+			makeObject("Service", "svc1"),
+		}
+
+		var sizes []int
+
+		dropServices := func(_ context.Context, obj unstructured.Unstructured, all []unstructured.Unstructured) (bool, error) {
+			sizes = append(sizes, len(all))
+
+			return obj.GetKind() != "Service", nil
+		}
+
+		result, err := pipeline.ApplyCollectionFilters(
+			t.Context(),
+			objects,
+			[]types.CollectionFilter{dropServices, dropServices},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(HaveLen(2))
+		g.Expect(sizes).To(Equal([]int{3, 3, 3, 2, 2}))
+	})
+
+	t.Run("should return error when filter fails", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{makeObject(kindPod, "pod1")}
+
+		failing := func(_ context.Context, _ unstructured.Unstructured, _ []unstructured.Unstructured) (bool, error) {
+			return false, errors.New("collection error")
+		}
+
+		_, err := pipeline.ApplyCollectionFilters(t.Context(), objects, []types.CollectionFilter{failing})
+		g.Expect(err).To(HaveOccurred())
+
+		var filterErr *filter.Error
+		g.Expect(errors.As(err, &filterErr)).To(BeTrue())
+		g.Expect(filterErr.Object.GetName()).To(Equal("pod1"))
+	})
+}
+
+func TestApplyCollectionTransformers(t *testing.T) {
+
+	t.Run("should return all objects when no transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{makeObject(kindPod, "pod1")}
+
+		result, err := pipeline.ApplyCollectionTransformers(t.Context(), objects, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(objects))
+	})
+
+	t.Run("should pass the result of each transformer to the next", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{makeObject(kindPod, "pod1")}
+
+		addService := func(_ context.Context, all []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+			return append(all, makeObject("Service", "svc1")), nil
+		}
+
+		reverse := func(_ context.Context, all []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+			result := slices.Clone(all)
+			slices.Reverse(result)
+
+			return result, nil
+		}
+
+		result, err := pipeline.ApplyCollectionTransformers(
+			t.Context(),
+			objects,
+			[]types.CollectionTransformer{addService, reverse},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(HaveLen(2))
+		g.Expect(result[0].GetName()).To(Equal("svc1"))
+		g.Expect(result[1].GetName()).To(Equal("pod1"))
+	})
+
+	t.Run("should return error when transformer fails", func(t *testing.T) {
+		g := NewWithT(t)
+		objects := []unstructured.Unstructured{makeObject(kindPod, "pod1")}
+
+		failing := func(_ context.Context, _ []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+			return nil, errors.New("collection error")
+		}
+
+		_, err := pipeline.ApplyCollectionTransformers(t.Context(), objects, []types.CollectionTransformer{failing})
+		g.Expect(err).To(MatchError("collection error"))
+	})
+}
+
 func TestApplyTransformers(t *testing.T) {
 	ctx := t.Context()
 
@@ -0,0 +1,193 @@
+// Package cdk8s provides a renderer for the synthesized output of cdk8s applications.
+package cdk8s
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const rendererType = "cdk8s"
+
+// Source represents the synthesized output of a cdk8s application.
+type Source struct {
+	// FS is the filesystem containing the synth output, typically os.DirFS("dist").
+	FS fs.FS
+
+	// Path specifies the glob pattern matching synth files within FS.
+	// Only .yaml, .yml and .json files are processed. Default: "*".
+	Path string
+}
+
+// Renderer handles cdk8s synth output rendering operations.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new cdk8s Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the cdk8s renderer as the output is already synthesized.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering cdk8s output %s: %w", holder.pattern(), err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to cdk8s output %s: %w",
+				holder.pattern(),
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the synth files matched by the sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.pattern()})
+	}
+
+	return sources
+}
+
+// renderSingle performs the rendering for a single cdk8s output.
+func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	pattern := holder.pattern()
+
+	// Use source and pattern as cache key, sources over different filesystems may share the pattern
+	cacheKey := fmt.Sprintf("%p:%s", holder, pattern)
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	matches, err := fs.Glob(holder.FS, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match pattern %s: %w", pattern, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoFilesMatched, pattern)
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for _, match := range matches {
+		switch filepath.Ext(match) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+
+		content, err := fs.ReadFile(holder.FS, match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", match, err)
+		}
+
+		// JSON is valid YAML, DecodeYAML handles both multi-document YAML and JSON arrays
+		objects, err := k8s.DecodeYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", match, err)
+		}
+
+		// Add source annotations if enabled
+		if r.opts.SourceAnnotations {
+			for i := range objects {
+				annotations := objects[i].GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+
+				annotations[types.AnnotationSourceType] = rendererType
+				annotations[types.AnnotationSourcePath] = pattern
+				annotations[types.AnnotationSourceFile] = match
+
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
+		result = append(result, objects...)
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package cdk8s
+
+import (
+	"errors"
+	"strings"
+
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+)
+
+// defaultPattern matches all synth files at the root of the output directory.
+const defaultPattern = "*"
+
+var (
+	// ErrNoFilesMatched is returned when no files match the specified pattern.
+	ErrNoFilesMatched = errors.New("no files matched pattern")
+)
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if h.FS == nil {
+		return utilerrors.ErrFsRequired
+	}
+
+	return nil
+}
+
+// pattern returns the configured glob pattern or the default one.
+func (h *sourceHolder) pattern() string {
+	if len(strings.TrimSpace(h.Path)) == 0 {
+		return defaultPattern
+	}
+
+	return h.Path
+}
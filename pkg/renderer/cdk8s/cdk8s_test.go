@@ -0,0 +1,171 @@
+package cdk8s_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/cdk8s"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+const chartYAML = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web-service-c8a4a7f2
+spec:
+  ports:
+    - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web-deployment-c8b5d5c1
+spec:
+  replicas: 2
+`
+
+const chartJSON = `[
+  {
+    "apiVersion": "v1",
+    "kind": "ConfigMap",
+    "metadata": {"name": "settings-c8d1a2b3"},
+    "data": {"key": "value"}
+  },
+  {
+    "apiVersion": "v1",
+    "kind": "Secret",
+    "metadata": {"name": "credentials-c8e2b3c4"}
+  }
+]`
+
+func TestRenderer(t *testing.T) {
+	ctx := t.Context()
+
+	dist := fstest.MapFS{
+		"web.k8s.yaml":  &fstest.MapFile{Data: []byte(chartYAML)},
+		"data.k8s.json": &fstest.MapFile{Data: []byte(chartJSON)},
+		"README.md":     &fstest.MapFile{Data: []byte("# not a manifest")},
+	}
+
+	t.Run("should render all synth files by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cdk8s.New([]cdk8s.Source{{FS: dist}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+
+		// files are processed in lexical order
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).To(Equal("Secret"))
+		g.Expect(objects[2].GetKind()).To(Equal("Service"))
+		g.Expect(objects[3].GetKind()).To(Equal("Deployment"))
+	})
+
+	t.Run("should render JSON array output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cdk8s.New([]cdk8s.Source{{FS: dist, Path: "*.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("settings-c8d1a2b3"))
+	})
+
+	t.Run("should fail when no files match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cdk8s.New([]cdk8s.Source{{FS: dist, Path: "missing/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(cdk8s.ErrNoFilesMatched))
+	})
+
+	t.Run("should not share cache entries across filesystems", func(t *testing.T) {
+		g := NewWithT(t)
+
+		other := fstest.MapFS{
+			"data.k8s.json": &fstest.MapFile{Data: []byte(chartJSON)},
+		}
+
+		renderer, err := cdk8s.New(
+			[]cdk8s.Source{{FS: dist}, {FS: other}},
+			cdk8s.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(6))
+		g.Expect(objects[4].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[5].GetKind()).To(Equal("Secret"))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cdk8s.New(
+			[]cdk8s.Source{{FS: dist, Path: "web.k8s.yaml"}},
+			cdk8s.WithTransformer(labels.Set(map[string]string{"source": "cdk8s"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("source", "cdk8s"))
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should reject missing FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cdk8s.New([]cdk8s.Source{{Path: "*.yaml"}})
+		g.Expect(err).To(MatchError(utilerrors.ErrFsRequired))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cdk8s.New([]cdk8s.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("cdk8s"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	dist := fstest.MapFS{
+		"data.k8s.json": &fstest.MapFile{Data: []byte(chartJSON)},
+	}
+
+	renderer, err := cdk8s.New(
+		[]cdk8s.Source{{FS: dist}},
+		cdk8s.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+
+	for _, obj := range objects {
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "cdk8s"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, "*"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "data.k8s.json"))
+	}
+}
@@ -0,0 +1,218 @@
+// Package cluster provides a renderer that lists live objects from a Kubernetes cluster.
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+)
+
+const rendererType = "cluster"
+
+// Source selects the live objects to list from the cluster.
+type Source struct {
+	// GroupVersionKind is the type of objects to list. Version and Kind are required.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Namespaces restricts listing to the given namespaces. Optional; if empty objects are listed
+	// across all namespaces. Ignored for cluster-scoped kinds.
+	Namespaces []string
+
+	// LabelSelector restricts listing to objects matching the selector, e.g. "app=web,tier!=db". Optional.
+	LabelSelector string
+}
+
+// Renderer lists live objects from a cluster and feeds them into the pipeline,
+// enabling export, backup or migration workflows with filters and transformers.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new cluster Renderer with the given inputs and options.
+// A client and a REST mapper are required, either provided directly or created from a REST configuration.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+		PageSize:     defaultPageSize,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	if err := setupClients(&rendererOpts); err != nil {
+		return nil, err
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the cluster renderer as objects are read as-is.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error listing cluster objects %s: %w", holder.GroupVersionKind, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to cluster objects %s: %w",
+				holder.GroupVersionKind,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// renderSingle lists the objects selected by a single Source.
+func (r *Renderer) renderSingle(ctx context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	cacheKey := dump.ForHash(holder.Source)
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	gvk := holder.GroupVersionKind
+
+	mapping, err := r.opts.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map %s to a resource: %w", gvk, err)
+	}
+
+	// An empty namespace lists across all namespaces
+	namespaces := []string{metav1.NamespaceAll}
+	if isNamespaced(mapping) && len(holder.Namespaces) > 0 {
+		namespaces = holder.Namespaces
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for _, namespace := range namespaces {
+		objects, err := r.list(ctx, mapping.Resource, namespace, isNamespaced(mapping), holder.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range objects {
+			// List items may omit type information
+			objects[i].SetGroupVersionKind(gvk)
+
+			// Add source annotations if enabled
+			if r.opts.SourceAnnotations {
+				annotations := objects[i].GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+
+				annotations[types.AnnotationSourceType] = rendererType
+				annotations[types.AnnotationSourcePath] = gvk.String()
+
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
+		result = append(result, objects...)
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// list retrieves all objects of a resource in a namespace, following continuation tokens.
+func (r *Renderer) list(
+	ctx context.Context,
+	resource schema.GroupVersionResource,
+	namespace string,
+	namespaced bool,
+	selector string,
+) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0)
+
+	opts := metav1.ListOptions{
+		LabelSelector: selector,
+		Limit:         r.opts.PageSize,
+	}
+
+	for {
+		var list *unstructured.UnstructuredList
+		var err error
+
+		if namespaced {
+			list, err = r.opts.Client.Resource(resource).Namespace(namespace).List(ctx, opts)
+		} else {
+			list, err = r.opts.Client.Resource(resource).List(ctx, opts)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s in namespace %q: %w", resource, namespace, err)
+		}
+
+		result = append(result, list.Items...)
+
+		opts.Continue = list.GetContinue()
+		if opts.Continue == "" {
+			break
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package cluster
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+)
+
+// RendererOption is a generic option for RendererOptions.
+type RendererOption = util.Option[RendererOptions]
+
+// RendererOptions is a struct-based option that can set multiple renderer options at once.
+type RendererOptions struct {
+	// Filters are renderer-specific filters applied during Process().
+	Filters []types.Filter
+
+	// Transformers are post-processing transformers applied after listing objects.
+	Transformers []types.Transformer
+
+	// Cache is a custom cache implementation for render results.
+	Cache cache.Interface[[]unstructured.Unstructured]
+
+	// SourceAnnotations enables automatic addition of source tracking annotations.
+	SourceAnnotations bool
+
+	// Config is the REST configuration used to create the client and the REST mapper
+	// when they are not provided explicitly.
+	Config *rest.Config
+
+	// Client is the dynamic client used to list objects.
+	Client dynamic.Interface
+
+	// Mapper resolves GroupVersionKinds to resources.
+	Mapper meta.RESTMapper
+
+	// PageSize is the maximum number of objects requested per list call. Zero disables paging.
+	PageSize int64
+}
+
+// ApplyTo applies the renderer options to the target configuration.
+func (opts RendererOptions) ApplyTo(target *RendererOptions) {
+	target.Filters = opts.Filters
+	target.Transformers = opts.Transformers
+
+	if opts.Cache != nil {
+		target.Cache = opts.Cache
+	}
+
+	if opts.Config != nil {
+		target.Config = opts.Config
+	}
+
+	if opts.Client != nil {
+		target.Client = opts.Client
+	}
+
+	if opts.Mapper != nil {
+		target.Mapper = opts.Mapper
+	}
+
+	if opts.PageSize > 0 {
+		target.PageSize = opts.PageSize
+	}
+
+	target.SourceAnnotations = opts.SourceAnnotations
+}
+
+// WithFilter adds a renderer-specific filter to this cluster renderer's processing chain.
+// Renderer-specific filters are applied during Process(), before results are returned to the engine.
+// For engine-level filtering applied to all renderers, use engine.WithFilter.
+func WithFilter(filter types.Filter) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Filters = append(opts.Filters, filter)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer to this cluster renderer's processing chain.
+// Renderer-specific transformers are applied during Process(), before results are returned to the engine.
+// For engine-level transformation applied to all renderers, use engine.WithTransformer.
+func WithTransformer(transformer types.Transformer) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Transformers = append(opts.Transformers, transformer)
+	})
+}
+
+// WithCache enables render result caching with the specified options.
+// If no options are provided, uses default TTL of 5 minutes.
+// By default, caching is NOT enabled.
+func WithCache(opts ...cache.Option) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(rendererOpts *RendererOptions) {
+		rendererOpts.Cache = cache.NewRenderCache(opts...)
+	})
+}
+
+// WithSourceAnnotations enables or disables automatic addition of source tracking annotations.
+// When enabled, the renderer adds metadata annotations to track the source type and the listed GroupVersionKind.
+// Annotations added: manifests.k8s-manifests-lib/source.type, source.path.
+// Default: false (disabled).
+func WithSourceAnnotations(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SourceAnnotations = enabled
+	})
+}
+
+// WithConfig sets the REST configuration used to connect to the cluster.
+// A dynamic client and a discovery based REST mapper are created from it unless
+// provided with WithClient and WithRESTMapper.
+func WithConfig(config *rest.Config) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Config = config
+	})
+}
+
+// WithClient sets the dynamic client used to list objects.
+func WithClient(client dynamic.Interface) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Client = client
+	})
+}
+
+// WithRESTMapper sets the mapper used to resolve GroupVersionKinds to resources.
+func WithRESTMapper(mapper meta.RESTMapper) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Mapper = mapper
+	})
+}
+
+// WithPageSize sets the maximum number of objects requested per list call.
+// Default: 500.
+func WithPageSize(size int64) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PageSize = size
+	})
+}
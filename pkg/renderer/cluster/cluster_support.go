@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// defaultPageSize is the default number of objects requested per list call.
+const defaultPageSize = 500
+
+var (
+	// ErrKindEmpty is returned when the GroupVersionKind of a Source has no kind or version.
+	ErrKindEmpty = errors.New("group version kind requires version and kind")
+
+	// ErrClientRequired is returned when neither a client nor a REST configuration is provided.
+	ErrClientRequired = errors.New("cluster client or REST config is required")
+
+	// ErrMapperRequired is returned when neither a REST mapper nor a REST configuration is provided.
+	ErrMapperRequired = errors.New("REST mapper or REST config is required")
+)
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if h.GroupVersionKind.Version == "" || h.GroupVersionKind.Kind == "" {
+		return ErrKindEmpty
+	}
+
+	if h.LabelSelector != "" {
+		if _, err := labels.Parse(h.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label selector %q: %w", h.LabelSelector, err)
+		}
+	}
+
+	return nil
+}
+
+// setupClients fills in the client and REST mapper from the REST configuration when needed.
+func setupClients(opts *RendererOptions) error {
+	if opts.Client == nil {
+		if opts.Config == nil {
+			return ErrClientRequired
+		}
+
+		client, err := dynamic.NewForConfig(opts.Config)
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+
+		opts.Client = client
+	}
+
+	if opts.Mapper == nil {
+		if opts.Config == nil {
+			return ErrMapperRequired
+		}
+
+		dc, err := discovery.NewDiscoveryClientForConfig(opts.Config)
+		if err != nil {
+			return fmt.Errorf("failed to create discovery client: %w", err)
+		}
+
+		opts.Mapper = restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+	}
+
+	return nil
+}
+
+// isNamespaced reports whether the resource of the mapping is namespace scoped.
+func isNamespaced(mapping *meta.RESTMapping) bool {
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
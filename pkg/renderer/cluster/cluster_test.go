@@ -0,0 +1,220 @@
+package cluster_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/cluster"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+var (
+	configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+)
+
+func newObject(gvk schema.GroupVersionKind, namespace string, name string, lbls map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(lbls)
+
+	return obj
+}
+
+func newMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+	mapper.Add(namespaceGVK, meta.RESTScopeRoot)
+
+	return mapper
+}
+
+func newClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+			{Version: "v1", Resource: "namespaces"}: "NamespaceList",
+		},
+		objects...,
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	ctx := t.Context()
+
+	client := newClient(
+		newObject(namespaceGVK, "", "team-a", nil),
+		newObject(namespaceGVK, "", "team-b", nil),
+		newObject(configMapGVK, "team-a", "web", map[string]string{"app": "web"}),
+		newObject(configMapGVK, "team-a", "db", map[string]string{"app": "db"}),
+		newObject(configMapGVK, "team-b", "web", map[string]string{"app": "web"}),
+	)
+
+	t.Run("should list objects across all namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: configMapGVK}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		for _, obj := range objects {
+			g.Expect(obj.GroupVersionKind()).To(Equal(configMapGVK))
+		}
+	})
+
+	t.Run("should list objects in selected namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: configMapGVK, Namespaces: []string{"team-b"}}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetNamespace()).To(Equal("team-b"))
+	})
+
+	t.Run("should list objects matching a label selector", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: configMapGVK, LabelSelector: "app=web"}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetName()).To(Equal("web"))
+		}
+	})
+
+	t.Run("should list cluster-scoped objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: namespaceGVK, Namespaces: []string{"ignored"}}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should fail on unknown kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(meta.IsNoMatchError(err)).To(BeTrue())
+	})
+
+	t.Run("should apply transformers and source annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: namespaceGVK}},
+			cluster.WithClient(client),
+			cluster.WithRESTMapper(newMapper()),
+			cluster.WithTransformer(labels.Set(map[string]string{"exported": "true"})),
+			cluster.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).ToNot(BeEmpty())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("exported", "true"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "cluster"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, "/v1, Kind=Namespace"))
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should require a client", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cluster.New([]cluster.Source{}, cluster.WithRESTMapper(newMapper()))
+		g.Expect(err).To(MatchError(cluster.ErrClientRequired))
+	})
+
+	t.Run("should require a REST mapper", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cluster.New([]cluster.Source{}, cluster.WithClient(newClient()))
+		g.Expect(err).To(MatchError(cluster.ErrMapperRequired))
+	})
+
+	t.Run("should reject empty kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: schema.GroupVersionKind{Version: "v1"}}},
+			cluster.WithClient(newClient()),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).To(MatchError(cluster.ErrKindEmpty))
+	})
+
+	t.Run("should reject invalid label selectors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := cluster.New(
+			[]cluster.Source{{GroupVersionKind: configMapGVK, LabelSelector: "app in (web"}},
+			cluster.WithClient(newClient()),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := cluster.New(
+			[]cluster.Source{},
+			cluster.WithClient(newClient()),
+			cluster.WithRESTMapper(newMapper()),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("cluster"))
+	})
+}
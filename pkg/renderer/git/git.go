@@ -0,0 +1,217 @@
+// Package git provides a renderer that reads Kubernetes manifests from a Git repository reference.
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const rendererType = "git"
+
+// Source represents a set of manifests stored in a Git repository.
+type Source struct {
+	// Repository is the repository URL or local path to clone from. Required.
+	Repository string
+
+	// Ref is the branch, tag, or commit SHA to check out.
+	// Empty means the remote HEAD.
+	Ref string
+
+	// Path specifies the glob pattern, relative to the repository root, matching YAML files.
+	// Only .yaml and .yml files are processed. Examples: "deploy/*.yaml", "config/base/*.yml". Required.
+	Path string
+}
+
+// Renderer handles rendering manifests checked out from Git repositories.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new Git Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+		Binary:       defaultBinary,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the Git renderer as it does not support templates.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering git repository %s@%s: %w", holder.Repository, holder.Ref, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to git repository %s@%s: %w",
+				holder.Repository,
+				holder.Ref,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// renderSingle checks out a single repository reference and decodes the matching files.
+func (r *Renderer) renderSingle(ctx context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	// Compute cache key from repository, ref, path and credentials
+	type cacheKeyData struct {
+		Repository  string
+		Ref         string
+		Path        string
+		Credentials string
+	}
+
+	var cacheKey string
+
+	// Only commit SHAs are cached, branches and tags can move between two renders
+	cacheable := r.opts.Cache != nil && IsCommitSHA(holder.Ref)
+
+	// Check cache (if enabled)
+	if cacheable {
+		cacheKey = dump.ForHash(cacheKeyData{
+			Repository:  holder.Repository,
+			Ref:         holder.Ref,
+			Path:        holder.Path,
+			Credentials: r.opts.Auth.digest(),
+		})
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "k8s-manifests-lib-git-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create checkout directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	if _, err := checkout(ctx, r.opts.Binary, holder.Repository, holder.Ref, dir, r.opts.Auth.env()); err != nil {
+		return nil, err
+	}
+
+	fsys := os.DirFS(dir)
+
+	matches, err := fs.Glob(fsys, holder.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match pattern %s: %w", holder.Path, err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoFilesMatched, holder.Path)
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for _, match := range matches {
+		ext := filepath.Ext(match)
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", match, err)
+		}
+
+		objects, err := k8s.DecodeYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", match, err)
+		}
+
+		// Add source annotations if enabled
+		if r.opts.SourceAnnotations {
+			for i := range objects {
+				annotations := objects[i].GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+
+				annotations[types.AnnotationSourceType] = rendererType
+				annotations[types.AnnotationSourcePath] = holder.Repository
+				annotations[types.AnnotationSourceFile] = match
+
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
+		result = append(result, objects...)
+	}
+
+	// Cache result (if enabled)
+	if cacheable {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
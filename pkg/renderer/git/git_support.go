@@ -0,0 +1,216 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+)
+
+const (
+	defaultBinary = "git"
+	defaultRef    = "HEAD"
+)
+
+var (
+	// ErrRepositoryEmpty is returned when a source repository is empty or whitespace-only.
+	ErrRepositoryEmpty = errors.New("repository cannot be empty or whitespace-only")
+
+	// ErrNoFilesMatched is returned when no files match the specified pattern.
+	ErrNoFilesMatched = errors.New("no files matched pattern")
+
+	// ErrRepositoryInvalid is returned when a repository could be mistaken for a git option.
+	ErrRepositoryInvalid = errors.New("repository cannot start with '-'")
+
+	// ErrRefInvalid is returned when a reference could be mistaken for a git option.
+	ErrRefInvalid = errors.New("ref cannot start with '-'")
+)
+
+// Auth holds the basic credentials, e.g. a user name and an access token, authenticating
+// HTTP(S) fetches.
+type Auth struct {
+	Username string
+	Password string
+}
+
+// env returns the environment passing the credentials to git, nil without credentials.
+func (a *Auth) env() []string {
+	if a == nil {
+		return nil
+	}
+
+	return basicAuthEnv(a.Username, a.Password)
+}
+
+// digest returns a digest of the credentials, keeping them out of the cache keys.
+func (a *Auth) digest() string {
+	if a == nil {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(dump.ForHash(*a)))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// IsCommitSHA reports whether the ref is a full SHA-1 or SHA-256 commit hash, the only refs
+// that can't move, so that their content can be reused.
+func IsCommitSHA(ref string) bool {
+	if len(ref) != 40 && len(ref) != 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(ref)
+
+	return err == nil
+}
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(strings.TrimSpace(h.Repository)) == 0 {
+		return ErrRepositoryEmpty
+	}
+	if len(strings.TrimSpace(h.Path)) == 0 {
+		return utilerrors.ErrPathEmpty
+	}
+
+	return validateArgs(h.Repository, h.Ref)
+}
+
+// validateArgs rejects a repository or a ref that git would interpret as an option.
+func validateArgs(repository string, ref string) error {
+	if strings.HasPrefix(repository, "-") {
+		return fmt.Errorf("%w: %s", ErrRepositoryInvalid, repository)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("%w: %s", ErrRefInvalid, ref)
+	}
+
+	return nil
+}
+
+// Checkout performs a shallow checkout of the given repository reference into dir and
+// returns the resolved commit SHA. The ref may be a branch, a tag, or a commit SHA; an
+// empty ref selects the remote HEAD. A repository or a ref starting with "-" is rejected
+// with ErrRepositoryInvalid or ErrRefInvalid, as git would interpret it as an option.
+//
+// Use it to feed a sub-path of a repository to another renderer, e.g. a kustomize
+// overlay or a helm chart:
+//
+//	sha, err := git.Checkout(ctx, "https://github.com/org/repo", "v1.2.0", dir)
+//	...
+//	kustomize.New([]kustomize.Source{{Path: filepath.Join(dir, "config/default")}})
+func Checkout(ctx context.Context, repository string, ref string, dir string) (string, error) {
+	return checkout(ctx, defaultBinary, repository, ref, dir, nil)
+}
+
+// CheckoutWithAuth is Checkout authenticating HTTP(S) fetches with the given basic credentials,
+// e.g. a user name and an access token. The credentials are passed to git through the
+// environment, so they never appear on the command line.
+func CheckoutWithAuth(
+	ctx context.Context,
+	repository string,
+	ref string,
+	dir string,
+	username string,
+	password string,
+) (string, error) {
+	return checkout(ctx, defaultBinary, repository, ref, dir, basicAuthEnv(username, password))
+}
+
+func checkout(
+	ctx context.Context,
+	binary string,
+	repository string,
+	ref string,
+	dir string,
+	env []string,
+) (string, error) {
+	if err := validateArgs(repository, ref); err != nil {
+		return "", err
+	}
+
+	if ref == "" {
+		ref = defaultRef
+	}
+
+	steps := [][]string{
+		{"init", "--quiet", "--", dir},
+		{"-C", dir, "fetch", "--quiet", "--depth", "1", "--", repository, ref},
+		{"-C", dir, "checkout", "--quiet", "FETCH_HEAD"},
+	}
+
+	for _, args := range steps {
+		if _, err := run(ctx, binary, env, args...); err != nil {
+			return "", err
+		}
+	}
+
+	sha, err := run(ctx, binary, env, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(sha), nil
+}
+
+// basicAuthEnv returns the environment configuring git to send the basic credentials
+// as an HTTP authorization header.
+func basicAuthEnv(username string, password string) []string {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + credentials,
+	}
+}
+
+// run executes a git command with the additional environment and returns its standard output.
+func run(ctx context.Context, binary string, env []string, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, binary, args...) //nolint:gosec // binary and arguments are configured by the caller
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// never prompt for credentials, fail instead
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", subcommand(args), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// subcommand returns the git subcommand in args, skipping the global options preceding it.
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-C":
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			return args[i]
+		}
+	}
+
+	return ""
+}
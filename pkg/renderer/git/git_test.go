@@ -0,0 +1,437 @@
+package git_test
+
+import (
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/git"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+const configMapV1 = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  version: v1
+`
+
+const configMapV2 = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  version: v2
+`
+
+const deployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+
+	out, err := exec.CommandContext(t.Context(), "git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupRepository creates a repository with a v1 tag and a newer commit on the default branch.
+// It returns the repository path and the SHA of the tagged commit.
+func setupRepository(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	dir := t.TempDir()
+
+	runGit(t, dir, "init", "--quiet", "--initial-branch=main")
+
+	writeFile(t, dir, "deploy/config.yaml", configMapV1)
+	writeFile(t, dir, "deploy/deployment.yaml", deployment)
+	writeFile(t, dir, "deploy/README.md", "not a manifest")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "--quiet", "-m", "v1")
+	runGit(t, dir, "tag", "v1")
+
+	sha := runGit(t, dir, "rev-parse", "HEAD")
+
+	writeFile(t, dir, "deploy/config.yaml", configMapV2)
+	runGit(t, dir, "commit", "--quiet", "-am", "v2")
+
+	return dir, sha
+}
+
+func TestRenderer(t *testing.T) {
+	g := NewWithT(t)
+	repository, sha := setupRepository(t)
+
+	tests := []struct {
+		name    string
+		ref     string
+		version string
+	}{
+		{name: "should render default branch", ref: "", version: "v2"},
+		{name: "should render branch", ref: "main", version: "v2"},
+		{name: "should render tag", ref: "v1", version: "v1"},
+		{name: "should render commit SHA", ref: sha, version: "v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, err := git.New([]git.Source{{
+				Repository: repository,
+				Ref:        tt.ref,
+				Path:       "deploy/*",
+			}})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(2))
+			g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+			g.Expect(objects[0].Object).To(HaveKeyWithValue("data", HaveKeyWithValue("version", tt.version)))
+			g.Expect(objects[1].GetKind()).To(Equal("Deployment"))
+		})
+	}
+
+	t.Run("should fail on unknown ref", func(t *testing.T) {
+		renderer, err := git.New([]git.Source{{
+			Repository: repository,
+			Ref:        "does-not-exist",
+			Path:       "deploy/*.yaml",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should fail when no files match", func(t *testing.T) {
+		renderer, err := git.New([]git.Source{{
+			Repository: repository,
+			Path:       "missing/*.yaml",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(git.ErrNoFilesMatched))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		renderer, err := git.New(
+			[]git.Source{{Repository: repository, Path: "deploy/*.yaml"}},
+			git.WithTransformer(labels.Set(map[string]string{"source": "git"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("source", "git"))
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should reject empty repository", func(t *testing.T) {
+		_, err := git.New([]git.Source{{Path: "*.yaml"}})
+		g.Expect(err).To(MatchError(git.ErrRepositoryEmpty))
+	})
+
+	t.Run("should reject empty path", func(t *testing.T) {
+		_, err := git.New([]git.Source{{Repository: "https://example.com/repo.git"}})
+		g.Expect(err).To(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should reject option-like repository", func(t *testing.T) {
+		_, err := git.New([]git.Source{{Repository: "--upload-pack=touch", Path: "*.yaml"}})
+		g.Expect(err).To(MatchError(git.ErrRepositoryInvalid))
+	})
+
+	t.Run("should reject option-like ref", func(t *testing.T) {
+		_, err := git.New([]git.Source{{
+			Repository: "https://example.com/repo.git",
+			Ref:        "--upload-pack=touch",
+			Path:       "*.yaml",
+		}})
+		g.Expect(err).To(MatchError(git.ErrRefInvalid))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		renderer, err := git.New([]git.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("git"))
+	})
+}
+
+func TestCheckout(t *testing.T) {
+	repository, sha := setupRepository(t)
+
+	t.Run("should checkout the ref", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+
+		resolved, err := git.Checkout(t.Context(), repository, "v1", dir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(resolved).To(Equal(sha))
+
+		content, err := os.ReadFile(filepath.Join(dir, "deploy", "config.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal(configMapV1))
+	})
+
+	t.Run("should reject option-like arguments", func(t *testing.T) {
+		g := NewWithT(t)
+
+		marker := filepath.Join(t.TempDir(), "marker")
+
+		_, err := git.Checkout(t.Context(), "file://"+repository, "--upload-pack=touch "+marker, t.TempDir())
+		g.Expect(err).To(MatchError(git.ErrRefInvalid))
+
+		_, err = git.Checkout(t.Context(), "--upload-pack=touch "+marker, "v1", t.TempDir())
+		g.Expect(err).To(MatchError(git.ErrRepositoryInvalid))
+
+		g.Expect(marker).ToNot(BeAnExistingFile())
+	})
+
+	t.Run("should name the failed git command", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := git.Checkout(t.Context(), repository, "does-not-exist", t.TempDir())
+		g.Expect(err).To(MatchError(ContainSubstring("git fetch failed")))
+	})
+}
+
+// newGitServer serves the repository over HTTP with git http-backend, requiring basic credentials.
+// It returns the repository URL.
+func newGitServer(t *testing.T, repository string, username string, password string) string {
+	t.Helper()
+
+	execPath := runGit(t, repository, "--exec-path")
+
+	backend := &cgi.Handler{
+		Path: filepath.Join(execPath, "git-http-backend"),
+		Env:  []string{"GIT_PROJECT_ROOT=" + repository, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="git"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		backend.ServeHTTP(w, r)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL + "/.git"
+}
+
+func TestCheckoutWithAuth(t *testing.T) {
+	repository, sha := setupRepository(t)
+	url := newGitServer(t, repository, "user", "token")
+
+	t.Run("should checkout with valid credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		resolved, err := git.CheckoutWithAuth(t.Context(), url, "v1", t.TempDir(), "user", "token")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(resolved).To(Equal(sha))
+	})
+
+	t.Run("should fail with invalid credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := git.CheckoutWithAuth(t.Context(), url, "v1", t.TempDir(), "user", "wrong")
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := git.Checkout(t.Context(), url, "v1", t.TempDir())
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+	repository, _ := setupRepository(t)
+
+	renderer, err := git.New(
+		[]git.Source{{Repository: repository, Path: "deploy/*.yaml"}},
+		git.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "git"))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, repository))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "deploy/config.yaml"))
+}
+
+func TestRendererAuth(t *testing.T) {
+	repository, _ := setupRepository(t)
+	url := newGitServer(t, repository, "user", "token")
+
+	t.Run("should render with valid credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := git.New(
+			[]git.Source{{Repository: url, Ref: "v1", Path: "deploy/*.yaml"}},
+			git.WithAuth(git.Auth{Username: "user", Password: "token"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := git.New([]git.Source{{Repository: url, Ref: "v1", Path: "deploy/*.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestCacheIntegration(t *testing.T) {
+	commit := func(t *testing.T, repository string) {
+		t.Helper()
+
+		writeFile(t, repository, "deploy/extra.yaml", deployment)
+		runGit(t, repository, "add", ".")
+		runGit(t, repository, "commit", "--quiet", "-m", "extra")
+	}
+
+	t.Run("should cache commit SHAs", func(t *testing.T) {
+		g := NewWithT(t)
+		repository, sha := setupRepository(t)
+
+		renderer, err := git.New(
+			[]git.Source{{Repository: repository, Ref: sha, Path: "deploy/*.yaml"}},
+			git.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		first, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The cached result is served without fetching the repository again
+		g.Expect(os.RemoveAll(repository)).To(Succeed())
+
+		second, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second).To(Equal(first))
+	})
+
+	t.Run("should not cache branches", func(t *testing.T) {
+		g := NewWithT(t)
+		repository, _ := setupRepository(t)
+
+		renderer, err := git.New(
+			[]git.Source{{Repository: repository, Ref: "main", Path: "deploy/*.yaml"}},
+			git.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		first, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(first).To(HaveLen(2))
+
+		commit(t, repository)
+
+		second, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second).To(HaveLen(3))
+	})
+
+	t.Run("should key cached results on the credentials", func(t *testing.T) {
+		g := NewWithT(t)
+		repository, sha := setupRepository(t)
+		url := newGitServer(t, repository, "user", "token")
+		renderCache := cache.NewRenderCache()
+
+		source := []git.Source{{Repository: url, Ref: sha, Path: "deploy/*.yaml"}}
+
+		authorized, err := git.New(source, git.RendererOptions{
+			Cache: renderCache,
+			Auth:  &git.Auth{Username: "user", Password: "token"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		unauthorized, err := git.New(source, git.RendererOptions{
+			Cache: renderCache,
+			Auth:  &git.Auth{Username: "user", Password: "wrong"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = authorized.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = unauthorized.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestIsCommitSHA(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(git.IsCommitSHA(strings.Repeat("a", 40))).To(BeTrue())
+	g.Expect(git.IsCommitSHA(strings.Repeat("0", 64))).To(BeTrue())
+	g.Expect(git.IsCommitSHA("main")).To(BeFalse())
+	g.Expect(git.IsCommitSHA(strings.Repeat("a", 12))).To(BeFalse())
+	g.Expect(git.IsCommitSHA(strings.Repeat("z", 40))).To(BeFalse())
+}
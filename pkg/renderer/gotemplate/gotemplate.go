@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"sync"
+	"text/template"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/dump"
@@ -13,6 +14,7 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
 )
 
@@ -26,8 +28,18 @@ type Source struct {
 
 	// Path specifies the glob pattern to match template files.
 	// Examples: "templates/*.tpl", "**/*.yaml.gotmpl"
+	// With inline templates, Path is optional and only identifies the source in errors and annotations.
 	Path string
 
+	// Template is the content of a single inline template, for programs building templates dynamically.
+	// Optional; cannot be combined with FS or Templates.
+	Template string
+
+	// Templates are inline templates keyed by name, rendered in name order. As with files, templates
+	// whose name starts with an underscore only provide named templates to the others and are not rendered.
+	// Optional; cannot be combined with FS or Template.
+	Templates map[string]string
+
 	// Values provides data to be substituted into templates during rendering.
 	// Function is called during rendering to obtain dynamic values.
 	// Accessible within templates via dot notation (e.g., {{ .FieldName }}).
@@ -43,6 +55,7 @@ type Source struct {
 type Renderer struct {
 	inputs []*sourceHolder
 	opts   RendererOptions
+	base   *template.Template
 }
 
 // New creates a new GoTemplate Renderer with the given inputs and options.
@@ -50,12 +63,19 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	rendererOpts := RendererOptions{
 		Filters:      make([]types.Filter, 0),
 		Transformers: make([]types.Transformer, 0),
+		MissingKey:   MissingKeyError,
 	}
 
 	for _, opt := range opts {
 		opt.ApplyTo(&rendererOpts)
 	}
 
+	switch rendererOpts.MissingKey {
+	case MissingKeyError, MissingKeyZero, MissingKeyDefault:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidMissingKey, rendererOpts.MissingKey)
+	}
+
 	// Wrap sources in holders and validate
 	holders := make([]*sourceHolder, len(inputs))
 	for i := range inputs {
@@ -71,11 +91,17 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	r := &Renderer{
 		inputs: holders,
 		opts:   rendererOpts,
+		base:   baseTemplate(rendererOpts),
 	}
 
 	return r, nil
 }
 
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
 // Process executes the rendering logic for all configured inputs.
 // This method is safe for concurrent use.
 func (r *Renderer) Process(ctx context.Context, renderTimeValues map[string]any) ([]unstructured.Unstructured, error) {
@@ -108,6 +134,28 @@ func (r *Renderer) Name() string {
 	return rendererType
 }
 
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the templates matched by the FS sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		if holder.inline() {
+			continue
+		}
+
+		sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.Path})
+	}
+
+	return sources
+}
+
 func (r *Renderer) values(
 	ctx context.Context,
 	holder *sourceHolder,
@@ -146,7 +194,7 @@ func (r *Renderer) renderSingle(
 	renderTimeValues map[string]any,
 ) ([]unstructured.Unstructured, error) {
 	// Parse templates if not already parsed (thread-safe lazy loading)
-	templates, err := holder.LoadTemplates()
+	templates, err := holder.LoadTemplates(r.base)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +211,10 @@ func (r *Renderer) renderSingle(
 
 	// Compute cache key from template path and values
 	type cacheKeyData struct {
-		Path   string
-		Values any
+		Path      string
+		Template  string
+		Templates map[string]string
+		Values    any
 	}
 
 	var cacheKey string
@@ -172,8 +222,10 @@ func (r *Renderer) renderSingle(
 	// Check cache (if enabled)
 	if r.opts.Cache != nil {
 		cacheKey = dump.ForHash(cacheKeyData{
-			Path:   holder.Path,
-			Values: values,
+			Path:      holder.Path,
+			Template:  holder.Template,
+			Templates: holder.Templates,
+			Values:    values,
 		})
 
 		// ensure objects are evicted
@@ -187,12 +239,7 @@ func (r *Renderer) renderSingle(
 	result := make([]unstructured.Unstructured, 0)
 
 	// Execute each template
-	for _, t := range templates.Templates() {
-		// Skip the root template
-		if t.Name() == "" {
-			continue
-		}
-
+	for _, t := range templates {
 		// Execute the template
 		var buf bytes.Buffer
 		if err := t.Execute(&buf, values); err != nil {
@@ -1,6 +1,10 @@
 package gotemplate
 
 import (
+	"errors"
+	"maps"
+	"text/template"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
@@ -8,6 +12,26 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
 )
 
+// MissingKey controls the behavior of templates indexing a map with a key that is not present.
+type MissingKey string
+
+const (
+	// MissingKeyError stops the rendering with an error.
+	MissingKeyError MissingKey = "error"
+
+	// MissingKeyZero renders the zero value of the map element type, e.g. an empty string for
+	// map[string]string. For map[string]any values the zero value is nil, rendered as "<no value>".
+	MissingKeyZero MissingKey = "zero"
+
+	// MissingKeyDefault renders "<no value>", the text/template default.
+	MissingKeyDefault MissingKey = "default"
+)
+
+var (
+	// ErrInvalidMissingKey is returned when the missing key behavior is not one of the MissingKey constants.
+	ErrInvalidMissingKey = errors.New("invalid missing key behavior")
+)
+
 // RendererOption is a generic option for RendererOptions.
 type RendererOption = util.Option[RendererOptions]
 
@@ -24,6 +48,20 @@ type RendererOptions struct {
 
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
+
+	// Funcs are custom functions available to templates. They override Sprig functions with the same name.
+	Funcs template.FuncMap
+
+	// Sprig enables the Sprig function library in templates.
+	Sprig bool
+
+	// LeftDelim and RightDelim are the template action delimiters. Empty means "{{" and "}}".
+	LeftDelim  string
+	RightDelim string
+
+	// MissingKey controls the behavior of templates on missing map keys.
+	// Default: MissingKeyError.
+	MissingKey MissingKey
 }
 
 // ApplyTo applies the renderer options to the target configuration.
@@ -36,6 +74,24 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	}
 
 	target.SourceAnnotations = opts.SourceAnnotations
+
+	if opts.Funcs != nil {
+		target.Funcs = opts.Funcs
+	}
+
+	target.Sprig = opts.Sprig
+
+	if opts.LeftDelim != "" {
+		target.LeftDelim = opts.LeftDelim
+	}
+
+	if opts.RightDelim != "" {
+		target.RightDelim = opts.RightDelim
+	}
+
+	if opts.MissingKey != "" {
+		target.MissingKey = opts.MissingKey
+	}
 }
 
 // WithFilter adds a renderer-specific filter to this GoTemplate renderer's processing chain.
@@ -74,3 +130,45 @@ func WithSourceAnnotations(enabled bool) RendererOption {
 		opts.SourceAnnotations = enabled
 	})
 }
+
+// WithFuncs adds custom functions available to all templates of the renderer. Functions added by later
+// calls override functions with the same name, as well as Sprig functions.
+// Default: only the text/template builtin functions.
+func WithFuncs(funcs template.FuncMap) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		if opts.Funcs == nil {
+			opts.Funcs = make(template.FuncMap, len(funcs))
+		}
+
+		maps.Copy(opts.Funcs, funcs)
+	})
+}
+
+// WithSprig enables or disables the Sprig function library (https://masterminds.github.io/sprig/), e.g.
+// default, b64enc, indent or toJson, plus toYaml as in Helm templates.
+// Default: false (disabled).
+func WithSprig(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Sprig = enabled
+	})
+}
+
+// WithDelims sets the template action delimiters, e.g. "[[" and "]]", so templates can coexist with
+// other templating systems using the default delimiters in the same files. An empty delimiter
+// keeps the corresponding default.
+// Default: "{{" and "}}".
+func WithDelims(left string, right string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.LeftDelim = left
+		opts.RightDelim = right
+	})
+}
+
+// WithMissingKey sets the behavior of templates indexing a map with a missing key: MissingKeyError fails
+// the rendering, MissingKeyZero renders the zero value and MissingKeyDefault renders "<no value>".
+// Default: MissingKeyError.
+func WithMissingKey(mode MissingKey) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.MissingKey = mode
+	})
+}
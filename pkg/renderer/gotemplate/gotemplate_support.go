@@ -2,14 +2,30 @@ package gotemplate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 	"sync"
 	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+
 	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
 )
 
+// inlineTemplateName is the name of the template of Source.Template.
+const inlineTemplateName = "template"
+
+var (
+	// ErrMultipleTemplateSources is returned when more than one of FS, Template and Templates is set.
+	ErrMultipleTemplateSources = errors.New("only one of FS, Template and Templates can be set")
+)
+
 // Values returns a Values function that always returns the provided static values.
 // This is a convenience helper for the common case of non-dynamic values.
 func Values(values any) func(context.Context) (any, error) {
@@ -25,12 +41,27 @@ type sourceHolder struct {
 	// Mutex protects concurrent access to templates field
 	mu *sync.RWMutex
 
-	// Parsed templates (lazy-loaded on first Process call, protected by mu)
-	templates *template.Template
+	// Parsed templates to render, in file order (lazy-loaded on first Process call, protected by mu)
+	templates []*template.Template
 }
 
 // Validate checks if the Source configuration is valid.
 func (h *sourceHolder) Validate() error {
+	sources := 0
+	for _, set := range []bool{h.FS != nil, h.Template != "", len(h.Templates) > 0} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return ErrMultipleTemplateSources
+	}
+
+	if h.inline() {
+		return nil
+	}
+
 	if h.FS == nil {
 		return utilerrors.ErrFsRequired
 	}
@@ -41,9 +72,13 @@ func (h *sourceHolder) Validate() error {
 	return nil
 }
 
-// LoadTemplates returns parsed templates, loading them lazily if needed.
+// LoadTemplates returns the parsed templates to render, loading them lazily if needed.
+// All matched files, or inline templates, are parsed into one template set, so named templates
+// defined with {{ define }} in any of them can be used by the others; files whose name starts
+// with an underscore, e.g. _helpers.tpl, only provide named templates and are not rendered.
+// The templates are parsed into a clone of base, which carries the functions and options of the renderer.
 // Thread-safe for concurrent use.
-func (h *sourceHolder) LoadTemplates() (*template.Template, error) {
+func (h *sourceHolder) LoadTemplates(base *template.Template) ([]*template.Template, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -51,12 +86,112 @@ func (h *sourceHolder) LoadTemplates() (*template.Template, error) {
 		return h.templates, nil
 	}
 
-	tmpl, err := template.ParseFS(h.FS, h.Path)
+	tmpl, err := base.Clone()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse templates (path: %s): %w", h.Path, err)
+		return nil, fmt.Errorf("failed to clone templates (path: %s): %w", h.Path, err)
 	}
 
-	h.templates = tmpl.Option("missingkey=error")
+	names, err := h.parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*template.Template, 0, len(names))
+	seen := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if strings.HasPrefix(name, "_") || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		if t := tmpl.Lookup(name); t != nil {
+			templates = append(templates, t)
+		}
+	}
+
+	h.templates = templates
 
 	return h.templates, nil
 }
+
+// inline reports whether the source holds inline templates rather than an FS.
+func (h *sourceHolder) inline() bool {
+	return h.Template != "" || len(h.Templates) > 0
+}
+
+// parse parses the templates of the source into tmpl and returns their names in rendering order.
+// Templates from files are named after the base name of their file.
+func (h *sourceHolder) parse(tmpl *template.Template) ([]string, error) {
+	switch {
+	case h.Template != "":
+		if _, err := tmpl.New(inlineTemplateName).Parse(h.Template); err != nil {
+			return nil, fmt.Errorf("failed to parse inline template: %w", err)
+		}
+
+		return []string{inlineTemplateName}, nil
+	case len(h.Templates) > 0:
+		names := slices.Sorted(maps.Keys(h.Templates))
+
+		for _, name := range names {
+			if _, err := tmpl.New(name).Parse(h.Templates[name]); err != nil {
+				return nil, fmt.Errorf("failed to parse inline template %s: %w", name, err)
+			}
+		}
+
+		return names, nil
+	}
+
+	if _, err := tmpl.ParseFS(h.FS, h.Path); err != nil {
+		return nil, fmt.Errorf("failed to parse templates (path: %s): %w", h.Path, err)
+	}
+
+	matches, err := fs.Glob(h.FS, h.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match templates (path: %s): %w", h.Path, err)
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, path.Base(match))
+	}
+
+	return names, nil
+}
+
+// baseTemplate returns the empty template all templates of the renderer are parsed into,
+// configured with the functions, delimiters and missing key behavior of the options.
+func baseTemplate(opts RendererOptions) *template.Template {
+	return template.New("").
+		Funcs(templateFuncs(opts)).
+		Delims(opts.LeftDelim, opts.RightDelim).
+		Option("missingkey=" + string(opts.MissingKey))
+}
+
+// templateFuncs returns the functions available to templates: Sprig functions, if enabled,
+// overridden by the custom functions.
+func templateFuncs(opts RendererOptions) template.FuncMap {
+	funcs := make(template.FuncMap)
+
+	if opts.Sprig {
+		maps.Copy(funcs, sprig.TxtFuncMap())
+
+		funcs["toYaml"] = toYAML
+	}
+
+	maps.Copy(funcs, opts.Funcs)
+
+	return funcs
+}
+
+// toYAML marshals a value to YAML without the trailing newline, like the Helm toYaml function.
+// Errors are rendered as an empty string, as Helm does.
+func toYAML(v any) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(string(data), "\n")
+}
@@ -2,8 +2,10 @@ package gotemplate_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"testing/fstest"
+	"text/template"
 
 	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
 	"github.com/onsi/gomega/types"
@@ -809,3 +811,312 @@ func TestSourceAnnotations(t *testing.T) {
 		}
 	})
 }
+
+const sprigTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ get . "name" | default "sprig-config" }}
+data:
+  secret: {{ "value" | b64enc }}
+  upper: {{ shout "value" }}
+  labels: |
+    {{- toYaml .labels | nindent 4 }}`
+
+func TestFuncs(t *testing.T) {
+	fs := fstest.MapFS{
+		"templates/configmap.yaml.tpl": &fstest.MapFile{Data: []byte(sprigTemplate)},
+	}
+
+	shout := gotemplate.WithFuncs(template.FuncMap{
+		"shout": strings.ToUpper,
+	})
+
+	t.Run("should render Sprig and custom functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS:   fs,
+				Path: "templates/*.tpl",
+				Values: gotemplate.Values(map[string]any{
+					"labels": map[string]any{"app": "test"},
+				}),
+			}},
+			gotemplate.WithSprig(true),
+			shout,
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0]).Should(And(
+			jqmatcher.Match(`.metadata.name == "sprig-config"`),
+			jqmatcher.Match(`.data.secret == "dmFsdWU="`),
+			jqmatcher.Match(`.data.upper == "VALUE"`),
+			jqmatcher.Match(`.data.labels == "app: test"`),
+		))
+	})
+
+	t.Run("should override Sprig functions with custom functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS:   fs,
+				Path: "templates/*.tpl",
+				Values: gotemplate.Values(map[string]any{
+					"labels": map[string]any{"app": "test"},
+				}),
+			}},
+			gotemplate.WithSprig(true),
+			shout,
+			gotemplate.WithFuncs(template.FuncMap{
+				"b64enc": func(s string) string { return "encoded-" + s },
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].Object).Should(HaveKeyWithValue("data", HaveKeyWithValue("secret", "encoded-value")))
+	})
+
+	t.Run("should fail on Sprig functions when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS:   fs,
+				Path: "templates/*.tpl",
+			}},
+			shout,
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+const helpersTemplate = `{{- define "labels" }}
+    app: {{ .Repo }}
+    component: {{ .Component }}
+{{- end -}}`
+
+const partialConfigMapTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Repo }}-config
+  labels:
+{{- template "labels" . }}`
+
+const partialServiceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Repo }}-service
+  labels:
+{{- template "labels" . }}`
+
+func TestPartials(t *testing.T) {
+	t.Run("should share named templates across files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS: fstest.MapFS{
+					"templates/_helpers.tpl":  &fstest.MapFile{Data: []byte(helpersTemplate)},
+					"templates/configmap.tpl": &fstest.MapFile{Data: []byte(partialConfigMapTemplate)},
+					"templates/service.tpl":   &fstest.MapFile{Data: []byte(partialServiceTemplate)},
+				},
+				Path: "templates/*.tpl",
+				Values: gotemplate.Values(map[string]any{
+					"Repo":      "test-app",
+					"Component": "frontend",
+				}),
+			}},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		// Only the non-underscore files are rendered, in file order
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("test-app-config"))
+		g.Expect(objects[1].GetName()).Should(Equal("test-app-service"))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).Should(Equal(map[string]string{
+				"app":       "test-app",
+				"component": "frontend",
+			}))
+		}
+	})
+
+	t.Run("should fail on undefined named templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS: fstest.MapFS{
+					"templates/configmap.tpl": &fstest.MapFile{Data: []byte(partialConfigMapTemplate)},
+				},
+				Path: "templates/*.tpl",
+				Values: gotemplate.Values(map[string]any{
+					"Repo":      "test-app",
+					"Component": "frontend",
+				}),
+			}},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestTemplateOptions(t *testing.T) {
+	const delimsTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: [[ .Repo ]]-config
+data:
+  passthrough: "{{ .NotRendered }}"`
+
+	const missingKeyTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-config
+data:
+  value: "{{ .missing }}"`
+
+	render := func(g *WithT, content string, opts ...gotemplate.RendererOption) ([]unstructured.Unstructured, error) {
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				FS: fstest.MapFS{
+					"templates/configmap.tpl": &fstest.MapFile{Data: []byte(content)},
+				},
+				Path:   "templates/*.tpl",
+				Values: gotemplate.Values(map[string]any{"Repo": "test-app"}),
+			}},
+			opts...,
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		return renderer.Process(t.Context(), nil)
+	}
+
+	t.Run("should use custom delimiters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := render(g, delimsTemplate, gotemplate.WithDelims("[[", "]]"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("test-app-config"))
+		g.Expect(objects[0].Object).Should(HaveKeyWithValue("data", HaveKeyWithValue("passthrough", "{{ .NotRendered }}")))
+	})
+
+	t.Run("should fail on missing keys by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := render(g, missingKeyTemplate)
+		g.Expect(err).Should(HaveOccurred())
+	})
+
+	t.Run("should render zero values on missing keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := render(g, missingKeyTemplate, gotemplate.WithMissingKey(gotemplate.MissingKeyZero))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].Object).Should(HaveKeyWithValue("data", HaveKeyWithValue("value", "<no value>")))
+	})
+
+	t.Run("should reject invalid missing key behaviors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := gotemplate.New(nil, gotemplate.WithMissingKey("ignore"))
+		g.Expect(err).Should(MatchError(gotemplate.ErrInvalidMissingKey))
+	})
+}
+
+func TestInlineTemplates(t *testing.T) {
+	values := gotemplate.Values(map[string]any{
+		"Repo":      "test-app",
+		"Component": "frontend",
+		"Port":      8080,
+	})
+
+	t.Run("should render a single inline template", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				Template: podTemplate,
+				Values:   values,
+			}},
+			gotemplate.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("test-app-pod"))
+		g.Expect(objects[0].GetAnnotations()).Should(HaveKeyWithValue(pkgtypes.AnnotationSourceFile, "template"))
+	})
+
+	t.Run("should render inline templates in name order with partials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{{
+				Templates: map[string]string{
+					"service.yaml":   partialServiceTemplate,
+					"configmap.yaml": partialConfigMapTemplate,
+					"_helpers.tpl":   helpersTemplate,
+				},
+				Values: values,
+			}},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetName()).Should(Equal("test-app-config"))
+		g.Expect(objects[1].GetName()).Should(Equal("test-app-service"))
+	})
+
+	t.Run("should cache inline templates by content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := gotemplate.New(
+			[]gotemplate.Source{
+				{Template: podTemplate, Values: values},
+				{Template: configMapTemplate, Values: values},
+			},
+			gotemplate.WithCache(),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetKind()).Should(Equal("Pod"))
+		g.Expect(objects[1].GetKind()).Should(Equal("ConfigMap"))
+	})
+
+	t.Run("should reject multiple template sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := gotemplate.New([]gotemplate.Source{{
+			FS:       fstest.MapFS{},
+			Path:     "*.tpl",
+			Template: podTemplate,
+		}})
+		g.Expect(err).Should(MatchError(gotemplate.ErrMultipleTemplateSources))
+	})
+}
@@ -3,8 +3,11 @@ package helm
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"sync"
 
+	"golang.org/x/sync/errgroup"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/engine"
@@ -15,6 +18,9 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
 )
 
 const rendererType = "helm"
@@ -24,10 +30,15 @@ type Source struct {
 	// Repo is the repository URL for chart lookup. Optional for local or OCI charts.
 	Repo string
 
-	// Chart specifies the chart to render. Supports OCI references (oci://registry/chart:tag)
-	// or local filesystem paths. Required.
+	// Chart specifies the chart to render. Supports OCI references (oci://registry/chart:tag),
+	// local chart directories or packaged chart archives (.tgz). When FS is set, Chart is
+	// the path of the chart directory or archive within FS. Required.
 	Chart string
 
+	// FS is the filesystem holding the chart, e.g. an embed.FS bundling charts into the binary.
+	// Optional; when set, Repo and ReleaseVersion are ignored and nothing is fetched remotely.
+	FS fs.FS
+
 	// ReleaseName is the Helm release name used in template rendering metadata.
 	// Required for proper .Release.Name substitution in templates.
 	ReleaseName string
@@ -35,15 +46,48 @@ type Source struct {
 	// ReleaseVersion constrains the chart version to fetch. Optional; uses latest if empty.
 	ReleaseVersion string
 
+	// Namespace is the release namespace exposed to templates as .Release.Namespace. Optional.
+	Namespace string
+
+	// ApplyNamespace sets Namespace on the rendered namespaced objects that do not declare one.
+	// Cluster-scoped objects, built-in or defined by CRDs rendered from the chart, are left untouched.
+	// Default is false.
+	ApplyNamespace bool
+
+	// ValuesFiles are YAML values files merged in order, later files taking precedence.
+	// Paths are read from FS when set, from the local filesystem otherwise. Optional.
+	// Precedence: chart defaults < ValuesFiles < Values < render-time values.
+	ValuesFiles []string
+
 	// Values provides template variable overrides during chart rendering.
 	// Function is called during rendering to obtain dynamic values.
 	// Merged with chart defaults via chartutil.ToRenderValues.
 	Values func(context.Context) (map[string]any, error)
 
+	// KubeVersion is the Kubernetes version exposed to templates as .Capabilities.KubeVersion,
+	// e.g. "v1.30.0". Optional; overrides the renderer-level version set via WithKubeVersion.
+	KubeVersion string
+
+	// APIVersions are additional API versions exposed to templates as .Capabilities.APIVersions,
+	// in group/version or group/version/kind form. Optional; added to the renderer-level versions.
+	APIVersions []string
+
+	// Auth overrides the renderer-level registry and repository authentication for this source.
+	// Optional.
+	Auth *registry.Auth
+
 	// ProcessDependencies determines whether chart dependencies should be processed.
 	// If true, chartutil.ProcessDependencies will be called during rendering.
 	// Default is false.
 	ProcessDependencies bool
+
+	// Dependencies enables or disables chart dependencies by alias or name, taking precedence over
+	// their condition and tags. Implies ProcessDependencies. Optional.
+	Dependencies map[string]bool
+
+	// Tags enables or disables the chart dependencies declaring the given tags, like the
+	// tags values section. Implies ProcessDependencies. Optional.
+	Tags map[string]bool
 }
 
 // Renderer handles Helm rendering operations.
@@ -56,7 +100,11 @@ type Renderer struct {
 	settings   *cli.EnvSettings
 	inputs     []*sourceHolder
 	helmEngine engine.Engine
+	lookup     engine.ClientProvider
 	opts       RendererOptions
+
+	// infos holds the release information of the cached render results, by cache key
+	infos cache.Interface[ReleaseInfo]
 }
 
 // New creates a new Helm Renderer with the given inputs and options.
@@ -64,6 +112,7 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	rendererOpts := RendererOptions{
 		Filters:      make([]types.Filter, 0),
 		Transformers: make([]types.Transformer, 0),
+		Concurrency:  1,
 	}
 
 	// Apply options
@@ -71,6 +120,12 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 		opt.ApplyTo(&rendererOpts)
 	}
 
+	if rendererOpts.KubeVersion != "" {
+		if _, err := chartutil.ParseKubeVersion(rendererOpts.KubeVersion); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrKubeVersionInvalid, err)
+		}
+	}
+
 	settings := rendererOpts.Settings
 	if settings == nil {
 		settings = cli.New()
@@ -88,51 +143,123 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 		}
 	}
 
+	helmEngine, lookup, err := newHelmEngine(rendererOpts)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &Renderer{
-		settings: settings,
-		inputs:   holders,
-		helmEngine: engine.Engine{
-			LintMode: rendererOpts.LintMode,
-			Strict:   rendererOpts.Strict,
-		},
-		opts: rendererOpts,
+		settings:   settings,
+		inputs:     holders,
+		helmEngine: helmEngine,
+		lookup:     lookup,
+		opts:       rendererOpts,
+	}
+
+	if rendererOpts.Cache != nil {
+		r.infos = cache.New[ReleaseInfo]()
 	}
 
 	return r, nil
 }
 
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
 // Process executes the rendering logic for all configured inputs.
 // It implements the types.Renderer interface.
 // This method is safe for concurrent use.
 func (r *Renderer) Process(ctx context.Context, renderTimeValues map[string]any) ([]unstructured.Unstructured, error) {
-	allObjects := make([]unstructured.Unstructured, 0)
+	objects, _, err := r.ProcessWithInfo(ctx, renderTimeValues)
+
+	return objects, err
+}
+
+// ProcessWithInfo is Process also returning the release information of each source, in source order,
+// e.g. to display the rendered NOTES.txt. Results served from the cache come with the release
+// information of the render they were cached from.
+// This method is safe for concurrent use.
+func (r *Renderer) ProcessWithInfo(
+	ctx context.Context,
+	renderTimeValues map[string]any,
+) ([]unstructured.Unstructured, []ReleaseInfo, error) {
+	results := make([][]unstructured.Unstructured, len(r.inputs))
+	infos := make([]ReleaseInfo, len(r.inputs))
+
+	// Sources are independent, render them with at most Concurrency workers.
+	// Results are collected in the original source order for consistent output.
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(r.opts.Concurrency, 1))
 
 	for i := range r.inputs {
-		objects, err := r.renderSingle(ctx, r.inputs[i], renderTimeValues)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"error rendering helm chart %s (release: %s): %w",
-				r.inputs[i].Chart,
-				r.inputs[i].ReleaseName,
-				err,
-			)
-		}
+		g.Go(func() error {
+			objects, info, err := r.processSource(gctx, r.inputs[i], renderTimeValues)
+			if err != nil {
+				return err
+			}
+
+			results[i] = objects
+			infos[i] = info
 
-		// Apply renderer-level filters and transformers per-source for better error context
-		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	allObjects := make([]unstructured.Unstructured, 0)
+	for _, objects := range results {
+		allObjects = append(allObjects, objects...)
+	}
+
+	return allObjects, infos, nil
+}
+
+// processSource renders a single source and applies the renderer-level filters and transformers.
+func (r *Renderer) processSource(
+	ctx context.Context,
+	holder *sourceHolder,
+	renderTimeValues map[string]any,
+) ([]unstructured.Unstructured, ReleaseInfo, error) {
+	objects, info, err := r.renderSingle(ctx, holder, renderTimeValues)
+	if err != nil {
+		return nil, ReleaseInfo{}, fmt.Errorf(
+			"error rendering helm chart %s (release: %s): %w",
+			holder.Chart,
+			holder.ReleaseName,
+			err,
+		)
+	}
+
+	// Unwrap List objects (if enabled)
+	if r.opts.ExpandLists {
+		objects, err = k8s.ExpandLists(objects)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"error applying filters/transformers to helm chart %s (release: %s): %w",
-				r.inputs[i].Chart,
-				r.inputs[i].ReleaseName,
+			return nil, ReleaseInfo{}, fmt.Errorf(
+				"error expanding lists in helm chart %s (release: %s): %w",
+				holder.Chart,
+				holder.ReleaseName,
 				err,
 			)
 		}
+	}
 
-		allObjects = append(allObjects, transformed...)
+	// Apply renderer-level filters and transformers per-source for better error context
+	transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+	if err != nil {
+		return nil, ReleaseInfo{}, fmt.Errorf(
+			"error applying filters/transformers to helm chart %s (release: %s): %w",
+			holder.Chart,
+			holder.ReleaseName,
+			err,
+		)
 	}
 
-	return allObjects, nil
+	return transformed, info, nil
 }
 
 // Name returns the renderer type identifier.
@@ -140,12 +267,46 @@ func (r *Renderer) Name() string {
 	return rendererType
 }
 
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled,
+// and the loaded charts of FS and local sources, so edits to local charts are picked up by the next render.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+		cache.Clear(r.infos)
+	}
+
+	for _, holder := range r.inputs {
+		holder.ResetChart()
+	}
+}
+
+// WatchSources implements types.Watchable by returning the charts of FS and local sources and
+// their values files. Charts of remote sources are not included.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		if holder.FS != nil || holder.isLocal() {
+			sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.Chart})
+		}
+
+		for _, file := range holder.ValuesFiles {
+			sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: file})
+		}
+	}
+
+	return sources
+}
+
 func (r *Renderer) values(
 	ctx context.Context,
 	holder *sourceHolder,
 	renderTimeValues map[string]any,
 ) (map[string]any, error) {
-	sourceValues := map[string]any{}
+	sourceValues, err := holder.valuesFiles()
+	if err != nil {
+		return nil, err
+	}
 
 	if holder.Values != nil {
 		v, err := holder.Values(ctx)
@@ -157,7 +318,9 @@ func (r *Renderer) values(
 				err,
 			)
 		}
-		sourceValues = v
+
+		// Deep merge with Source values taking precedence over values files
+		sourceValues = util.DeepMerge(sourceValues, v)
 	}
 
 	// Deep merge with render-time values taking precedence
@@ -169,6 +332,7 @@ func (r *Renderer) values(
 func (r *Renderer) prepareRenderValues(
 	ctx context.Context,
 	holder *sourceHolder,
+	helmChart *chart.Chart,
 	renderTimeValues map[string]any,
 ) (chartutil.Values, error) {
 	// Get values dynamically (includes render-time values)
@@ -182,9 +346,19 @@ func (r *Renderer) prepareRenderValues(
 		)
 	}
 
+	// Typed tags take precedence over the tags values section
+	if len(holder.Tags) > 0 {
+		tags := make(map[string]any, len(holder.Tags))
+		for tag, enabled := range holder.Tags {
+			tags[tag] = enabled
+		}
+
+		values = util.DeepMerge(values, map[string]any{"tags": tags})
+	}
+
 	// Process dependencies if enabled
-	if holder.ProcessDependencies {
-		if err := chartutil.ProcessDependencies(holder.chart, values); err != nil {
+	if holder.processDependencies() {
+		if err := chartutil.ProcessDependencies(helmChart, values); err != nil {
 			return nil, fmt.Errorf(
 				"failed to process dependencies for chart %q (release %q): %w",
 				holder.Chart,
@@ -194,16 +368,22 @@ func (r *Renderer) prepareRenderValues(
 		}
 	}
 
+	caps, err := r.capabilities(holder)
+	if err != nil {
+		return nil, err
+	}
+
 	// Prepare render values
 	renderValues, err := chartutil.ToRenderValues(
-		holder.chart,
+		helmChart,
 		values,
 		chartutil.ReleaseOptions{
 			Name:      holder.ReleaseName,
+			Namespace: holder.Namespace,
 			Revision:  1,
 			IsInstall: true,
 		},
-		nil,
+		caps,
 	)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -224,17 +404,30 @@ func (r *Renderer) renderSingle(
 	ctx context.Context,
 	holder *sourceHolder,
 	renderTimeValues map[string]any,
-) ([]unstructured.Unstructured, error) {
+) ([]unstructured.Unstructured, ReleaseInfo, error) {
 	// Load chart if not already loaded (thread-safe lazy loading)
-	chart, err := holder.LoadChart(r.settings)
+	chart, err := holder.LoadChart(r.settings, r.opts)
 	if err != nil {
-		return nil, err
+		return nil, ReleaseInfo{}, err
+	}
+
+	// Processing dependencies mutates the chart, work on a private copy
+	if holder.processDependencies() {
+		chart, err = holder.dependencyChart(chart)
+		if err != nil {
+			return nil, ReleaseInfo{}, fmt.Errorf(
+				"failed to resolve dependencies for chart %q (release %q): %w",
+				holder.Chart,
+				holder.ReleaseName,
+				err,
+			)
+		}
 	}
 
 	// Prepare render values (includes render-time values)
-	renderValues, err := r.prepareRenderValues(ctx, holder, renderTimeValues)
+	renderValues, err := r.prepareRenderValues(ctx, holder, chart, renderTimeValues)
 	if err != nil {
-		return nil, fmt.Errorf(
+		return nil, ReleaseInfo{}, fmt.Errorf(
 			"failed to prepare render values for chart %q (release %q): %w",
 			holder.Chart,
 			holder.ReleaseName,
@@ -247,6 +440,8 @@ func (r *Renderer) renderSingle(
 		Chart          string
 		ReleaseName    string
 		ReleaseVersion string
+		Dependencies   map[string]bool
+		ApplyNamespace bool
 		RenderValues   chartutil.Values
 	}
 
@@ -258,43 +453,75 @@ func (r *Renderer) renderSingle(
 			Chart:          holder.Chart,
 			ReleaseName:    holder.ReleaseName,
 			ReleaseVersion: holder.ReleaseVersion,
+			Dependencies:   holder.Dependencies,
+			ApplyNamespace: holder.ApplyNamespace,
 			RenderValues:   renderValues,
 		})
 
 		// ensure objects are evicted
 		r.opts.Cache.Sync()
+		r.infos.Sync()
 
+		// Results cached without their release information, e.g. by another renderer sharing the
+		// cache, are rendered again
 		if cached, found := r.opts.Cache.Get(cacheKey); found {
-			return cached, nil
+			if info, found := r.infos.Get(cacheKey); found {
+				return cached, info, nil
+			}
 		}
 	}
 
 	// Render the chart
-	files, err := r.helmEngine.Render(chart, renderValues)
+	var files map[string]string
+	if r.lookup != nil {
+		files, err = engine.RenderWithClientProvider(chart, renderValues, r.lookup)
+	} else {
+		files, err = r.helmEngine.Render(chart, renderValues)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to render chart %q (release %q): %w", holder.Chart, holder.ReleaseName, err)
+		return nil, ReleaseInfo{}, fmt.Errorf("failed to render chart %q (release %q): %w", holder.Chart, holder.ReleaseName, err)
 	}
 
 	result := make([]unstructured.Unstructured, 0)
 
-	// Process CRDs first
-	crdObjects, err := r.processCRDs(chart, holder)
-	if err != nil {
-		return nil, err
+	// Process CRDs first (if enabled)
+	if !r.opts.SkipCRDs {
+		crdObjects, err := r.processCRDs(chart, holder)
+		if err != nil {
+			return nil, ReleaseInfo{}, err
+		}
+		result = append(result, crdObjects...)
 	}
-	result = append(result, crdObjects...)
 
 	// Process rendered templates
 	templateObjects, err := r.processRenderedTemplates(files, holder)
 	if err != nil {
-		return nil, err
+		return nil, ReleaseInfo{}, err
+	}
+	templateObjects = r.applyHookPolicy(templateObjects)
+
+	// Run the post-renderer (if configured) on the rendered templates
+	if r.opts.PostRenderer != nil {
+		templateObjects, err = r.postRender(templateObjects)
+		if err != nil {
+			return nil, ReleaseInfo{}, fmt.Errorf("failed to post-render chart %q (release %q): %w", holder.Chart, holder.ReleaseName, err)
+		}
 	}
+
 	result = append(result, templateObjects...)
 
+	if holder.ApplyNamespace && holder.Namespace != "" {
+		applyNamespace(result, holder.Namespace)
+	}
+
+	r.addReleaseAnnotations(result, chart, holder)
+	info := newReleaseInfo(holder, chart, files)
+
 	// Cache result (if enabled)
 	if r.opts.Cache != nil {
 		r.opts.Cache.Set(cacheKey, result)
+		r.infos.Set(cacheKey, info)
 	}
 
-	return result, nil
+	return result, info, nil
 }
@@ -0,0 +1,120 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+var (
+	// ErrDependencyNotFound is returned when a dependency toggle does not match any chart dependency.
+	ErrDependencyNotFound = errors.New("dependency not found in chart")
+
+	// ErrDependencyMissing is returned when a dependency declared in Chart.yaml is not vendored in charts/.
+	ErrDependencyMissing = errors.New("chart dependency missing")
+)
+
+// processDependencies reports whether chart dependencies are processed for the source.
+func (h *sourceHolder) processDependencies() bool {
+	return h.ProcessDependencies || len(h.Dependencies) > 0 || len(h.Tags) > 0
+}
+
+// dependencyChart returns a private copy of the chart with the dependency toggles applied.
+// Processing dependencies mutates the chart, so each render works on its own copy to keep
+// renders with different values independent.
+func (h *sourceHolder) dependencyChart(c *chart.Chart) (*chart.Chart, error) {
+	files := make([]*loader.BufferedFile, 0, len(c.Raw))
+	for _, f := range c.Raw {
+		files = append(files, &loader.BufferedFile{Name: f.Name, Data: f.Data})
+	}
+
+	copied, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy chart: %w", err)
+	}
+
+	if err := action.CheckDependencies(copied, copied.Metadata.Dependencies); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDependencyMissing, err)
+	}
+
+	if err := applyDependencyToggles(copied, h.Dependencies); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+// applyDependencyToggles enables or disables the chart dependencies matching the toggles by
+// alias or name. Enabled dependencies ignore their condition and tags, disabled ones are removed.
+func applyDependencyToggles(c *chart.Chart, toggles map[string]bool) error {
+	if len(toggles) == 0 {
+		return nil
+	}
+
+	available := make([]string, 0, len(c.Metadata.Dependencies))
+	for _, dep := range c.Metadata.Dependencies {
+		available = append(available, dependencyName(dep))
+	}
+
+	for name := range toggles {
+		if !slices.Contains(available, name) {
+			sort.Strings(available)
+
+			return fmt.Errorf("%w: %q (available: %s)", ErrDependencyNotFound, name, strings.Join(available, ", "))
+		}
+	}
+
+	declared := make(map[string]struct{}, len(c.Metadata.Dependencies))
+	referenced := make(map[string]struct{}, len(c.Metadata.Dependencies))
+	kept := make([]*chart.Dependency, 0, len(c.Metadata.Dependencies))
+
+	for _, dep := range c.Metadata.Dependencies {
+		declared[dep.Name] = struct{}{}
+
+		enabled, ok := toggles[dependencyName(dep)]
+		if ok && !enabled {
+			continue
+		}
+
+		if ok {
+			dep.Condition = ""
+			dep.Tags = nil
+		}
+
+		referenced[dep.Name] = struct{}{}
+		kept = append(kept, dep)
+	}
+
+	// Drop the vendored charts of disabled dependencies, as charts not declared in
+	// Chart.yaml are always rendered
+	charts := make([]*chart.Chart, 0, len(c.Dependencies()))
+
+	for _, sub := range c.Dependencies() {
+		_, isDeclared := declared[sub.Name()]
+		_, isReferenced := referenced[sub.Name()]
+
+		if !isDeclared || isReferenced {
+			charts = append(charts, sub)
+		}
+	}
+
+	c.Metadata.Dependencies = kept
+	c.SetDependencies(charts...)
+
+	return nil
+}
+
+// dependencyName returns the name a dependency is referred to by, its alias when set.
+func dependencyName(dep *chart.Dependency) string {
+	if dep.Alias != "" {
+		return dep.Alias
+	}
+
+	return dep.Name
+}
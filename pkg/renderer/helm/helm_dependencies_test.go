@@ -0,0 +1,160 @@
+package helm_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+
+	. "github.com/onsi/gomega"
+)
+
+const parentChartYAML = `
+apiVersion: v2
+name: parent
+version: 0.1.0
+dependencies:
+  - name: db
+    version: 0.1.0
+    condition: db.enabled
+  - name: cache
+    version: 0.1.0
+    tags:
+      - backend
+  - name: worker
+    version: 0.1.0
+    alias: replica
+`
+
+const parentValuesYAML = `
+db:
+  enabled: false
+tags:
+  backend: false
+`
+
+const subchartTemplateYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Chart.Name }}
+`
+
+func newDependenciesFS(parentChart string) fstest.MapFS {
+	chartFS := fstest.MapFS{
+		"parent/Chart.yaml":  &fstest.MapFile{Data: []byte(parentChart)},
+		"parent/values.yaml": &fstest.MapFile{Data: []byte(parentValuesYAML)},
+	}
+
+	for _, name := range []string{"db", "cache", "worker"} {
+		chartYAML := "apiVersion: v2\nname: " + name + "\nversion: 0.1.0\n"
+
+		chartFS["parent/charts/"+name+"/Chart.yaml"] = &fstest.MapFile{Data: []byte(chartYAML)}
+		chartFS["parent/charts/"+name+"/templates/configmap.yaml"] = &fstest.MapFile{Data: []byte(subchartTemplateYAML)}
+	}
+
+	return chartFS
+}
+
+func TestDependencies(t *testing.T) {
+	chartFS := newDependenciesFS(parentChartYAML)
+
+	render := func(g *WithT, source helm.Source, values map[string]any) ([]string, error) {
+		source.FS = chartFS
+		source.Chart = "parent"
+		source.ReleaseName = "deps"
+
+		renderer, err := helm.New([]helm.Source{source})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), values)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			names = append(names, obj.GetName())
+		}
+
+		return names, nil
+	}
+
+	t.Run("should apply conditions and tags from values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		names, err := render(g, helm.Source{ProcessDependencies: true}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names).To(ConsistOf("replica"))
+	})
+
+	t.Run("should enable dependencies regardless of their condition", func(t *testing.T) {
+		g := NewWithT(t)
+
+		names, err := render(g, helm.Source{Dependencies: map[string]bool{"db": true}}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names).To(ConsistOf("db", "replica"))
+	})
+
+	t.Run("should disable dependencies by alias", func(t *testing.T) {
+		g := NewWithT(t)
+
+		names, err := render(g, helm.Source{Dependencies: map[string]bool{"replica": false}}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names).To(BeEmpty())
+	})
+
+	t.Run("should enable dependencies by tag", func(t *testing.T) {
+		g := NewWithT(t)
+
+		names, err := render(g, helm.Source{Tags: map[string]bool{"backend": true}}, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(names).To(ConsistOf("cache", "replica"))
+	})
+
+	t.Run("should process dependencies independently on each render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:                  chartFS,
+			Chart:               "parent",
+			ReleaseName:         "deps",
+			ProcessDependencies: true,
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		objects, err = renderer.Process(t.Context(), map[string]any{"db": map[string]any{"enabled": true}})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should reject unknown dependencies", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := render(g, helm.Source{Dependencies: map[string]bool{"worker": false}}, nil)
+		g.Expect(err).To(MatchError(helm.ErrDependencyNotFound))
+		g.Expect(err.Error()).To(ContainSubstring("available: cache, db, replica"))
+	})
+
+	t.Run("should report dependencies missing from charts directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		chartFS := newDependenciesFS(parentChartYAML + "  - name: queue\n    version: 0.1.0\n")
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:                  chartFS,
+			Chart:               "parent",
+			ReleaseName:         "deps",
+			ProcessDependencies: true,
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(helm.ErrDependencyMissing))
+		g.Expect(err.Error()).To(ContainSubstring("queue"))
+	})
+}
@@ -0,0 +1,94 @@
+package helm
+
+import (
+	"slices"
+	"strings"
+
+	helmrelease "helm.sh/helm/v3/pkg/release"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AnnotationHook is the annotation added to objects rendered from Helm hook manifests when source
+// annotations are enabled. Its value is the comma-separated list of hook events declared by the manifest.
+const AnnotationHook = "manifests.k8s-manifests-lib/helm.hook"
+
+// HookPolicy decides whether an object declaring the given Helm hook events
+// (e.g. "pre-install", "test") is kept in the rendered output.
+type HookPolicy func(hooks []string) bool
+
+// IncludeHooks returns a HookPolicy keeping all hook manifests. This is the default policy.
+func IncludeHooks() HookPolicy {
+	return func(_ []string) bool {
+		return true
+	}
+}
+
+// ExcludeHooks returns a HookPolicy dropping all hook manifests.
+func ExcludeHooks() HookPolicy {
+	return func(_ []string) bool {
+		return false
+	}
+}
+
+// KeepHooks returns a HookPolicy keeping only hook manifests declaring at least one of the given
+// hook events, e.g. KeepHooks("pre-install", "pre-upgrade") drops test and deletion hooks.
+func KeepHooks(hooks ...string) HookPolicy {
+	return func(declared []string) bool {
+		for _, hook := range declared {
+			if slices.Contains(hooks, hook) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// hookEvents returns the hook events declared by an object through the helm.sh/hook annotation.
+func hookEvents(obj unstructured.Unstructured) []string {
+	value, ok := obj.GetAnnotations()[helmrelease.HookAnnotation]
+	if !ok {
+		return nil
+	}
+
+	events := make([]string, 0)
+
+	for event := range strings.SplitSeq(value, ",") {
+		event = strings.TrimSpace(event)
+		if event != "" {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+// applyHookPolicy drops the hook objects rejected by the renderer hook policy and, when source
+// annotations are enabled, annotates the kept ones with AnnotationHook.
+func (r *Renderer) applyHookPolicy(objects []unstructured.Unstructured) []unstructured.Unstructured {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+
+	for _, obj := range objects {
+		events := hookEvents(obj)
+		if len(events) == 0 {
+			result = append(result, obj)
+
+			continue
+		}
+
+		if r.opts.HookPolicy != nil && !r.opts.HookPolicy(events) {
+			continue
+		}
+
+		if r.opts.SourceAnnotations {
+			annotations := obj.GetAnnotations()
+			annotations[AnnotationHook] = strings.Join(events, ",")
+			obj.SetAnnotations(annotations)
+		}
+
+		result = append(result, obj)
+	}
+
+	return result
+}
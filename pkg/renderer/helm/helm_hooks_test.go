@@ -0,0 +1,92 @@
+package helm_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+
+	. "github.com/onsi/gomega"
+)
+
+const hookTemplatesYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-install, pre-upgrade
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-connection
+  annotations:
+    helm.sh/hook: test
+`
+
+func TestHookPolicy(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":           &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/templates/hooks.yaml": &fstest.MapFile{Data: []byte(hookTemplatesYAML)},
+	}
+
+	render := func(g *WithT, opts ...helm.RendererOption) map[string]map[string]string {
+		renderer, err := helm.New(
+			[]helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "hooks"}},
+			opts...,
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := make(map[string]map[string]string, len(objects))
+		for _, obj := range objects {
+			result[obj.GetName()] = obj.GetAnnotations()
+		}
+
+		return result
+	}
+
+	t.Run("should include hooks unchanged by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g)
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects["migrate"]).ToNot(HaveKey(helm.AnnotationHook))
+		g.Expect(objects["test-connection"]).ToNot(HaveKey(helm.AnnotationHook))
+	})
+
+	t.Run("should annotate hooks with source annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g, helm.WithSourceAnnotations(true))
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects["app"]).ToNot(HaveKey(helm.AnnotationHook))
+		g.Expect(objects["migrate"]).To(HaveKeyWithValue(helm.AnnotationHook, "pre-install,pre-upgrade"))
+		g.Expect(objects["test-connection"]).To(HaveKeyWithValue(helm.AnnotationHook, "test"))
+	})
+
+	t.Run("should exclude hooks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g, helm.WithHookPolicy(helm.ExcludeHooks()))
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects).To(HaveKey("app"))
+	})
+
+	t.Run("should keep selected hooks", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := render(g, helm.WithHookPolicy(helm.KeepHooks("pre-upgrade")))
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects).To(HaveKey("app"))
+		g.Expect(objects).To(HaveKey("migrate"))
+	})
+}
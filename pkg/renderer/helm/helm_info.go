@@ -0,0 +1,37 @@
+package helm
+
+import (
+	"path"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// notesFile is the name of the template holding the chart usage notes.
+const notesFile = "NOTES.txt"
+
+// ReleaseInfo holds the non-object artifacts of rendering a source.
+type ReleaseInfo struct {
+	// ReleaseName is the release name of the source.
+	ReleaseName string
+
+	// Chart is the metadata of the rendered chart, as declared in Chart.yaml.
+	Chart *chart.Metadata
+
+	// Notes is the rendered NOTES.txt of the chart. Empty if the chart has no notes.
+	Notes string
+}
+
+// newReleaseInfo returns the release information of a rendering of the chart.
+func newReleaseInfo(holder *sourceHolder, helmChart *chart.Chart, files map[string]string) ReleaseInfo {
+	info := ReleaseInfo{
+		ReleaseName: holder.ReleaseName,
+		Notes:       files[path.Join(helmChart.ChartFullPath(), "templates", notesFile)],
+	}
+
+	if helmChart.Metadata != nil {
+		metadata := *helmChart.Metadata
+		info.Chart = &metadata
+	}
+
+	return info
+}
@@ -0,0 +1,82 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/engine"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	// ErrLookupMapperRequired is returned when a lookup client is configured without a REST mapper.
+	ErrLookupMapperRequired = errors.New("lookup client requires a REST mapper")
+
+	// ErrLookupStrictUnsupported is returned when a lookup client is combined with strict mode,
+	// which the Helm engine does not support for custom clients.
+	ErrLookupStrictUnsupported = errors.New("strict mode is not supported with a lookup client, use a lookup REST config")
+)
+
+// lookupClientProvider resolves the clients used by the Helm lookup function from a dynamic client
+// and a REST mapper. It implements engine.ClientProvider.
+type lookupClientProvider struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// GetClientFor returns the client for the resources of the given apiVersion and kind, and
+// whether they are namespaced.
+func (p lookupClientProvider) GetClientFor(apiVersion string, kind string) (dynamic.NamespaceableResourceInterface, bool, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid apiVersion %q: %w", apiVersion, err)
+	}
+
+	mapping, err := p.mapper.RESTMapping(gv.WithKind(kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to map %s %s to a resource: %w", apiVersion, kind, err)
+	}
+
+	return p.client.Resource(mapping.Resource), mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+// newHelmEngine creates the Helm template engine, wiring the lookup function to the
+// configured cluster (if any). Without a cluster, lookup returns empty results.
+func newHelmEngine(opts RendererOptions) (engine.Engine, engine.ClientProvider, error) {
+	switch {
+	case opts.LookupClient != nil:
+		if opts.LookupMapper == nil {
+			return engine.Engine{}, nil, ErrLookupMapperRequired
+		}
+
+		if opts.Strict {
+			return engine.Engine{}, nil, ErrLookupStrictUnsupported
+		}
+
+		provider := lookupClientProvider{
+			client: opts.LookupClient,
+			mapper: opts.LookupMapper,
+		}
+
+		// Lookup is disabled in lint mode, like in Helm
+		if opts.LintMode {
+			return engine.Engine{LintMode: true}, nil, nil
+		}
+
+		return engine.Engine{}, provider, nil
+	case opts.LookupConfig != nil:
+		e := engine.New(opts.LookupConfig)
+		e.LintMode = opts.LintMode
+		e.Strict = opts.Strict
+
+		return e, nil, nil
+	default:
+		return engine.Engine{
+			LintMode: opts.LintMode,
+			Strict:   opts.Strict,
+		}, nil, nil
+	}
+}
@@ -0,0 +1,101 @@
+package helm_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+
+	. "github.com/onsi/gomega"
+)
+
+const lookupTemplateYAML = `
+{{- $secret := lookup "v1" "Secret" .Release.Namespace "credentials" }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: lookup
+data:
+  found: {{ not (empty $secret) | quote }}
+  {{- if $secret }}
+  password: {{ index $secret.data "password" | quote }}
+  {{- end }}
+`
+
+func TestLookup(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":            &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/templates/lookup.yaml": &fstest.MapFile{Data: []byte(lookupTemplateYAML)},
+	}
+
+	sources := []helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "lookup", Namespace: "apps"}}
+
+	secretGVK := schema.GroupVersionKind{Version: "v1", Kind: "Secret"}
+
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(secretGVK)
+	secret.SetNamespace("apps")
+	secret.SetName("credentials")
+	secret.Object["data"] = map[string]any{"password": "czNjcjN0"}
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), secret)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(secretGVK, meta.RESTScopeNamespace)
+
+	render := func(g *WithT, opts ...helm.RendererOption) map[string]any {
+		renderer, err := helm.New(sources, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		data, ok := objects[0].Object["data"].(map[string]any)
+		g.Expect(ok).To(BeTrue())
+
+		return data
+	}
+
+	t.Run("should resolve lookups against the client", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g, helm.WithLookupClient(client, mapper))
+		g.Expect(data).To(HaveKeyWithValue("found", "true"))
+		g.Expect(data).To(HaveKeyWithValue("password", "czNjcjN0"))
+	})
+
+	t.Run("should return empty lookups without a cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g)
+		g.Expect(data).To(HaveKeyWithValue("found", "false"))
+	})
+
+	t.Run("should return empty lookups in lint mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g, helm.WithLookupClient(client, mapper), helm.WithLintMode(true))
+		g.Expect(data).To(HaveKeyWithValue("found", "false"))
+	})
+
+	t.Run("should require a REST mapper", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helm.New(sources, helm.WithLookupClient(client, nil))
+		g.Expect(err).To(MatchError(helm.ErrLookupMapperRequired))
+	})
+
+	t.Run("should reject strict mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helm.New(sources, helm.WithLookupClient(client, mapper), helm.WithStrict(true))
+		g.Expect(err).To(MatchError(helm.ErrLookupStrictUnsupported))
+	})
+}
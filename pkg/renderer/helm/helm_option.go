@@ -2,12 +2,17 @@ package helm
 
 import (
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/postrender"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
 )
 
 // RendererOption is a generic option for RendererOptions.
@@ -31,6 +36,12 @@ type RendererOptions struct {
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
 
+	// ReleaseAnnotations enables automatic addition of release metadata annotations.
+	ReleaseAnnotations bool
+
+	// ExpandLists enables unwrapping of List objects into their items.
+	ExpandLists bool
+
 	// LintMode allows some 'required' template values to be missing without failing.
 	// This is useful during linting when not all values are available.
 	LintMode bool
@@ -38,6 +49,50 @@ type RendererOptions struct {
 	// Strict enables strict template rendering mode.
 	// When enabled, template rendering will fail if a template references a value that was not passed in.
 	Strict bool
+
+	// Auth is the default registry and repository authentication, overridable per source.
+	Auth registry.Auth
+
+	// ChartCacheDir is the directory downloaded charts pinned to an exact version are stored in
+	// and reused from. Empty means charts are downloaded to the Helm repository cache on each load.
+	ChartCacheDir string
+
+	// Offline disables network fetches; only local charts and charts in ChartCacheDir are used.
+	Offline bool
+
+	// Keyring is the path of the public keyring chart provenance files are verified against.
+	// Empty means charts are not verified.
+	Keyring string
+
+	// Concurrency bounds the number of sources rendered concurrently.
+	Concurrency int
+
+	// SkipCRDs disables emitting the CRDs shipped in the chart crds/ directories.
+	SkipCRDs bool
+
+	// LookupConfig is the REST configuration of the cluster the lookup template function queries.
+	LookupConfig *rest.Config
+
+	// LookupClient is the dynamic client the lookup template function queries, e.g. a fake client
+	// serving a simulated cluster. Requires LookupMapper.
+	LookupClient dynamic.Interface
+
+	// LookupMapper resolves the kinds queried by the lookup template function to resources.
+	LookupMapper meta.RESTMapper
+
+	// PostRenderer modifies the rendered templates before renderer-level filters and transformers.
+	PostRenderer postrender.PostRenderer
+
+	// HookPolicy decides which Helm hook manifests are kept. Nil keeps all of them.
+	HookPolicy HookPolicy
+
+	// KubeVersion is the Kubernetes version exposed to templates as .Capabilities.KubeVersion.
+	// Sources may override it. Empty means use the Helm default.
+	KubeVersion string
+
+	// APIVersions are additional API versions exposed to templates as .Capabilities.APIVersions,
+	// in group/version or group/version/kind form. Sources may add their own.
+	APIVersions []string
 }
 
 // ApplyTo applies the renderer options to the target configuration.
@@ -54,8 +109,56 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	}
 
 	target.SourceAnnotations = opts.SourceAnnotations
+	target.ReleaseAnnotations = opts.ReleaseAnnotations
+	target.ExpandLists = opts.ExpandLists
 	target.LintMode = opts.LintMode
 	target.Strict = opts.Strict
+	target.SkipCRDs = opts.SkipCRDs
+	target.Offline = opts.Offline
+
+	if opts.Auth != (registry.Auth{}) {
+		target.Auth = opts.Auth
+	}
+
+	if opts.ChartCacheDir != "" {
+		target.ChartCacheDir = opts.ChartCacheDir
+	}
+
+	if opts.Keyring != "" {
+		target.Keyring = opts.Keyring
+	}
+
+	if opts.Concurrency > 0 {
+		target.Concurrency = opts.Concurrency
+	}
+
+	if opts.LookupConfig != nil {
+		target.LookupConfig = opts.LookupConfig
+	}
+
+	if opts.LookupClient != nil {
+		target.LookupClient = opts.LookupClient
+	}
+
+	if opts.LookupMapper != nil {
+		target.LookupMapper = opts.LookupMapper
+	}
+
+	if opts.PostRenderer != nil {
+		target.PostRenderer = opts.PostRenderer
+	}
+
+	if opts.HookPolicy != nil {
+		target.HookPolicy = opts.HookPolicy
+	}
+
+	if opts.KubeVersion != "" {
+		target.KubeVersion = opts.KubeVersion
+	}
+
+	if len(opts.APIVersions) > 0 {
+		target.APIVersions = opts.APIVersions
+	}
 }
 
 // WithFilter adds a renderer-specific filter to this Helm renderer's processing chain.
@@ -102,6 +205,17 @@ func WithSourceAnnotations(enabled bool) RendererOption {
 	})
 }
 
+// WithReleaseAnnotations enables or disables automatic addition of release metadata annotations.
+// When enabled, the renderer annotates every object with the release name, chart name, chart version
+// and app version, so downstream filters can group objects by release.
+// Annotations added: manifests.k8s-manifests-lib/helm.release, helm.chart, helm.chart.version, helm.app.version.
+// Default: false (disabled).
+func WithReleaseAnnotations(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ReleaseAnnotations = enabled
+	})
+}
+
 // WithLintMode enables or disables lint mode during template rendering.
 // When enabled, some 'required' template values may be missing without causing rendering to fail.
 // This is useful during linting when not all values are available.
@@ -113,11 +227,137 @@ func WithLintMode(enabled bool) RendererOption {
 }
 
 // WithStrict enables or disables strict template rendering mode.
-// When enabled, template rendering will fail if a template references a value that was not passed in.
-// This helps catch missing values early during development.
+// When enabled, template rendering will fail if a template references a value that was not passed in,
+// instead of rendering it as an empty string. This helps catch missing values early, e.g. in CI.
+// Strict mode cannot be combined with WithLookupClient.
 // Default: false (disabled).
 func WithStrict(enabled bool) RendererOption {
 	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
 		opts.Strict = enabled
 	})
 }
+
+// WithExpandLists enables or disables unwrapping of List objects (kind List or any *List kind)
+// into their individual items. When enabled, the items are expanded before renderer-level
+// filters and transformers are applied, so they operate on the contained objects.
+// Default: false (disabled).
+func WithExpandLists(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ExpandLists = enabled
+	})
+}
+
+// WithIncludeCRDs enables or disables emitting the CRDs shipped in the crds/ directory of the chart
+// and its dependencies. These manifests are not templated; they are emitted before the rendered templates
+// and, when source annotations are enabled, annotated with AnnotationCRD.
+// Default: true (enabled).
+func WithIncludeCRDs(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SkipCRDs = !enabled
+	})
+}
+
+// WithLookupConfig resolves the lookup template function against the cluster of the given
+// REST configuration. Without a lookup cluster, lookup returns empty results.
+// Note that cached render results do not track the looked-up objects.
+func WithLookupConfig(config *rest.Config) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.LookupConfig = config
+	})
+}
+
+// WithLookupClient resolves the lookup template function using the given dynamic client and
+// REST mapper, e.g. a fake dynamic client holding a simulated cluster state.
+// Not supported together with strict mode; lookup is disabled in lint mode, like in Helm.
+func WithLookupClient(client dynamic.Interface, mapper meta.RESTMapper) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.LookupClient = client
+		opts.LookupMapper = mapper
+	})
+}
+
+// WithPostRenderer sets a Helm post-renderer, e.g. postrender.NewExec("kustomize-wrapper.sh"),
+// modifying the manifests rendered from the chart templates. It runs before the renderer-level
+// filters and transformers; CRDs from the crds/ directory are not post-rendered, like in Helm.
+func WithPostRenderer(pr postrender.PostRenderer) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.PostRenderer = pr
+	})
+}
+
+// WithHookPolicy sets the policy deciding which Helm hook manifests (objects with a helm.sh/hook
+// annotation) are kept, see IncludeHooks, ExcludeHooks and KeepHooks. When source annotations are
+// enabled, kept hook objects are annotated with AnnotationHook so downstream filters can act on them.
+// Default: IncludeHooks.
+func WithHookPolicy(policy HookPolicy) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.HookPolicy = policy
+	})
+}
+
+// WithKubeVersion sets the Kubernetes version exposed to templates as .Capabilities.KubeVersion
+// for all sources, e.g. "v1.30.0". Sources may override it with Source.KubeVersion.
+// Default: the Helm default version.
+func WithKubeVersion(version string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.KubeVersion = version
+	})
+}
+
+// WithAPIVersions adds API versions exposed to templates as .Capabilities.APIVersions for all sources,
+// e.g. "monitoring.coreos.com/v1" or "monitoring.coreos.com/v1/ServiceMonitor".
+// Versions declared in Source.APIVersions are added on top.
+func WithAPIVersions(versions ...string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.APIVersions = append(opts.APIVersions, versions...)
+	})
+}
+
+// WithChartCacheDir sets the directory where pulled charts are stored. Charts pinned to an exact
+// version (Source.ReleaseVersion) are downloaded once and reused across renders and processes
+// sharing the directory. Charts without an exact version are always fetched.
+// Default: charts are downloaded to the Helm repository cache on each load.
+func WithChartCacheDir(dir string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ChartCacheDir = dir
+	})
+}
+
+// WithOffline enables or disables offline mode. When enabled, rendering fails with ErrChartNotCached
+// if a chart is neither local, embedded, nor available in the chart cache directory.
+// Default: false (disabled).
+func WithOffline(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Offline = enabled
+	})
+}
+
+// WithVerification enables chart provenance verification against the given public keyring
+// (e.g. a file exported with gpg --export). Charts must be packaged archives with a valid
+// .prov file next to them; unsigned charts, unpacked chart directories and charts signed by
+// keys not in the keyring are rejected.
+// Default: charts are not verified.
+func WithVerification(keyring string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Keyring = keyring
+	})
+}
+
+// WithConcurrency bounds the number of sources rendered concurrently. Chart pulls and template
+// rendering are independent per source; the output keeps the source order regardless of the limit.
+// Filters, transformers and post-renderers must be safe for concurrent use when n is greater than 1.
+// Default: 1 (sources are rendered sequentially).
+func WithConcurrency(n int) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Concurrency = n
+	})
+}
+
+// WithRegistryAuth sets the default authentication used to pull charts from OCI registries
+// and HTTP repositories for all sources. Sources can override it by setting Source.Auth.
+// Token and docker config credentials only apply to OCI registries.
+func WithRegistryAuth(auth registry.Auth) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Auth = auth
+	})
+}
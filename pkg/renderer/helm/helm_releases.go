@@ -0,0 +1,166 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+var (
+	// ErrReleaseInvalid is returned when a release entry of a releases file is incomplete.
+	ErrReleaseInvalid = errors.New("invalid release")
+
+	// ErrValuesEntryInvalid is returned when a release values entry is neither a file path nor a map.
+	ErrValuesEntryInvalid = errors.New("values entry must be a file path or a map")
+)
+
+// releasesFile is the helmfile-like document describing a set of releases.
+type releasesFile struct {
+	Repositories []releaseRepository `json:"repositories,omitempty"`
+	Releases     []release           `json:"releases,omitempty"`
+}
+
+// releaseRepository maps a repository name to its URL.
+type releaseRepository struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// release describes a single chart release.
+type release struct {
+	Name                string `json:"name"`
+	Namespace           string `json:"namespace,omitempty"`
+	Chart               string `json:"chart"`
+	Version             string `json:"version,omitempty"`
+	Values              []any  `json:"values,omitempty"`
+	ProcessDependencies bool   `json:"processDependencies,omitempty"`
+	Installed           *bool  `json:"installed,omitempty"`
+}
+
+// LoadReleases reads a helmfile-style releases file and expands it into helm Sources.
+//
+// The file lists the chart repositories and the releases to render:
+//
+//	repositories:
+//	  - name: bitnami
+//	    url: https://charts.bitnami.com/bitnami
+//	releases:
+//	  - name: web
+//	    namespace: frontend
+//	    chart: bitnami/nginx          # <repository>/<chart>, oci:// reference or local path
+//	    version: 15.0.0
+//	    values:
+//	      - values/web.yaml           # file, relative to the releases file
+//	      - replicaCount: 2           # inline values
+//
+// Values entries are deep merged in order, later entries taking precedence. Local chart paths
+// and values files are resolved relative to the directory of the releases file. Releases with
+// `installed: false` are skipped.
+func LoadReleases(path string) ([]Source, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read releases file %s: %w", path, err)
+	}
+
+	var file releasesFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode releases file %s: %w", path, err)
+	}
+
+	baseDir := filepath.Dir(path)
+
+	repositories := make(map[string]string, len(file.Repositories))
+	for _, repo := range file.Repositories {
+		repositories[repo.Name] = repo.URL
+	}
+
+	sources := make([]Source, 0, len(file.Releases))
+
+	for i, rel := range file.Releases {
+		if rel.Installed != nil && !*rel.Installed {
+			continue
+		}
+
+		if strings.TrimSpace(rel.Name) == "" || strings.TrimSpace(rel.Chart) == "" {
+			return nil, fmt.Errorf("%w: releases[%d] requires name and chart", ErrReleaseInvalid, i)
+		}
+
+		values, err := releaseValues(baseDir, rel.Values)
+		if err != nil {
+			return nil, fmt.Errorf("release %s: %w", rel.Name, err)
+		}
+
+		repo, chart := resolveReleaseChart(baseDir, rel.Chart, repositories)
+
+		sources = append(sources, Source{
+			Repo:                repo,
+			Chart:               chart,
+			ReleaseName:         rel.Name,
+			ReleaseVersion:      rel.Version,
+			Namespace:           rel.Namespace,
+			Values:              Values(values),
+			ProcessDependencies: rel.ProcessDependencies,
+		})
+	}
+
+	return sources, nil
+}
+
+// resolveReleaseChart splits a release chart reference into repository URL and chart.
+func resolveReleaseChart(baseDir string, chart string, repositories map[string]string) (string, string) {
+	if strings.HasPrefix(chart, "oci://") {
+		return "", chart
+	}
+
+	if name, rest, found := strings.Cut(chart, "/"); found {
+		if url, ok := repositories[name]; ok {
+			return url, rest
+		}
+	}
+
+	if !filepath.IsAbs(chart) {
+		chart = filepath.Join(baseDir, chart)
+	}
+
+	return "", chart
+}
+
+// releaseValues merges the values entries of a release in order.
+func releaseValues(baseDir string, entries []any) (map[string]any, error) {
+	result := map[string]any{}
+
+	for i, entry := range entries {
+		var values map[string]any
+
+		switch v := entry.(type) {
+		case string:
+			path := v
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read values file %s: %w", v, err)
+			}
+
+			if err := yaml.Unmarshal(data, &values); err != nil {
+				return nil, fmt.Errorf("failed to decode values file %s: %w", v, err)
+			}
+		case map[string]any:
+			values = v
+		default:
+			return nil, fmt.Errorf("%w: values[%d] is %T", ErrValuesEntryInvalid, i, entry)
+		}
+
+		result = util.DeepMerge(result, values)
+	}
+
+	return result, nil
+}
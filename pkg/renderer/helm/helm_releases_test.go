@@ -0,0 +1,178 @@
+package helm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+
+	. "github.com/onsi/gomega"
+)
+
+const localChartYAML = `
+apiVersion: v2
+name: local
+version: 0.1.0
+`
+
+const localChartValuesYAML = `
+greeting: hello
+replicas: 1
+`
+
+const localChartConfigMapYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+data:
+  greeting: {{ .Values.greeting | quote }}
+  replicas: {{ .Values.replicas | quote }}
+`
+
+const releasesYAML = `
+repositories:
+  - name: example
+    url: https://charts.example.com
+releases:
+  - name: frontend
+    namespace: web
+    chart: ./chart
+    values:
+      - values/frontend.yaml
+      - replicas: 3
+  - name: backend
+    namespace: api
+    chart: ./chart
+  - name: remote
+    chart: example/nginx
+    version: 1.2.3
+    installed: false
+  - name: registry
+    chart: oci://registry.example.com/charts/app
+    installed: false
+`
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupReleases(t *testing.T, releases string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, "chart", "Chart.yaml"), localChartYAML)
+	writeFile(t, filepath.Join(dir, "chart", "values.yaml"), localChartValuesYAML)
+	writeFile(t, filepath.Join(dir, "chart", "templates", "configmap.yaml"), localChartConfigMapYAML)
+	writeFile(t, filepath.Join(dir, "values", "frontend.yaml"), "greeting: hi\nreplicas: 2\n")
+	writeFile(t, filepath.Join(dir, "releases.yaml"), releases)
+
+	return filepath.Join(dir, "releases.yaml")
+}
+
+func TestLoadReleases(t *testing.T) {
+
+	t.Run("should load releases and skip uninstalled ones", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, releasesYAML)
+
+		sources, err := helm.LoadReleases(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sources).To(HaveLen(2))
+
+		g.Expect(sources[0].ReleaseName).To(Equal("frontend"))
+		g.Expect(sources[0].Namespace).To(Equal("web"))
+		g.Expect(sources[0].Chart).To(Equal(filepath.Join(filepath.Dir(path), "chart")))
+		g.Expect(sources[0].Repo).To(BeEmpty())
+
+		values, err := sources[0].Values(t.Context())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(values).To(HaveKeyWithValue("greeting", "hi"))
+		g.Expect(values).To(HaveKeyWithValue("replicas", BeNumerically("==", 3)))
+	})
+
+	t.Run("should resolve repository charts", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, `
+repositories:
+  - name: example
+    url: https://charts.example.com
+releases:
+  - name: remote
+    chart: example/nginx
+    version: 1.2.3
+  - name: registry
+    chart: oci://registry.example.com/charts/app
+`)
+
+		sources, err := helm.LoadReleases(path)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(sources).To(HaveLen(2))
+
+		g.Expect(sources[0].Repo).To(Equal("https://charts.example.com"))
+		g.Expect(sources[0].Chart).To(Equal("nginx"))
+		g.Expect(sources[0].ReleaseVersion).To(Equal("1.2.3"))
+
+		g.Expect(sources[1].Repo).To(BeEmpty())
+		g.Expect(sources[1].Chart).To(Equal("oci://registry.example.com/charts/app"))
+	})
+
+	t.Run("should render loaded releases", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, releasesYAML)
+
+		sources, err := helm.LoadReleases(path)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := helm.New(sources)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(objects[0].GetName()).To(Equal("frontend"))
+		g.Expect(objects[0].GetNamespace()).To(Equal("web"))
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("greeting", "hi"))
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("replicas", "3"))
+
+		g.Expect(objects[1].GetName()).To(Equal("backend"))
+		g.Expect(objects[1].GetNamespace()).To(Equal("api"))
+		g.Expect(objects[1].Object["data"]).To(HaveKeyWithValue("greeting", "hello"))
+	})
+
+	t.Run("should reject releases without chart", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, "releases:\n  - name: broken\n")
+
+		_, err := helm.LoadReleases(path)
+		g.Expect(err).To(MatchError(helm.ErrReleaseInvalid))
+	})
+
+	t.Run("should reject invalid values entries", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, "releases:\n  - name: broken\n    chart: ./chart\n    values:\n      - 42\n")
+
+		_, err := helm.LoadReleases(path)
+		g.Expect(err).To(MatchError(helm.ErrValuesEntryInvalid))
+	})
+
+	t.Run("should fail on missing values file", func(t *testing.T) {
+		g := NewWithT(t)
+		path := setupReleases(t, "releases:\n  - name: broken\n    chart: ./chart\n    values:\n      - missing.yaml\n")
+
+		_, err := helm.LoadReleases(path)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
@@ -1,22 +1,51 @@
 package helm
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/Masterminds/semver/v3"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/registry"
+	helmregistry "helm.sh/helm/v3/pkg/registry"
+	orasauth "oras.land/oras-go/v2/registry/remote/auth"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+)
+
+const (
+	// AnnotationCRD is the source annotation marking objects loaded from the crds/ directory of a chart.
+	AnnotationCRD = "manifests.k8s-manifests-lib/helm.crd"
+
+	// AnnotationRelease is the release annotation holding the release name.
+	AnnotationRelease = "manifests.k8s-manifests-lib/helm.release"
+
+	// AnnotationChart is the release annotation holding the chart name.
+	AnnotationChart = "manifests.k8s-manifests-lib/helm.chart"
+
+	// AnnotationChartVersion is the release annotation holding the chart version.
+	AnnotationChartVersion = "manifests.k8s-manifests-lib/helm.chart.version"
+
+	// AnnotationAppVersion is the release annotation holding the chart appVersion, when set.
+	AnnotationAppVersion = "manifests.k8s-manifests-lib/helm.app.version"
 )
 
 const (
@@ -34,6 +63,12 @@ var (
 
 	// ErrReleaseNameTooLong is returned when a release name exceeds the maximum length.
 	ErrReleaseNameTooLong = errors.New("release name exceeds maximum length")
+
+	// ErrChartNotCached is returned in offline mode when a chart is not available locally.
+	ErrChartNotCached = errors.New("chart not available in cache and offline mode is enabled")
+
+	// ErrKubeVersionInvalid is returned when a Kubernetes version cannot be parsed.
+	ErrKubeVersionInvalid = errors.New("invalid kubernetes version")
 )
 
 // Values returns a Values function that always returns the provided static values.
@@ -74,12 +109,18 @@ func (h *sourceHolder) Validate() error {
 		)
 	}
 
+	if h.KubeVersion != "" {
+		if _, err := chartutil.ParseKubeVersion(h.KubeVersion); err != nil {
+			return fmt.Errorf("%w: %w", ErrKubeVersionInvalid, err)
+		}
+	}
+
 	return nil
 }
 
 // LoadChart returns the loaded Helm chart, loading it lazily if needed.
 // Thread-safe for concurrent use.
-func (h *sourceHolder) LoadChart(settings *cli.EnvSettings) (*chart.Chart, error) {
+func (h *sourceHolder) LoadChart(settings *cli.EnvSettings, opts RendererOptions) (*chart.Chart, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -87,12 +128,24 @@ func (h *sourceHolder) LoadChart(settings *cli.EnvSettings) (*chart.Chart, error
 		return h.chart, nil
 	}
 
-	opt, err := createChartPathOptions(&h.Source)
-	if err != nil {
-		return nil, err
+	if h.FS != nil {
+		if opts.Keyring != "" {
+			if err := verifyChartFS(h.FS, h.Chart, opts.Keyring); err != nil {
+				return nil, err
+			}
+		}
+
+		c, err := loadChartFS(h.FS, h.Chart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chart %s from filesystem: %w", h.Chart, err)
+		}
+
+		h.chart = c
+
+		return h.chart, nil
 	}
 
-	path, err := opt.LocateChart(h.Chart, settings)
+	chartPath, err := h.locateChart(settings, opts)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"unable to locate chart (repo: %s, name: %s, version: %s): %w",
@@ -103,7 +156,7 @@ func (h *sourceHolder) LoadChart(settings *cli.EnvSettings) (*chart.Chart, error
 		)
 	}
 
-	c, err := loader.Load(path)
+	c, err := loader.Load(chartPath)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to load chart (repo: %s, name: %s, version: %s): %w",
@@ -119,11 +172,275 @@ func (h *sourceHolder) LoadChart(settings *cli.EnvSettings) (*chart.Chart, error
 	return h.chart, nil
 }
 
+// ResetChart discards the loaded chart of FS and local sources, so the next render loads
+// the chart again with the changes made since. Charts of remote sources are kept.
+// Thread-safe for concurrent use.
+func (h *sourceHolder) ResetChart() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.FS != nil {
+		h.chart = nil
+
+		return
+	}
+
+	if h.isLocal() {
+		h.chart = nil
+	}
+}
+
+// isLocal reports whether the chart of the source is a local chart directory or archive.
+func (h *sourceHolder) isLocal() bool {
+	_, err := os.Stat(h.Chart)
+
+	return err == nil
+}
+
+// locateChart returns the local path of the chart, downloading it if needed.
+// When a chart cache directory is configured, charts pinned to an exact version are
+// downloaded once into it and reused across renders and processes.
+func (h *sourceHolder) locateChart(settings *cli.EnvSettings, opts RendererOptions) (string, error) {
+	auth := opts.Auth
+	if h.Auth != nil {
+		auth = *h.Auth
+	}
+
+	opt, err := createChartPathOptions(&h.Source, auth)
+	if err != nil {
+		return "", err
+	}
+
+	// Local charts never require a network fetch
+	if _, err := os.Stat(h.Chart); err == nil {
+		if opts.Keyring != "" {
+			if err := verifyChart(h.Chart, opts.Keyring); err != nil {
+				return "", err
+			}
+		}
+
+		return opt.LocateChart(h.Chart, settings)
+	}
+
+	// Helm downloads the .prov file next to the chart and verifies it
+	opt.Verify = opts.Keyring != ""
+	opt.Keyring = opts.Keyring
+
+	cachePath := ""
+	if opts.ChartCacheDir != "" && isExactVersion(h.ReleaseVersion) {
+		cachePath = filepath.Join(opts.ChartCacheDir, chartCacheName(&h.Source))
+
+		if cached(cachePath, opt.Verify) {
+			if opt.Verify {
+				if err := verifyChart(cachePath, opts.Keyring); err != nil {
+					return "", err
+				}
+			}
+
+			return cachePath, nil
+		}
+	}
+
+	if opts.Offline {
+		return "", fmt.Errorf("%w: %s", ErrChartNotCached, h.Chart)
+	}
+
+	if cachePath == "" {
+		return opt.LocateChart(h.Chart, settings)
+	}
+
+	if err := os.MkdirAll(opts.ChartCacheDir, 0o750); err != nil {
+		return "", fmt.Errorf("unable to create chart cache directory: %w", err)
+	}
+
+	// Download into a temporary directory and move the archive into place atomically,
+	// so concurrent processes sharing the cache never observe partial downloads
+	tmp, err := os.MkdirTemp(opts.ChartCacheDir, ".download-")
+	if err != nil {
+		return "", fmt.Errorf("unable to create chart download directory: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	downloadSettings := *settings
+	downloadSettings.RepositoryCache = tmp
+
+	downloaded, err := opt.LocateChart(h.Chart, &downloadSettings)
+	if err != nil {
+		return "", err
+	}
+
+	// The provenance file is moved first, so a cached chart is never seen without it
+	if opt.Verify {
+		if err := os.Rename(downloaded+".prov", cachePath+".prov"); err != nil {
+			return "", fmt.Errorf("unable to store chart provenance in cache: %w", err)
+		}
+	}
+
+	if err := os.Rename(downloaded, cachePath); err != nil {
+		return "", fmt.Errorf("unable to store chart in cache: %w", err)
+	}
+
+	return cachePath, nil
+}
+
+// cached reports whether the chart archive, and its provenance file when verifying, are in the cache.
+// Charts cached without verification are downloaded again to fetch their provenance file.
+func cached(cachePath string, verify bool) bool {
+	if _, err := os.Stat(cachePath); err != nil {
+		return false
+	}
+
+	if verify {
+		if _, err := os.Stat(cachePath + ".prov"); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isExactVersion reports whether a chart version identifies a single release, as opposed
+// to an empty version or a version constraint.
+func isExactVersion(version string) bool {
+	_, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+
+	return err == nil
+}
+
+// chartCacheName returns the file name of a chart archive in the chart cache directory,
+// derived from the repository, chart and version.
+func chartCacheName(source *Source) string {
+	sum := sha256.Sum256([]byte(source.Repo + "\x00" + source.Chart + "\x00" + source.ReleaseVersion))
+	name := path.Base(strings.TrimPrefix(source.Chart, "oci://"))
+
+	return fmt.Sprintf("%s-%s-%s.tgz", name, source.ReleaseVersion, hex.EncodeToString(sum[:])[:12])
+}
+
+// valuesFiles reads and merges the values files of the source in order.
+// Files are read on each call so changes are picked up by subsequent renders.
+func (h *sourceHolder) valuesFiles() (map[string]any, error) {
+	result := map[string]any{}
+
+	for _, file := range h.ValuesFiles {
+		var (
+			data []byte
+			err  error
+		)
+
+		if h.FS != nil {
+			data, err = fs.ReadFile(h.FS, path.Clean(filepath.ToSlash(file)))
+		} else {
+			data, err = os.ReadFile(file)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file %s: %w", file, err)
+		}
+
+		values, err := chartutil.ReadValues(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode values file %s: %w", file, err)
+		}
+
+		result = util.DeepMerge(result, values)
+	}
+
+	return result, nil
+}
+
+// loadChartFS loads a chart directory or a packaged chart archive from the given filesystem.
+// Note that .helmignore rules are not applied to chart directories loaded from a filesystem.
+func loadChartFS(fsys fs.FS, chartPath string) (*chart.Chart, error) {
+	chartPath = path.Clean(filepath.ToSlash(chartPath))
+
+	info, err := fs.Stat(fsys, chartPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		f, err := fsys.Open(chartPath)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		return loader.LoadArchive(f)
+	}
+
+	files := make([]*loader.BufferedFile, 0)
+
+	err = fs.WalkDir(fsys, chartPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		name := p
+		if chartPath != "." {
+			name = strings.TrimPrefix(p, chartPath+"/")
+		}
+
+		files = append(files, &loader.BufferedFile{Name: name, Data: data})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadFiles(files)
+}
+
+// capabilities returns the capabilities exposed to the templates of a source, combining
+// the renderer-level and source-level Kubernetes version and API versions with Helm defaults.
+func (r *Renderer) capabilities(holder *sourceHolder) (*chartutil.Capabilities, error) {
+	caps := chartutil.DefaultCapabilities.Copy()
+
+	kubeVersion := r.opts.KubeVersion
+	if holder.KubeVersion != "" {
+		kubeVersion = holder.KubeVersion
+	}
+
+	if kubeVersion != "" {
+		kv, err := chartutil.ParseKubeVersion(kubeVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrKubeVersionInvalid, err)
+		}
+
+		caps.KubeVersion = *kv
+	}
+
+	apiVersions := make(chartutil.VersionSet, 0, len(caps.APIVersions)+len(r.opts.APIVersions)+len(holder.APIVersions))
+	apiVersions = append(apiVersions, caps.APIVersions...)
+	apiVersions = append(apiVersions, r.opts.APIVersions...)
+	apiVersions = append(apiVersions, holder.APIVersions...)
+
+	caps.APIVersions = apiVersions
+
+	return caps, nil
+}
+
 // createChartPathOptions creates ChartPathOptions for a Source.
 // Creates a fresh registry client and install instance per call.
 // This allows each Source to have different credential/authentication requirements.
-func createChartPathOptions(source *Source) (action.ChartPathOptions, error) {
-	c, err := registry.NewClient()
+func createChartPathOptions(source *Source, auth registry.Auth) (action.ChartPathOptions, error) {
+	clientOpts, err := registryClientOptions(source.Chart, auth)
+	if err != nil {
+		return action.ChartPathOptions{}, err
+	}
+
+	c, err := helmregistry.NewClient(clientOpts...)
 	if err != nil {
 		return action.ChartPathOptions{}, fmt.Errorf("unable to create registry client: %w", err)
 	}
@@ -136,9 +453,56 @@ func createChartPathOptions(source *Source) (action.ChartPathOptions, error) {
 	opt.RepoURL = source.Repo
 	opt.Version = source.ReleaseVersion
 
+	// Settings used by HTTP repositories
+	opt.Username = auth.Username
+	opt.Password = auth.Password
+	opt.CaFile = auth.CAFile
+	opt.CertFile = auth.CertFile
+	opt.KeyFile = auth.KeyFile
+	opt.InsecureSkipTLSverify = auth.InsecureSkipTLSVerify
+	opt.PlainHTTP = auth.PlainHTTP
+
 	return opt, nil
 }
 
+// registryClientOptions translates the authentication settings into Helm registry client options.
+// Without explicit credentials, the Helm registry configuration and the Docker credentials are used.
+func registryClientOptions(chartRef string, auth registry.Auth) ([]helmregistry.ClientOption, error) {
+	opts := make([]helmregistry.ClientOption, 0)
+
+	if auth.PlainHTTP {
+		opts = append(opts, helmregistry.ClientOptPlainHTTP())
+	}
+
+	client, err := auth.HTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure registry client: %w", err)
+	}
+
+	if auth.HasTLSConfig() {
+		opts = append(opts, helmregistry.ClientOptHTTPClient(client))
+	}
+
+	if auth.Username == "" && auth.Token == "" && auth.ConfigFile == "" {
+		return opts, nil
+	}
+
+	host, _, _ := strings.Cut(strings.TrimPrefix(chartRef, "oci://"), "/")
+
+	credential, err := auth.Credential(host)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, helmregistry.ClientOptAuthorizer(orasauth.Client{
+		Client:     client,
+		Cache:      orasauth.NewCache(),
+		Credential: credential,
+	}))
+
+	return opts, nil
+}
+
 // addSourceAnnotations adds source tracking annotations to a slice of unstructured objects.
 // Only modifies objects if source annotations are enabled in renderer options.
 func (r *Renderer) addSourceAnnotations(objects []unstructured.Unstructured, chartPath, fileName string) {
@@ -160,6 +524,67 @@ func (r *Renderer) addSourceAnnotations(objects []unstructured.Unstructured, cha
 	}
 }
 
+// postRender runs the configured post-renderer over the given objects.
+// Objects are serialized to a multi-document YAML stream, annotations included,
+// so source and hook annotations survive post-renderers preserving metadata.
+func (r *Renderer) postRender(objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	data, err := k8s.EncodeYAML(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.opts.PostRenderer.Run(bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if out == nil {
+		return make([]unstructured.Unstructured, 0), nil
+	}
+
+	return k8s.DecodeYAML(out.Bytes())
+}
+
+// addReleaseAnnotations adds release metadata annotations to a slice of unstructured objects.
+// Only modifies objects if release annotations are enabled in renderer options.
+func (r *Renderer) addReleaseAnnotations(objects []unstructured.Unstructured, helmChart *chart.Chart, holder *sourceHolder) {
+	if !r.opts.ReleaseAnnotations {
+		return
+	}
+
+	for i := range objects {
+		annotations := objects[i].GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+
+		annotations[AnnotationRelease] = holder.ReleaseName
+		annotations[AnnotationChart] = helmChart.Metadata.Name
+		annotations[AnnotationChartVersion] = helmChart.Metadata.Version
+
+		if helmChart.Metadata.AppVersion != "" {
+			annotations[AnnotationAppVersion] = helmChart.Metadata.AppVersion
+		}
+
+		objects[i].SetAnnotations(annotations)
+	}
+}
+
+// applyNamespace sets the namespace on the namespaced objects without one.
+func applyNamespace(objects []unstructured.Unstructured, namespace string) {
+	for i := range objects {
+		if objects[i].GetNamespace() != "" {
+			continue
+		}
+
+		if k8s.IsClusterScoped(objects[i].GroupVersionKind().GroupKind(), objects...) {
+			continue
+		}
+
+		objects[i].SetNamespace(namespace)
+	}
+}
+
 // processCRDs extracts and processes CRD objects from a Helm chart.
 // Returns the decoded unstructured objects with source annotations added if enabled.
 func (r *Renderer) processCRDs(helmChart *chart.Chart, holder *sourceHolder) ([]unstructured.Unstructured, error) {
@@ -172,6 +597,15 @@ func (r *Renderer) processCRDs(helmChart *chart.Chart, holder *sourceHolder) ([]
 		}
 
 		r.addSourceAnnotations(objects, holder.Chart, crd.Name)
+
+		if r.opts.SourceAnnotations {
+			for i := range objects {
+				annotations := objects[i].GetAnnotations()
+				annotations[AnnotationCRD] = "true"
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
 		result = append(result, objects...)
 	}
 
@@ -1,17 +1,33 @@
 package helm_test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
 
 	"github.com/rs/xid"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
 
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
 
 	. "github.com/onsi/gomega"
 )
@@ -431,6 +447,695 @@ func TestNew(t *testing.T) {
 	})
 }
 
+// setupLocalChart writes the local test chart to a temporary directory and returns its path.
+func setupLocalChart(t *testing.T) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "local")
+
+	writeFile(t, filepath.Join(dir, "Chart.yaml"), localChartYAML)
+	writeFile(t, filepath.Join(dir, "values.yaml"), localChartValuesYAML)
+	writeFile(t, filepath.Join(dir, "templates", "configmap.yaml"), localChartConfigMapYAML)
+
+	return dir
+}
+
+// packageLocalChart packages the local test chart and returns the path of the archive.
+func packageLocalChart(t *testing.T) string {
+	t.Helper()
+
+	c, err := loader.LoadDir(setupLocalChart(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := chartutil.Save(c, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return archive
+}
+
+func TestLocalCharts(t *testing.T) {
+	t.Run("should render chart from a local directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{{
+			Chart:       setupLocalChart(t),
+			ReleaseName: "dir",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("dir"))
+	})
+
+	t.Run("should render chart from a local archive", func(t *testing.T) {
+		g := NewWithT(t)
+
+		archive := packageLocalChart(t)
+		g.Expect(archive).To(HaveSuffix(".tgz"))
+
+		renderer, err := helm.New([]helm.Source{{
+			Chart:       archive,
+			ReleaseName: "archive",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("archive"))
+	})
+
+	t.Run("should render chart directory from a filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		chartFS := fstest.MapFS{
+			"charts/local/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+			"charts/local/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+			"charts/local/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+		}
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:          chartFS,
+			Chart:       "charts/local",
+			ReleaseName: "embedded",
+			Namespace:   "apps",
+			Values:      helm.Values(map[string]any{"greeting": "hi"}),
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("embedded"))
+		g.Expect(objects[0].GetNamespace()).To(Equal("apps"))
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("greeting", "hi"))
+	})
+
+	t.Run("should render chart archive from a filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		archive := packageLocalChart(t)
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:          os.DirFS(filepath.Dir(archive)),
+			Chart:       filepath.Base(archive),
+			ReleaseName: "embedded-archive",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("embedded-archive"))
+	})
+
+	t.Run("should reload local charts on invalidation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := setupLocalChart(t)
+		chartFS := fstest.MapFS{
+			"charts/local/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+			"charts/local/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+		}
+
+		renderer, err := helm.New([]helm.Source{
+			{Chart: dir, ReleaseName: "dir"},
+			{FS: chartFS, Chart: "charts/local", ReleaseName: "embedded"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		const secondConfigMap = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-extra\n"
+
+		writeFile(t, filepath.Join(dir, "templates", "extra.yaml"), secondConfigMap)
+		chartFS["charts/local/templates/extra.yaml"] = &fstest.MapFile{Data: []byte(secondConfigMap)}
+
+		// The loaded charts are reused until invalidated
+		objects, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		renderer.Invalidate()
+
+		objects, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+	})
+
+	t.Run("should fail on missing chart in filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:          fstest.MapFS{},
+			Chart:       "charts/missing",
+			ReleaseName: "missing",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to load chart charts/missing from filesystem"))
+	})
+}
+
+const capabilitiesTemplateYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: capabilities
+data:
+  kubeVersion: {{ .Capabilities.KubeVersion.Version | quote }}
+  monitoring: {{ .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" | quote }}
+  gateway: {{ .Capabilities.APIVersions.Has "gateway.networking.k8s.io/v1/Gateway" | quote }}
+`
+
+func TestWatchSources(t *testing.T) {
+	t.Run("should return local charts and values files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		chart := setupLocalChart(t)
+		values := filepath.Join(t.TempDir(), "values.yaml")
+
+		renderer, err := helm.New([]helm.Source{
+			{Chart: chart, ReleaseName: "local", ValuesFiles: []string{values}},
+			{Chart: "oci://registry-1.docker.io/bitnamicharts/redis", ReleaseName: "remote"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(renderer.WatchSources()).To(Equal([]types.WatchSource{
+			{Pattern: chart},
+			{Pattern: values},
+		}))
+	})
+}
+
+func TestCapabilities(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":                  &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/templates/capabilities.yaml": &fstest.MapFile{Data: []byte(capabilitiesTemplateYAML)},
+	}
+
+	render := func(g *WithT, source helm.Source, opts ...helm.RendererOption) map[string]any {
+		source.FS = chartFS
+		source.Chart = "chart"
+		source.ReleaseName = "capabilities"
+
+		renderer, err := helm.New([]helm.Source{source}, opts...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		data, ok := objects[0].Object["data"].(map[string]any)
+		g.Expect(ok).To(BeTrue())
+
+		return data
+	}
+
+	t.Run("should use renderer-level capabilities", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g, helm.Source{},
+			helm.WithKubeVersion("v1.30.2"),
+			helm.WithAPIVersions("monitoring.coreos.com/v1"),
+		)
+
+		g.Expect(data).To(HaveKeyWithValue("kubeVersion", "v1.30.2"))
+		g.Expect(data).To(HaveKeyWithValue("monitoring", "true"))
+		g.Expect(data).To(HaveKeyWithValue("gateway", "false"))
+	})
+
+	t.Run("should let sources override the version and add API versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g,
+			helm.Source{
+				KubeVersion: "1.28.0",
+				APIVersions: []string{"gateway.networking.k8s.io/v1/Gateway"},
+			},
+			helm.WithKubeVersion("v1.30.2"),
+			helm.WithAPIVersions("monitoring.coreos.com/v1"),
+		)
+
+		g.Expect(data).To(HaveKeyWithValue("kubeVersion", "v1.28.0"))
+		g.Expect(data).To(HaveKeyWithValue("monitoring", "true"))
+		g.Expect(data).To(HaveKeyWithValue("gateway", "true"))
+	})
+
+	t.Run("should reject invalid versions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := helm.New([]helm.Source{}, helm.WithKubeVersion("not-a-version"))
+		g.Expect(err).To(MatchError(helm.ErrKubeVersionInvalid))
+
+		_, err = helm.New([]helm.Source{{Chart: "chart", ReleaseName: "test", KubeVersion: "not-a-version"}})
+		g.Expect(err).To(MatchError(helm.ErrKubeVersionInvalid))
+	})
+}
+
+const crdYAML = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+`
+
+func TestCRDs(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/crds/widgets.yaml":        &fstest.MapFile{Data: []byte(crdYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+	}
+
+	sources := []helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "crds"}}
+
+	t.Run("should emit chart CRDs first by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.WithSourceAnnotations(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(objects[0].GetKind()).To(Equal("CustomResourceDefinition"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(helm.AnnotationCRD, "true"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "crds/widgets.yaml"))
+
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetAnnotations()).ToNot(HaveKey(helm.AnnotationCRD))
+	})
+
+	t.Run("should skip chart CRDs when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.WithIncludeCRDs(false))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+	})
+
+	t.Run("should include chart CRDs with struct options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.RendererOptions{Strict: true})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("CustomResourceDefinition"))
+	})
+
+	t.Run("should skip chart CRDs with struct options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.RendererOptions{SkipCRDs: true})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+	})
+}
+
+func TestValuesFiles(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+		"values/base.yaml":               &fstest.MapFile{Data: []byte("greeting: base\nreplicas: 2\n")},
+		"values/prod.yaml":               &fstest.MapFile{Data: []byte("replicas: 5\n")},
+	}
+
+	render := func(g *WithT, source helm.Source, values map[string]any) map[string]any {
+		renderer, err := helm.New([]helm.Source{source})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), values)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		data, ok := objects[0].Object["data"].(map[string]any)
+		g.Expect(ok).To(BeTrue())
+
+		return data
+	}
+
+	t.Run("should merge values files in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g, helm.Source{
+			FS:          chartFS,
+			Chart:       "chart",
+			ReleaseName: "files",
+			ValuesFiles: []string{"values/base.yaml", "values/prod.yaml"},
+		}, nil)
+
+		g.Expect(data).To(HaveKeyWithValue("greeting", "base"))
+		g.Expect(data).To(HaveKeyWithValue("replicas", "5"))
+	})
+
+	t.Run("should layer Source values and render-time values on top", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data := render(g, helm.Source{
+			FS:          chartFS,
+			Chart:       "chart",
+			ReleaseName: "files",
+			ValuesFiles: []string{"values/base.yaml", "values/prod.yaml"},
+			Values:      helm.Values(map[string]any{"greeting": "source", "replicas": 3}),
+		}, map[string]any{"replicas": 7})
+
+		g.Expect(data).To(HaveKeyWithValue("greeting", "source"))
+		g.Expect(data).To(HaveKeyWithValue("replicas", "7"))
+	})
+
+	t.Run("should read values files from the local filesystem", func(t *testing.T) {
+		g := NewWithT(t)
+
+		valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+		writeFile(t, valuesFile, "greeting: local\n")
+
+		data := render(g, helm.Source{
+			Chart:       setupLocalChart(t),
+			ReleaseName: "files",
+			ValuesFiles: []string{valuesFile},
+		}, nil)
+
+		g.Expect(data).To(HaveKeyWithValue("greeting", "local"))
+	})
+
+	t.Run("should fail on missing values files", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{{
+			FS:          chartFS,
+			Chart:       "chart",
+			ReleaseName: "files",
+			ValuesFiles: []string{"values/missing.yaml"},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to read values file values/missing.yaml"))
+	})
+}
+
+// newChartRepository serves the packaged local test chart from an HTTP repository
+// protected by basic authentication.
+func newChartRepository(t *testing.T, username string, password string, requests *atomic.Int32) string {
+	t.Helper()
+
+	archive := packageLocalChart(t)
+
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := "apiVersion: v1\nentries:\n  local:\n    - apiVersion: v2\n      name: local\n      version: 0.1.0\n" +
+		"      urls:\n        - " + filepath.Base(archive) + "\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/index.yaml":
+			_, _ = w.Write([]byte(index))
+		case "/" + filepath.Base(archive):
+			_, _ = w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+// newSettings returns Helm settings using temporary repository configuration and cache.
+func newSettings(t *testing.T) *cli.EnvSettings {
+	t.Helper()
+
+	settings := cli.New()
+	settings.RepositoryCache = t.TempDir()
+	settings.RepositoryConfig = filepath.Join(t.TempDir(), "repositories.yaml")
+
+	return settings
+}
+
+func TestRegistryAuth(t *testing.T) {
+	var requests atomic.Int32
+	repoURL := newChartRepository(t, "user", "secret", &requests)
+
+	source := helm.Source{
+		Repo:        repoURL,
+		Chart:       "local",
+		ReleaseName: "private",
+	}
+
+	t.Run("should pull charts from private repositories", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{source},
+			helm.WithSettings(newSettings(t)),
+			helm.WithRegistryAuth(registry.Auth{Username: "user", Password: "secret"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("private"))
+	})
+
+	t.Run("should keep credentials set before struct options", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{source},
+			helm.WithSettings(newSettings(t)),
+			helm.WithRegistryAuth(registry.Auth{Username: "user", Password: "secret"}),
+			helm.RendererOptions{Strict: true},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should let sources override credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		override := source
+		override.Auth = &registry.Auth{Username: "user", Password: "wrong"}
+
+		renderer, err := helm.New(
+			[]helm.Source{override},
+			helm.WithSettings(newSettings(t)),
+			helm.WithRegistryAuth(registry.Auth{Username: "user", Password: "secret"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{source}, helm.WithSettings(newSettings(t)))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestChartCache(t *testing.T) {
+	var requests atomic.Int32
+	repoURL := newChartRepository(t, "user", "secret", &requests)
+
+	source := helm.Source{
+		Repo:           repoURL,
+		Chart:          "local",
+		ReleaseName:    "cached",
+		ReleaseVersion: "0.1.0",
+	}
+
+	auth := helm.WithRegistryAuth(registry.Auth{Username: "user", Password: "secret"})
+
+	render := func(g *WithT, source helm.Source, opts ...helm.RendererOption) error {
+		renderer, err := helm.New([]helm.Source{source}, append(opts, helm.WithSettings(newSettings(t)), auth)...)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+
+		return err
+	}
+
+	t.Run("should reuse charts across renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		requests.Store(0)
+
+		g.Expect(render(g, source, helm.WithChartCacheDir(dir))).To(Succeed())
+		g.Expect(requests.Load()).ToNot(BeZero())
+
+		entries, err := os.ReadDir(dir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(entries).To(HaveLen(1))
+		g.Expect(entries[0].Name()).To(HavePrefix("local-0.1.0-"))
+
+		requests.Store(0)
+
+		g.Expect(render(g, source, helm.WithChartCacheDir(dir))).To(Succeed())
+		g.Expect(requests.Load()).To(BeZero())
+	})
+
+	t.Run("should render cached charts offline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+
+		g.Expect(render(g, source, helm.WithChartCacheDir(dir))).To(Succeed())
+
+		requests.Store(0)
+
+		g.Expect(render(g, source, helm.WithChartCacheDir(dir), helm.WithOffline(true))).To(Succeed())
+		g.Expect(requests.Load()).To(BeZero())
+	})
+
+	t.Run("should fail offline when charts are not cached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		requests.Store(0)
+
+		err := render(g, source, helm.WithChartCacheDir(t.TempDir()), helm.WithOffline(true))
+		g.Expect(err).To(MatchError(helm.ErrChartNotCached))
+		g.Expect(requests.Load()).To(BeZero())
+	})
+
+	t.Run("should fail offline on version constraints", func(t *testing.T) {
+		g := NewWithT(t)
+
+		constrained := source
+		constrained.ReleaseVersion = "^0.1.0"
+
+		err := render(g, constrained, helm.WithChartCacheDir(t.TempDir()), helm.WithOffline(true))
+		g.Expect(err).To(MatchError(helm.ErrChartNotCached))
+	})
+
+	t.Run("should render local charts offline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		local := helm.Source{Chart: setupLocalChart(t), ReleaseName: "local"}
+
+		g.Expect(render(g, local, helm.WithOffline(true))).To(Succeed())
+	})
+}
+
+// postRendererFunc adapts a function to the Helm PostRenderer interface.
+type postRendererFunc func(*bytes.Buffer) (*bytes.Buffer, error)
+
+func (f postRendererFunc) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	return f(renderedManifests)
+}
+
+func TestPostRenderer(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/crds/widgets.yaml":        &fstest.MapFile{Data: []byte(crdYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+	}
+
+	sources := []helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "post"}}
+
+	t.Run("should post-render templates before transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var input string
+
+		pr := postRendererFunc(func(in *bytes.Buffer) (*bytes.Buffer, error) {
+			input = in.String()
+
+			out := strings.ReplaceAll(in.String(), "hello", "post-rendered")
+			out += "---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: extra\n"
+
+			return bytes.NewBufferString(out), nil
+		})
+
+		renderer, err := helm.New(
+			sources,
+			helm.WithPostRenderer(pr),
+			helm.WithSourceAnnotations(true),
+			helm.WithTransformer(labels.Set(map[string]string{"transformed": "true"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		// CRDs are not post-rendered
+		g.Expect(input).ToNot(ContainSubstring("CustomResourceDefinition"))
+		g.Expect(objects[0].GetKind()).To(Equal("CustomResourceDefinition"))
+
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].Object["data"]).To(HaveKeyWithValue("greeting", "post-rendered"))
+		g.Expect(objects[1].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "local/templates/configmap.yaml"))
+
+		g.Expect(objects[2].GetKind()).To(Equal("Secret"))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("transformed", "true"))
+		}
+	})
+
+	t.Run("should fail on post-renderer errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pr := postRendererFunc(func(_ *bytes.Buffer) (*bytes.Buffer, error) {
+			return nil, errors.New("post-renderer failed")
+		})
+
+		renderer, err := helm.New(sources, helm.WithPostRenderer(pr))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(ContainSubstring("post-renderer failed")))
+	})
+}
+
 func TestValuesHelper(t *testing.T) {
 
 	t.Run("should return static values", func(t *testing.T) {
@@ -798,3 +1503,311 @@ func TestSourceAnnotations(t *testing.T) {
 		}
 	})
 }
+
+func TestReleaseAnnotations(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML + "appVersion: 1.2.3\n")},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/crds/widgets.yaml":        &fstest.MapFile{Data: []byte(crdYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+	}
+
+	sources := []helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "metadata"}}
+
+	t.Run("should add release annotations when enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.WithReleaseAnnotations(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(helm.AnnotationRelease, "metadata"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(helm.AnnotationChart, "local"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(helm.AnnotationChartVersion, "0.1.0"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(helm.AnnotationAppVersion, "1.2.3"))
+		}
+	})
+
+	t.Run("should not add release annotations when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetAnnotations()).ToNot(HaveKey(helm.AnnotationRelease))
+		}
+	})
+}
+
+const namespacedTemplatesYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: explicit
+  namespace: other
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: cluster-widget
+`
+
+const clusterWidgetCRDYAML = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  names:
+    kind: Widget
+`
+
+func TestNamespace(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":             &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/crds/widgets.yaml":      &fstest.MapFile{Data: []byte(clusterWidgetCRDYAML)},
+		"chart/templates/objects.yaml": &fstest.MapFile{Data: []byte(namespacedTemplatesYAML)},
+	}
+
+	render := func(g *WithT, applyNamespace bool) map[string]string {
+		renderer, err := helm.New([]helm.Source{{
+			FS:             chartFS,
+			Chart:          "chart",
+			ReleaseName:    "ns",
+			Namespace:      "apps",
+			ApplyNamespace: applyNamespace,
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result := make(map[string]string, len(objects))
+		for _, obj := range objects {
+			result[obj.GetName()] = obj.GetNamespace()
+		}
+
+		return result
+	}
+
+	t.Run("should apply the namespace to namespaced objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		namespaces := render(g, true)
+		g.Expect(namespaces).To(HaveKeyWithValue("plain", "apps"))
+		g.Expect(namespaces).To(HaveKeyWithValue("explicit", "other"))
+		g.Expect(namespaces).To(HaveKeyWithValue("reader", ""))
+		g.Expect(namespaces).To(HaveKeyWithValue("cluster-widget", ""))
+		g.Expect(namespaces).To(HaveKeyWithValue("widgets.example.com", ""))
+	})
+
+	t.Run("should not apply the namespace by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		namespaces := render(g, false)
+		g.Expect(namespaces).To(HaveKeyWithValue("plain", ""))
+	})
+}
+
+const missingValueTemplateYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: strict
+data:
+  greeting: {{ .Values.greeting | quote }}
+  missing: "{{ .Values.missing }}"
+`
+
+func TestStrict(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(missingValueTemplateYAML)},
+	}
+
+	sources := []helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "strict"}}
+
+	t.Run("should render missing values as empty by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("missing", ""))
+	})
+
+	t.Run("should fail on missing values in strict mode", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.WithStrict(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(ContainSubstring("missing")))
+	})
+
+	t.Run("should render in strict mode when values are provided", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(sources, helm.WithStrict(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), map[string]any{"missing": "provided"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].Object["data"]).To(HaveKeyWithValue("missing", "provided"))
+	})
+}
+
+func TestConcurrency(t *testing.T) {
+	chartPath := setupLocalChart(t)
+
+	sources := []helm.Source{
+		{Chart: chartPath, ReleaseName: "first"},
+		{Chart: chartPath, ReleaseName: "second"},
+		{Chart: chartPath, ReleaseName: "third"},
+	}
+
+	t.Run("should render sources concurrently in source order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// Each source blocks until all of them are rendering, which only completes when
+		// the sources are processed concurrently
+		var barrier sync.WaitGroup
+		barrier.Add(len(sources))
+
+		renderer, err := helm.New(
+			sources,
+			helm.WithConcurrency(len(sources)),
+			helm.WithTransformer(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+				barrier.Done()
+				barrier.Wait()
+
+				return obj, nil
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetName()).To(Equal("first"))
+		g.Expect(objects[1].GetName()).To(Equal("second"))
+		g.Expect(objects[2].GetName()).To(Equal("third"))
+	})
+
+	t.Run("should report errors of failing sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			append(sources, helm.Source{Chart: filepath.Join(t.TempDir(), "missing"), ReleaseName: "missing"}),
+			helm.WithConcurrency(2),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(ContainSubstring("release: missing")))
+	})
+}
+
+func TestInfo(t *testing.T) {
+	chartFS := fstest.MapFS{
+		"chart/Chart.yaml":               &fstest.MapFile{Data: []byte(localChartYAML)},
+		"chart/values.yaml":              &fstest.MapFile{Data: []byte(localChartValuesYAML)},
+		"chart/templates/configmap.yaml": &fstest.MapFile{Data: []byte(localChartConfigMapYAML)},
+		"chart/templates/NOTES.txt":      &fstest.MapFile{Data: []byte("Release {{ .Release.Name }} says {{ .Values.greeting }}")},
+	}
+
+	t.Run("should return notes and chart metadata with the render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{
+			{FS: chartFS, Chart: "chart", ReleaseName: "notes"},
+			{Chart: setupLocalChart(t), ReleaseName: "plain"},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, info, err := renderer.ProcessWithInfo(t.Context(), map[string]any{"greeting": "hi"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(info).To(HaveLen(2))
+		g.Expect(info[0].ReleaseName).To(Equal("notes"))
+		g.Expect(info[0].Notes).To(Equal("Release notes says hi"))
+		g.Expect(info[0].Chart).ToNot(BeNil())
+		g.Expect(info[0].Chart.Name).To(Equal("local"))
+		g.Expect(info[0].Chart.Version).To(Equal("0.1.0"))
+		g.Expect(info[1].ReleaseName).To(Equal("plain"))
+		g.Expect(info[1].Notes).To(BeEmpty())
+		g.Expect(info[1].Chart).ToNot(BeNil())
+	})
+
+	t.Run("should return the info of cached renders", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "notes"}},
+			helm.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, greeting := range []string{"hi", "hello", "hi"} {
+			_, info, err := renderer.ProcessWithInfo(t.Context(), map[string]any{"greeting": greeting})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(info).To(HaveLen(1))
+			g.Expect(info[0].Notes).To(Equal("Release notes says " + greeting))
+		}
+	})
+
+	t.Run("should return the info of each concurrent render", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New([]helm.Source{{FS: chartFS, Chart: "chart", ReleaseName: "notes"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var wg sync.WaitGroup
+
+		notes := make([]string, 8)
+		for i := range notes {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, info, err := renderer.ProcessWithInfo(t.Context(), map[string]any{"greeting": strconv.Itoa(i)})
+				if err == nil {
+					notes[i] = info[0].Notes
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		for i, note := range notes {
+			g.Expect(note).To(Equal("Release notes says " + strconv.Itoa(i)))
+		}
+	})
+}
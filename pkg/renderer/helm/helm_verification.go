@@ -0,0 +1,57 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"helm.sh/helm/v3/pkg/downloader"
+)
+
+var (
+	// ErrChartVerification is returned when the provenance of a chart cannot be verified.
+	ErrChartVerification = errors.New("chart verification failed")
+)
+
+// verifyChart verifies the chart archive at chartPath against its .prov file and the keyring.
+func verifyChart(chartPath string, keyring string) error {
+	if _, err := downloader.VerifyChart(chartPath, keyring); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrChartVerification, chartPath, err)
+	}
+
+	return nil
+}
+
+// verifyChartFS verifies a chart archive embedded in a filesystem. The archive and its .prov
+// file are copied to a temporary directory, as Helm only verifies files on disk.
+func verifyChartFS(fsys fs.FS, chartPath string, keyring string) error {
+	tmp, err := os.MkdirTemp("", "helm-verify-")
+	if err != nil {
+		return fmt.Errorf("unable to create verification directory: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	target := filepath.Join(tmp, path.Base(chartPath))
+
+	for _, name := range []string{chartPath, chartPath + ".prov"} {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrChartVerification, chartPath, err)
+		}
+
+		dest := target
+		if name != chartPath {
+			dest += ".prov"
+		}
+
+		if err := os.WriteFile(dest, data, 0o600); err != nil {
+			return fmt.Errorf("unable to copy %s for verification: %w", name, err)
+		}
+	}
+
+	return verifyChart(target, keyring)
+}
@@ -0,0 +1,169 @@
+package helm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck
+	"helm.sh/helm/v3/pkg/provenance"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/helm"
+
+	. "github.com/onsi/gomega"
+)
+
+// newKeyring generates a signing key and returns the paths of its secret and public keyrings.
+func newKeyring(t *testing.T) (string, string) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	secring := filepath.Join(dir, "secring.gpg")
+	pubring := filepath.Join(dir, "pubring.gpg")
+
+	sec, err := os.Create(secring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = sec.Close() }()
+
+	if err := entity.SerializePrivate(sec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := os.Create(pubring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = pub.Close() }()
+
+	if err := entity.Serialize(pub); err != nil {
+		t.Fatal(err)
+	}
+
+	return secring, pubring
+}
+
+// signChart writes the provenance file of the chart archive, signed with the secret keyring.
+func signChart(t *testing.T, archive string, secring string) {
+	t.Helper()
+
+	signer, err := provenance.NewFromKeyring(secring, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := signer.ClearSign(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(archive+".prov", []byte(sig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerification(t *testing.T) {
+	secring, pubring := newKeyring(t)
+
+	signed := packageLocalChart(t)
+	signChart(t, signed, secring)
+
+	t.Run("should render signed charts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{{Chart: signed, ReleaseName: "signed"}},
+			helm.WithVerification(pubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should reject unsigned charts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{{Chart: packageLocalChart(t), ReleaseName: "unsigned"}},
+			helm.WithVerification(pubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(helm.ErrChartVerification))
+	})
+
+	t.Run("should reject charts signed with unknown keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, otherPubring := newKeyring(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{{Chart: signed, ReleaseName: "unknown"}},
+			helm.WithVerification(otherPubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(helm.ErrChartVerification))
+	})
+
+	t.Run("should reject unpacked charts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := helm.New(
+			[]helm.Source{{Chart: setupLocalChart(t), ReleaseName: "unpacked"}},
+			helm.WithVerification(pubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(helm.ErrChartVerification))
+	})
+
+	t.Run("should verify embedded charts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		archive, err := os.ReadFile(signed)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		prov, err := os.ReadFile(signed + ".prov")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		chartFS := fstest.MapFS{
+			"charts/local-0.1.0.tgz":      &fstest.MapFile{Data: archive},
+			"charts/local-0.1.0.tgz.prov": &fstest.MapFile{Data: prov},
+			"unsigned/local-0.1.0.tgz":    &fstest.MapFile{Data: archive},
+		}
+
+		renderer, err := helm.New(
+			[]helm.Source{{FS: chartFS, Chart: "charts/local-0.1.0.tgz", ReleaseName: "embedded"}},
+			helm.WithVerification(pubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		renderer, err = helm.New(
+			[]helm.Source{{FS: chartFS, Chart: "unsigned/local-0.1.0.tgz", ReleaseName: "embedded"}},
+			helm.WithVerification(pubring),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(helm.ErrChartVerification))
+	})
+}
@@ -0,0 +1,209 @@
+package json_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/json"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+const podJSON = `{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {"name": "test-pod", "labels": {"app": "test-app"}},
+  "spec": {"containers": [{"name": "nginx", "image": "nginx:latest"}]}
+}`
+
+const arrayJSON = `[
+  {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "test-service"}},
+  {"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "test-secret"}}
+]`
+
+const listJSON = `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config-1"}},
+    {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config-2"}}
+  ]
+}`
+
+const podListJSON = `{
+  "apiVersion": "v1",
+  "kind": "PodList",
+  "items": [
+    {"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "pod-1"}}
+  ]
+}`
+
+func TestRenderer(t *testing.T) {
+	ctx := t.Context()
+
+	testFS := fstest.MapFS{
+		"manifests/pod.json":      &fstest.MapFile{Data: []byte(podJSON)},
+		"manifests/array.json":    &fstest.MapFile{Data: []byte(arrayJSON)},
+		"manifests/list.json":     &fstest.MapFile{Data: []byte(listJSON)},
+		"manifests/podlist.json":  &fstest.MapFile{Data: []byte(podListJSON)},
+		"manifests/ignored.yaml":  &fstest.MapFile{Data: []byte("apiVersion: v1\nkind: Pod\n")},
+		"manifests/invalid.jsonx": &fstest.MapFile{Data: []byte("{")},
+	}
+
+	t.Run("should load single object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{{FS: testFS, Path: "manifests/pod.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("app", "test-app"))
+	})
+
+	t.Run("should not share cache entries across filesystems", func(t *testing.T) {
+		g := NewWithT(t)
+
+		other := fstest.MapFS{
+			"manifests/pod.json": &fstest.MapFile{Data: []byte(arrayJSON)},
+		}
+
+		renderer, err := json.New(
+			[]json.Source{
+				{FS: testFS, Path: "manifests/pod.json"},
+				{FS: other, Path: "manifests/pod.json"},
+			},
+			json.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+		g.Expect(objects[1].GetName()).To(Equal("test-service"))
+	})
+
+	t.Run("should load arrays of objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{{FS: testFS, Path: "manifests/array.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Service"))
+		g.Expect(objects[1].GetKind()).To(Equal("Secret"))
+	})
+
+	t.Run("should unwrap List kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{{FS: testFS, Path: "manifests/*list.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetName()).To(Equal("config-1"))
+		g.Expect(objects[1].GetName()).To(Equal("config-2"))
+		g.Expect(objects[2].GetName()).To(Equal("pod-1"))
+	})
+
+	t.Run("should only process JSON files matching glob", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{{FS: testFS, Path: "manifests/*"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(6))
+	})
+
+	t.Run("should fail when no files match", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{{FS: testFS, Path: "missing/*.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(json.ErrNoFilesMatched))
+	})
+
+	t.Run("should apply filters and transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New(
+			[]json.Source{{FS: testFS, Path: "manifests/*.json"}},
+			json.WithFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+			json.WithTransformer(labels.Set(map[string]string{"source": "json"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetKind()).To(Equal("Pod"))
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("source", "json"))
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should reject missing FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := json.New([]json.Source{{Path: "*.json"}})
+		g.Expect(err).To(MatchError(utilerrors.ErrFsRequired))
+	})
+
+	t.Run("should reject empty path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := json.New([]json.Source{{FS: fstest.MapFS{}}})
+		g.Expect(err).To(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := json.New([]json.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("json"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	testFS := fstest.MapFS{
+		"list.json": &fstest.MapFile{Data: []byte(listJSON)},
+	}
+
+	renderer, err := json.New(
+		[]json.Source{{FS: testFS, Path: "*.json"}},
+		json.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+
+	for _, obj := range objects {
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "json"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "list.json"))
+	}
+}
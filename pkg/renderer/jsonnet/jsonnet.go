@@ -0,0 +1,296 @@
+package jsonnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+)
+
+const (
+	rendererType = "jsonnet"
+
+	// defaultBinary is the jsonnet executable looked up in PATH when no binary is configured.
+	defaultBinary = "jsonnet"
+)
+
+// Source represents the input for a Jsonnet rendering operation.
+type Source struct {
+	// Path is the Jsonnet entrypoint file to evaluate. Required.
+	Path string
+
+	// JPaths are additional library search directories used to resolve imports (-J).
+	JPaths []string
+
+	// ExtVars are external string variables available via std.extVar() (--ext-str).
+	ExtVars map[string]string
+
+	// ExtCode are external variables whose values are Jsonnet code (--ext-code).
+	ExtCode map[string]string
+
+	// Values provides top-level arguments (TLAs) passed to the entrypoint function.
+	// Function is called during rendering to obtain dynamic values.
+	// Each top-level key becomes a TLA whose value is the JSON encoding of the entry (--tla-code).
+	Values func(context.Context) (map[string]any, error)
+}
+
+// Renderer handles Jsonnet rendering operations.
+// It implements types.Renderer.
+//
+// Evaluation is delegated to the jsonnet executable, so the binary must be available
+// in PATH or configured via WithBinary.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new Jsonnet Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+		Binary:       defaultBinary,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are deep merged with Source-level values and passed as top-level arguments.
+func (r *Renderer) Process(ctx context.Context, renderTimeValues map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder, renderTimeValues)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering jsonnet file %s: %w", holder.Path, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to jsonnet file %s: %w",
+				holder.Path,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the entrypoints and the library
+// directories of the sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		sources = append(sources, types.WatchSource{Pattern: holder.Path})
+
+		for _, jpath := range holder.JPaths {
+			sources = append(sources, types.WatchSource{Pattern: jpath})
+		}
+	}
+
+	return sources
+}
+
+func (r *Renderer) values(
+	ctx context.Context,
+	holder *sourceHolder,
+	renderTimeValues map[string]any,
+) (map[string]any, error) {
+	sourceValues := map[string]any{}
+
+	if holder.Values != nil {
+		v, err := holder.Values(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get values for jsonnet file %q: %w", holder.Path, err)
+		}
+		sourceValues = v
+	}
+
+	// Deep merge with render-time values taking precedence
+	return util.DeepMerge(sourceValues, renderTimeValues), nil
+}
+
+// renderSingle performs the rendering for a single Jsonnet input.
+func (r *Renderer) renderSingle(
+	ctx context.Context,
+	holder *sourceHolder,
+	renderTimeValues map[string]any,
+) ([]unstructured.Unstructured, error) {
+	values, err := r.values(ctx, holder, renderTimeValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute cache key from evaluation inputs
+	type cacheKeyData struct {
+		Binary  string
+		Path    string
+		JPaths  []string
+		ExtVars map[string]string
+		ExtCode map[string]string
+		Values  map[string]any
+	}
+
+	var cacheKey string
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		cacheKey = dump.ForHash(cacheKeyData{
+			Binary:  r.opts.Binary,
+			Path:    holder.Path,
+			JPaths:  holder.JPaths,
+			ExtVars: holder.ExtVars,
+			ExtCode: holder.ExtCode,
+			Values:  values,
+		})
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	args, err := evaluationArgs(holder, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	//nolint:gosec // The binary and its arguments are configured by the library user.
+	cmd := exec.CommandContext(ctx, r.opts.Binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to evaluate jsonnet: %w: %s",
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	// Decode with the apimachinery decoder so that integers are kept as int64
+	var out any
+	if err := utiljson.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("failed to decode jsonnet output: %w", err)
+	}
+
+	result, err := collectObjects(out)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add source annotations if enabled
+	if r.opts.SourceAnnotations {
+		for i := range result {
+			annotations := result[i].GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+
+			annotations[types.AnnotationSourceType] = rendererType
+			annotations[types.AnnotationSourcePath] = holder.Path
+
+			result[i].SetAnnotations(annotations)
+		}
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// evaluationArgs builds the jsonnet command line for a source.
+// Map entries are emitted in key order so that invocations are deterministic.
+func evaluationArgs(holder *sourceHolder, values map[string]any) ([]string, error) {
+	args := make([]string, 0)
+
+	for _, p := range holder.JPaths {
+		args = append(args, "--jpath", p)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(holder.ExtVars)) {
+		args = append(args, "--ext-str", k+"="+holder.ExtVars[k])
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(holder.ExtCode)) {
+		args = append(args, "--ext-code", k+"="+holder.ExtCode[k])
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		data, err := json.Marshal(values[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode top-level argument %q: %w", k, err)
+		}
+
+		args = append(args, "--tla-code", k+"="+string(data))
+	}
+
+	args = append(args, holder.Path)
+
+	return args, nil
+}
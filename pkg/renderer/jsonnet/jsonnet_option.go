@@ -0,0 +1,93 @@
+package jsonnet
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+)
+
+// RendererOption is a generic option for RendererOptions.
+type RendererOption = util.Option[RendererOptions]
+
+// RendererOptions is a struct-based option that can set multiple renderer options at once.
+type RendererOptions struct {
+	// Filters are renderer-specific filters applied during Process().
+	Filters []types.Filter
+
+	// Transformers are renderer-specific transformers applied during Process().
+	Transformers []types.Transformer
+
+	// Cache is a custom cache implementation for render results.
+	Cache cache.Interface[[]unstructured.Unstructured]
+
+	// SourceAnnotations enables automatic addition of source tracking annotations.
+	SourceAnnotations bool
+
+	// Binary is the jsonnet executable used to evaluate sources.
+	// Empty means use the jsonnet binary found in PATH.
+	Binary string
+}
+
+// ApplyTo applies the renderer options to the target configuration.
+func (opts RendererOptions) ApplyTo(target *RendererOptions) {
+	target.Filters = opts.Filters
+	target.Transformers = opts.Transformers
+
+	if opts.Cache != nil {
+		target.Cache = opts.Cache
+	}
+
+	if opts.Binary != "" {
+		target.Binary = opts.Binary
+	}
+
+	target.SourceAnnotations = opts.SourceAnnotations
+}
+
+// WithFilter adds a renderer-specific filter to this Jsonnet renderer's processing chain.
+// Renderer-specific filters are applied during Process(), before results are returned to the engine.
+// For engine-level filtering applied to all renderers, use engine.WithFilter.
+func WithFilter(f types.Filter) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Filters = append(opts.Filters, f)
+	})
+}
+
+// WithTransformer adds a renderer-specific transformer to this Jsonnet renderer's processing chain.
+// Renderer-specific transformers are applied during Process(), before results are returned to the engine.
+// For engine-level transformation applied to all renderers, use engine.WithTransformer.
+func WithTransformer(t types.Transformer) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Transformers = append(opts.Transformers, t)
+	})
+}
+
+// WithCache enables render result caching with the specified options.
+// If no options are provided, uses default TTL of 5 minutes.
+// By default, caching is NOT enabled.
+func WithCache(opts ...cache.Option) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(rendererOpts *RendererOptions) {
+		rendererOpts.Cache = cache.NewRenderCache(opts...)
+	})
+}
+
+// WithSourceAnnotations enables or disables automatic addition of source tracking annotations.
+// When enabled, the renderer adds metadata annotations to track the source type and entrypoint path.
+// Annotations added: manifests.k8s-manifests-lib/source.type, source.path.
+// Default: false (disabled).
+func WithSourceAnnotations(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.SourceAnnotations = enabled
+	})
+}
+
+// WithBinary sets the jsonnet executable used to evaluate sources.
+// Any implementation compatible with the jsonnet CLI flags (e.g. go-jsonnet, jrsonnet) can be used.
+// Default: "jsonnet" resolved from PATH.
+func WithBinary(binary string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Binary = binary
+	})
+}
@@ -0,0 +1,98 @@
+package jsonnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+)
+
+var (
+	// ErrUnexpectedOutput is returned when the evaluated Jsonnet contains values that are not objects.
+	ErrUnexpectedOutput = errors.New("jsonnet output must be an object, an array, or a map of objects")
+)
+
+// Values returns a Values function that always returns the provided static values.
+// This is a convenience helper for the common case of non-dynamic values.
+func Values(values map[string]any) func(context.Context) (map[string]any, error) {
+	return func(_ context.Context) (map[string]any, error) {
+		return values, nil
+	}
+}
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(strings.TrimSpace(h.Path)) == 0 {
+		return utilerrors.ErrPathEmpty
+	}
+
+	return nil
+}
+
+// collectObjects flattens the evaluated Jsonnet output into unstructured objects.
+//
+// The common Jsonnet layouts are supported:
+//   - a single Kubernetes object (has apiVersion and kind)
+//   - an array of objects (possibly nested)
+//   - a map of arbitrary keys to objects, as produced by kubecfg/tanka style libraries
+//
+// Map keys are walked in sorted order so that the output is deterministic.
+func collectObjects(value any) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0)
+
+	switch v := value.(type) {
+	case nil:
+		return result, nil
+	case []any:
+		for i := range v {
+			objects, err := collectObjects(v[i])
+			if err != nil {
+				return nil, fmt.Errorf("item[%d]: %w", i, err)
+			}
+
+			result = append(result, objects...)
+		}
+	case map[string]any:
+		if isKubernetesObject(v) {
+			result = append(result, unstructured.Unstructured{Object: v})
+
+			return result, nil
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			objects, err := collectObjects(v[k])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+
+			result = append(result, objects...)
+		}
+	default:
+		return nil, fmt.Errorf("%w, got %T", ErrUnexpectedOutput, value)
+	}
+
+	return result, nil
+}
+
+// isKubernetesObject reports whether the map carries a non-empty apiVersion and kind.
+func isKubernetesObject(m map[string]any) bool {
+	apiVersion, ok := m["apiVersion"].(string)
+	if !ok || apiVersion == "" {
+		return false
+	}
+
+	kind, ok := m["kind"].(string)
+
+	return ok && kind != ""
+}
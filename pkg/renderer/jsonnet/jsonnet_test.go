@@ -0,0 +1,417 @@
+package jsonnet_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/jsonnet"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+// fakeJsonnet emulates the jsonnet CLI: it records its arguments next to the
+// entrypoint and prints the entrypoint content, which in tests is plain JSON.
+const fakeJsonnet = `#!/bin/sh
+for last; do :; done
+printf '%s\n' "$@" > "$(dirname "$last")/args.txt"
+cat "$last"
+`
+
+const failingJsonnet = `#!/bin/sh
+echo "RUNTIME ERROR: boom" >&2
+exit 1
+`
+
+const singleObjectJSON = `{
+  "apiVersion": "v1",
+  "kind": "ConfigMap",
+  "metadata": {"name": "single"}
+}`
+
+const arrayJSON = `[
+  {"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "pod"}},
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}}
+]`
+
+const mapJSON = `{
+  "frontend": {
+    "deployment": {"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "frontend"}},
+    "service": {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "frontend"}}
+  },
+  "config": {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}}
+}`
+
+const deploymentJSON = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {"name": "deployment"},
+  "spec": {"replicas": 3}
+}`
+
+const invalidJSON = `{"value": 42}`
+
+func writeFile(t *testing.T, dir string, name string, content string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(content), perm)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return path
+}
+
+func setup(t *testing.T, content string) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	binary := writeFile(t, dir, "jsonnet", fakeJsonnet, 0o700)
+	path := writeFile(t, dir, "main.jsonnet", content, 0o600)
+
+	return binary, path
+}
+
+func readArgs(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(path), "args.txt"))
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestRenderer(t *testing.T) {
+
+	t.Run("should render single object", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetName()).Should(Equal("single"))
+	})
+
+	t.Run("should render array of objects", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, arrayJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+		g.Expect(objects[0].GetKind()).Should(Equal("Pod"))
+		g.Expect(objects[1].GetKind()).Should(Equal("ConfigMap"))
+	})
+
+	t.Run("should render nested map of objects in key order", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, mapJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(3))
+		g.Expect(objects[0].GetKind()).Should(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).Should(Equal("Deployment"))
+		g.Expect(objects[2].GetKind()).Should(Equal("Service"))
+	})
+
+	t.Run("should decode integers as int64", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, deploymentJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		replicas, found, err := unstructured.NestedInt64(objects[0].Object, "spec", "replicas")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(replicas).Should(Equal(int64(3)))
+	})
+
+	t.Run("should pass jpaths, ext vars and values as arguments", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{
+			Path:    path,
+			JPaths:  []string{"vendor", "lib"},
+			ExtVars: map[string]string{"env": "prod"},
+			ExtCode: map[string]string{"debug": "false"},
+			Values:  jsonnet.Values(map[string]any{"replicas": 1, "name": "app"}),
+		}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), map[string]any{"replicas": 3})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(readArgs(t, path)).Should(Equal([]string{
+			"--jpath", "vendor",
+			"--jpath", "lib",
+			"--ext-str", "env=prod",
+			"--ext-code", "debug=false",
+			"--tla-code", `name="app"`,
+			"--tla-code", "replicas=3",
+			path,
+		}))
+	})
+
+	t.Run("should apply filters", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, arrayJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetKind()).Should(Equal("Pod"))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithTransformer(labels.Set(map[string]string{"managed-by": "jsonnet-renderer"})),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("managed-by", "jsonnet-renderer"))
+	})
+
+	t.Run("should return error for non-object output", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, invalidJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(errors.Is(err, jsonnet.ErrUnexpectedOutput)).Should(BeTrue())
+	})
+
+	t.Run("should surface evaluation errors", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := t.TempDir()
+		binary := writeFile(t, dir, "jsonnet", failingJsonnet, 0o700)
+		path := writeFile(t, dir, "main.jsonnet", singleObjectJSON, 0o600)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).Should(ContainSubstring("RUNTIME ERROR: boom"))
+	})
+
+	t.Run("should return error when values function fails", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{
+			Path: path,
+			Values: func(_ context.Context) (map[string]any, error) {
+				return nil, errors.New("values error")
+			},
+		}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).Should(HaveOccurred())
+		g.Expect(err.Error()).Should(ContainSubstring("values error"))
+	})
+
+	t.Run("should implement Name() method", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer, err := jsonnet.New([]jsonnet.Source{})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(renderer.Name()).Should(Equal("jsonnet"))
+	})
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("should reject input without path", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := jsonnet.New([]jsonnet.Source{{}})
+		g.Expect(err).Should(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should reject input with whitespace-only path", func(t *testing.T) {
+		g := NewWithT(t)
+		_, err := jsonnet.New([]jsonnet.Source{{Path: "   "}})
+		g.Expect(err).Should(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should accept valid input", func(t *testing.T) {
+		g := NewWithT(t)
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: "main.jsonnet"}})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(renderer).ShouldNot(BeNil())
+	})
+}
+
+func TestCacheIntegration(t *testing.T) {
+
+	t.Run("should cache identical renders", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithCache(),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result1, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		// Changing the file must not be observed while the entry is cached
+		writeFile(t, filepath.Dir(path), "main.jsonnet", arrayJSON, 0o600)
+
+		result2, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result2).Should(Equal(result1))
+	})
+
+	t.Run("should miss cache on different values", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithCache(),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), map[string]any{"replicas": 1})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		writeFile(t, filepath.Dir(path), "main.jsonnet", arrayJSON, 0o600)
+
+		result, err := renderer.Process(t.Context(), map[string]any{"replicas": 2})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+	})
+
+	t.Run("should miss cache on different binaries", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+		other := writeFile(t, t.TempDir(), "jsonnet", "#!/bin/sh\nprintf '%s' '"+strings.ReplaceAll(arrayJSON, "\n", "")+"'\n", 0o700)
+		renderCache := cache.NewRenderCache()
+
+		renderer1, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.RendererOptions{Binary: binary, Cache: renderCache},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		renderer2, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.RendererOptions{Binary: other, Cache: renderCache},
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result1, err := renderer1.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result1).Should(HaveLen(1))
+
+		result2, err := renderer2.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result2).Should(HaveLen(2))
+	})
+
+	t.Run("should return clones from cache", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithCache(),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result1, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		result1[0].SetName("modified-name")
+
+		result2, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result2[0].GetName()).Should(Equal("single"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+
+	t.Run("should add source annotations when enabled", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, arrayJSON)
+
+		renderer, err := jsonnet.New(
+			[]jsonnet.Source{{Path: path}},
+			jsonnet.WithBinary(binary),
+			jsonnet.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(2))
+
+		for _, obj := range objects {
+			annotations := obj.GetAnnotations()
+			g.Expect(annotations).Should(HaveKeyWithValue(types.AnnotationSourceType, "jsonnet"))
+			g.Expect(annotations).Should(HaveKeyWithValue(types.AnnotationSourcePath, path))
+		}
+	})
+
+	t.Run("should not add source annotations when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+		binary, path := setup(t, singleObjectJSON)
+
+		renderer, err := jsonnet.New([]jsonnet.Source{{Path: path}}, jsonnet.WithBinary(binary))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects[0].GetAnnotations()).ShouldNot(HaveKey(types.AnnotationSourceType))
+	})
+}
@@ -0,0 +1,148 @@
+// Package kpt provides a renderer that hydrates kpt packages by executing their Kptfile function pipeline.
+package kpt
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+)
+
+const rendererType = "kpt"
+
+// Source represents a kpt package to render.
+type Source struct {
+	// Path is the directory containing the root Kptfile. Required.
+	Path string
+}
+
+// Renderer handles kpt package rendering operations.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new kpt Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the kpt renderer; packages are configured through their function pipeline.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering kpt package %s: %w", holder.Path, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to kpt package %s: %w",
+				holder.Path,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the package directories of the sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		sources = append(sources, types.WatchSource{Pattern: holder.Path})
+	}
+
+	return sources
+}
+
+// renderSingle hydrates a single kpt package.
+func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	var cacheKey string
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		cacheKey = dump.ForHash(holder.Path)
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	nodes, err := r.renderPackage(holder.Path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.toUnstructured(holder, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
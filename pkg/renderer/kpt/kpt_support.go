@@ -0,0 +1,372 @@
+package kpt
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+)
+
+const (
+	kptfileName = "Kptfile"
+
+	// localConfigAnnotation marks resources consumed by functions but not part of the output.
+	localConfigAnnotation = "config.kubernetes.io/local-config"
+
+	// containerUser is the user functions run as inside containers, matching kpt defaults.
+	containerUser = "nobody"
+)
+
+var (
+	// ErrKptfileNotFound is returned when the package directory does not contain a Kptfile.
+	ErrKptfileNotFound = errors.New("kptfile not found")
+
+	// ErrExecNotAllowed is returned when a pipeline declares an exec function but exec is not allowed.
+	ErrExecNotAllowed = errors.New("exec functions are not allowed, enable them with WithAllowExec")
+
+	// ErrInvalidFunction is returned when a pipeline function declares neither or both of image and exec.
+	ErrInvalidFunction = errors.New("function must specify exactly one of image or exec")
+)
+
+// kptfile is the subset of the kpt.dev/v1 Kptfile used for rendering.
+type kptfile struct {
+	Pipeline struct {
+		Mutators   []function `json:"mutators,omitempty"   yaml:"mutators,omitempty"`
+		Validators []function `json:"validators,omitempty" yaml:"validators,omitempty"`
+	} `json:"pipeline,omitempty" yaml:"pipeline,omitempty"`
+}
+
+// function is a single step of a Kptfile pipeline.
+type function struct {
+	Name       string            `json:"name,omitempty"       yaml:"name,omitempty"`
+	Image      string            `json:"image,omitempty"      yaml:"image,omitempty"`
+	Exec       string            `json:"exec,omitempty"       yaml:"exec,omitempty"`
+	ConfigPath string            `json:"configPath,omitempty" yaml:"configPath,omitempty"`
+	ConfigMap  map[string]string `json:"configMap,omitempty"  yaml:"configMap,omitempty"`
+}
+
+// String returns a human readable identifier for the function.
+func (f function) String() string {
+	switch {
+	case f.Name != "":
+		return f.Name
+	case f.Image != "":
+		return f.Image
+	default:
+		return f.Exec
+	}
+}
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(strings.TrimSpace(h.Path)) == 0 {
+		return utilerrors.ErrPathEmpty
+	}
+
+	return nil
+}
+
+// renderPackage hydrates the package at dir: subpackages are rendered first, then the
+// package pipeline runs over the package resources together with the subpackage output.
+// rel is the path of dir relative to the root package and is used for file annotations.
+func (r *Renderer) renderPackage(dir string, rel string) ([]*kyaml.RNode, error) {
+	kf, err := readKptfile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := (&kio.LocalPackageReader{
+		PackagePath:     dir,
+		PackageFileName: kptfileName,
+	}).Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package %s: %w", dir, err)
+	}
+
+	if err := prefixPath(nodes, rel); err != nil {
+		return nil, err
+	}
+
+	subpackages, err := findSubpackages(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range subpackages {
+		subNodes, err := r.renderPackage(filepath.Join(dir, sub), filepath.Join(rel, sub))
+		if err != nil {
+			return nil, fmt.Errorf("subpackage %s: %w", sub, err)
+		}
+
+		nodes = append(nodes, subNodes...)
+	}
+
+	for _, fn := range kf.Pipeline.Mutators {
+		filter, err := r.newFunctionFilter(dir, fn)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, err = filter.Filter(nodes)
+		if err != nil {
+			return nil, fmt.Errorf("mutator %s failed: %w", fn, err)
+		}
+	}
+
+	for _, fn := range kf.Pipeline.Validators {
+		filter, err := r.newFunctionFilter(dir, fn)
+		if err != nil {
+			return nil, err
+		}
+
+		// validators must not modify resources, their output is discarded
+		if _, err := filter.Filter(copyNodes(nodes)); err != nil {
+			return nil, fmt.Errorf("validator %s failed: %w", fn, err)
+		}
+	}
+
+	return nodes, nil
+}
+
+// newFunctionFilter creates the kio.Filter running a pipeline function.
+func (r *Renderer) newFunctionFilter(dir string, fn function) (kio.Filter, error) {
+	if (fn.Image == "") == (fn.Exec == "") {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFunction, fn)
+	}
+
+	config, err := functionConfig(dir, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if fn.Exec != "" {
+		if !r.opts.AllowExec {
+			return nil, fmt.Errorf("%w: %s", ErrExecNotAllowed, fn.Exec)
+		}
+
+		path := fn.Exec
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		return &exec.Filter{
+			Path:       path,
+			WorkingDir: dir,
+			FunctionFilter: runtimeutil.FunctionFilter{
+				FunctionConfig: config,
+				GlobalScope:    true,
+			},
+		}, nil
+	}
+
+	filter := container.NewContainer(runtimeutil.ContainerSpec{Image: fn.Image}, containerUser)
+	filter.Exec.FunctionConfig = config
+	filter.Exec.GlobalScope = true
+
+	return &filter, nil
+}
+
+// functionConfig loads the functionConfig passed to a pipeline function, if any.
+func functionConfig(dir string, fn function) (*kyaml.RNode, error) {
+	switch {
+	case fn.ConfigPath != "":
+		config, err := kyaml.ReadFile(filepath.Join(dir, fn.ConfigPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read function config %s: %w", fn.ConfigPath, err)
+		}
+
+		return config, nil
+	case len(fn.ConfigMap) > 0:
+		config, err := kyaml.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name": "function-input",
+				"annotations": map[string]any{
+					localConfigAnnotation: "true",
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create function config: %w", err)
+		}
+
+		if err := config.LoadMapIntoConfigMapData(fn.ConfigMap); err != nil {
+			return nil, fmt.Errorf("failed to create function config: %w", err)
+		}
+
+		return config, nil
+	default:
+		return nil, nil //nolint:nilnil // a nil functionConfig is valid
+	}
+}
+
+// toUnstructured converts the hydrated resources, dropping local config and internal annotations.
+func (r *Renderer) toUnstructured(holder *sourceHolder, nodes []*kyaml.RNode) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(nodes))
+
+	for _, node := range nodes {
+		if node.GetAnnotations()[localConfigAnnotation] == "true" {
+			continue
+		}
+
+		file, _, err := kioutil.GetFileAnnotations(node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file annotations: %w", err)
+		}
+
+		if err := clearInternalAnnotations(node); err != nil {
+			return nil, err
+		}
+
+		m, err := node.Map()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert resource: %w", err)
+		}
+
+		obj := unstructured.Unstructured{Object: m}
+
+		// Add source annotations if enabled
+		if r.opts.SourceAnnotations {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+
+			annotations[types.AnnotationSourceType] = rendererType
+			annotations[types.AnnotationSourcePath] = holder.Path
+			annotations[types.AnnotationSourceFile] = file
+
+			obj.SetAnnotations(annotations)
+		}
+
+		result = append(result, obj)
+	}
+
+	return result, nil
+}
+
+// readKptfile loads the Kptfile of a package directory.
+func readKptfile(dir string) (*kptfile, error) {
+	data, err := os.ReadFile(filepath.Join(dir, kptfileName))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrKptfileNotFound, dir)
+		}
+
+		return nil, fmt.Errorf("failed to read Kptfile: %w", err)
+	}
+
+	var kf kptfile
+	if err := kyaml.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("failed to decode Kptfile in %s: %w", dir, err)
+	}
+
+	return &kf, nil
+}
+
+// findSubpackages returns the directories, relative to dir, of the direct subpackages of a package.
+func findSubpackages(dir string) ([]string, error) {
+	result := make([]string, 0)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || path == dir {
+			return nil
+		}
+
+		if _, err := os.Stat(filepath.Join(path, kptfileName)); err != nil {
+			return nil //nolint:nilerr // not a subpackage, keep walking
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, rel)
+
+		// nested packages are handled by the subpackage itself
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subpackages in %s: %w", dir, err)
+	}
+
+	return result, nil
+}
+
+// prefixPath rewrites the file annotations of nodes read from a subpackage so that they are
+// relative to the root package.
+func prefixPath(nodes []*kyaml.RNode, rel string) error {
+	if rel == "" {
+		return nil
+	}
+
+	for _, node := range nodes {
+		path, _, err := kioutil.GetFileAnnotations(node)
+		if err != nil {
+			return fmt.Errorf("failed to read file annotations: %w", err)
+		}
+
+		value := filepath.ToSlash(filepath.Join(rel, path))
+
+		for _, key := range []string{kioutil.PathAnnotation, kioutil.LegacyPathAnnotation} {
+			if err := node.PipeE(kyaml.SetAnnotation(key, value)); err != nil {
+				return fmt.Errorf("failed to set file annotation: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// clearInternalAnnotations removes the bookkeeping annotations added by the kio reader.
+func clearInternalAnnotations(node *kyaml.RNode) error {
+	for key := range node.GetAnnotations() {
+		if !strings.HasPrefix(key, "internal.config.kubernetes.io/") &&
+			key != kioutil.LegacyPathAnnotation &&
+			key != kioutil.LegacyIndexAnnotation &&
+			key != kioutil.LegacyIdAnnotation {
+			continue
+		}
+
+		if err := node.PipeE(kyaml.ClearAnnotation(key)); err != nil {
+			return fmt.Errorf("failed to clear annotation %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// copyNodes deep copies resources so that validators cannot alter the pipeline output.
+func copyNodes(nodes []*kyaml.RNode) []*kyaml.RNode {
+	result := make([]*kyaml.RNode, len(nodes))
+	for i := range nodes {
+		result[i] = nodes[i].Copy()
+	}
+
+	return result
+}
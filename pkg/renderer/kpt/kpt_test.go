@@ -0,0 +1,260 @@
+package kpt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kpt"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+const rootKptfile = `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: app
+pipeline:
+  mutators:
+    - exec: ./scale.sh
+  validators:
+    - exec: ./check-env.sh
+      configMap:
+        env: prod
+`
+
+const subKptfile = `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: sub
+pipeline:
+  mutators:
+    - exec: ./rename.sh
+`
+
+const plainKptfile = `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: plain
+`
+
+const failingKptfile = `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: failing
+pipeline:
+  validators:
+    - exec: ./fail.sh
+`
+
+const deploymentYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+
+const localConfigYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+  annotations:
+    config.kubernetes.io/local-config: "true"
+data:
+  replicas: "3"
+`
+
+const subConfigMapYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: sub-config
+data:
+  key: value
+`
+
+// scaleFn is a KRM function that sets replicas to 3.
+const scaleFn = `#!/bin/sh
+sed 's/replicas: 1/replicas: 3/'
+`
+
+// renameFn is a KRM function that renames the sub-package ConfigMap.
+const renameFn = `#!/bin/sh
+sed 's/name: sub-config/name: sub-config-renamed/'
+`
+
+// checkEnvFn is a KRM validator that requires the functionConfig to carry env: prod.
+const checkEnvFn = `#!/bin/sh
+input=$(cat)
+echo "$input" | grep -q "env: prod" || exit 1
+echo "$input"
+`
+
+const failFn = `#!/bin/sh
+echo "validation failed" >&2
+exit 1
+`
+
+func writeFile(t *testing.T, dir string, name string, content string, perm os.FileMode) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupPackage(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	writeFile(t, dir, "Kptfile", rootKptfile, 0o600)
+	writeFile(t, dir, "deployment.yaml", deploymentYAML, 0o600)
+	writeFile(t, dir, "setters.yaml", localConfigYAML, 0o600)
+	writeFile(t, dir, "scale.sh", scaleFn, 0o700)
+	writeFile(t, dir, "check-env.sh", checkEnvFn, 0o700)
+	writeFile(t, dir, "sub/Kptfile", subKptfile, 0o600)
+	writeFile(t, dir, "sub/configmap.yaml", subConfigMapYAML, 0o600)
+	writeFile(t, dir, "sub/rename.sh", renameFn, 0o700)
+
+	return dir
+}
+
+func TestRenderer(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should hydrate package and subpackages", func(t *testing.T) {
+		dir := setupPackage(t)
+
+		renderer, err := kpt.New([]kpt.Source{{Path: dir}}, kpt.WithAllowExec(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(objects[0].GetKind()).To(Equal("Deployment"))
+		g.Expect(objects[0].Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeNumerically("==", 3))))
+		g.Expect(objects[0].GetAnnotations()).To(BeEmpty())
+
+		g.Expect(objects[1].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetName()).To(Equal("sub-config-renamed"))
+	})
+
+	t.Run("should render package without pipeline", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "Kptfile", plainKptfile, 0o600)
+		writeFile(t, dir, "deployment.yaml", deploymentYAML, 0o600)
+
+		renderer, err := kpt.New([]kpt.Source{{Path: dir}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("app"))
+	})
+
+	t.Run("should reject exec functions unless allowed", func(t *testing.T) {
+		dir := setupPackage(t)
+
+		renderer, err := kpt.New([]kpt.Source{{Path: dir}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kpt.ErrExecNotAllowed))
+	})
+
+	t.Run("should fail when validator fails", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "Kptfile", failingKptfile, 0o600)
+		writeFile(t, dir, "deployment.yaml", deploymentYAML, 0o600)
+		writeFile(t, dir, "fail.sh", failFn, 0o700)
+
+		renderer, err := kpt.New([]kpt.Source{{Path: dir}}, kpt.WithAllowExec(true))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("validator"))
+	})
+
+	t.Run("should fail without Kptfile", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "deployment.yaml", deploymentYAML, 0o600)
+
+		renderer, err := kpt.New([]kpt.Source{{Path: dir}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kpt.ErrKptfileNotFound))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		dir := setupPackage(t)
+
+		renderer, err := kpt.New(
+			[]kpt.Source{{Path: dir}},
+			kpt.WithAllowExec(true),
+			kpt.WithTransformer(labels.Set(map[string]string{"source": "kpt"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("source", "kpt"))
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should reject empty path", func(t *testing.T) {
+		_, err := kpt.New([]kpt.Source{{Path: " "}})
+		g.Expect(err).To(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		renderer, err := kpt.New([]kpt.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("kpt"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+	dir := setupPackage(t)
+
+	renderer, err := kpt.New(
+		[]kpt.Source{{Path: dir}},
+		kpt.WithAllowExec(true),
+		kpt.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "kpt"))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, dir))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "deployment.yaml"))
+	g.Expect(objects[1].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "sub/configmap.yaml"))
+}
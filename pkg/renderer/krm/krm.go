@@ -0,0 +1,198 @@
+// Package krm provides a renderer running KRM functions, executables or containers
+// accepting a ResourceList on stdin, as generators or over the output of other renderers.
+package krm
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	utilkrm "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/krm"
+)
+
+const rendererType = "krm"
+
+// Source represents a pipeline of KRM functions.
+type Source struct {
+	// Functions are run in order, the output of a function being the input of the next one. Required.
+	Functions []utilkrm.Function
+
+	// Inputs are renderers whose output is passed to the first function. Optional; without
+	// inputs the functions act as generators and receive an empty ResourceList.
+	// Render-time values are forwarded to the inputs.
+	Inputs []types.Renderer
+}
+
+// Renderer handles KRM function rendering operations.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new KRM Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+func (r *Renderer) Process(ctx context.Context, values map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder, values)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering KRM functions %s: %w", holder.name(), err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to KRM functions %s: %w",
+				holder.name(),
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the local files read by the inputs of
+// the sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0)
+
+	for _, holder := range r.inputs {
+		for _, input := range holder.Inputs {
+			if watchable, ok := input.(types.Watchable); ok {
+				sources = append(sources, watchable.WatchSources()...)
+			}
+		}
+	}
+
+	return sources
+}
+
+// renderSingle runs the function pipeline of a single Source.
+func (r *Renderer) renderSingle(
+	ctx context.Context,
+	holder *sourceHolder,
+	values map[string]any,
+) ([]unstructured.Unstructured, error) {
+	objects := make([]unstructured.Unstructured, 0)
+
+	for _, input := range holder.Inputs {
+		result, err := input.Process(ctx, values)
+		if err != nil {
+			return nil, fmt.Errorf("input %s failed: %w", input.Name(), err)
+		}
+
+		objects = append(objects, result...)
+	}
+
+	type cacheKeyData struct {
+		Functions []utilkrm.Function
+		Objects   []unstructured.Unstructured
+	}
+
+	var cacheKey string
+
+	// Check cache (if enabled), functions are only run again if their input changes
+	if r.opts.Cache != nil {
+		cacheKey = dump.ForHash(cacheKeyData{
+			Functions: holder.Functions,
+			Objects:   objects,
+		})
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	result := objects
+
+	for _, fn := range holder.Functions {
+		var err error
+
+		result, err = utilkrm.Run(ctx, fn, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add source annotations if enabled
+	if r.opts.SourceAnnotations {
+		for i := range result {
+			annotations := result[i].GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+
+			annotations[types.AnnotationSourceType] = rendererType
+			annotations[types.AnnotationSourcePath] = holder.name()
+
+			result[i].SetAnnotations(annotations)
+		}
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
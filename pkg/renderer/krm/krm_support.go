@@ -0,0 +1,44 @@
+package krm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+var (
+	// ErrNoFunctions is returned when a Source does not declare any function.
+	ErrNoFunctions = errors.New("at least one function is required")
+)
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(h.Functions) == 0 {
+		return ErrNoFunctions
+	}
+
+	for i, fn := range h.Functions {
+		if err := fn.Validate(); err != nil {
+			return fmt.Errorf("functions[%d]: %w", i, err)
+		}
+	}
+
+	for i, input := range h.Inputs {
+		if err := types.ValidateRenderer(input); err != nil {
+			return fmt.Errorf("inputs[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// name returns a human readable identifier of the source for error messages.
+func (h *sourceHolder) name() string {
+	return h.Functions[len(h.Functions)-1].String()
+}
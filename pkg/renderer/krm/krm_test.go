@@ -0,0 +1,176 @@
+package krm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/krm"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/mem"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilkrm "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/krm"
+
+	. "github.com/onsi/gomega"
+)
+
+// generatorFn is a KRM function that ignores its input and generates a Deployment.
+const generatorFn = `#!/bin/sh
+cat > /dev/null
+cat <<'EOF'
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: generated
+  spec:
+    replicas: 1
+EOF
+`
+
+// scaleFn is a KRM function that sets the replicas of every resource to 3.
+const scaleFn = `#!/bin/sh
+sed 's/replicas: 1/replicas: 3/'
+`
+
+func writeFunction(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "fn.sh")
+	if err := os.WriteFile(path, []byte(content), 0o700); err != nil { //nolint:gosec // test function must be executable
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": name},
+			"spec":       map[string]any{"replicas": int64(1)},
+		},
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should run generator functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := krm.New([]krm.Source{{
+			Functions: []utilkrm.Function{{Exec: writeFunction(t, generatorFn)}},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("generated"))
+	})
+
+	t.Run("should run functions over the output of input renderers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		input, err := mem.New([]mem.Source{{
+			Objects: []unstructured.Unstructured{newDeployment("web"), newDeployment("api")},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		renderer, err := krm.New([]krm.Source{{
+			Functions: []utilkrm.Function{{Exec: writeFunction(t, scaleFn)}},
+			Inputs:    []types.Renderer{input},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		for _, obj := range objects {
+			replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+			g.Expect(replicas).To(BeEquivalentTo(3))
+		}
+	})
+
+	t.Run("should chain functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := krm.New([]krm.Source{{
+			Functions: []utilkrm.Function{
+				{Exec: writeFunction(t, generatorFn)},
+				{Exec: writeFunction(t, scaleFn)},
+			},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		replicas, _, _ := unstructured.NestedInt64(objects[0].Object, "spec", "replicas")
+		g.Expect(replicas).To(BeEquivalentTo(3))
+	})
+
+	t.Run("should apply transformers and source annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fn := writeFunction(t, generatorFn)
+
+		renderer, err := krm.New(
+			[]krm.Source{{Functions: []utilkrm.Function{{Exec: fn}}}},
+			krm.WithTransformer(labels.Set(map[string]string{"source": "krm"})),
+			krm.WithSourceAnnotations(true),
+			krm.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("source", "krm"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "krm"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, fn))
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should require functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := krm.New([]krm.Source{{}})
+		g.Expect(err).To(MatchError(krm.ErrNoFunctions))
+	})
+
+	t.Run("should reject invalid functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := krm.New([]krm.Source{{Functions: []utilkrm.Function{{}}}})
+		g.Expect(err).To(MatchError(utilkrm.ErrInvalidFunction))
+	})
+
+	t.Run("should reject nil inputs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := krm.New([]krm.Source{{
+			Functions: []utilkrm.Function{{Exec: "fn"}},
+			Inputs:    []types.Renderer{nil},
+		}})
+		g.Expect(err).To(MatchError(types.ErrRendererNil))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := krm.New([]krm.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("krm"))
+	})
+}
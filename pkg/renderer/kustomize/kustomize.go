@@ -3,6 +3,7 @@ package kustomize
 import (
 	"context"
 	"fmt"
+	"io/fs"
 
 	"sigs.k8s.io/kustomize/api/resmap"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
 )
 
 const rendererType = "kustomize"
@@ -20,9 +22,16 @@ const rendererType = "kustomize"
 // Source represents the input for a Kustomize rendering operation.
 type Source struct {
 	// Path specifies the directory containing kustomization.yaml.
-	// Must be a valid filesystem path to a kustomization root.
+	// Must be a valid filesystem path to a kustomization root. When FS is set, Path is
+	// the directory of the kustomization within FS, e.g. "." for its root.
 	Path string
 
+	// FS is the filesystem holding the kustomization tree, e.g. an embed.FS bundling
+	// kustomizations into the binary or an fstest.MapFS generated in memory.
+	// Optional; when set, the kustomization and everything it references locally are
+	// read from FS, so the tree must be self-contained.
+	FS fs.FS
+
 	// Values provides dynamic key-value data written as a ConfigMap.
 	// Function is called during rendering to obtain dynamic values.
 	// The values are written to a ConfigMap file at Path/values.yaml.
@@ -41,6 +50,35 @@ type Source struct {
 	// If LoadRestrictionsUnknown (zero value), uses the renderer-wide default.
 	// Set to LoadRestrictionsRootOnly or LoadRestrictionsNone to override.
 	LoadRestrictions kustomizetypes.LoadRestrictions
+
+	// The following fields are merged into the kustomization at Path, as if they were written in it,
+	// without modifying the kustomization on disk.
+
+	// Patches are strategic merge or JSON6902 patches, inline or from a file relative to Path,
+	// optionally restricted to the objects matching their target.
+	Patches []kustomizetypes.Patch
+
+	// CommonLabels are added to all objects and selectors. They override labels of the kustomization.
+	CommonLabels map[string]string
+
+	// CommonAnnotations are added to all objects. They override annotations of the kustomization.
+	CommonAnnotations map[string]string
+
+	// NamePrefix is prepended to the name prefix of the kustomization.
+	NamePrefix string
+
+	// NameSuffix is appended to the name suffix of the kustomization.
+	NameSuffix string
+
+	// ConfigMapGenerator are generated ConfigMaps, added to the generators of the kustomization.
+	ConfigMapGenerator []kustomizetypes.ConfigMapArgs
+
+	// SecretGenerator are generated Secrets, added to the generators of the kustomization.
+	SecretGenerator []kustomizetypes.SecretArgs
+
+	// Components are the Kustomize Components (kind: Component) added to the components of the
+	// kustomization. Entries are directories, absolute or relative to Path, or remote bases.
+	Components []string
 }
 
 // Renderer is a renderer that uses kustomize to render resources.
@@ -59,6 +97,7 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 		Transformers:     make([]types.Transformer, 0),
 		Plugins:          make([]resmap.Transformer, 0),
 		LoadRestrictions: kustomizetypes.LoadRestrictionsRootOnly,
+		RemoteTimeout:    defaultRemoteTimeout,
 	}
 
 	// Apply all options to RendererOptions
@@ -88,11 +127,35 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	return r, nil
 }
 
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
 // Name returns the renderer type identifier.
 func (r *Renderer) Name() string {
 	return rendererType
 }
 
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the kustomization directories of the
+// sources. Files referenced from outside these directories, e.g. sibling bases, are not included.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.Path})
+	}
+
+	return sources
+}
+
 // Process implements types.Renderer by rendering the kustomize resources and applying filters and transformers.
 func (r *Renderer) Process(ctx context.Context, renderTimeValues map[string]any) ([]unstructured.Unstructured, error) {
 	allObjects := make([]unstructured.Unstructured, 0)
@@ -137,17 +200,52 @@ func (r *Renderer) renderSingle(
 
 	// Compute cache key from input Path and Values
 	type cacheKeyData struct {
-		Path   string
-		Values map[string]string
+		Path               string
+		FS                 string
+		Values             map[string]string
+		Patches            []kustomizetypes.Patch
+		CommonLabels       map[string]string
+		CommonAnnotations  map[string]string
+		NamePrefix         string
+		NameSuffix         string
+		ConfigMapGenerator []kustomizetypes.ConfigMapArgs
+		SecretGenerator    []kustomizetypes.SecretArgs
+		Components         []string
+		Digest             string
 	}
 
 	var cacheKey string
 
 	// Check cache (if enabled)
 	if r.opts.Cache != nil {
+		var digest string
+
+		if r.opts.ContentHash {
+			digest, err = r.engine.digest(holder.Source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compute content digest for path %q: %w", holder.Path, err)
+			}
+		}
+
+		// Sources over different filesystems may share the same Path, key them on the source itself
+		var fsKey string
+		if holder.FS != nil {
+			fsKey = fmt.Sprintf("%p", holder)
+		}
+
 		cacheKey = dump.ForHash(cacheKeyData{
-			Path:   holder.Path,
-			Values: values,
+			Path:               holder.Path,
+			FS:                 fsKey,
+			Values:             values,
+			Patches:            holder.Patches,
+			CommonLabels:       holder.CommonLabels,
+			CommonAnnotations:  holder.CommonAnnotations,
+			NamePrefix:         holder.NamePrefix,
+			NameSuffix:         holder.NameSuffix,
+			ConfigMapGenerator: holder.ConfigMapGenerator,
+			SecretGenerator:    holder.SecretGenerator,
+			Components:         holder.Components,
+			Digest:             digest,
 		})
 
 		// ensure objects are evicted
@@ -159,7 +257,7 @@ func (r *Renderer) renderSingle(
 	}
 
 	// No filesystem writes needed - values passed to engine
-	result, err := r.engine.Run(holder.Source, values)
+	result, err := r.engine.Run(ctx, holder.Source, values)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run kustomize for path %q: %w", holder.Path, err)
 	}
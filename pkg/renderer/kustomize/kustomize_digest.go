@@ -0,0 +1,152 @@
+package kustomize
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// treeDigester hashes the files of a kustomization directory and of the local kustomizations
+// it references, so changes to any of them change the digest.
+type treeDigester struct {
+	fs      filesys.FileSystem
+	visited map[string]bool
+	files   map[string]bool
+}
+
+// digest returns the digest of the kustomization tree of a source, see treeDigest.
+func (e *Engine) digest(input Source) (string, error) {
+	fsys, kustPath, err := e.sourceFS(input)
+	if err != nil {
+		return "", err
+	}
+
+	root, _, err := fsys.CleanedAbs(kustPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %q: %w", input.Path, err)
+	}
+
+	return treeDigest(fsys, root.String(), input.Components...)
+}
+
+// treeDigest returns a digest of the content of the kustomization at root, its subdirectories,
+// the local bases and components it references, recursively, and the extra directories.
+// Remote bases are not part of the digest.
+func treeDigest(fsys filesys.FileSystem, root string, extra ...string) (string, error) {
+	d := &treeDigester{
+		fs:      fsys,
+		visited: make(map[string]bool),
+		files:   make(map[string]bool),
+	}
+
+	if err := d.addKustomization(root); err != nil {
+		return "", err
+	}
+
+	for _, dir := range extra {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+
+		if fsys.IsDir(dir) {
+			if err := d.addKustomization(dir); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Hash the files in a stable order, each one with its path
+	names := make([]string, 0, len(d.files))
+	for name := range d.files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	h := sha256.New()
+
+	for _, name := range names {
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		_, _ = h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// addKustomization collects the files under dir and follows the local directories referenced
+// by the kustomization in dir, if any.
+func (d *treeDigester) addKustomization(dir string) error {
+	if d.visited[dir] {
+		return nil
+	}
+
+	d.visited[dir] = true
+
+	err := d.fs.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			d.files[name] = true
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %w", dir, err)
+	}
+
+	kust, _, err := readKustomization(d.fs, dir)
+	if err != nil {
+		// Directories without a kustomization are plain file trees, e.g. a component
+		// referenced by a source that kustomize reports as missing
+		return nil //nolint:nilerr
+	}
+
+	for _, patch := range kust.Patches {
+		if patch.Path != "" {
+			d.addFile(filepath.Join(dir, patch.Path))
+		}
+	}
+
+	for _, entry := range slices.Concat(kust.Resources, kust.Components, kust.Transformers, kust.Generators) {
+		p := filepath.Join(dir, entry)
+
+		if !d.fs.IsDir(p) {
+			// Files outside of dir are allowed by LoadRestrictionsNone
+			d.addFile(p)
+
+			continue
+		}
+
+		if err := d.addKustomization(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addFile collects a file referenced by a kustomization, ignoring missing files and remote entries.
+func (d *treeDigester) addFile(name string) {
+	if d.fs.Exists(name) && !d.fs.IsDir(name) {
+		d.files[name] = true
+	}
+}
@@ -0,0 +1,98 @@
+package kustomize_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
+
+	. "github.com/onsi/gomega"
+)
+
+// countingPlugin counts the kustomize builds it takes part in.
+type countingPlugin struct {
+	calls *int
+}
+
+func (p countingPlugin) Transform(_ resmap.ResMap) error {
+	*p.calls++
+
+	return nil
+}
+
+func TestContentHash(t *testing.T) {
+	render := func(g *WithT, renderer *kustomize.Renderer) string {
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+
+		return objects[0].GetName()
+	}
+
+	setup := func(t *testing.T) (string, string) {
+		t.Helper()
+
+		dir := t.TempDir()
+		writeFile(t, dir, "base/kustomization.yaml", baseKustomization)
+		writeFile(t, dir, "base/configmap.yaml", baseConfigMap)
+		writeFile(t, dir, "overlay/kustomization.yaml", overlayKustomization)
+
+		return dir + "/base", dir + "/overlay"
+	}
+
+	t.Run("should invalidate the cache when a referenced file changes", func(t *testing.T) {
+		g := NewWithT(t)
+		base, overlay := setup(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: overlay}},
+			kustomize.WithCache(),
+			kustomize.WithContentHash(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(render(g, renderer)).To(Equal("app-config"))
+
+		// The base is outside of the overlay directory
+		writeFile(t, base, "configmap.yaml", strings.Replace(baseConfigMap, "name: app-config", "name: app-settings", 1))
+
+		g.Expect(render(g, renderer)).To(Equal("app-settings"))
+	})
+
+	t.Run("should serve cached results without content hash", func(t *testing.T) {
+		g := NewWithT(t)
+		base, overlay := setup(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: overlay}},
+			kustomize.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(render(g, renderer)).To(Equal("app-config"))
+
+		writeFile(t, base, "configmap.yaml", strings.Replace(baseConfigMap, "name: app-config", "name: app-settings", 1))
+
+		g.Expect(render(g, renderer)).To(Equal("app-config"))
+	})
+
+	t.Run("should hit the cache when nothing changes", func(t *testing.T) {
+		g := NewWithT(t)
+		_, overlay := setup(t)
+
+		calls := 0
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: overlay}},
+			kustomize.WithCache(),
+			kustomize.WithContentHash(true),
+			kustomize.WithPlugin(countingPlugin{calls: &calls}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		render(g, renderer)
+		render(g, renderer)
+
+		g.Expect(calls).To(Equal(1))
+	})
+}
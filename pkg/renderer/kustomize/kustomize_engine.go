@@ -1,6 +1,7 @@
 package kustomize
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"slices"
@@ -44,32 +45,58 @@ func NewEngine(fs filesys.FileSystem, opts *RendererOptions) *Engine {
 }
 
 // Run executes the kustomize build process for the given source and returns the rendered objects.
-func (e *Engine) Run(input Source, values map[string]string) ([]unstructured.Unstructured, error) {
-	restrictions := e.opts.LoadRestrictions
-	if input.LoadRestrictions != kustomizetypes.LoadRestrictionsUnknown {
-		restrictions = input.LoadRestrictions
+func (e *Engine) Run(ctx context.Context, input Source, values map[string]string) ([]unstructured.Unstructured, error) {
+	// Create kustomizer with the build options and appropriate restrictions
+	kustomizer := krusty.MakeKustomizer(e.buildOptions(input))
+
+	fsys, kustPath, err := e.sourceFS(input)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load filesystem of path %q: %w", input.Path, err)
 	}
 
-	// Create kustomizer with appropriate restrictions
-	kustomizer := krusty.MakeKustomizer(&krusty.Options{
-		LoadRestrictions: restrictions,
-		PluginConfig:     &kustomizetypes.PluginConfig{},
-	})
+	root, _, err := fsys.CleanedAbs(kustPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %q: %w", input.Path, err)
+	}
 
-	kust, name, err := readKustomization(e.fs, input.Path)
+	kust, name, err := readKustomization(fsys, kustPath)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read kustomization from path %q: %w", input.Path, err)
+		return nil, newBuildError(fsys, input, root.String(), err)
 	}
 
+	// Merge the kustomization fields of the source
+	merged := mergeSource(kust, input, root.String())
+
+	// Fetch remote bases and point the kustomization to the fetched content. The content of
+	// FS sources is not on disk, so their remote bases are left to kustomize
+	remote := false
+	cleanup := func() {}
+
+	if input.FS == nil {
+		remote, cleanup, err = e.resolveRemoteBases(ctx, root.String(), kust)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve remote bases of path %q: %w", input.Path, err)
+		}
+	}
+
+	defer cleanup()
+
 	// Prepare filesystem with overlays if needed
-	fs, addedOriginAnnotations, err := e.prepareFilesystem(input.Path, kust, name, values)
+	fs, addedOriginAnnotations, err := e.prepareFilesystem(fsys, kustPath, kust, name, values, remote || merged)
 	if err != nil {
 		return nil, err
 	}
 
-	resMap, err := kustomizer.Run(fs, input.Path)
+	// Check the declared functions against the allowlist before kustomize runs any of them
+	if e.opts.FunctionPlugins != nil && len(e.opts.FunctionPlugins.Allowed) > 0 {
+		if err := checkFunctions(fs, root.String(), e.opts.FunctionPlugins.Allowed); err != nil {
+			return nil, fmt.Errorf("failed to check functions for path %q: %w", input.Path, err)
+		}
+	}
+
+	resMap, err := kustomizer.Run(fs, kustPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run kustomize for path %q: %w", input.Path, err)
+		return nil, newBuildError(fs, input, root.String(), err)
 	}
 
 	for _, t := range e.opts.Plugins {
@@ -94,20 +121,45 @@ func (e *Engine) Run(input Source, values map[string]string) ([]unstructured.Uns
 	return result, nil
 }
 
-// prepareFilesystem creates a union filesystem with overlays if needed for source annotations or values.
+// buildOptions returns the krusty options of the build of a source.
+func (e *Engine) buildOptions(input Source) *krusty.Options {
+	opts := krusty.Options{}
+	if e.opts.BuildOptions != nil {
+		opts = *e.opts.BuildOptions
+	}
+
+	if opts.LoadRestrictions == kustomizetypes.LoadRestrictionsUnknown {
+		opts.LoadRestrictions = e.opts.LoadRestrictions
+	}
+
+	if input.LoadRestrictions != kustomizetypes.LoadRestrictionsUnknown {
+		opts.LoadRestrictions = input.LoadRestrictions
+	}
+
+	if opts.PluginConfig == nil {
+		opts.PluginConfig = e.opts.FunctionPlugins.pluginConfig()
+	}
+
+	return &opts
+}
+
+// prepareFilesystem creates a union filesystem with overlays if needed for source annotations, values,
+// or a kustomization modified by the source fields or to reference fetched remote bases.
 // Returns the filesystem to use, whether origin annotations were added, and any error.
 func (e *Engine) prepareFilesystem(
+	base filesys.FileSystem,
 	inputPath string,
 	kust *kustomizetypes.Kustomization,
 	kustName string,
 	values map[string]string,
+	modified bool,
 ) (filesys.FileSystem, bool, error) {
-	// If neither source annotations nor values are needed, use the base filesystem
-	if !e.opts.SourceAnnotations && len(values) == 0 {
-		return e.fs, false, nil
+	// If neither source annotations, values nor a modified kustomization are needed, use the base filesystem
+	if !e.opts.SourceAnnotations && len(values) == 0 && !modified {
+		return base, false, nil
 	}
 
-	p, f, err := e.fs.CleanedAbs(inputPath)
+	p, f, err := base.CleanedAbs(inputPath)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to resolve path %q: %w", inputPath, err)
 	}
@@ -115,22 +167,23 @@ func (e *Engine) prepareFilesystem(
 		return nil, false, fmt.Errorf("path %q must be a dir: %w", inputPath, err)
 	}
 
-	builder := unionfs.NewBuilder(e.fs)
+	builder := unionfs.NewBuilder(base)
 	addedOriginAnnotations := false
 
-	// Add modified kustomization if source annotations are enabled
-	if e.opts.SourceAnnotations {
-		if !slices.Contains(kust.BuildMetadata, kustomizetypes.OriginAnnotations) {
-			kust.BuildMetadata = append(kust.BuildMetadata, kustomizetypes.OriginAnnotations)
-			addedOriginAnnotations = true
-
-			data, err := goyaml.Marshal(kust)
-			if err != nil {
-				return nil, false, fmt.Errorf("failed to marshal kustomization: %w", err)
-			}
+	// Enable origin annotations if source annotations are enabled
+	if e.opts.SourceAnnotations && !slices.Contains(kust.BuildMetadata, kustomizetypes.OriginAnnotations) {
+		kust.BuildMetadata = append(kust.BuildMetadata, kustomizetypes.OriginAnnotations)
+		addedOriginAnnotations = true
+	}
 
-			builder.WithOverride(filepath.Join(p.String(), kustName), data)
+	// Add modified kustomization
+	if addedOriginAnnotations || modified {
+		data, err := goyaml.Marshal(kust)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to marshal kustomization: %w", err)
 		}
+
+		builder.WithOverride(filepath.Join(p.String(), kustName), data)
 	}
 
 	// Add values ConfigMap if provided
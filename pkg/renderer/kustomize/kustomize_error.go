@@ -0,0 +1,137 @@
+package kustomize
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+var (
+	// recursedPathPattern matches the kustomization directories kustomize recurses into.
+	recursedPathPattern = regexp.MustCompile(`recursed accumulation of path '([^']+)'`)
+
+	// accumulatedFilePattern matches the resources kustomize accumulates.
+	accumulatedFilePattern = regexp.MustCompile(`accumulating resources from '([^']+)'`)
+
+	// yamlFilePattern matches the file of YAML errors.
+	yamlFilePattern = regexp.MustCompile(`in File: (\S+)`)
+
+	// yamlLinePattern matches the line of YAML errors.
+	yamlLinePattern = regexp.MustCompile(`yaml: line (\d+)`)
+)
+
+// BuildError is returned when a kustomize build fails. It exposes the kustomization and the file
+// that caused the failure, when they can be determined from the kustomize error, so callers can
+// report actionable diagnostics.
+type BuildError struct {
+	// Path is the path of the rendered source.
+	Path string
+
+	// Kustomization is the directory of the innermost kustomization being built when the build
+	// failed, e.g. a base of the source kustomization.
+	Kustomization string
+
+	// File is the resource or kustomization file that caused the failure. Empty if unknown.
+	File string
+
+	// Line is the line of File where parsing failed. Zero if unknown.
+	Line int
+
+	// Err is the underlying kustomize error.
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	location := e.Kustomization
+
+	switch {
+	case e.File != "" && e.Line > 0:
+		location = fmt.Sprintf("%s (%s:%d)", e.Kustomization, e.File, e.Line)
+	case e.File != "":
+		location = fmt.Sprintf("%s (%s)", e.Kustomization, e.File)
+	}
+
+	return fmt.Sprintf("kustomize build of path %q failed at %s: %v", e.Path, location, e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// newBuildError wraps an error of the build of the kustomization at root, extracting the offending
+// kustomization, file and line from the error message. Paths of FS sources are reported relative
+// to the FS.
+func newBuildError(fsys filesys.FileSystem, input Source, root string, err error) *BuildError {
+	msg := err.Error()
+
+	be := &BuildError{
+		Path:          input.Path,
+		Kustomization: root,
+		Err:           err,
+	}
+
+	// Only the part of the message after the innermost kustomization refers to its files
+	if m := recursedPathPattern.FindAllStringSubmatchIndex(msg, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		be.Kustomization = msg[last[2]:last[3]]
+		msg = msg[last[1]:]
+	}
+
+	if m := accumulatedFilePattern.FindAllStringSubmatch(msg, -1); len(m) > 0 {
+		be.File = m[len(m)-1][1]
+	}
+
+	if m := yamlFilePattern.FindStringSubmatch(msg); m != nil {
+		be.File = m[1]
+	}
+
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		be.Line, _ = strconv.Atoi(m[1])
+
+		// A YAML error without a resource file is an error of the kustomization itself
+		if be.File == "" {
+			be.File = kustomizationFile(fsys, be.Kustomization)
+		}
+	}
+
+	// Files are relative to the kustomization referencing them
+	if be.File != "" && !filepath.IsAbs(be.File) && !strings.Contains(be.File, "://") {
+		be.File = filepath.Join(be.Kustomization, be.File)
+	}
+
+	if input.FS != nil {
+		be.Kustomization = fsPath(be.Kustomization)
+		be.File = fsPath(be.File)
+	}
+
+	return be
+}
+
+// kustomizationFile returns the name of the kustomization file in dir, or empty if there is none.
+func kustomizationFile(fsys filesys.FileSystem, dir string) string {
+	for _, filename := range kustomizationFiles {
+		if fsys.Exists(filepath.Join(dir, filename)) {
+			return filename
+		}
+	}
+
+	return ""
+}
+
+// fsPath returns the path within the FS of a source of a path in the in-memory filesystem.
+func fsPath(p string) string {
+	if p == "" || !strings.HasPrefix(p, fsRoot) {
+		return p
+	}
+
+	rel, err := filepath.Rel(fsRoot, p)
+	if err != nil {
+		return p
+	}
+
+	return filepath.ToSlash(rel)
+}
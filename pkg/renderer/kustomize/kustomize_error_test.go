@@ -0,0 +1,103 @@
+package kustomize_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
+
+	. "github.com/onsi/gomega"
+)
+
+const malformedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: malformed
+  labels: [
+`
+
+const missingResourceKustomization = `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+resources:
+- configmap.yaml
+- missing.yaml
+`
+
+func buildError(t *testing.T, g *WithT, sources ...kustomize.Source) *kustomize.BuildError {
+	t.Helper()
+
+	renderer, err := kustomize.New(sources)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = renderer.Process(t.Context(), nil)
+	g.Expect(err).To(HaveOccurred())
+
+	var buildErr *kustomize.BuildError
+	g.Expect(errors.As(err, &buildErr)).To(BeTrue())
+
+	return buildErr
+}
+
+func TestBuildError(t *testing.T) {
+	t.Run("should report missing resources of a base", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		writeFile(t, dir, "base/kustomization.yaml", missingResourceKustomization)
+		writeFile(t, dir, "base/configmap.yaml", baseConfigMap)
+		writeFile(t, dir, "overlay/kustomization.yaml", overlayKustomization)
+
+		buildErr := buildError(t, g, kustomize.Source{Path: filepath.Join(dir, "overlay")})
+		g.Expect(buildErr.Path).To(Equal(filepath.Join(dir, "overlay")))
+		g.Expect(buildErr.Kustomization).To(Equal(filepath.Join(dir, "base")))
+		g.Expect(buildErr.File).To(Equal(filepath.Join(dir, "base", "missing.yaml")))
+		g.Expect(buildErr.Line).To(BeZero())
+		g.Expect(buildErr.Err).To(HaveOccurred())
+	})
+
+	t.Run("should report the file and line of malformed resources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		writeFile(t, dir, "kustomization.yaml", baseKustomization)
+		writeFile(t, dir, "configmap.yaml", malformedConfigMap)
+
+		buildErr := buildError(t, g, kustomize.Source{Path: dir})
+		g.Expect(buildErr.Kustomization).To(Equal(dir))
+		g.Expect(buildErr.File).To(Equal(filepath.Join(dir, "configmap.yaml")))
+		g.Expect(buildErr.Line).To(Equal(5))
+		g.Expect(buildErr.Error()).To(ContainSubstring("configmap.yaml:5"))
+	})
+
+	t.Run("should report malformed kustomizations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		writeFile(t, dir, "kustomization.yaml", "resources:\n  - configmap.yaml\n  foo: [\n")
+
+		buildErr := buildError(t, g, kustomize.Source{Path: dir})
+		g.Expect(buildErr.Kustomization).To(Equal(dir))
+		g.Expect(buildErr.File).To(Equal(filepath.Join(dir, "kustomization.yaml")))
+		g.Expect(buildErr.Line).To(BeNumerically(">", 0))
+	})
+
+	t.Run("should report paths within FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		buildErr := buildError(t, g, kustomize.Source{
+			FS: fstest.MapFS{
+				"base/kustomization.yaml":     {Data: []byte(baseKustomization)},
+				"base/configmap.yaml":         {Data: []byte(malformedConfigMap)},
+				"overlays/kustomization.yaml": {Data: []byte(overlayKustomization)},
+			},
+			Path: "overlays",
+		})
+		g.Expect(buildErr.Kustomization).To(Equal("base"))
+		g.Expect(buildErr.File).To(Equal("base/configmap.yaml"))
+		g.Expect(buildErr.Line).To(Equal(5))
+	})
+}
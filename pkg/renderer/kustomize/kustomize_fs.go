@@ -0,0 +1,58 @@
+package kustomize
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// fsRoot is the directory the content of FS sources is mounted at in the in-memory filesystem.
+const fsRoot = "/"
+
+// memoryFS copies the content of fsys into an in-memory kustomize filesystem, mounted at fsRoot,
+// so kustomization trees embedded with go:embed or generated in memory can be built like
+// kustomizations on disk.
+func memoryFS(fsys fs.FS) (filesys.FileSystem, error) {
+	mem := filesys.MakeFsInMemory()
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(fsRoot, filepath.FromSlash(name))
+
+		if d.IsDir() {
+			return mem.MkdirAll(target)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return mem.WriteFile(target, data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy kustomization filesystem: %w", err)
+	}
+
+	return mem, nil
+}
+
+// sourceFS returns the filesystem and the path of the kustomization of a source: the content of
+// the source FS for FS sources, the engine filesystem otherwise.
+func (e *Engine) sourceFS(input Source) (filesys.FileSystem, string, error) {
+	if input.FS == nil {
+		return e.fs, input.Path, nil
+	}
+
+	mem, err := memoryFS(input.FS)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return mem, filepath.Join(fsRoot, filepath.FromSlash(input.Path)), nil
+}
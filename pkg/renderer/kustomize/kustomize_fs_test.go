@@ -0,0 +1,133 @@
+package kustomize_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+func newKustomizationFS() fstest.MapFS {
+	return fstest.MapFS{
+		"base/kustomization.yaml":     {Data: []byte(baseKustomization)},
+		"base/configmap.yaml":         {Data: []byte(baseConfigMap)},
+		"overlays/kustomization.yaml": {Data: []byte(overlayKustomization)},
+	}
+}
+
+func TestFS(t *testing.T) {
+	t.Run("should render a kustomization from FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			FS:   newKustomizationFS(),
+			Path: "overlays",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("app-config"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("environment", "production"))
+	})
+
+	t.Run("should render the root of FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			FS: fstest.MapFS{
+				"kustomization.yaml": {Data: []byte(basicKustomization)},
+				"configmap.yaml":     {Data: []byte(basicConfigMap)},
+				"pod.yaml":           {Data: []byte(basicPod)},
+			},
+			Path: ".",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should apply values, source fields and annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{
+				FS:         newKustomizationFS(),
+				Path:       "overlays",
+				NamePrefix: "fs-",
+				Values:     kustomize.Values(map[string]string{"key": "value"}),
+			}},
+			kustomize.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("fs-app-config"))
+		g.Expect(objects[0].GetAnnotations()).To(And(
+			HaveKeyWithValue(types.AnnotationSourcePath, "overlays"),
+			HaveKeyWithValue(types.AnnotationSourceFile, "../base/configmap.yaml"),
+		))
+	})
+
+	t.Run("should not share cache entries across filesystems", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{
+				{FS: newKustomizationFS(), Path: "base"},
+				{
+					FS: fstest.MapFS{
+						"base/kustomization.yaml": {Data: []byte(basicKustomization)},
+						"base/configmap.yaml":     {Data: []byte(basicConfigMap)},
+						"base/pod.yaml":           {Data: []byte(basicPod)},
+					},
+					Path: "base",
+				},
+			},
+			kustomize.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+		g.Expect(objects[0].GetName()).To(Equal("app-config"))
+		g.Expect(objects[1].GetName()).To(Equal("test-configmap"))
+	})
+
+	t.Run("should not escape FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			FS: fstest.MapFS{
+				"overlays/kustomization.yaml": {Data: []byte(overlayKustomization)},
+			},
+			Path: "overlays",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should fail on missing path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			FS:   newKustomizationFS(),
+			Path: "missing",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
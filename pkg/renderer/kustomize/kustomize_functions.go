@@ -0,0 +1,251 @@
+package kustomize
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+// builtinPluginAPIVersion is the apiVersion of the configurations of builtin kustomize plugins.
+const builtinPluginAPIVersion = "builtin"
+
+var (
+	// ErrFunctionNotAllowed is returned when a kustomization declares a KRM function or plugin
+	// that is not in the allowlist, or whose functions cannot be checked.
+	ErrFunctionNotAllowed = errors.New("KRM function not allowed")
+)
+
+// FunctionPlugins configures the KRM function plugins run during kustomize builds, declared
+// in the transformers, generators and validators of kustomizations.
+type FunctionPlugins struct {
+	// EnableExec allows exec functions, like kustomize build --enable-exec.
+	EnableExec bool
+
+	// Network enables network access for container functions.
+	Network bool
+
+	// Allowed are the trusted function images and executables. Images listed without a tag or
+	// digest match all of them; relative executables are resolved against the kustomization
+	// declaring them. Empty allows all functions and legacy plugins, like kustomize build
+	// --enable-alpha-plugins.
+	Allowed []string
+}
+
+// pluginConfig returns the kustomize plugin configuration for the function plugins.
+func (f *FunctionPlugins) pluginConfig() *kustomizetypes.PluginConfig {
+	if f == nil {
+		return &kustomizetypes.PluginConfig{}
+	}
+
+	pc := kustomizetypes.MakePluginConfig(kustomizetypes.PluginRestrictionsNone, kustomizetypes.BploUseStaticallyLinked)
+	pc.FnpLoadingOptions.EnableExec = f.EnableExec
+	pc.FnpLoadingOptions.Network = f.Network
+
+	return pc
+}
+
+// functionChecker walks the kustomizations reachable from a root and checks the KRM functions
+// they declare against the allowlist, before kustomize runs any of them.
+type functionChecker struct {
+	fs      filesys.FileSystem
+	allowed []string
+	visited map[string]bool
+}
+
+// checkFunctions checks the functions declared by the kustomization at root and the local
+// kustomizations it references.
+func checkFunctions(fs filesys.FileSystem, root string, allowed []string) error {
+	c := &functionChecker{
+		fs:      fs,
+		allowed: allowed,
+		visited: make(map[string]bool),
+	}
+
+	return c.checkKustomization(root)
+}
+
+func (c *functionChecker) checkKustomization(dir string) error {
+	if c.visited[dir] {
+		return nil
+	}
+
+	c.visited[dir] = true
+
+	kust, _, err := readKustomization(c.fs, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range slices.Concat(kust.Transformers, kust.Generators, kust.Validators) {
+		if err := c.checkConfigs(dir, entry); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range slices.Concat(kust.Resources, kust.Components) {
+		if err := c.checkBase(dir, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBase checks the functions of a resource or component entry of a kustomization.
+func (c *functionChecker) checkBase(dir string, entry string) error {
+	p := filepath.Join(dir, entry)
+
+	switch {
+	case c.fs.IsDir(p):
+		return c.checkKustomization(p)
+	case c.fs.Exists(p):
+		return nil
+	}
+
+	// Let kustomize report missing files
+	if !isRemoteEntry(entry) {
+		return nil
+	}
+
+	// Remote files hold resources only, while remote bases of nested kustomizations are
+	// fetched by kustomize itself and their functions cannot be checked beforehand
+	if isRemoteFile(entry) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: unable to check the functions of remote base %s", ErrFunctionNotAllowed, entry)
+}
+
+// checkConfigs checks the function configurations of a transformers, generators or validators entry,
+// either inline, a file, or a kustomization generating them.
+func (c *functionChecker) checkConfigs(dir string, entry string) error {
+	if strings.Contains(entry, "\n") {
+		return c.checkDocuments(dir, []byte(entry))
+	}
+
+	p := filepath.Join(dir, entry)
+
+	if c.fs.IsDir(p) {
+		kust, _, err := readKustomization(c.fs, p)
+		if err != nil {
+			return err
+		}
+
+		for _, resource := range kust.Resources {
+			if err := c.checkConfigs(p, resource); err != nil {
+				return err
+			}
+		}
+
+		return c.checkKustomization(p)
+	}
+
+	if !c.fs.Exists(p) {
+		if isRemoteEntry(entry) {
+			return fmt.Errorf("%w: unable to check remote configuration %s", ErrFunctionNotAllowed, entry)
+		}
+
+		// Let kustomize report missing files
+		return nil
+	}
+
+	data, err := c.fs.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+
+	return c.checkDocuments(dir, data)
+}
+
+// checkDocuments checks the function configurations in YAML documents.
+func (c *functionChecker) checkDocuments(dir string, data []byte) error {
+	nodes, err := kio.FromBytes(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse plugin configuration: %w", err)
+	}
+
+	for _, node := range nodes {
+		spec, err := runtimeutil.GetFunctionSpec(node)
+		if err != nil {
+			return fmt.Errorf("failed to read function of %s: %w", node.GetName(), err)
+		}
+
+		switch {
+		case spec == nil && node.GetApiVersion() == builtinPluginAPIVersion:
+			continue
+		case spec == nil:
+			return fmt.Errorf("%w: legacy plugin %s/%s", ErrFunctionNotAllowed, node.GetApiVersion(), node.GetKind())
+		case spec.Container.Image != "":
+			if !c.imageAllowed(spec.Container.Image) {
+				return fmt.Errorf("%w: image %s", ErrFunctionNotAllowed, spec.Container.Image)
+			}
+		case spec.Exec.Path != "":
+			if !c.execAllowed(dir, spec.Exec.Path) {
+				return fmt.Errorf("%w: executable %s", ErrFunctionNotAllowed, spec.Exec.Path)
+			}
+		}
+	}
+
+	return nil
+}
+
+// imageAllowed reports whether the image, or its repository when listed without tag or digest, is allowed.
+func (c *functionChecker) imageAllowed(image string) bool {
+	repository, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+
+	return slices.Contains(c.allowed, image) || slices.Contains(c.allowed, repository)
+}
+
+// execAllowed reports whether the executable, as declared or resolved against dir, is allowed.
+func (c *functionChecker) execAllowed(dir string, path string) bool {
+	resolved := path
+	if !filepath.IsAbs(path) {
+		resolved = filepath.Join(dir, path)
+	}
+
+	for _, allowed := range c.allowed {
+		if allowed == path || filepath.Clean(allowed) == resolved {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRemoteFile reports whether a remote entry is a single manifest file fetched over HTTP(S).
+func isRemoteFile(entry string) bool {
+	u, _, _ := strings.Cut(entry, "?")
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		return false
+	}
+
+	if _, isBase := parseRemoteBase(entry); isBase {
+		return false
+	}
+
+	ext := filepath.Ext(u)
+
+	return ext == ".yaml" || ext == ".yml" || ext == ".json"
+}
+
+// isRemoteEntry reports whether a kustomization entry that does not exist locally looks like a
+// remote reference, e.g. an URL or a github.com/org/repo shorthand.
+func isRemoteEntry(entry string) bool {
+	if strings.Contains(entry, "://") || strings.HasPrefix(entry, "git@") {
+		return true
+	}
+
+	host, _, _ := strings.Cut(entry, "/")
+
+	return strings.Contains(host, ".") && host != "." && host != ".."
+}
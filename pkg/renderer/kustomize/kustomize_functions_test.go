@@ -0,0 +1,151 @@
+package kustomize_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
+
+	. "github.com/onsi/gomega"
+)
+
+const functionKustomization = `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+resources:
+- configmap.yaml
+
+transformers:
+- rename.yaml
+`
+
+const renameFunctionConfig = `
+apiVersion: example.com/v1
+kind: Rename
+metadata:
+  name: rename
+  annotations:
+    config.kubernetes.io/function: |
+      exec:
+        path: ./rename.sh
+`
+
+const labelFunctionConfig = `
+apiVersion: example.com/v1
+kind: SetLabels
+metadata:
+  name: labels
+  annotations:
+    config.kubernetes.io/function: |
+      container:
+        image: gcr.io/kpt-fn/set-labels:v0.1
+`
+
+// renameFn is a KRM function that renames the app-config ConfigMap.
+const renameFn = `#!/bin/sh
+sed 's/name: app-config/name: renamed/'
+`
+
+func setupFunctionKustomization(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	writeFile(t, dir, "kustomization.yaml", functionKustomization)
+	writeFile(t, dir, "configmap.yaml", baseConfigMap)
+	writeFile(t, dir, "rename.yaml", renameFunctionConfig)
+	writeFile(t, dir, "rename.sh", renameFn)
+
+	if err := os.Chmod(filepath.Join(dir, "rename.sh"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestFunctionPlugins(t *testing.T) {
+	t.Run("should not run functions by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{Path: setupFunctionKustomization(t)}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should run exec functions when enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setupFunctionKustomization(t)}},
+			kustomize.WithFunctionPlugins(kustomize.FunctionPlugins{EnableExec: true}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("renamed"))
+	})
+
+	t.Run("should run allowed functions", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := setupFunctionKustomization(t)
+
+		for _, allowed := range []string{"./rename.sh", filepath.Join(dir, "rename.sh")} {
+			renderer, err := kustomize.New(
+				[]kustomize.Source{{Path: dir}},
+				kustomize.WithFunctionPlugins(kustomize.FunctionPlugins{
+					EnableExec: true,
+					Allowed:    []string{allowed},
+				}),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects[0].GetName()).To(Equal("renamed"))
+		}
+	})
+
+	t.Run("should reject functions not in the allowlist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setupFunctionKustomization(t)}},
+			kustomize.WithFunctionPlugins(kustomize.FunctionPlugins{
+				EnableExec: true,
+				Allowed:    []string{"gcr.io/kpt-fn/set-labels"},
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kustomize.ErrFunctionNotAllowed))
+	})
+
+	t.Run("should reject functions declared by bases", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := setupFunctionKustomization(t)
+
+		// The overlay references the base, which declares an image not in the allowlist
+		writeFile(t, dir, "base/kustomization.yaml", "resources: []\ntransformers:\n- labels.yaml\n")
+		writeFile(t, dir, "base/labels.yaml", labelFunctionConfig)
+		writeFile(t, dir, "kustomization.yaml", functionKustomization+"- base\n")
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: dir}},
+			kustomize.WithFunctionPlugins(kustomize.FunctionPlugins{
+				EnableExec: true,
+				Allowed:    []string{"./rename.sh", "gcr.io/kpt-fn/set-labels:v0.2"},
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kustomize.ErrFunctionNotAllowed))
+		g.Expect(err).To(MatchError(ContainSubstring("gcr.io/kpt-fn/set-labels:v0.1")))
+	})
+}
@@ -1,6 +1,10 @@
 package kustomize
 
 import (
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/kustomize/api/krusty"
 	"sigs.k8s.io/kustomize/api/resmap"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
 
@@ -31,10 +35,36 @@ type RendererOptions struct {
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
 
+	// ContentHash includes a digest of the kustomization file tree in the cache key.
+	ContentHash bool
+
 	// LoadRestrictions sets renderer-wide default for load restrictions.
 	// Individual Sources can override this via Source.LoadRestrictions.
 	// Default: LoadRestrictionsRootOnly (security best practice).
 	LoadRestrictions kustomizetypes.LoadRestrictions
+
+	// RemoteTimeout bounds each fetch of a remote base. Default: 1 minute.
+	RemoteTimeout time.Duration
+
+	// RemoteAuth are the credentials used to fetch remote bases over HTTP(S).
+	// Nil means remote bases are fetched anonymously.
+	RemoteAuth *RemoteAuth
+
+	// RemoteCacheDir is the directory fetched remote bases are stored in and reused from.
+	// Empty means remote bases are fetched on each render.
+	RemoteCacheDir string
+
+	// RemoteClient is the HTTP client used to download archive remote bases.
+	// Nil means use a default client.
+	RemoteClient *http.Client
+
+	// FunctionPlugins enables KRM function plugins during kustomize builds.
+	// Nil means function plugins are disabled.
+	FunctionPlugins *FunctionPlugins
+
+	// BuildOptions are the krusty options kustomize builds start from.
+	// Nil means the renderer defaults.
+	BuildOptions *krusty.Options
 }
 
 // ApplyTo applies the renderer options to the target configuration.
@@ -49,6 +79,31 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	}
 
 	target.SourceAnnotations = opts.SourceAnnotations
+	target.ContentHash = opts.ContentHash
+
+	if opts.RemoteTimeout > 0 {
+		target.RemoteTimeout = opts.RemoteTimeout
+	}
+
+	if opts.RemoteAuth != nil {
+		target.RemoteAuth = opts.RemoteAuth
+	}
+
+	if opts.RemoteCacheDir != "" {
+		target.RemoteCacheDir = opts.RemoteCacheDir
+	}
+
+	if opts.RemoteClient != nil {
+		target.RemoteClient = opts.RemoteClient
+	}
+
+	if opts.FunctionPlugins != nil {
+		target.FunctionPlugins = opts.FunctionPlugins
+	}
+
+	if opts.BuildOptions != nil {
+		target.BuildOptions = opts.BuildOptions
+	}
 }
 
 // WithFilter adds a renderer-specific filter to this Kustomize renderer's processing chain.
@@ -85,6 +140,17 @@ func WithCache(opts ...cache.Option) RendererOption {
 	})
 }
 
+// WithContentHash enables or disables content-based cache invalidation. When enabled, the files of the
+// kustomization directory and of the local bases, components and files it references are hashed on each
+// render and the digest is part of the cache key, so edits to any of them invalidate cached results
+// before the TTL expires. Remote bases are not hashed. Only effective together with WithCache.
+// Default: false (disabled, the cache keys on path, values and source fields only).
+func WithContentHash(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ContentHash = enabled
+	})
+}
+
 // WithSourceAnnotations enables or disables automatic addition of source tracking annotations.
 // When enabled, the renderer adds metadata annotations to track the source type and path.
 // Annotations added: manifests.k8s-manifests-lib/source.type, source.path.
@@ -106,3 +172,62 @@ func WithLoadRestrictions(restrictions kustomizetypes.LoadRestrictions) Renderer
 		opts.LoadRestrictions = restrictions
 	})
 }
+
+// WithRemoteTimeout bounds each fetch of a remote base (git repository or archive).
+// A timeout query parameter on the remote URL overrides it for that base.
+// Default: 1 minute.
+func WithRemoteTimeout(timeout time.Duration) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RemoteTimeout = timeout
+	})
+}
+
+// WithRemoteAuth sets the basic credentials, e.g. a user name and an access token, used to fetch
+// remote bases over HTTP(S). SSH remotes use the SSH configuration of the environment.
+func WithRemoteAuth(auth RemoteAuth) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RemoteAuth = &auth
+	})
+}
+
+// WithRemoteCacheDir sets the directory where fetched remote bases are stored. Archives and git
+// remotes pinned to a full commit SHA are fetched once and reused across renders and processes
+// sharing the directory, so archive URLs should be immutable. Git remotes pinned to a branch or a
+// tag, or without a ref, are always fetched.
+// Default: remote bases are fetched into a temporary directory on each render.
+func WithRemoteCacheDir(dir string) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RemoteCacheDir = dir
+	})
+}
+
+// WithRemoteHTTPClient sets the HTTP client used to download archive remote bases.
+// Use this to configure TLS settings, proxies, or custom transports.
+func WithRemoteHTTPClient(client *http.Client) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.RemoteClient = client
+	})
+}
+
+// WithFunctionPlugins enables KRM function plugins, container functions and, with EnableExec, exec
+// functions, declared in the transformers, generators and validators of kustomizations. When
+// FunctionPlugins.Allowed is set, builds declaring other functions fail with ErrFunctionNotAllowed
+// before any function runs.
+// Default: function plugins are disabled.
+func WithFunctionPlugins(plugins FunctionPlugins) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.FunctionPlugins = &plugins
+	})
+}
+
+// WithBuildOptions sets the krusty options kustomize builds start from, exposing build knobs the renderer
+// does not surface individually, e.g. Reorder to sort the output like kustomize build --reorder legacy, or
+// AddManagedbyLabel. LoadRestrictions and PluginConfig, when set, take precedence over WithLoadRestrictions
+// and WithFunctionPlugins; Source.LoadRestrictions still overrides LoadRestrictions per source, and the
+// FunctionPlugins allowlist is still checked.
+// Default: kustomize output order, no managed-by label.
+func WithBuildOptions(buildOpts krusty.Options) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.BuildOptions = &buildOpts
+	})
+}
@@ -0,0 +1,410 @@
+package kustomize
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/git"
+)
+
+const (
+	// defaultRemoteTimeout is the default bound of each remote base fetch.
+	defaultRemoteTimeout = 1 * time.Minute
+
+	// gitForcePrefix is the go-getter style prefix forcing an URL to be fetched with git.
+	gitForcePrefix = "git::"
+
+	// subPathSeparator separates the repository or archive URL from the path within it.
+	subPathSeparator = "//"
+)
+
+var (
+	// ErrInvalidRemotePath is returned when the path of a remote base escapes the fetched content.
+	ErrInvalidRemotePath = errors.New("remote path must be relative and within the remote content")
+
+	// ErrUnexpectedStatus is returned when an archive download does not answer with HTTP 200.
+	ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+)
+
+// RemoteAuth holds the basic credentials used to fetch remote bases over HTTP(S),
+// e.g. a user name and an access token.
+type RemoteAuth struct {
+	Username string
+	Password string
+}
+
+// remoteBase is a remote base referenced by a kustomization, either a git repository
+// or an HTTP(S) tar archive.
+type remoteBase struct {
+	// url is the repository or archive URL.
+	url string
+
+	// ref is the git branch, tag or commit. Empty selects the remote HEAD.
+	ref string
+
+	// path is the directory of the base within the repository or archive.
+	path string
+
+	// archive reports whether url is a tar archive rather than a git repository.
+	archive bool
+
+	// timeout overrides the renderer fetch timeout, from the timeout query parameter.
+	timeout time.Duration
+}
+
+// parseRemoteBase parses a kustomization resource entry referencing a remote base, in the
+// kustomize remote URL format, e.g. https://github.com/org/repo//config/default?ref=v1.0.0
+// or https://example.com/bundle.tar.gz//base. It returns false for local paths and for
+// entries whose repository boundary is ambiguous, which are left to kustomize.
+func parseRemoteBase(entry string) (remoteBase, bool) {
+	raw, forceGit := strings.CutPrefix(entry, gitForcePrefix)
+	raw, query, _ := strings.Cut(raw, "?")
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return remoteBase{}, false
+	}
+
+	rb := remoteBase{
+		ref: params.Get("ref"),
+	}
+
+	if rb.ref == "" {
+		rb.ref = params.Get("version")
+	}
+
+	if t := params.Get("timeout"); t != "" {
+		if d, err := time.ParseDuration(t); err == nil {
+			rb.timeout = d
+		} else if s, err := time.ParseDuration(t + "s"); err == nil {
+			rb.timeout = s
+		}
+	}
+
+	scheme, rest, found := strings.Cut(raw, "://")
+
+	switch {
+	case !found && strings.HasPrefix(raw, "git@"):
+		scheme, rest = "", raw
+	case scheme == "ssh" || scheme == "file" || scheme == "http" || scheme == "https":
+		scheme += "://"
+	default:
+		return remoteBase{}, false
+	}
+
+	repo, subPath, separated := strings.Cut(rest, subPathSeparator)
+	if !separated {
+		if before, after, ok := strings.Cut(rest, ".git/"); ok {
+			repo, subPath, separated = before+".git", after, true
+		}
+	}
+
+	rb.url = scheme + repo
+	rb.path = subPath
+	rb.archive = isArchive(repo)
+
+	// Plain HTTP(S) URLs without a repository boundary are file resources or
+	// shorthands kustomize resolves on its own
+	isHTTP := scheme == "http://" || scheme == "https://"
+	if isHTTP && !separated && !forceGit && !rb.archive && !strings.HasSuffix(repo, ".git") {
+		return remoteBase{}, false
+	}
+
+	return rb, true
+}
+
+// isArchive reports whether the URL points to a tar archive.
+func isArchive(u string) bool {
+	return strings.HasSuffix(u, ".tar.gz") || strings.HasSuffix(u, ".tgz") || strings.HasSuffix(u, ".tar")
+}
+
+// cacheName returns the name of the remote base directory in the remote cache.
+func (rb remoteBase) cacheName() string {
+	h := sha256.Sum256([]byte(rb.url + "\x00" + rb.ref))
+
+	return hex.EncodeToString(h[:])[:16]
+}
+
+// resolveRemoteBases fetches the remote bases referenced by the resources and components of the
+// kustomization and replaces them with paths relative to root. It returns whether the kustomization
+// was modified and a function removing the fetched content not stored in the remote cache.
+func (e *Engine) resolveRemoteBases(
+	ctx context.Context,
+	root string,
+	kust *kustomizetypes.Kustomization,
+) (bool, func(), error) {
+	cleanups := make([]func(), 0)
+	cleanup := func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+
+	modified := false
+
+	for _, entries := range [][]string{kust.Resources, kust.Components} {
+		for i, entry := range entries {
+			rb, ok := parseRemoteBase(entry)
+			if !ok {
+				continue
+			}
+
+			dir, remove, err := e.fetchRemoteBase(ctx, rb)
+			if err != nil {
+				cleanup()
+
+				return false, nil, fmt.Errorf("failed to fetch remote base %q: %w", entry, err)
+			}
+
+			cleanups = append(cleanups, remove)
+
+			// Kustomize only accepts relative paths for bases
+			rel, err := filepath.Rel(root, dir)
+			if err != nil {
+				cleanup()
+
+				return false, nil, fmt.Errorf("failed to resolve remote base %q: %w", entry, err)
+			}
+
+			entries[i] = rel
+			modified = true
+		}
+	}
+
+	return modified, cleanup, nil
+}
+
+// fetchRemoteBase fetches a remote base and returns the directory of the base. When the remote
+// cache is enabled, archives and git commits are fetched once and reused; otherwise the content is
+// fetched into a temporary directory removed by the returned function.
+func (e *Engine) fetchRemoteBase(ctx context.Context, rb remoteBase) (string, func(), error) {
+	if rb.path != "" && !filepath.IsLocal(filepath.FromSlash(rb.path)) {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidRemotePath, rb.path)
+	}
+
+	// The ref comes from the kustomization, never let git interpret it as an option
+	if strings.HasPrefix(rb.ref, "-") {
+		return "", nil, fmt.Errorf("%w: %s", git.ErrRefInvalid, rb.ref)
+	}
+
+	noop := func() {}
+
+	if e.opts.RemoteCacheDir != "" && (rb.archive || git.IsCommitSHA(rb.ref)) {
+		target := filepath.Join(e.opts.RemoteCacheDir, rb.cacheName())
+
+		if _, err := os.Stat(target); err != nil {
+			if err := e.fetchIntoCache(ctx, rb, target); err != nil {
+				return "", nil, err
+			}
+		}
+
+		dir, err := e.baseDir(target, rb)
+
+		return dir, noop, err
+	}
+
+	tmp, err := os.MkdirTemp("", "kustomize-remote-")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to create remote base directory: %w", err)
+	}
+
+	remove := func() { _ = os.RemoveAll(tmp) }
+
+	if err := e.fetch(ctx, rb, tmp); err != nil {
+		remove()
+
+		return "", nil, err
+	}
+
+	dir, err := e.baseDir(tmp, rb)
+	if err != nil {
+		remove()
+
+		return "", nil, err
+	}
+
+	return dir, remove, nil
+}
+
+// fetchIntoCache fetches a remote base into a temporary directory of the cache and moves it
+// into place atomically, so concurrent renders sharing the cache never observe partial content.
+func (e *Engine) fetchIntoCache(ctx context.Context, rb remoteBase, target string) error {
+	if err := os.MkdirAll(e.opts.RemoteCacheDir, 0o750); err != nil {
+		return fmt.Errorf("unable to create remote cache directory: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp(e.opts.RemoteCacheDir, ".fetch-")
+	if err != nil {
+		return fmt.Errorf("unable to create remote fetch directory: %w", err)
+	}
+
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := e.fetch(ctx, rb, tmp); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, target); err != nil {
+		// Another render stored the same remote base in the meantime
+		if _, statErr := os.Stat(target); statErr == nil {
+			return nil
+		}
+
+		return fmt.Errorf("unable to store remote base in cache: %w", err)
+	}
+
+	return nil
+}
+
+// baseDir returns the resolved directory of the base within the fetched content.
+func (e *Engine) baseDir(dir string, rb remoteBase) (string, error) {
+	confirmed, _, err := e.fs.CleanedAbs(filepath.Join(dir, filepath.FromSlash(rb.path)))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrInvalidRemotePath, rb.path, err)
+	}
+
+	return confirmed.String(), nil
+}
+
+// fetch downloads the remote base content into dir, honoring the configured timeout.
+func (e *Engine) fetch(ctx context.Context, rb remoteBase, dir string) error {
+	timeout := e.opts.RemoteTimeout
+	if rb.timeout > 0 {
+		timeout = rb.timeout
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if rb.archive {
+		return e.fetchArchive(ctx, rb.url, dir)
+	}
+
+	var err error
+	if e.opts.RemoteAuth != nil {
+		_, err = git.CheckoutWithAuth(ctx, rb.url, rb.ref, dir, e.opts.RemoteAuth.Username, e.opts.RemoteAuth.Password)
+	} else {
+		_, err = git.Checkout(ctx, rb.url, rb.ref, dir)
+	}
+
+	return err
+}
+
+// fetchArchive downloads a tar archive, optionally gzip compressed, and extracts it into dir.
+func (e *Engine) fetchArchive(ctx context.Context, archiveURL string, dir string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if e.opts.RemoteAuth != nil {
+		req.SetBasicAuth(e.opts.RemoteAuth.Username, e.opts.RemoteAuth.Password)
+	}
+
+	client := e.opts.RemoteClient
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var r io.Reader = resp.Body
+
+	if !strings.HasSuffix(archiveURL, ".tar") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+
+		r = gz
+	}
+
+	return untar(r, dir)
+}
+
+// untar extracts the directories and regular files of a tar stream into dir.
+func untar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		name := filepath.FromSlash(strings.TrimPrefix(header.Name, "./"))
+		if name == "" || name == "." {
+			continue
+		}
+
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("%w: %s", ErrInvalidRemotePath, header.Name)
+		}
+
+		target := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o750); err != nil {
+				return fmt.Errorf("failed to create %s: %w", header.Name, err)
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, tr); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+		}
+	}
+}
+
+// writeArchiveFile writes the content of an archive entry to target.
+func writeArchiveFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
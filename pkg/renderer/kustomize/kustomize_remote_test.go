@@ -0,0 +1,334 @@
+package kustomize_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/git"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
+
+	. "github.com/onsi/gomega"
+)
+
+const remoteOverlayKustomization = `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+namePrefix: remote-
+
+resources:
+- %s
+`
+
+// setupRemoteRepository creates a git repository holding the base kustomization under base/,
+// tagged v1, and returns its file URL.
+func setupRemoteRepository(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	dir := t.TempDir()
+
+	writeFile(t, dir, "base/kustomization.yaml", baseKustomization)
+	writeFile(t, dir, "base/configmap.yaml", baseConfigMap)
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "base"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.CommandContext(t.Context(), "git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+
+	return "file://" + dir
+}
+
+// runRemoteGit runs a git command in the repository behind the file URL and returns its output.
+func runRemoteGit(t *testing.T, repository string, args ...string) string {
+	t.Helper()
+
+	dir := strings.TrimPrefix(repository, "file://")
+	args = append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+
+	out, err := exec.CommandContext(t.Context(), "git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// newArchiveServer serves the base kustomization as bundle.tar.gz, under the bundle/base directory,
+// requiring the given basic credentials. It returns the archive URL.
+func newArchiveServer(t *testing.T, username string, password string, requests *atomic.Int32) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range map[string]string{
+		"bundle/base/kustomization.yaml": baseKustomization,
+		"bundle/base/configmap.yaml":     baseConfigMap,
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write(buf.Bytes())
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL + "/bundle.tar.gz"
+}
+
+// setupRemoteOverlay writes an overlay kustomization referencing the given remote base.
+func setupRemoteOverlay(t *testing.T, remote string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "kustomization.yaml", strings.Replace(remoteOverlayKustomization, "%s", remote, 1))
+
+	return dir
+}
+
+func TestRemoteBases(t *testing.T) {
+	t.Run("should render git remote bases", func(t *testing.T) {
+		g := NewWithT(t)
+
+		repository := setupRemoteRepository(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			Path: setupRemoteOverlay(t, repository+"//base?ref=v1"),
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("remote-app-config"))
+	})
+
+	t.Run("should render archive remote bases with credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		archive := newArchiveServer(t, "user", "token", &requests)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setupRemoteOverlay(t, archive+"//bundle/base")}},
+			kustomize.WithRemoteAuth(kustomize.RemoteAuth{Username: "user", Password: "token"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("remote-app-config"))
+	})
+
+	t.Run("should fail on archive remote bases without credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		archive := newArchiveServer(t, "user", "token", &requests)
+
+		renderer, err := kustomize.New([]kustomize.Source{{Path: setupRemoteOverlay(t, archive+"//bundle/base")}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kustomize.ErrUnexpectedStatus))
+	})
+
+	t.Run("should reuse remote bases from the cache directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		archive := newArchiveServer(t, "user", "token", &requests)
+		cacheDir := t.TempDir()
+
+		for range 2 {
+			renderer, err := kustomize.New(
+				[]kustomize.Source{{Path: setupRemoteOverlay(t, archive+"//bundle/base")}},
+				kustomize.WithRemoteAuth(kustomize.RemoteAuth{Username: "user", Password: "token"}),
+				kustomize.WithRemoteCacheDir(cacheDir),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(objects).To(HaveLen(1))
+		}
+
+		g.Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	t.Run("should reuse git remote bases pinned to a commit from the cache directory", func(t *testing.T) {
+		g := NewWithT(t)
+
+		repository := setupRemoteRepository(t)
+		sha := runRemoteGit(t, repository, "rev-parse", "HEAD")
+		cacheDir := t.TempDir()
+
+		render := func(ref string) []string {
+			renderer, err := kustomize.New(
+				[]kustomize.Source{{Path: setupRemoteOverlay(t, repository+"//base?ref="+ref)}},
+				kustomize.WithRemoteCacheDir(cacheDir),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			objects, err := renderer.Process(t.Context(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			names := make([]string, 0, len(objects))
+			for _, obj := range objects {
+				names = append(names, obj.GetName())
+			}
+
+			return names
+		}
+
+		g.Expect(render(sha)).To(ConsistOf("remote-app-config"))
+		g.Expect(render("v1")).To(ConsistOf("remote-app-config"))
+
+		// Move the tag to a commit renaming the ConfigMap
+		renamed := strings.Replace(baseConfigMap, "app-config", "renamed-config", 1)
+		writeFile(t, strings.TrimPrefix(repository, "file://"), "base/configmap.yaml", renamed)
+		runRemoteGit(t, repository, "commit", "--quiet", "-am", "rename")
+		runRemoteGit(t, repository, "tag", "--force", "v1")
+
+		g.Expect(render("v1")).To(ConsistOf("remote-renamed-config"))
+		g.Expect(render(sha)).To(ConsistOf("remote-app-config"))
+
+		entries, err := os.ReadDir(cacheDir)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(entries).To(HaveLen(1))
+	})
+
+	t.Run("should reject option-like refs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		repository := setupRemoteRepository(t)
+		marker := filepath.Join(t.TempDir(), "marker")
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			Path: setupRemoteOverlay(t, repository+"//base?ref=--upload-pack=touch%20"+marker),
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(git.ErrRefInvalid))
+		g.Expect(marker).ToNot(BeAnExistingFile())
+	})
+
+	t.Run("should download archives with the configured HTTP client", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		archive := newArchiveServer(t, "user", "token", &requests)
+
+		var intercepted atomic.Int32
+		client := &http.Client{
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				intercepted.Add(1)
+				r.SetBasicAuth("user", "token")
+
+				return http.DefaultTransport.RoundTrip(r)
+			}),
+		}
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setupRemoteOverlay(t, archive+"//bundle/base")}},
+			kustomize.WithRemoteHTTPClient(client),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(intercepted.Load()).To(BeEquivalentTo(1))
+	})
+
+	t.Run("should reject paths escaping the remote content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		archive := newArchiveServer(t, "user", "token", &requests)
+
+		renderer, err := kustomize.New([]kustomize.Source{{Path: setupRemoteOverlay(t, archive+"//../base")}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(kustomize.ErrInvalidRemotePath))
+		g.Expect(requests.Load()).To(BeZero())
+	})
+
+	t.Run("should fail when the fetch times out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		blocked := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			select {
+			case <-blocked:
+			case <-r.Context().Done():
+			}
+		}))
+		t.Cleanup(server.Close)
+		t.Cleanup(func() { close(blocked) })
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setupRemoteOverlay(t, server.URL+"/bundle.tar.gz//bundle/base")}},
+			kustomize.WithRemoteTimeout(100*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+}
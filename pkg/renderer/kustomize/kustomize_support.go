@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"strings"
 
@@ -49,6 +50,70 @@ func (h *sourceHolder) Validate() error {
 	return nil
 }
 
+// mergeSource merges the kustomization fields of the source into the kustomization at root and
+// reports whether kust was modified. Maps override the kustomization entries, lists are appended to them.
+func mergeSource(kust *kustomizetypes.Kustomization, input Source, root string) bool {
+	modified := false
+
+	if len(input.Patches) > 0 {
+		kust.Patches = append(kust.Patches, input.Patches...)
+		modified = true
+	}
+
+	if len(input.CommonLabels) > 0 {
+		if kust.CommonLabels == nil {
+			kust.CommonLabels = make(map[string]string, len(input.CommonLabels))
+		}
+
+		maps.Copy(kust.CommonLabels, input.CommonLabels)
+		modified = true
+	}
+
+	if len(input.CommonAnnotations) > 0 {
+		if kust.CommonAnnotations == nil {
+			kust.CommonAnnotations = make(map[string]string, len(input.CommonAnnotations))
+		}
+
+		maps.Copy(kust.CommonAnnotations, input.CommonAnnotations)
+		modified = true
+	}
+
+	// Prefixes and suffixes wrap the ones of the kustomization, as an overlay would
+	if input.NamePrefix != "" {
+		kust.NamePrefix = input.NamePrefix + kust.NamePrefix
+		modified = true
+	}
+
+	if input.NameSuffix != "" {
+		kust.NameSuffix += input.NameSuffix
+		modified = true
+	}
+
+	if len(input.ConfigMapGenerator) > 0 {
+		kust.ConfigMapGenerator = append(kust.ConfigMapGenerator, input.ConfigMapGenerator...)
+		modified = true
+	}
+
+	if len(input.SecretGenerator) > 0 {
+		kust.SecretGenerator = append(kust.SecretGenerator, input.SecretGenerator...)
+		modified = true
+	}
+
+	for _, component := range input.Components {
+		// Kustomize only accepts relative paths for components
+		if filepath.IsAbs(component) {
+			if rel, err := filepath.Rel(root, component); err == nil {
+				component = rel
+			}
+		}
+
+		kust.Components = append(kust.Components, component)
+		modified = true
+	}
+
+	return modified
+}
+
 func computeValues(ctx context.Context, input Source, renderTimeValues map[string]any) (map[string]string, error) {
 	sourceValues := map[string]any{}
 
@@ -8,9 +8,12 @@ import (
 
 	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
 	"github.com/rs/xid"
+	"sigs.k8s.io/kustomize/api/krusty"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/kustomize"
@@ -1123,3 +1126,243 @@ func TestLoadRestrictions(t *testing.T) {
 		g.Expect(err.Error()).Should(ContainSubstring("failed to run kustomize"))
 	})
 }
+
+func TestSourceKustomizationFields(t *testing.T) {
+	t.Run("should apply source patches metadata and generators", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := setupBasicKustomization(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			Path: dir,
+			Patches: []kustomizetypes.Patch{
+				{Patch: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: configmap\ndata:\n  key: patched\n"},
+				{
+					Patch:  `[{"op": "replace", "path": "/spec/containers/0/image", "value": "nginx:1.27"}]`,
+					Target: &kustomizetypes.Selector{ResId: resid.NewResIdKindOnly("Pod", "pod")},
+				},
+			},
+			CommonLabels:      map[string]string{"team": "platform"},
+			CommonAnnotations: map[string]string{"owner": "sre"},
+			NamePrefix:        "prod-",
+			NameSuffix:        "-v1",
+			ConfigMapGenerator: []kustomizetypes.ConfigMapArgs{{
+				GeneratorArgs: kustomizetypes.GeneratorArgs{
+					Name:          "generated",
+					KvPairSources: kustomizetypes.KvPairSources{LiteralSources: []string{"mode=fast"}},
+					Options:       &kustomizetypes.GeneratorOptions{DisableNameSuffixHash: true},
+				},
+			}},
+			SecretGenerator: []kustomizetypes.SecretArgs{{
+				GeneratorArgs: kustomizetypes.GeneratorArgs{
+					Name:          "credentials",
+					KvPairSources: kustomizetypes.KvPairSources{LiteralSources: []string{"token=secret"}},
+					Options:       &kustomizetypes.GeneratorOptions{DisableNameSuffixHash: true},
+				},
+			}},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+
+		names := make(map[string]string, len(objects))
+		for _, obj := range objects {
+			names[obj.GetKind()+"/"+obj.GetName()] = obj.GetName()
+
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("team", "platform"))
+			g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("owner", "sre"))
+		}
+
+		g.Expect(names).To(HaveKey("ConfigMap/prod-test-configmap-v1"))
+		g.Expect(names).To(HaveKey("Pod/prod-test-pod-v1"))
+		g.Expect(names).To(HaveKey("ConfigMap/prod-test-generated-v1"))
+		g.Expect(names).To(HaveKey("Secret/prod-test-credentials-v1"))
+
+		for _, obj := range objects {
+			switch obj.GetName() {
+			case "prod-test-configmap-v1":
+				g.Expect(obj).To(jqmatcher.Match(`.data.key == "patched"`))
+			case "prod-test-pod-v1":
+				g.Expect(obj).To(jqmatcher.Match(`.spec.containers[0].image == "nginx:1.27"`))
+			}
+		}
+
+		// The kustomization on disk is left untouched
+		content, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal(basicKustomization))
+	})
+
+	t.Run("should not share cache entries across source fields", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := setupBasicKustomization(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: dir}, {Path: dir, NamePrefix: "prod-"}},
+			kustomize.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+		g.Expect(objects[0].GetName()).To(Equal("test-configmap"))
+		g.Expect(objects[2].GetName()).To(Equal("prod-test-configmap"))
+	})
+}
+
+const labelComponent = `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+commonLabels:
+  feature: enabled
+`
+
+const resourceComponent = `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+- extra.yaml
+`
+
+const extraConfigMap = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra
+`
+
+func TestSourceComponents(t *testing.T) {
+	t.Run("should apply source components", func(t *testing.T) {
+		g := NewWithT(t)
+		dir := setupBasicKustomization(t)
+
+		// A component within the kustomization directory and one outside of it
+		writeFile(t, dir, "components/extra/kustomization.yaml", resourceComponent)
+		writeFile(t, dir, "components/extra/extra.yaml", extraConfigMap)
+
+		labels := t.TempDir()
+		writeFile(t, labels, "kustomization.yaml", labelComponent)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			Path:       dir,
+			Components: []string{"components/extra", labels},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		names := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			names = append(names, obj.GetName())
+
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("feature", "enabled"))
+		}
+
+		g.Expect(names).To(ConsistOf("test-configmap", "test-pod", "test-extra"))
+	})
+
+	t.Run("should fail on missing components", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{
+			Path:       setupBasicKustomization(t),
+			Components: []string{"components/missing"},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestBuildOptions(t *testing.T) {
+	const podFirstKustomization = `
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+resources:
+- pod.yaml
+- configmap.yaml
+`
+
+	setup := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		writeFile(t, dir, "kustomization.yaml", podFirstKustomization)
+		writeFile(t, dir, "configmap.yaml", basicConfigMap)
+		writeFile(t, dir, "pod.yaml", basicPod)
+
+		return dir
+	}
+
+	kinds := func(objects []unstructured.Unstructured) []string {
+		result := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			result = append(result, obj.GetKind())
+		}
+
+		return result
+	}
+
+	t.Run("should keep the kustomization order by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New([]kustomize.Source{{Path: setup(t)}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kinds(objects)).To(Equal([]string{"Pod", "ConfigMap"}))
+	})
+
+	t.Run("should apply reorder and managed-by label", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{Path: setup(t)}},
+			kustomize.WithBuildOptions(krusty.Options{
+				Reorder:           krusty.ReorderOptionLegacy,
+				AddManagedbyLabel: true,
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(kinds(objects)).To(Equal([]string{"ConfigMap", "Pod"}))
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKey("app.kubernetes.io/managed-by"))
+		}
+	})
+
+	t.Run("should keep the source load restrictions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dir := t.TempDir()
+		writeFile(t, dir, "shared/configmap.yaml", basicConfigMap)
+		writeFile(t, dir, "app/kustomization.yaml", "resources:\n- ../shared/configmap.yaml\n")
+
+		renderer, err := kustomize.New(
+			[]kustomize.Source{{
+				Path:             filepath.Join(dir, "app"),
+				LoadRestrictions: kustomizetypes.LoadRestrictionsNone,
+			}},
+			kustomize.WithBuildOptions(krusty.Options{
+				LoadRestrictions: kustomizetypes.LoadRestrictionsRootOnly,
+			}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
@@ -5,8 +5,11 @@ package mem
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
@@ -19,6 +22,11 @@ type Source struct {
 	// Objects contains pre-constructed Kubernetes manifests to pass through.
 	// Useful for testing, composition, or when objects are already in memory.
 	Objects []unstructured.Unstructured
+
+	// TypedObjects contains typed API objects, e.g. *appsv1.Deployment built by controllers,
+	// converted to unstructured when the renderer is created and rendered after Objects.
+	// Objects without apiVersion and kind get them from the renderer scheme, see WithScheme.
+	TypedObjects []runtime.Object
 }
 
 // Renderer handles memory-based rendering operations.
@@ -33,6 +41,7 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	rendererOpts := RendererOptions{
 		Filters:      make([]types.Filter, 0),
 		Transformers: make([]types.Transformer, 0),
+		Scheme:       scheme.Scheme,
 	}
 
 	for _, opt := range opts {
@@ -48,6 +57,13 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 		if err := holders[i].Validate(); err != nil {
 			return nil, err
 		}
+
+		typed, err := convertTyped(rendererOpts.Scheme, inputs[i].TypedObjects)
+		if err != nil {
+			return nil, err
+		}
+
+		holders[i].typed = typed
 	}
 
 	r := &Renderer{
@@ -58,13 +74,18 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	return r, nil
 }
 
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
 // Process implements types.Renderer by returning the objects that were provided during construction.
 // Render-time values are ignored by the memory renderer as objects are already constructed.
 func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
 	// Make deep copies of all objects from all inputs
 	allObjects := make([]unstructured.Unstructured, 0)
 	for _, holder := range r.inputs {
-		for _, obj := range holder.Objects {
+		for _, obj := range slices.Concat(holder.Objects, holder.typed) {
 			objCopy := obj.DeepCopy()
 
 			// Add source annotations if enabled
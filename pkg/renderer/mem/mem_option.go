@@ -1,6 +1,8 @@
 package mem
 
 import (
+	"k8s.io/apimachinery/pkg/runtime"
+
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
 )
@@ -18,6 +20,10 @@ type RendererOptions struct {
 
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
+
+	// Scheme resolves the apiVersion and kind of typed objects.
+	// Default: the client-go scheme with the built-in Kubernetes types.
+	Scheme *runtime.Scheme
 }
 
 // ApplyTo applies the renderer options to the target configuration.
@@ -25,6 +31,10 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	target.Filters = opts.Filters
 	target.Transformers = opts.Transformers
 	target.SourceAnnotations = opts.SourceAnnotations
+
+	if opts.Scheme != nil {
+		target.Scheme = opts.Scheme
+	}
 }
 
 // WithFilter adds a renderer-specific filter to this Mem renderer's processing chain.
@@ -54,3 +64,12 @@ func WithSourceAnnotations(enabled bool) RendererOption {
 		opts.SourceAnnotations = enabled
 	})
 }
+
+// WithScheme sets the scheme resolving the apiVersion and kind of typed objects without them, e.g. a
+// scheme with custom resource types registered.
+// Default: the client-go scheme with the built-in Kubernetes types.
+func WithScheme(s *runtime.Scheme) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Scheme = s
+	})
+}
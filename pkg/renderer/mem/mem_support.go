@@ -3,6 +3,8 @@ package mem
 import (
 	"errors"
 	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 var (
@@ -13,6 +15,9 @@ var (
 // sourceHolder wraps a Source with internal state for consistency with other renderers.
 type sourceHolder struct {
 	Source
+
+	// The TypedObjects converted to unstructured
+	typed []unstructured.Unstructured
 }
 
 // Validate checks if the Source configuration is valid.
@@ -0,0 +1,55 @@
+package mem
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+var (
+	// ErrUnregisteredType is returned when a typed object has no apiVersion and kind and its
+	// type is not registered in the renderer scheme.
+	ErrUnregisteredType = errors.New("object type not registered in scheme")
+)
+
+// convertTyped converts typed API objects to unstructured objects. Objects without apiVersion
+// and kind get them from the scheme, the objects themselves are not modified.
+func convertTyped(scheme *runtime.Scheme, objects []runtime.Object) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+
+	for i, obj := range objects {
+		if obj == nil {
+			return nil, fmt.Errorf("%w at index %d", ErrObjectEmpty, i)
+		}
+
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Kind == "" || gvk.Version == "" {
+			gvks, _, err := scheme.ObjectKinds(obj)
+			if err != nil {
+				return nil, fmt.Errorf("%w at index %d: %w", ErrUnregisteredType, i, err)
+			}
+
+			gvk = gvks[0]
+		}
+
+		u, err := k8s.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert object at index %d: %w", i, err)
+		}
+
+		u.SetGroupVersionKind(gvk)
+
+		// Typed objects built in code have a zero creation timestamp, converted to null
+		if ts, found, _ := unstructured.NestedFieldNoCopy(u.Object, "metadata", "creationTimestamp"); found && ts == nil {
+			unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+		}
+
+		result = append(result, *u)
+	}
+
+	return result, nil
+}
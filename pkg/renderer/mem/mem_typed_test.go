@@ -0,0 +1,127 @@
+package mem_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/mem"
+
+	. "github.com/onsi/gomega"
+)
+
+// widget is a custom resource type not registered in the default scheme.
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Size int `json:"size"`
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	c := *w
+	w.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+
+	return &c
+}
+
+func TestTypedObjects(t *testing.T) {
+	replicas := int32(2)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+
+	t.Run("should convert typed objects with the scheme", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := mem.New([]mem.Source{{
+			Objects: []unstructured.Unstructured{
+				{Object: map[string]any{
+					"apiVersion": "v1",
+					"kind":       "Pod",
+					"metadata":   map[string]any{"name": "pod"},
+				}},
+			},
+			TypedObjects: []runtime.Object{
+				deployment,
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+					ObjectMeta: metav1.ObjectMeta{Name: "config"},
+				},
+			},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+
+		g.Expect(objects[1].GroupVersionKind()).To(Equal(appsv1.SchemeGroupVersion.WithKind("Deployment")))
+		g.Expect(objects[1].GetName()).To(Equal("app"))
+		g.Expect(objects[1].Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", BeNumerically("==", 2))))
+		g.Expect(objects[1].Object).To(HaveKeyWithValue("metadata", Not(HaveKey("creationTimestamp"))))
+
+		g.Expect(objects[2].GetKind()).To(Equal("ConfigMap"))
+
+		// The typed objects are not modified
+		g.Expect(deployment.Kind).To(BeEmpty())
+	})
+
+	t.Run("should fail on types not registered in the scheme", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := mem.New([]mem.Source{{
+			TypedObjects: []runtime.Object{&widget{Size: 3}},
+		}})
+		g.Expect(err).To(MatchError(mem.ErrUnregisteredType))
+	})
+
+	t.Run("should use a custom scheme", func(t *testing.T) {
+		g := NewWithT(t)
+
+		gv := schema.GroupVersion{Group: "example.com", Version: "v1"}
+
+		s := runtime.NewScheme()
+		s.AddKnownTypeWithName(gv.WithKind("Widget"), &widget{})
+
+		renderer, err := mem.New(
+			[]mem.Source{{
+				TypedObjects: []runtime.Object{&widget{
+					ObjectMeta: metav1.ObjectMeta{Name: "widget"},
+					Size:       3,
+				}},
+			}},
+			mem.WithScheme(s),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GroupVersionKind()).To(Equal(gv.WithKind("Widget")))
+		g.Expect(objects[0].Object).To(HaveKeyWithValue("size", BeNumerically("==", 3)))
+	})
+
+	t.Run("should reject nil typed objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := mem.New([]mem.Source{{
+			TypedObjects: []runtime.Object{nil},
+		}})
+		g.Expect(err).To(MatchError(mem.ErrObjectEmpty))
+	})
+}
@@ -0,0 +1,187 @@
+// Package oci provides a renderer that pulls plain manifest bundles stored as OCI artifacts.
+package oci
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+)
+
+const rendererType = "oci"
+
+// Source represents an OCI artifact containing Kubernetes manifests.
+type Source struct {
+	// Reference is the artifact reference including a tag or digest, with an optional oci:// prefix.
+	// Examples: "ghcr.io/org/manifests:v1.0.0", "oci://registry.example.com/app@sha256:...". Required.
+	Reference string
+
+	// Path is an optional glob pattern selecting files inside the artifact, e.g. "deploy/*.yaml".
+	// Empty means all .yaml and .yml files.
+	Path string
+
+	// Auth overrides the renderer-level registry authentication for this source.
+	Auth *registry.Auth
+}
+
+// Renderer handles pulling and rendering OCI manifest bundles.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new OCI Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the OCI renderer as it does not support templates.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering OCI artifact %s: %w", holder.Reference, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to OCI artifact %s: %w",
+				holder.Reference,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// renderSingle pulls a single artifact and decodes the manifests it contains.
+func (r *Renderer) renderSingle(ctx context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	// Compute cache key from reference and path
+	type cacheKeyData struct {
+		Reference string
+		Path      string
+	}
+
+	var cacheKey string
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		cacheKey = dump.ForHash(cacheKeyData{
+			Reference: holder.Reference,
+			Path:      holder.Path,
+		})
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	auth := r.opts.Auth
+	if holder.Auth != nil {
+		auth = *holder.Auth
+	}
+
+	files, err := pull(ctx, holder.Reference, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for _, f := range files {
+		if !holder.Matches(f.name) {
+			continue
+		}
+
+		objects, err := k8s.DecodeYAML(f.content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", f.name, err)
+		}
+
+		// Add source annotations if enabled
+		if r.opts.SourceAnnotations {
+			for i := range objects {
+				annotations := objects[i].GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+
+				annotations[types.AnnotationSourceType] = rendererType
+				annotations[types.AnnotationSourcePath] = holder.Reference
+				annotations[types.AnnotationSourceFile] = f.name
+
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
+		result = append(result, objects...)
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
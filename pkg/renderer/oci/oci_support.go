@@ -0,0 +1,200 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	orasregistry "oras.land/oras-go/v2/registry"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+)
+
+const (
+	referencePrefix = "oci://"
+
+	// maxFileSize bounds the size of a single file extracted from an archive layer.
+	maxFileSize = 64 << 20
+)
+
+var (
+	// ErrReferenceEmpty is returned when a source reference is empty or whitespace-only.
+	ErrReferenceEmpty = errors.New("reference cannot be empty or whitespace-only")
+
+	// ErrReferenceNoVersion is returned when a source reference has neither a tag nor a digest.
+	ErrReferenceNoVersion = errors.New("reference must include a tag or a digest")
+
+	// ErrUnsupportedArtifact is returned when the reference does not resolve to an image manifest.
+	ErrUnsupportedArtifact = errors.New("unsupported artifact media type")
+
+	// ErrFileTooLarge is returned when a file inside an archive layer exceeds the size limit.
+	ErrFileTooLarge = errors.New("file exceeds maximum size")
+)
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(strings.TrimSpace(h.Reference)) == 0 {
+		return ErrReferenceEmpty
+	}
+
+	ref, err := orasregistry.ParseReference(strings.TrimPrefix(h.Reference, referencePrefix))
+	if err != nil {
+		return fmt.Errorf("invalid reference %q: %w", h.Reference, err)
+	}
+
+	if ref.Reference == "" {
+		return fmt.Errorf("%w: %s", ErrReferenceNoVersion, h.Reference)
+	}
+
+	if h.Path != "" {
+		if _, err := path.Match(h.Path, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %q: %w", h.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether a file from the artifact should be rendered.
+func (h *sourceHolder) Matches(name string) bool {
+	ext := path.Ext(name)
+	if ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+
+	if h.Path == "" {
+		return true
+	}
+
+	matched, _ := path.Match(h.Path, name)
+
+	return matched
+}
+
+// file is a named file extracted from an artifact.
+type file struct {
+	name    string
+	content []byte
+}
+
+// pull fetches the artifact manifest and returns the files contained in its layers, in layer order.
+//
+// Archive layers (tar, optionally gzip compressed, as produced by Flux) are expanded; any other
+// layer is returned as a single file named after its org.opencontainers.image.title annotation,
+// as produced by ORAS.
+func pull(ctx context.Context, reference string, auth registry.Auth) ([]file, error) {
+	repo, err := registry.NewRepository(strings.TrimPrefix(reference, referencePrefix), auth)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, data, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArtifact, desc.MediaType)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+
+	result := make([]file, 0)
+
+	for _, layer := range manifest.Layers {
+		blob, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch layer %s: %w", layer.Digest, err)
+		}
+
+		files, err := extract(layer, blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract layer %s: %w", layer.Digest, err)
+		}
+
+		result = append(result, files...)
+	}
+
+	return result, nil
+}
+
+// extract returns the files contained in a layer.
+func extract(layer ocispec.Descriptor, blob []byte) ([]file, error) {
+	mediaType := layer.MediaType
+
+	switch {
+	case strings.HasSuffix(mediaType, "tar+gzip") || strings.HasSuffix(mediaType, "tar.gzip"):
+		gz, err := gzip.NewReader(bytes.NewReader(blob))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+
+		return untar(gz)
+	case strings.HasSuffix(mediaType, ".tar") || strings.HasSuffix(mediaType, "+tar"):
+		return untar(bytes.NewReader(blob))
+	default:
+		name := layer.Annotations[ocispec.AnnotationTitle]
+		if name == "" {
+			name = layer.Digest.Encoded() + ".yaml"
+		}
+
+		return []file{{name: name, content: blob}}, nil
+	}
+}
+
+// untar reads all regular files from a tar stream.
+func untar(r io.Reader) ([]file, error) {
+	result := make([]file, 0)
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		if len(data) > maxFileSize {
+			return nil, fmt.Errorf("%w: %s", ErrFileTooLarge, header.Name)
+		}
+
+		result = append(result, file{
+			name:    path.Clean(strings.TrimPrefix(header.Name, "./")),
+			content: data,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,389 @@
+package oci_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/oci"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+
+	. "github.com/onsi/gomega"
+)
+
+const configMapYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: bundle-config
+data:
+  key: value
+`
+
+const deploymentYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bundle-app
+spec:
+  replicas: 1
+`
+
+const serviceYAML = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: bundle-svc
+`
+
+const fluxContentMediaType = "application/vnd.cncf.flux.content.v1.tar+gzip"
+
+// testRegistry is a minimal read-only OCI distribution server.
+type testRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	username  string
+	password  string
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.username != "" {
+		user, pass, ok := req.BasicAuth()
+		if !ok || user != r.username || pass != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+	}
+
+	switch {
+	case req.URL.Path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case strings.Contains(req.URL.Path, "/manifests/"):
+		ref := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+		data, ok := r.manifests[ref]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+		_, _ = w.Write(data)
+	case strings.Contains(req.URL.Path, "/blobs/"):
+		dgst := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+		data, ok := r.blobs[dgst]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(data)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// push stores an artifact made of the given layers under tag and returns the manifest digest.
+func (r *testRegistry) push(t *testing.T, tag string, layers ...layer) string {
+	t.Helper()
+
+	config := []byte("{}")
+	r.blobs[digest.FromBytes(config).String()] = config
+
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config: ocispec.Descriptor{
+			MediaType: "application/vnd.test.config.v1+json",
+			Digest:    digest.FromBytes(config),
+			Size:      int64(len(config)),
+		},
+	}
+	manifest.SchemaVersion = 2
+
+	for _, l := range layers {
+		r.blobs[digest.FromBytes(l.data).String()] = l.data
+
+		desc := ocispec.Descriptor{
+			MediaType: l.mediaType,
+			Digest:    digest.FromBytes(l.data),
+			Size:      int64(len(l.data)),
+		}
+		if l.title != "" {
+			desc.Annotations = map[string]string{ocispec.AnnotationTitle: l.title}
+		}
+
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dgst := digest.FromBytes(data).String()
+	r.manifests[tag] = data
+	r.manifests[dgst] = data
+
+	return dgst
+}
+
+type layer struct {
+	mediaType string
+	title     string
+	data      []byte
+}
+
+func tarGzip(t *testing.T, files map[string]string, names ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range names {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func setupRegistry(t *testing.T) (*testRegistry, string) {
+	t.Helper()
+
+	reg := &testRegistry{
+		manifests: make(map[string][]byte),
+		blobs:     make(map[string][]byte),
+	}
+
+	server := httptest.NewServer(reg)
+	t.Cleanup(server.Close)
+
+	return reg, strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestRenderer(t *testing.T) {
+	g := NewWithT(t)
+	reg, host := setupRegistry(t)
+
+	fluxLayer := layer{
+		mediaType: fluxContentMediaType,
+		data: tarGzip(t, map[string]string{
+			"./deploy/config.yaml":     configMapYAML,
+			"./deploy/deployment.yaml": deploymentYAML,
+			"./README.md":              "not a manifest",
+		}, "./deploy/config.yaml", "./deploy/deployment.yaml", "./README.md"),
+	}
+
+	orasLayer := layer{
+		mediaType: "application/yaml",
+		title:     "service.yaml",
+		data:      []byte(serviceYAML),
+	}
+
+	reg.push(t, "flux", fluxLayer)
+	reg.push(t, "oras", orasLayer)
+	dgst := reg.push(t, "mixed", fluxLayer, orasLayer)
+
+	auth := registry.Auth{PlainHTTP: true}
+
+	t.Run("should render Flux-style tarball", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: "oci://" + host + "/bundle:flux"}},
+			oci.WithRegistryAuth(auth),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("bundle-config"))
+		g.Expect(objects[1].GetName()).To(Equal("bundle-app"))
+	})
+
+	t.Run("should render ORAS-pushed files", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:oras"}},
+			oci.WithRegistryAuth(auth),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Service"))
+	})
+
+	t.Run("should resolve digest references", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle@" + dgst}},
+			oci.WithRegistryAuth(auth),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+	})
+
+	t.Run("should select files by path", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:mixed", Path: "deploy/deployment.yaml"}},
+			oci.WithRegistryAuth(auth),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Deployment"))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:oras"}},
+			oci.WithRegistryAuth(auth),
+			oci.WithTransformer(labels.Set(map[string]string{"source": "oci"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("source", "oci"))
+	})
+
+	t.Run("should fail on missing tag", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:missing"}},
+			oci.WithRegistryAuth(auth),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func TestRegistryAuth(t *testing.T) {
+	g := NewWithT(t)
+	reg, host := setupRegistry(t)
+
+	reg.username = "user"
+	reg.password = "secret"
+	reg.push(t, "v1", layer{mediaType: "application/yaml", title: "service.yaml", data: []byte(serviceYAML)})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:v1"}},
+			oci.WithRegistryAuth(registry.Auth{PlainHTTP: true}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should use renderer credentials", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{Reference: host + "/bundle:v1"}},
+			oci.WithRegistryAuth(registry.Auth{PlainHTTP: true, Username: "user", Password: "secret"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+
+	t.Run("should prefer source credentials", func(t *testing.T) {
+		renderer, err := oci.New(
+			[]oci.Source{{
+				Reference: host + "/bundle:v1",
+				Auth:      &registry.Auth{PlainHTTP: true, Username: "user", Password: "secret"},
+			}},
+			oci.WithRegistryAuth(registry.Auth{PlainHTTP: true, Username: "user", Password: "wrong"}),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should reject empty reference", func(t *testing.T) {
+		_, err := oci.New([]oci.Source{{Reference: " "}})
+		g.Expect(err).To(MatchError(oci.ErrReferenceEmpty))
+	})
+
+	t.Run("should reject reference without tag or digest", func(t *testing.T) {
+		_, err := oci.New([]oci.Source{{Reference: "oci://registry.example.com/bundle"}})
+		g.Expect(err).To(MatchError(oci.ErrReferenceNoVersion))
+	})
+
+	t.Run("should reject invalid path pattern", func(t *testing.T) {
+		_, err := oci.New([]oci.Source{{Reference: "registry.example.com/bundle:v1", Path: "["}})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		renderer, err := oci.New([]oci.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("oci"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+	reg, host := setupRegistry(t)
+
+	reg.push(t, "v1", layer{mediaType: "application/yaml", title: "service.yaml", data: []byte(serviceYAML)})
+
+	reference := host + "/bundle:v1"
+
+	renderer, err := oci.New(
+		[]oci.Source{{Reference: reference}},
+		oci.WithRegistryAuth(registry.Auth{PlainHTTP: true}),
+		oci.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(1))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "oci"))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, reference))
+	g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "service.yaml"))
+}
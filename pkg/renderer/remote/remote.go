@@ -0,0 +1,232 @@
+// Package remote provides a renderer that fetches Kubernetes manifests from HTTP(S) URLs.
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const rendererType = "remote"
+
+// Source represents a remote manifest to fetch and render.
+type Source struct {
+	// URL is the HTTP or HTTPS location of a (multi-document) YAML manifest. Required.
+	URL string
+
+	// Headers are additional HTTP headers sent with the request (e.g. Authorization).
+	Headers map[string]string
+
+	// Username and Password enable HTTP basic authentication when Username is set.
+	Username string
+	Password string
+
+	// Checksum is the expected digest of the downloaded content, in the form
+	// "<algorithm>:<hex>" (sha256 or sha512). A bare hex value is treated as sha256.
+	// Optional; when set, content that does not match is rejected.
+	Checksum string
+}
+
+// Renderer handles fetching and rendering remote manifests.
+// It implements types.Renderer.
+type Renderer struct {
+	inputs []*sourceHolder
+	client *http.Client
+	opts   RendererOptions
+}
+
+// New creates a new remote Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+		Timeout:      defaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	client := rendererOpts.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		client: client,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the remote renderer as it does not support templates.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering remote manifest %s: %w", holder.URL, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to remote manifest %s: %w",
+				holder.URL,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// renderSingle fetches and decodes a single remote manifest.
+func (r *Renderer) renderSingle(ctx context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	// Compute cache key from URL, expected checksum and credentials, so content fetched with
+	// different credentials is never shared
+	type cacheKeyData struct {
+		URL         string
+		Checksum    string
+		Credentials string
+	}
+
+	var cacheKey string
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		cacheKey = dump.ForHash(cacheKeyData{
+			URL:         holder.URL,
+			Checksum:    holder.Checksum,
+			Credentials: holder.credentialsDigest(),
+		})
+
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	content, err := r.fetch(ctx, holder)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := holder.VerifyChecksum(content); err != nil {
+		return nil, err
+	}
+
+	result, err := k8s.DecodeYAML(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode YAML: %w", err)
+	}
+
+	// Add source annotations if enabled
+	if r.opts.SourceAnnotations {
+		for i := range result {
+			annotations := result[i].GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+
+			annotations[types.AnnotationSourceType] = rendererType
+			annotations[types.AnnotationSourcePath] = holder.URL
+
+			result[i].SetAnnotations(annotations)
+		}
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// fetch downloads the content of a remote source honoring the configured timeout.
+func (r *Renderer) fetch(ctx context.Context, holder *sourceHolder) ([]byte, error) {
+	if r.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.opts.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, holder.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range holder.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if holder.Username != "" {
+		req.SetBasicAuth(holder.Username, holder.Password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,127 @@
+package remote
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/dump"
+)
+
+const (
+	// defaultTimeout bounds a single download when no timeout is configured.
+	defaultTimeout = 30 * time.Second
+
+	algorithmSHA256 = "sha256"
+	algorithmSHA512 = "sha512"
+)
+
+var (
+	// ErrURLEmpty is returned when a source URL is empty or whitespace-only.
+	ErrURLEmpty = errors.New("url cannot be empty or whitespace-only")
+
+	// ErrUnsupportedScheme is returned when a source URL is not http or https.
+	ErrUnsupportedScheme = errors.New("url scheme must be http or https")
+
+	// ErrUnsupportedChecksum is returned when a checksum uses an unknown algorithm.
+	ErrUnsupportedChecksum = errors.New("unsupported checksum algorithm")
+
+	// ErrInvalidChecksum is returned when a checksum digest is empty or not a hex digest of the algorithm.
+	ErrInvalidChecksum = errors.New("invalid checksum digest")
+
+	// ErrChecksumMismatch is returned when downloaded content does not match the expected checksum.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrUnexpectedStatus is returned when the server responds with a non-200 status code.
+	ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+)
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if len(strings.TrimSpace(h.URL)) == 0 {
+		return ErrURLEmpty
+	}
+
+	u, err := url.Parse(h.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", h.URL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %s", ErrUnsupportedScheme, h.URL)
+	}
+
+	if h.Checksum != "" {
+		if _, _, err := parseChecksum(h.Checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// credentialsDigest returns a digest of the headers and basic credentials sent with the request,
+// keeping the secrets they may hold out of the cache keys.
+func (h *sourceHolder) credentialsDigest() string {
+	sum := sha256.Sum256([]byte(dump.ForHash([]any{h.Headers, h.Username, h.Password})))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChecksum checks the content against the expected checksum, if any.
+func (h *sourceHolder) VerifyChecksum(content []byte) error {
+	if h.Checksum == "" {
+		return nil
+	}
+
+	hasher, expected, err := parseChecksum(h.Checksum)
+	if err != nil {
+		return err
+	}
+
+	hasher.Write(content)
+	actual := hex.EncodeToString(hasher.Sum(nil))
+
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: expected %s, got %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
+
+// parseChecksum splits a checksum into its hash implementation and hex digest.
+func parseChecksum(checksum string) (hash.Hash, string, error) {
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	if !found {
+		algorithm, digest = algorithmSHA256, checksum
+	}
+
+	var hasher hash.Hash
+
+	switch strings.ToLower(algorithm) {
+	case algorithmSHA256:
+		hasher = sha256.New()
+	case algorithmSHA512:
+		hasher = sha512.New()
+	default:
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedChecksum, algorithm)
+	}
+
+	// An empty or truncated digest would never be verified as expected
+	if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != hasher.Size() {
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidChecksum, checksum)
+	}
+
+	return hasher, digest, nil
+}
@@ -0,0 +1,306 @@
+package remote_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/filter/meta/gvk"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/remote"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const installManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: install-config
+data:
+  key: value
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: install-pod
+spec:
+  containers:
+  - name: app
+    image: nginx
+`
+
+const sha256Zeros = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+func newServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func serveManifest(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(installManifest))
+}
+
+func checksumOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestRenderer(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should fetch and decode remote manifest", func(t *testing.T) {
+		server := newServer(t, serveManifest)
+
+		renderer, err := remote.New([]remote.Source{{URL: server.URL + "/install.yaml"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("install-config"))
+		g.Expect(objects[1].GetName()).To(Equal("install-pod"))
+	})
+
+	t.Run("should send headers and basic auth", func(t *testing.T) {
+		server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "secret" || r.Header.Get("X-Custom") != "custom" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			serveManifest(w, r)
+		})
+
+		renderer, err := remote.New([]remote.Source{{
+			URL:      server.URL,
+			Headers:  map[string]string{"X-Custom": "custom"},
+			Username: "user",
+			Password: "secret",
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should fail on non-200 status", func(t *testing.T) {
+		server := newServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		renderer, err := remote.New([]remote.Source{{URL: server.URL}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(remote.ErrUnexpectedStatus))
+	})
+
+	t.Run("should verify checksum", func(t *testing.T) {
+		server := newServer(t, serveManifest)
+
+		renderer, err := remote.New([]remote.Source{{
+			URL:      server.URL,
+			Checksum: checksumOf(installManifest),
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should reject checksum mismatch", func(t *testing.T) {
+		server := newServer(t, serveManifest)
+
+		renderer, err := remote.New([]remote.Source{{
+			URL:      server.URL,
+			Checksum: sha256Zeros,
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(remote.ErrChecksumMismatch))
+	})
+
+	t.Run("should honor timeout", func(t *testing.T) {
+		server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+
+			serveManifest(w, r)
+		})
+
+		renderer, err := remote.New(
+			[]remote.Source{{URL: server.URL}},
+			remote.WithTimeout(50*time.Millisecond),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should apply filters and transformers", func(t *testing.T) {
+		server := newServer(t, serveManifest)
+
+		renderer, err := remote.New(
+			[]remote.Source{{URL: server.URL}},
+			remote.WithFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("Pod"))),
+			remote.WithTransformer(labels.Set(map[string]string{"source": "remote"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("Pod"))
+		g.Expect(objects[0].GetLabels()).To(HaveKeyWithValue("source", "remote"))
+	})
+}
+
+func TestNew(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should reject empty URL", func(t *testing.T) {
+		_, err := remote.New([]remote.Source{{URL: "  "}})
+		g.Expect(err).To(MatchError(remote.ErrURLEmpty))
+	})
+
+	t.Run("should reject unsupported scheme", func(t *testing.T) {
+		_, err := remote.New([]remote.Source{{URL: "file:///tmp/install.yaml"}})
+		g.Expect(err).To(MatchError(remote.ErrUnsupportedScheme))
+	})
+
+	t.Run("should reject unsupported checksum algorithm", func(t *testing.T) {
+		_, err := remote.New([]remote.Source{{URL: "https://example.com/install.yaml", Checksum: "md5:abc"}})
+		g.Expect(err).To(MatchError(remote.ErrUnsupportedChecksum))
+	})
+
+	t.Run("should reject empty or malformed checksum digests", func(t *testing.T) {
+		for _, checksum := range []string{"sha256:", "sha512:abc", "sha256:not-hex"} {
+			_, err := remote.New([]remote.Source{{URL: "https://example.com/install.yaml", Checksum: checksum}})
+			g.Expect(err).To(MatchError(remote.ErrInvalidChecksum))
+		}
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		renderer, err := remote.New([]remote.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("remote"))
+	})
+}
+
+func TestCacheIntegration(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Run("should avoid refetching when cache is enabled", func(t *testing.T) {
+		var hits atomic.Int32
+
+		server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			serveManifest(w, r)
+		})
+
+		renderer, err := remote.New(
+			[]remote.Source{{URL: server.URL}},
+			remote.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		first, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		second, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(second).To(Equal(first))
+		g.Expect(hits.Load()).To(Equal(int32(1)))
+	})
+
+	t.Run("should not share cache entries across credentials", func(t *testing.T) {
+		var hits atomic.Int32
+
+		server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			serveManifest(w, r)
+		})
+
+		renderer, err := remote.New(
+			[]remote.Source{
+				{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}},
+				{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer wrong"}},
+			},
+			remote.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).To(MatchError(remote.ErrUnexpectedStatus))
+		g.Expect(hits.Load()).To(Equal(int32(2)))
+	})
+
+	t.Run("should refetch when cache is disabled", func(t *testing.T) {
+		var hits atomic.Int32
+
+		server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			serveManifest(w, r)
+		})
+
+		renderer, err := remote.New([]remote.Source{{URL: server.URL}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(hits.Load()).To(Equal(int32(2)))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newServer(t, serveManifest)
+
+	renderer, err := remote.New(
+		[]remote.Source{{URL: server.URL + "/install.yaml"}},
+		remote.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, obj := range objects {
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "remote"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, server.URL+"/install.yaml"))
+	}
+}
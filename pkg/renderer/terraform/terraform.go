@@ -0,0 +1,226 @@
+// Package terraform provides a renderer for Kubernetes manifests exposed as Terraform/OpenTofu outputs.
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const rendererType = "terraform"
+
+// Source represents Terraform/OpenTofu outputs holding Kubernetes manifests.
+type Source struct {
+	// FS is the filesystem containing the outputs file.
+	FS fs.FS
+
+	// Path is the file holding the outputs, either the result of `terraform output -json`
+	// or a state file (terraform.tfstate). Required.
+	Path string
+
+	// Outputs restricts rendering to the named outputs. Optional; if empty all outputs are
+	// considered and the ones not holding manifests are skipped.
+	Outputs []string
+}
+
+// Renderer handles Terraform output rendering operations.
+// It implements types.Renderer.
+//
+// An output value may be a manifest object, a list of manifest objects or a string holding
+// (multi-document) YAML or JSON manifests, e.g. the result of yamlencode or templatefile.
+type Renderer struct {
+	inputs []*sourceHolder
+	opts   RendererOptions
+}
+
+// New creates a new Terraform Renderer with the given inputs and options.
+func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
+	rendererOpts := RendererOptions{
+		Filters:      make([]types.Filter, 0),
+		Transformers: make([]types.Transformer, 0),
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&rendererOpts)
+	}
+
+	// Wrap sources in holders and validate
+	holders := make([]*sourceHolder, len(inputs))
+	for i := range inputs {
+		holders[i] = &sourceHolder{
+			Source: inputs[i],
+		}
+		if err := holders[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Renderer{
+		inputs: holders,
+		opts:   rendererOpts,
+	}
+
+	return r, nil
+}
+
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
+// Process executes the rendering logic for all configured inputs.
+// Render-time values are ignored by the Terraform renderer as outputs are already evaluated.
+func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+	allObjects := make([]unstructured.Unstructured, 0)
+
+	for _, holder := range r.inputs {
+		objects, err := r.renderSingle(ctx, holder)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering terraform outputs %s: %w", holder.Path, err)
+		}
+
+		// Apply renderer-level filters and transformers per-source for better error context
+		transformed, err := pipeline.Apply(ctx, objects, r.opts.Filters, r.opts.Transformers)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error applying filters/transformers to terraform outputs %s: %w",
+				holder.Path,
+				err,
+			)
+		}
+
+		allObjects = append(allObjects, transformed...)
+	}
+
+	return allObjects, nil
+}
+
+// Name returns the renderer type identifier.
+func (r *Renderer) Name() string {
+	return rendererType
+}
+
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the state files of the sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.Path})
+	}
+
+	return sources
+}
+
+// renderSingle performs the rendering for a single outputs file.
+func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
+	type cacheKeyData struct {
+		Path    string
+		Outputs []string
+	}
+
+	cacheKey := dump.ForHash(cacheKeyData{
+		Path:    holder.Path,
+		Outputs: holder.Outputs,
+	})
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	outputs, err := readOutputs(holder.FS, holder.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := holder.Outputs
+	if len(names) == 0 {
+		names = make([]string, 0, len(outputs))
+		for name := range outputs {
+			names = append(names, name)
+		}
+
+		// Process outputs in a stable order
+		slices.Sort(names)
+	}
+
+	result := make([]unstructured.Unstructured, 0)
+
+	for _, name := range names {
+		out, ok := outputs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrOutputNotFound, name)
+		}
+
+		objects, err := decodeOutput(out)
+		if err != nil {
+			// Outputs that were not explicitly requested may hold arbitrary strings
+			if len(holder.Outputs) == 0 {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to decode output %s: %w", name, err)
+		}
+
+		// Add source annotations if enabled
+		if r.opts.SourceAnnotations {
+			for i := range objects {
+				annotations := objects[i].GetAnnotations()
+				if annotations == nil {
+					annotations = make(map[string]string)
+				}
+
+				annotations[types.AnnotationSourceType] = rendererType
+				annotations[types.AnnotationSourcePath] = holder.Path
+				annotations[types.AnnotationSourceFile] = name
+
+				objects[i].SetAnnotations(annotations)
+			}
+		}
+
+		result = append(result, objects...)
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, result)
+	}
+
+	return result, nil
+}
+
+// decodeOutput extracts the manifests held by an output value.
+func decodeOutput(out output) ([]unstructured.Unstructured, error) {
+	content := []byte(out.Value)
+
+	// String outputs hold encoded manifests, e.g. the result of yamlencode
+	var encoded string
+	if err := json.Unmarshal(out.Value, &encoded); err == nil {
+		content = []byte(encoded)
+	}
+
+	// JSON is valid YAML, DecodeYAML handles objects, arrays and multi-document strings
+	return k8s.DecodeYAML(content)
+}
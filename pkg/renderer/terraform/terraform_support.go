@@ -0,0 +1,92 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+)
+
+var (
+	// ErrOutputNotFound is returned when a requested output is not defined.
+	ErrOutputNotFound = errors.New("output not found")
+
+	// ErrInvalidOutputs is returned when a file is neither `terraform output -json` output nor a state file.
+	ErrInvalidOutputs = errors.New("invalid terraform outputs")
+)
+
+// output is a single entry of `terraform output -json` or of the outputs section of a state file.
+type output struct {
+	Sensitive bool            `json:"sensitive"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// sourceHolder wraps a Source with internal state for consistency with other renderers.
+type sourceHolder struct {
+	Source
+}
+
+// Validate checks if the Source configuration is valid.
+func (h *sourceHolder) Validate() error {
+	if h.FS == nil {
+		return utilerrors.ErrFsRequired
+	}
+	if len(strings.TrimSpace(h.Path)) == 0 {
+		return utilerrors.ErrPathEmpty
+	}
+
+	return nil
+}
+
+// ReadOutputs reads a `terraform output -json` file or a state file and returns the output values by name.
+// The result can be used as values for templating renderers, e.g. as gotemplate or helm Source values.
+func ReadOutputs(fsys fs.FS, path string) (map[string]any, error) {
+	outputs, err := readOutputs(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(outputs))
+
+	for name, out := range outputs {
+		var value any
+		if err := json.Unmarshal(out.Value, &value); err != nil {
+			return nil, fmt.Errorf("failed to decode output %s: %w", name, err)
+		}
+
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// readOutputs decodes the outputs of either a `terraform output -json` file or a state file.
+func readOutputs(fsys fs.FS, path string) (map[string]output, error) {
+	content, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(content, &top); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidOutputs, path, err)
+	}
+
+	// A state file has a numeric top level version and nests the outputs
+	data := content
+
+	var version int
+	if raw, ok := top["outputs"]; ok && json.Unmarshal(top["version"], &version) == nil {
+		data = raw
+	}
+
+	var outputs map[string]output
+	if err := json.Unmarshal(data, &outputs); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidOutputs, path, err)
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,227 @@
+package terraform_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/terraform"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
+
+	. "github.com/onsi/gomega"
+)
+
+const outputsJSON = `{
+  "cluster_endpoint": {
+    "sensitive": false,
+    "type": "string",
+    "value": "https://10.0.0.1:6443"
+  },
+  "config_map": {
+    "sensitive": false,
+    "type": ["object", {}],
+    "value": {
+      "apiVersion": "v1",
+      "kind": "ConfigMap",
+      "metadata": {"name": "infra"},
+      "data": {"endpoint": "https://10.0.0.1:6443"}
+    }
+  },
+  "manifests": {
+    "sensitive": false,
+    "type": "string",
+    "value": "apiVersion: v1\nkind: Service\nmetadata:\n  name: db\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: db-credentials\n"
+  },
+  "invalid": {
+    "sensitive": false,
+    "type": "string",
+    "value": "key: value: other"
+  }
+}`
+
+const stateJSON = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "outputs": {
+    "namespaces": {
+      "type": ["tuple", []],
+      "value": [
+        {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "team-a"}},
+        {"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "team-b"}}
+      ]
+    }
+  },
+  "resources": []
+}`
+
+func TestRenderer(t *testing.T) {
+	ctx := t.Context()
+
+	testFS := fstest.MapFS{
+		"outputs.json":      &fstest.MapFile{Data: []byte(outputsJSON)},
+		"terraform.tfstate": &fstest.MapFile{Data: []byte(stateJSON)},
+		"broken.json":       &fstest.MapFile{Data: []byte("[1, 2]")},
+	}
+
+	t.Run("should render manifests from all outputs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{FS: testFS, Path: "outputs.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(3))
+
+		// outputs are processed in lexical order
+		g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(objects[1].GetKind()).To(Equal("Service"))
+		g.Expect(objects[2].GetKind()).To(Equal("Secret"))
+	})
+
+	t.Run("should render selected outputs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{
+			FS:      testFS,
+			Path:    "outputs.json",
+			Outputs: []string{"manifests"},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("db"))
+	})
+
+	t.Run("should render outputs from a state file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{FS: testFS, Path: "terraform.tfstate"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetName()).To(Equal("team-a"))
+		g.Expect(objects[1].GetName()).To(Equal("team-b"))
+	})
+
+	t.Run("should fail on missing output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{
+			FS:      testFS,
+			Path:    "outputs.json",
+			Outputs: []string{"missing"},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(terraform.ErrOutputNotFound))
+	})
+
+	t.Run("should fail on invalid selected output", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{
+			FS:      testFS,
+			Path:    "outputs.json",
+			Outputs: []string{"invalid"},
+		}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should fail on invalid outputs file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{{FS: testFS, Path: "broken.json"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(terraform.ErrInvalidOutputs))
+	})
+
+	t.Run("should apply transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New(
+			[]terraform.Source{{FS: testFS, Path: "terraform.tfstate"}},
+			terraform.WithTransformer(labels.Set(map[string]string{"source": "terraform"})),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		for _, obj := range objects {
+			g.Expect(obj.GetLabels()).To(HaveKeyWithValue("source", "terraform"))
+		}
+	})
+}
+
+func TestReadOutputs(t *testing.T) {
+	g := NewWithT(t)
+
+	testFS := fstest.MapFS{
+		"outputs.json": &fstest.MapFile{Data: []byte(outputsJSON)},
+	}
+
+	values, err := terraform.ReadOutputs(testFS, "outputs.json")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(HaveKeyWithValue("cluster_endpoint", "https://10.0.0.1:6443"))
+	g.Expect(values).To(HaveKeyWithValue("config_map", HaveKeyWithValue("kind", "ConfigMap")))
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should reject missing FS", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := terraform.New([]terraform.Source{{Path: "outputs.json"}})
+		g.Expect(err).To(MatchError(utilerrors.ErrFsRequired))
+	})
+
+	t.Run("should reject empty path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := terraform.New([]terraform.Source{{FS: fstest.MapFS{}}})
+		g.Expect(err).To(MatchError(utilerrors.ErrPathEmpty))
+	})
+
+	t.Run("should return renderer name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := terraform.New([]terraform.Source{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.Name()).To(Equal("terraform"))
+	})
+}
+
+func TestSourceAnnotations(t *testing.T) {
+	g := NewWithT(t)
+
+	testFS := fstest.MapFS{
+		"terraform.tfstate": &fstest.MapFile{Data: []byte(stateJSON)},
+	}
+
+	renderer, err := terraform.New(
+		[]terraform.Source{{FS: testFS, Path: "terraform.tfstate"}},
+		terraform.WithSourceAnnotations(true),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	objects, err := renderer.Process(t.Context(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(objects).To(HaveLen(2))
+
+	for _, obj := range objects {
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "terraform"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourcePath, "terraform.tfstate"))
+		g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "namespaces"))
+	}
+}
@@ -2,16 +2,20 @@ package yaml
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"path/filepath"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/pipeline"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cache"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
 )
 
@@ -34,6 +38,24 @@ type Source struct {
 	// Path specifies the glob pattern to match YAML files.
 	// Only .yaml and .yml files are processed. Examples: "manifests/*.yaml", "**/*.yml"
 	Path string
+
+	// Recursive enables descending into directories matched by Path, loading all
+	// .yaml and .yml files found in the tree. Files are processed in lexical order
+	// and source annotations keep their path relative to FS. Default: false.
+	Recursive bool
+
+	// Reader provides YAML content from a stream, such as os.Stdin.
+	// It is consumed on the first render and its content is reused afterwards.
+	// Mutually exclusive with FS/Path and Data.
+	Reader io.Reader
+
+	// Data provides raw YAML content held in memory.
+	// Mutually exclusive with FS/Path and Reader.
+	Data []byte
+
+	// Values provides the variables substituted in the YAML content when substitution is enabled
+	// with WithSubstitution. Render-time values take precedence. Optional.
+	Values func(context.Context) (map[string]any, error)
 }
 
 // Renderer handles YAML file rendering operations.
@@ -59,6 +81,7 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	for i := range inputs {
 		holders[i] = &sourceHolder{
 			Source: inputs[i],
+			mu:     &sync.Mutex{},
 		}
 		if err := holders[i].Validate(); err != nil {
 			return nil, err
@@ -73,15 +96,28 @@ func New(inputs []Source, opts ...RendererOption) (*Renderer, error) {
 	return r, nil
 }
 
+// NewRenderer implements types.Source by creating a Renderer with default options for the source.
+func (s Source) NewRenderer() (types.Renderer, error) {
+	return New([]Source{s})
+}
+
 // Process executes the rendering logic for all configured inputs.
-// Render-time values are ignored by the YAML renderer as it does not support templates.
-func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructured.Unstructured, error) {
+// Render-time values are only used by variable substitution, see WithSubstitution.
+func (r *Renderer) Process(ctx context.Context, renderTimeValues map[string]any) ([]unstructured.Unstructured, error) {
 	allObjects := make([]unstructured.Unstructured, 0)
 
 	for _, holder := range r.inputs {
-		objects, err := r.renderSingle(ctx, holder)
+		objects, err := r.renderSingle(ctx, holder, renderTimeValues)
 		if err != nil {
-			return nil, fmt.Errorf("error rendering YAML pattern %s: %w", holder.Path, err)
+			return nil, fmt.Errorf("error rendering YAML pattern %s: %w", holder.name(), err)
+		}
+
+		// Unwrap List objects (if enabled)
+		if r.opts.ExpandLists {
+			objects, err = k8s.ExpandLists(objects)
+			if err != nil {
+				return nil, fmt.Errorf("error expanding lists in YAML pattern %s: %w", holder.name(), err)
+			}
 		}
 
 		// Apply renderer-level filters and transformers per-source for better error context
@@ -89,7 +125,7 @@ func (r *Renderer) Process(ctx context.Context, _ map[string]any) ([]unstructure
 		if err != nil {
 			return nil, fmt.Errorf(
 				"error applying filters/transformers to YAML pattern %s: %w",
-				holder.Path,
+				holder.name(),
 				err,
 			)
 		}
@@ -105,10 +141,58 @@ func (r *Renderer) Name() string {
 	return rendererType
 }
 
+// Invalidate implements types.Invalidator by discarding the cached render results, if caching is enabled.
+func (r *Renderer) Invalidate() {
+	if r.opts.Cache != nil {
+		cache.Clear(r.opts.Cache)
+	}
+}
+
+// WatchSources implements types.Watchable by returning the files matched by the FS sources.
+func (r *Renderer) WatchSources() []types.WatchSource {
+	sources := make([]types.WatchSource, 0, len(r.inputs))
+
+	for _, holder := range r.inputs {
+		if holder.inMemory() {
+			continue
+		}
+
+		sources = append(sources, types.WatchSource{FS: holder.FS, Pattern: holder.Path})
+	}
+
+	return sources
+}
+
 // renderSingle performs the rendering for a single YAML input.
-func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unstructured.Unstructured, error) {
-	// Use path as cache key
+func (r *Renderer) renderSingle(
+	ctx context.Context,
+	holder *sourceHolder,
+	renderTimeValues map[string]any,
+) ([]unstructured.Unstructured, error) {
+	var vars map[string]string
+
+	if r.opts.Substitution != nil {
+		v, err := variables(ctx, holder, renderTimeValues)
+		if err != nil {
+			return nil, err
+		}
+
+		vars = v
+	}
+
+	if holder.inMemory() {
+		return r.renderContent(ctx, holder, vars)
+	}
+
+	// Use path as cache key, recursive sources are keyed separately
 	cacheKey := holder.Path
+	if holder.Recursive {
+		cacheKey = "recursive:" + holder.Path
+	}
+
+	if r.opts.Substitution != nil {
+		cacheKey += ":" + variablesKey(vars)
+	}
 
 	// Check cache (if enabled)
 	if r.opts.Cache != nil {
@@ -132,9 +216,16 @@ func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unst
 		return nil, fmt.Errorf("%w: %s", ErrNoFilesMatched, holder.Path)
 	}
 
+	if holder.Recursive {
+		matches, err = expandDirectories(holder.FS, matches)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Process each matched file
 	for _, match := range matches {
-		fileObjects, err := r.loadYAMLFile(holder.FS, match)
+		fileObjects, err := r.loadYAMLFile(holder.FS, match, vars)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load %s: %w", match, err)
 		}
@@ -150,8 +241,64 @@ func (r *Renderer) renderSingle(_ context.Context, holder *sourceHolder) ([]unst
 	return result, nil
 }
 
+// renderContent performs the rendering for a Reader or Data based input.
+func (r *Renderer) renderContent(
+	_ context.Context,
+	holder *sourceHolder,
+	vars map[string]string,
+) ([]unstructured.Unstructured, error) {
+	content, err := holder.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Use content hash as cache key
+	sum := sha256.Sum256(content)
+	cacheKey := hex.EncodeToString(sum[:])
+
+	if r.opts.Substitution != nil {
+		cacheKey += ":" + variablesKey(vars)
+	}
+
+	// Check cache (if enabled)
+	if r.opts.Cache != nil {
+		// ensure objects are evicted
+		r.opts.Cache.Sync()
+
+		if cached, found := r.opts.Cache.Get(cacheKey); found {
+			return cached, nil
+		}
+	}
+
+	objects, err := r.decode(holder.name(), content, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add source annotations if enabled
+	if r.opts.SourceAnnotations {
+		for i := range objects {
+			annotations := objects[i].GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+
+			annotations[types.AnnotationSourceType] = rendererType
+
+			objects[i].SetAnnotations(annotations)
+		}
+	}
+
+	// Cache result (if enabled)
+	if r.opts.Cache != nil {
+		r.opts.Cache.Set(cacheKey, objects)
+	}
+
+	return objects, nil
+}
+
 // loadYAMLFile loads and parses a single YAML file.
-func (r *Renderer) loadYAMLFile(fsys fs.FS, path string) ([]unstructured.Unstructured, error) {
+func (r *Renderer) loadYAMLFile(fsys fs.FS, path string, vars map[string]string) ([]unstructured.Unstructured, error) {
 	// Check if path is a directory
 	info, err := fs.Stat(fsys, path)
 	if err != nil {
@@ -183,9 +330,9 @@ func (r *Renderer) loadYAMLFile(fsys fs.FS, path string) ([]unstructured.Unstruc
 	}
 
 	// Decode YAML content
-	objects, err := k8s.DecodeYAML(content)
+	objects, err := r.decode(path, content, vars)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		return nil, err
 	}
 
 	// Add source annotations if enabled
@@ -205,3 +352,29 @@ func (r *Renderer) loadYAMLFile(fsys fs.FS, path string) ([]unstructured.Unstruc
 
 	return objects, nil
 }
+
+// decode substitutes the variables of the content, if enabled, and decodes it into objects,
+// validating the documents first when strict decoding is enabled.
+func (r *Renderer) decode(name string, content []byte, vars map[string]string) ([]unstructured.Unstructured, error) {
+	if r.opts.Substitution != nil {
+		substituted, err := r.opts.Substitution.apply(content, vars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to substitute variables in %s: %w", name, err)
+		}
+
+		content = substituted
+	}
+
+	if r.opts.StrictDecoding {
+		if err := validateStrict(name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := k8s.DecodeYAML(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode YAML: %w", err)
+	}
+
+	return objects, nil
+}
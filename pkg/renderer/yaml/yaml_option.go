@@ -24,6 +24,16 @@ type RendererOptions struct {
 
 	// SourceAnnotations enables automatic addition of source tracking annotations.
 	SourceAnnotations bool
+
+	// ExpandLists enables unwrapping of List objects into their items.
+	ExpandLists bool
+
+	// StrictDecoding rejects malformed or non-Kubernetes documents instead of skipping them.
+	StrictDecoding bool
+
+	// Substitution enables variable substitution in YAML content.
+	// Nil means content is decoded as is.
+	Substitution *Substitution
 }
 
 // ApplyTo applies the renderer options to the target configuration.
@@ -36,6 +46,12 @@ func (opts RendererOptions) ApplyTo(target *RendererOptions) {
 	}
 
 	target.SourceAnnotations = opts.SourceAnnotations
+	target.ExpandLists = opts.ExpandLists
+	target.StrictDecoding = opts.StrictDecoding
+
+	if opts.Substitution != nil {
+		target.Substitution = opts.Substitution
+	}
 }
 
 // WithFilter adds a renderer-specific filter to this YAML renderer's processing chain.
@@ -74,3 +90,35 @@ func WithSourceAnnotations(enabled bool) RendererOption {
 		opts.SourceAnnotations = enabled
 	})
 }
+
+// WithExpandLists enables or disables unwrapping of List objects (kind List or any *List kind)
+// into their individual items. When enabled, the items are expanded before renderer-level
+// filters and transformers are applied, so they operate on the contained objects.
+// Default: false (disabled).
+func WithExpandLists(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.ExpandLists = enabled
+	})
+}
+
+// WithStrictDecoding enables or disables strict decoding. When enabled, documents with duplicate keys,
+// !!binary values, or that are not Kubernetes objects (missing apiVersion or kind) fail the rendering
+// with a *DecodeError holding the file and document index, instead of being skipped. Empty documents
+// are still allowed.
+// Default: false (disabled).
+func WithStrictDecoding(enabled bool) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.StrictDecoding = enabled
+	})
+}
+
+// WithSubstitution enables the substitution of ${VAR}, ${VAR:-default} and $(VAR) references in YAML
+// content before decoding, like envsubst, with variables from Source.Values merged with the render-time
+// values and, if Substitution.Env is set, from the environment. Nested values are referenced with dotted
+// names, e.g. ${image.tag}. Unresolved references are kept unless Substitution.Strict is set.
+// Default: substitution is disabled.
+func WithSubstitution(substitution Substitution) RendererOption {
+	return util.FunctionalOption[RendererOptions](func(opts *RendererOptions) {
+		opts.Substitution = &substitution
+	})
+}
@@ -0,0 +1,134 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	goyaml "gopkg.in/yaml.v3"
+)
+
+// binaryTag is the YAML tag of base64 encoded binary values.
+const binaryTag = "!!binary"
+
+var (
+	// ErrBinaryContent is returned by strict decoding for documents holding !!binary values.
+	ErrBinaryContent = errors.New("binary content is not allowed")
+
+	// ErrNotKubernetesObject is returned by strict decoding for documents, or items of sequence
+	// documents, that are not objects with apiVersion and kind.
+	ErrNotKubernetesObject = errors.New("document is not a Kubernetes object")
+)
+
+// DecodeError is returned by strict decoding when a document of a source is rejected.
+type DecodeError struct {
+	// File is the path of the file within FS, or the source name for Reader and Data sources.
+	File string
+
+	// Document is the zero based index of the document within the file.
+	Document int
+
+	// Err is the cause, e.g. ErrNotKubernetesObject or a YAML syntax or duplicate key error.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("invalid YAML document[%d] in %s: %v", e.Document, e.File, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// validateStrict checks that every document of the content is a Kubernetes object, or a sequence
+// of them, without duplicate keys or binary values. Empty documents are allowed.
+func validateStrict(name string, content []byte) error {
+	decoder := goyaml.NewDecoder(bytes.NewReader(content))
+
+	for index := 0; ; index++ {
+		var doc goyaml.Node
+
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err == nil {
+			err = validateDocument(&doc)
+		}
+
+		if err != nil {
+			return &DecodeError{
+				File:     name,
+				Document: index,
+				Err:      err,
+			}
+		}
+	}
+}
+
+// validateDocument checks a single document.
+func validateDocument(doc *goyaml.Node) error {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	if err := checkBinary(doc); err != nil {
+		return err
+	}
+
+	// Decoding reports duplicate keys
+	var out any
+	if err := doc.Decode(&out); err != nil {
+		return err
+	}
+
+	switch v := out.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return checkObject(v)
+	case []any:
+		for i, item := range v {
+			m, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: item %d is not a mapping", ErrNotKubernetesObject, i)
+			}
+
+			if err := checkObject(m); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: not a mapping", ErrNotKubernetesObject)
+	}
+}
+
+// checkObject checks that a decoded document has a non-empty apiVersion and kind.
+func checkObject(obj map[string]any) error {
+	for _, field := range []string{"apiVersion", "kind"} {
+		if v, ok := obj[field].(string); !ok || v == "" {
+			return fmt.Errorf("%w: missing %s", ErrNotKubernetesObject, field)
+		}
+	}
+
+	return nil
+}
+
+// checkBinary reports nodes tagged as binary, at any depth.
+func checkBinary(node *goyaml.Node) error {
+	if node.Tag == binaryTag {
+		return fmt.Errorf("%w: line %d", ErrBinaryContent, node.Line)
+	}
+
+	for _, child := range node.Content {
+		if err := checkBinary(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package yaml_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
+
+	. "github.com/onsi/gomega"
+)
+
+const duplicateKeyYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-config
+  name: duplicate
+`
+
+const binaryYAML = `
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test-secret
+data:
+  key: !!binary aGVsbG8=
+`
+
+const notKubernetesYAML = `
+name: not-an-object
+values:
+  key: value
+`
+
+const configMapListYAML = `
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- name: second
+`
+
+func TestStrictDecoding(t *testing.T) {
+	decodeError := func(g *WithT, err error) *yaml.DecodeError {
+		g.Expect(err).To(HaveOccurred())
+
+		var decodeErr *yaml.DecodeError
+		g.Expect(errors.As(err, &decodeErr)).To(BeTrue())
+
+		return decodeErr
+	}
+
+	tests := []struct {
+		name     string
+		content  string
+		document int
+		cause    error
+	}{
+		{
+			name:     "should reject duplicate keys",
+			content:  podYAML + "---\n" + duplicateKeyYAML,
+			document: 1,
+		},
+		{
+			name:     "should reject binary content",
+			content:  binaryYAML,
+			document: 0,
+			cause:    yaml.ErrBinaryContent,
+		},
+		{
+			name:     "should reject non-Kubernetes documents",
+			content:  podYAML + "---\n" + notKubernetesYAML,
+			document: 1,
+			cause:    yaml.ErrNotKubernetesObject,
+		},
+		{
+			name:     "should reject non-Kubernetes items of sequences",
+			content:  configMapListYAML,
+			document: 0,
+			cause:    yaml.ErrNotKubernetesObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			renderer, err := yaml.New(
+				[]yaml.Source{{
+					FS: fstest.MapFS{
+						"manifests/objects.yaml": &fstest.MapFile{Data: []byte(tt.content)},
+					},
+					Path: "manifests/*.yaml",
+				}},
+				yaml.WithStrictDecoding(true),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = renderer.Process(t.Context(), nil)
+
+			decodeErr := decodeError(g, err)
+			g.Expect(decodeErr.File).To(Equal("manifests/objects.yaml"))
+			g.Expect(decodeErr.Document).To(Equal(tt.document))
+
+			if tt.cause != nil {
+				g.Expect(decodeErr).To(MatchError(tt.cause))
+			}
+		})
+	}
+
+	t.Run("should report the source name of in-memory sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Data: []byte(notKubernetesYAML)}},
+			yaml.WithStrictDecoding(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(t.Context(), nil)
+
+		decodeErr := decodeError(g, err)
+		g.Expect(decodeErr.File).To(Equal("<data>"))
+		g.Expect(decodeErr.Document).To(Equal(0))
+	})
+
+	t.Run("should accept Kubernetes objects and empty documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Data: []byte("---\n# comment only\n---\n" + podYAML + "---\n" + configMapYAML)}},
+			yaml.WithStrictDecoding(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should skip non-Kubernetes documents when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{Data: []byte(podYAML + "---\n" + notKubernetesYAML)}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+	})
+}
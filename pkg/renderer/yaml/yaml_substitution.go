@@ -0,0 +1,147 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/dump"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+var (
+	// ErrUnresolvedVariable is returned by strict substitution for variables without a value.
+	ErrUnresolvedVariable = errors.New("unresolved variable")
+
+	// variablePattern matches ${VAR}, ${VAR:-default} and $(VAR) references, optionally escaped
+	// with a leading $. Names may contain dots to reference nested values, e.g. ${image.tag}.
+	variablePattern = regexp.MustCompile(
+		`\$(\$?)(?:\{([A-Za-z_][A-Za-z0-9_.]*)(?::-([^}]*))?\}|\(([A-Za-z_][A-Za-z0-9_.]*)\))`,
+	)
+)
+
+// Substitution configures the variable substitution pass run on YAML content before decoding,
+// similar to envsubst. Variables are written as ${VAR}, ${VAR:-default} or $(VAR) and resolved
+// from the source values merged with the render-time values; $${VAR} and $$(VAR) are escapes
+// rendering the reference literally.
+type Substitution struct {
+	// Env resolves variables without a value from the process environment.
+	Env bool
+
+	// Strict fails the rendering on variables without a value or default, instead of leaving
+	// the reference unchanged.
+	Strict bool
+}
+
+// Values returns a Values function that always returns the provided static values.
+// This is a convenience helper for the common case of non-dynamic values.
+func Values(values map[string]any) func(context.Context) (map[string]any, error) {
+	return func(_ context.Context) (map[string]any, error) {
+		return values, nil
+	}
+}
+
+// variables returns the substitution variables of a source: its values deep merged with the
+// render-time values, flattened into dotted names.
+func variables(
+	ctx context.Context,
+	holder *sourceHolder,
+	renderTimeValues map[string]any,
+) (map[string]string, error) {
+	values := map[string]any{}
+
+	if holder.Values != nil {
+		v, err := holder.Values(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get values: %w", err)
+		}
+
+		if v != nil {
+			values = v
+		}
+	}
+
+	result := make(map[string]string)
+	flatten("", util.DeepMerge(values, renderTimeValues), result)
+
+	return result, nil
+}
+
+// flatten adds the scalar values of a nested map to result, keyed by their dotted path.
+func flatten(prefix string, values map[string]any, result map[string]string) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch value := v.(type) {
+		case map[string]any:
+			flatten(key, value, result)
+		case nil:
+			result[key] = ""
+		default:
+			result[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+// variablesKey returns the part of the cache key identifying the substitution variables.
+func variablesKey(vars map[string]string) string {
+	return dump.ForHash(vars)
+}
+
+// apply substitutes the variable references in content.
+func (s *Substitution) apply(content []byte, vars map[string]string) ([]byte, error) {
+	var unresolved []string
+
+	result := variablePattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		groups := variablePattern.FindSubmatch(match)
+
+		// Escaped references lose the escape and are kept literally
+		if len(groups[1]) > 0 {
+			return match[1:]
+		}
+
+		name := string(groups[2])
+		if name == "" {
+			name = string(groups[4])
+		}
+
+		if value, ok := s.lookup(name, vars); ok {
+			return []byte(value)
+		}
+
+		// Unmatched groups are nil, while an empty default is an empty slice
+		if groups[3] != nil {
+			return groups[3]
+		}
+
+		unresolved = append(unresolved, name)
+
+		return match
+	})
+
+	if s.Strict && len(unresolved) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnresolvedVariable, strings.Join(unresolved, ", "))
+	}
+
+	return result, nil
+}
+
+// lookup resolves a variable from the values, then from the environment if enabled.
+func (s *Substitution) lookup(name string, vars map[string]string) (string, bool) {
+	if value, ok := vars[name]; ok {
+		return value, true
+	}
+
+	if s.Env {
+		return os.LookupEnv(name)
+	}
+
+	return "", false
+}
@@ -0,0 +1,141 @@
+package yaml_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
+
+	. "github.com/onsi/gomega"
+)
+
+const substitutionYAML = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ${name}
+  namespace: $(namespace)
+data:
+  image: "${image.repository}:${image.tag}"
+  replicas: "${replicas:-1}"
+  empty: "${unset:-}"
+  escaped: "$${name}"
+  env: "${YAML_SUBSTITUTION_TEST}"
+  missing: "${missing}"
+`
+
+func TestSubstitution(t *testing.T) {
+	values := map[string]any{
+		"name":      "app-config",
+		"namespace": "default",
+		"image": map[string]any{
+			"repository": "nginx",
+			"tag":        "1.25",
+		},
+	}
+
+	render := func(t *testing.T, renderTimeValues map[string]any, opts ...yaml.RendererOption) (map[string]any, error) {
+		t.Helper()
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{
+				Data:   []byte(substitutionYAML),
+				Values: yaml.Values(values),
+			}},
+			opts...,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		objects, err := renderer.Process(t.Context(), renderTimeValues)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(objects) != 1 {
+			return nil, nil
+		}
+
+		return objects[0].Object, nil
+	}
+
+	t.Run("should substitute variables", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("YAML_SUBSTITUTION_TEST", "from-env")
+
+		obj, err := render(t, nil, yaml.WithSubstitution(yaml.Substitution{}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).To(HaveKeyWithValue("metadata", And(
+			HaveKeyWithValue("name", "app-config"),
+			HaveKeyWithValue("namespace", "default"),
+		)))
+		g.Expect(obj).To(HaveKeyWithValue("data", Equal(map[string]any{
+			"image":    "nginx:1.25",
+			"replicas": "1",
+			"empty":    "",
+			"escaped":  "${name}",
+			"env":      "${YAML_SUBSTITUTION_TEST}",
+			"missing":  "${missing}",
+		})))
+	})
+
+	t.Run("should prefer render-time values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := render(t, map[string]any{
+			"replicas": 3,
+			"image":    map[string]any{"tag": "1.26"},
+		}, yaml.WithSubstitution(yaml.Substitution{}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).To(HaveKeyWithValue("data", And(
+			HaveKeyWithValue("image", "nginx:1.26"),
+			HaveKeyWithValue("replicas", "3"),
+		)))
+	})
+
+	t.Run("should resolve variables from the environment", func(t *testing.T) {
+		g := NewWithT(t)
+		t.Setenv("YAML_SUBSTITUTION_TEST", "from-env")
+
+		obj, err := render(t, nil, yaml.WithSubstitution(yaml.Substitution{Env: true}))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).To(HaveKeyWithValue("data", HaveKeyWithValue("env", "from-env")))
+	})
+
+	t.Run("should fail on unresolved variables when strict", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := render(t, nil, yaml.WithSubstitution(yaml.Substitution{Strict: true}))
+		g.Expect(err).To(MatchError(yaml.ErrUnresolvedVariable))
+		g.Expect(err).To(MatchError(ContainSubstring("YAML_SUBSTITUTION_TEST, missing")))
+	})
+
+	t.Run("should not substitute when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := render(t, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).To(HaveKeyWithValue("metadata", HaveKeyWithValue("name", "${name}")))
+	})
+
+	t.Run("should key the cache on the variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Data: []byte(substitutionYAML), Values: yaml.Values(values)}},
+			yaml.WithSubstitution(yaml.Substitution{}),
+			yaml.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		first, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(first).To(HaveLen(1))
+		g.Expect(first[0].GetName()).To(Equal("app-config"))
+
+		second, err := renderer.Process(t.Context(), map[string]any{"name": "other-config"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(second).To(HaveLen(1))
+		g.Expect(second[0].GetName()).To(Equal("other-config"))
+	})
+}
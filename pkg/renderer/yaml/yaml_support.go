@@ -1,18 +1,59 @@
 package yaml
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
 )
 
-// sourceHolder wraps a Source with internal state for consistency with other renderers.
+const (
+	// readerSourceName identifies Reader based sources in error messages.
+	readerSourceName = "<reader>"
+
+	// dataSourceName identifies Data based sources in error messages.
+	dataSourceName = "<data>"
+)
+
+var (
+	// ErrConflictingSources is returned when more than one of FS/Path, Reader or Data is set.
+	ErrConflictingSources = errors.New("exactly one of FS/Path, Reader or Data must be set")
+)
+
+// sourceHolder wraps a Source with internal state for lazy loading and thread-safety.
 type sourceHolder struct {
 	Source
+
+	// Mutex protects concurrent access to the buffered reader content
+	mu *sync.Mutex
+
+	// The content read from Reader (protected by mu)
+	content []byte
+	read    bool
 }
 
 // Validate checks if the Source configuration is valid.
 func (h *sourceHolder) Validate() error {
+	inMemory := 0
+	if h.Reader != nil {
+		inMemory++
+	}
+	if h.Data != nil {
+		inMemory++
+	}
+
+	if inMemory > 1 || (inMemory == 1 && (h.FS != nil || len(strings.TrimSpace(h.Path)) != 0)) {
+		return ErrConflictingSources
+	}
+	if inMemory == 1 {
+		return nil
+	}
+
 	if h.FS == nil {
 		return utilerrors.ErrFsRequired
 	}
@@ -22,3 +63,83 @@ func (h *sourceHolder) Validate() error {
 
 	return nil
 }
+
+// inMemory reports whether the source provides its content through Reader or Data.
+func (h *sourceHolder) inMemory() bool {
+	return h.Reader != nil || h.Data != nil
+}
+
+// name returns a human readable identifier of the source for error messages.
+func (h *sourceHolder) name() string {
+	switch {
+	case h.Reader != nil:
+		return readerSourceName
+	case h.Data != nil:
+		return dataSourceName
+	default:
+		return h.Path
+	}
+}
+
+// bytes returns the in-memory content of the source.
+// The Reader is consumed on first use and its content is retained for subsequent renders.
+func (h *sourceHolder) bytes() ([]byte, error) {
+	if h.Data != nil {
+		return h.Data, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.read {
+		content, err := io.ReadAll(h.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from reader: %w", err)
+		}
+
+		h.content = content
+		h.read = true
+	}
+
+	return h.content, nil
+}
+
+// expandDirectories replaces the directories in matches with the YAML files they contain, recursively.
+func expandDirectories(fsys fs.FS, matches []string) ([]string, error) {
+	result := make([]string, 0, len(matches))
+
+	for _, match := range matches {
+		info, err := fs.Stat(fsys, match)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", match, err)
+		}
+
+		if !info.IsDir() {
+			result = append(result, match)
+
+			continue
+		}
+
+		err = fs.WalkDir(fsys, match, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			switch filepath.Ext(path) {
+			case ".yaml", ".yml":
+				result = append(result, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", match, err)
+		}
+	}
+
+	return result, nil
+}
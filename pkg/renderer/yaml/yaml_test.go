@@ -1,6 +1,7 @@
 package yaml_test
 
 import (
+	"strings"
 	"testing"
 	"testing/fstest"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/renderer/yaml"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/labels"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilerrors "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/errors"
 
 	. "github.com/onsi/gomega"
 )
@@ -216,6 +218,198 @@ func TestRenderer(t *testing.T) {
 	})
 }
 
+func TestExpandLists(t *testing.T) {
+	ctx := t.Context()
+
+	listYAML := `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config-1
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: pod-1
+`
+
+	t.Run("should keep lists by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{Data: []byte(listYAML)}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetKind()).To(Equal("List"))
+	})
+
+	t.Run("should expand lists before filters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Data: []byte(listYAML)}},
+			yaml.WithExpandLists(true),
+			yaml.WithFilter(gvk.Filter(corev1.SchemeGroupVersion.WithKind("ConfigMap"))),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("config-1"))
+	})
+}
+
+func TestRecursive(t *testing.T) {
+	ctx := t.Context()
+
+	testFS := fstest.MapFS{
+		"manifests/pod.yaml":              &fstest.MapFile{Data: []byte(podYAML)},
+		"manifests/config/configmap.yaml": &fstest.MapFile{Data: []byte(configMapYAML)},
+		"manifests/config/deep/multi.yml": &fstest.MapFile{Data: []byte(multiDocYAML)},
+		"manifests/config/README.md":      &fstest.MapFile{Data: []byte("# docs")},
+	}
+
+	t.Run("should load a whole tree", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{FS: testFS, Path: "manifests", Recursive: true}},
+			yaml.WithSourceAnnotations(true),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "manifests/config/configmap.yaml"))
+		g.Expect(objects[1].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "manifests/config/deep/multi.yml"))
+		g.Expect(objects[3].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceFile, "manifests/pod.yaml"))
+	})
+
+	t.Run("should descend into matched directories only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests/*", Recursive: true}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(4))
+	})
+
+	t.Run("should reject directories when not recursive", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{{FS: testFS, Path: "manifests"}})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = renderer.Process(ctx, nil)
+		g.Expect(err).To(MatchError(yaml.ErrPathIsDirectory))
+	})
+}
+
+func TestInMemorySources(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should load YAML from reader", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New([]yaml.Source{
+			{Reader: strings.NewReader(multiDocYAML)},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+		g.Expect(objects[0].GetKind()).To(Equal("Service"))
+		g.Expect(objects[1].GetKind()).To(Equal("Secret"))
+
+		// The reader is consumed once, subsequent renders reuse its content
+		objects, err = renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should load YAML from data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer, err := yaml.New(
+			[]yaml.Source{{Data: []byte(podYAML)}},
+			yaml.WithSourceAnnotations(true),
+			yaml.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(1))
+		g.Expect(objects[0].GetName()).To(Equal("test-pod"))
+		g.Expect(objects[0].GetAnnotations()).To(HaveKeyWithValue(types.AnnotationSourceType, "yaml"))
+	})
+
+	t.Run("should mix file and in-memory sources", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+			{Data: []byte(configMapYAML)},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		objects, err := renderer.Process(ctx, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+	})
+
+	t.Run("should reject conflicting sources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{
+			{Data: []byte(podYAML), Reader: strings.NewReader(podYAML)},
+		})
+		g.Expect(err).To(MatchError(yaml.ErrConflictingSources))
+
+		_, err = yaml.New([]yaml.Source{
+			{FS: fstest.MapFS{}, Path: "*.yaml", Data: []byte(podYAML)},
+		})
+		g.Expect(err).To(MatchError(yaml.ErrConflictingSources))
+	})
+
+	t.Run("should require a source", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := yaml.New([]yaml.Source{{}})
+		g.Expect(err).To(MatchError(utilerrors.ErrFsRequired))
+	})
+}
+
+func TestWatchSources(t *testing.T) {
+	t.Run("should return the FS sources only", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fsys := fstest.MapFS{"pod.yaml": {Data: []byte(podYAML)}}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: fsys, Path: "*.yaml"},
+			{Data: []byte(multiDocYAML)},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var watchable types.Watchable = renderer
+		g.Expect(watchable.WatchSources()).To(Equal([]types.WatchSource{{FS: fsys, Pattern: "*.yaml"}}))
+	})
+}
+
 func TestCacheIntegration(t *testing.T) {
 
 	t.Run("should cache identical renders", func(t *testing.T) {
@@ -338,6 +532,38 @@ func TestCacheIntegration(t *testing.T) {
 			g.Expect(result2[0].GetName()).ToNot(Equal("modified-name"))
 		}
 	})
+
+	t.Run("should render changed sources after invalidation", func(t *testing.T) {
+		g := NewWithT(t)
+		testFS := fstest.MapFS{
+			"pod.yaml": &fstest.MapFile{Data: []byte(podYAML)},
+		}
+
+		renderer, err := yaml.New([]yaml.Source{
+			{FS: testFS, Path: "*.yaml"},
+		},
+			yaml.WithCache(),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result1, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result1).To(HaveLen(1))
+
+		testFS["configmap.yaml"] = &fstest.MapFile{Data: []byte(configMapYAML)}
+
+		// Cache hit - the new file is not seen
+		result2, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result2).To(HaveLen(1))
+
+		var invalidator types.Invalidator = renderer
+		invalidator.Invalidate()
+
+		result3, err := renderer.Process(t.Context(), nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result3).To(HaveLen(2))
+	})
 }
 
 func BenchmarkYamlRenderWithoutCache(b *testing.B) {
@@ -0,0 +1,102 @@
+// Package apiversion provides a transformer rewriting deprecated or removed apiVersions to their
+// successors, to keep manifests written for older clusters deployable on newer ones.
+package apiversion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// ErrMigrationLoop is returned when migrations lead back to an already visited GroupVersionKind.
+var ErrMigrationLoop = errors.New("migration loop")
+
+// Migration rewrites objects of a GroupVersionKind to a successor.
+type Migration struct {
+	// From is the deprecated GroupVersionKind.
+	From schema.GroupVersionKind
+
+	// To is the successor GroupVersionKind.
+	To schema.GroupVersionKind
+
+	// Convert adapts the content of the object to the successor schema, if it changed. It is
+	// called after the apiVersion and kind are updated.
+	Convert func(obj *unstructured.Unstructured) error
+}
+
+// Option is a generic option for the Migrate transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple migration options at once.
+type Options struct {
+	// Migrations are applied in addition to the built-in ones, taking precedence over them.
+	Migrations []Migration
+}
+
+// ApplyTo applies the migration options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Migrations = append(target.Migrations, opts.Migrations...)
+}
+
+// WithMigrations adds migrations, e.g. for custom resources. A migration from a GroupVersionKind
+// replaces the built-in migration from the same one.
+// Default: only the built-in migrations, see Migrations.
+func WithMigrations(migrations ...Migration) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Migrations = append(opts.Migrations, migrations...)
+	})
+}
+
+// Migrate returns a transformer rewriting objects of deprecated GroupVersionKinds to their
+// successors. Migrations are chained, e.g. a migration from v1beta1 to v1beta2 followed by one
+// from v1beta2 to v1. Objects without a migration are returned unchanged.
+func Migrate(opts ...Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	table := make(map[schema.GroupVersionKind]Migration)
+
+	for _, m := range Migrations() {
+		table[m.From] = m
+	}
+
+	for _, m := range options.Migrations {
+		table[m.From] = m
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		visited := make(map[schema.GroupVersionKind]struct{})
+
+		for {
+			gvk := obj.GroupVersionKind()
+
+			m, ok := table[gvk]
+			if !ok {
+				return obj, nil
+			}
+
+			if _, ok := visited[gvk]; ok {
+				return unstructured.Unstructured{}, fmt.Errorf("%w: %s", ErrMigrationLoop, gvk)
+			}
+
+			visited[gvk] = struct{}{}
+
+			obj.SetGroupVersionKind(m.To)
+
+			if m.Convert != nil {
+				if err := m.Convert(&obj); err != nil {
+					return unstructured.Unstructured{}, fmt.Errorf("unable to migrate %s to %s: %w", m.From, m.To, err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package apiversion
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Migrations returns the built-in migrations, covering the beta APIs removed up to Kubernetes 1.32
+// whose successor can be derived from the object alone.
+func Migrations() []Migration {
+	var result []Migration
+
+	add := func(kinds []string, to string, convert func(obj *unstructured.Unstructured) error, from ...string) {
+		for _, kind := range kinds {
+			for _, f := range from {
+				result = append(result, Migration{
+					From:    schema.FromAPIVersionAndKind(f, kind),
+					To:      schema.FromAPIVersionAndKind(to, kind),
+					Convert: convert,
+				})
+			}
+		}
+	}
+
+	add([]string{"Deployment", "DaemonSet", "ReplicaSet", "StatefulSet"}, "apps/v1", defaultSelector,
+		"extensions/v1beta1", "apps/v1beta1", "apps/v1beta2")
+	add([]string{"NetworkPolicy"}, "networking.k8s.io/v1", nil,
+		"extensions/v1beta1")
+	add([]string{"Ingress"}, "networking.k8s.io/v1", convertIngress,
+		"extensions/v1beta1", "networking.k8s.io/v1beta1")
+	add([]string{"IngressClass"}, "networking.k8s.io/v1", nil,
+		"networking.k8s.io/v1beta1")
+	add([]string{"PodDisruptionBudget"}, "policy/v1", nil,
+		"policy/v1beta1")
+	add([]string{"CronJob"}, "batch/v1", nil,
+		"batch/v1beta1", "batch/v2alpha1")
+	add([]string{"HorizontalPodAutoscaler"}, "autoscaling/v2", nil,
+		"autoscaling/v2beta2")
+	add([]string{"Role", "ClusterRole", "RoleBinding", "ClusterRoleBinding"}, "rbac.authorization.k8s.io/v1", nil,
+		"rbac.authorization.k8s.io/v1beta1", "rbac.authorization.k8s.io/v1alpha1")
+	add([]string{"PriorityClass"}, "scheduling.k8s.io/v1", nil,
+		"scheduling.k8s.io/v1beta1", "scheduling.k8s.io/v1alpha1")
+	add([]string{"StorageClass", "VolumeAttachment", "CSIDriver", "CSINode", "CSIStorageCapacity"}, "storage.k8s.io/v1", nil,
+		"storage.k8s.io/v1beta1")
+	add([]string{"Lease"}, "coordination.k8s.io/v1", nil,
+		"coordination.k8s.io/v1beta1")
+	add([]string{"RuntimeClass"}, "node.k8s.io/v1", nil,
+		"node.k8s.io/v1beta1")
+
+	return result
+}
+
+// defaultSelector sets the selector of workloads to the labels of their pod template when missing,
+// as it was defaulted by the beta APIs and is required by apps/v1.
+func defaultSelector(obj *unstructured.Unstructured) error {
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "selector"); found {
+		return nil
+	}
+
+	labels, found, err := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+	if err != nil || !found {
+		return err
+	}
+
+	return unstructured.SetNestedStringMap(obj.Object, labels, "spec", "selector", "matchLabels")
+}
+
+// convertIngress converts the backends of an Ingress to the networking.k8s.io/v1 schema: the
+// default backend moves from backend to defaultBackend, serviceName and servicePort move to
+// service.name and service.port, and paths get the pathType required by v1.
+func convertIngress(obj *unstructured.Unstructured) error {
+	spec, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil || !found {
+		return err
+	}
+
+	if backend, ok := spec["backend"].(map[string]any); ok {
+		delete(spec, "backend")
+		spec["defaultBackend"] = convertBackend(backend)
+	}
+
+	rules, _ := spec["rules"].([]any)
+	for _, r := range rules {
+		rule, _ := r.(map[string]any)
+
+		paths, _, _ := unstructured.NestedSlice(rule, "http", "paths")
+		for i, p := range paths {
+			path, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if _, ok := path["pathType"]; !ok {
+				path["pathType"] = "ImplementationSpecific"
+			}
+
+			if backend, ok := path["backend"].(map[string]any); ok {
+				path["backend"] = convertBackend(backend)
+			}
+
+			paths[i] = path
+		}
+
+		if len(paths) > 0 {
+			if err := unstructured.SetNestedSlice(rule, paths, "http", "paths"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return unstructured.SetNestedMap(obj.Object, spec, "spec")
+}
+
+// convertBackend converts a serviceName/servicePort backend to a service backend. Resource
+// backends are unchanged.
+func convertBackend(backend map[string]any) map[string]any {
+	name, ok := backend["serviceName"]
+	if !ok {
+		return backend
+	}
+
+	port := map[string]any{}
+
+	switch p := backend["servicePort"].(type) {
+	case string:
+		if v := intstr.Parse(p); v.Type == intstr.Int {
+			port["number"] = int64(v.IntVal)
+		} else {
+			port["name"] = p
+		}
+	case int64:
+		port["number"] = p
+	case float64:
+		port["number"] = int64(p)
+	}
+
+	return map[string]any{
+		"service": map[string]any{
+			"name": name,
+			"port": port,
+		},
+	}
+}
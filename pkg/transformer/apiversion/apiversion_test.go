@@ -0,0 +1,165 @@
+package apiversion_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/apiversion"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+
+	. "github.com/onsi/gomega"
+)
+
+const legacyYAML = `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 1
+---
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  backend:
+    serviceName: default
+    servicePort: 80
+  rules:
+  - host: example.com
+    http:
+      paths:
+      - path: /
+        backend:
+          serviceName: web
+          servicePort: http
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+func TestMigrate(t *testing.T) {
+	t.Run("should migrate deprecated apiVersions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(legacyYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		migrate := apiversion.Migrate()
+
+		pdb, err := migrate(t.Context(), objects[0])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(pdb.GetAPIVersion()).Should(Equal("policy/v1"))
+		g.Expect(pdb.Object["spec"]).Should(HaveKeyWithValue("minAvailable", BeNumerically("==", 1)))
+
+		deployment, err := migrate(t.Context(), objects[1])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(deployment.GetAPIVersion()).Should(Equal("apps/v1"))
+
+		selector, _, err := unstructured.NestedStringMap(deployment.Object, "spec", "selector", "matchLabels")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(selector).Should(Equal(map[string]string{"app": "web"}))
+
+		configMap, err := migrate(t.Context(), objects[3])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(configMap).Should(Equal(objects[3]))
+	})
+
+	t.Run("should convert Ingress backends", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(legacyYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ingress, err := apiversion.Migrate()(t.Context(), objects[2])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(ingress.GetAPIVersion()).Should(Equal("networking.k8s.io/v1"))
+
+		spec := ingress.Object["spec"].(map[string]any)
+		g.Expect(spec).ShouldNot(HaveKey("backend"))
+		g.Expect(spec["defaultBackend"]).Should(Equal(map[string]any{
+			"service": map[string]any{
+				"name": "default",
+				"port": map[string]any{"number": int64(80)},
+			},
+		}))
+
+		paths, _, err := unstructured.NestedSlice(spec["rules"].([]any)[0].(map[string]any), "http", "paths")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(paths).Should(ConsistOf(map[string]any{
+			"path":     "/",
+			"pathType": "ImplementationSpecific",
+			"backend": map[string]any{
+				"service": map[string]any{
+					"name": "web",
+					"port": map[string]any{"name": "http"},
+				},
+			},
+		}))
+	})
+
+	t.Run("should chain custom migrations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v1alpha1 := schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "Widget"}
+		v1beta1 := schema.GroupVersionKind{Group: "example.com", Version: "v1beta1", Kind: "Widget"}
+		v1 := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+		migrate := apiversion.Migrate(apiversion.WithMigrations(
+			apiversion.Migration{From: v1alpha1, To: v1beta1},
+			apiversion.Migration{
+				From: v1beta1,
+				To:   v1,
+				Convert: func(obj *unstructured.Unstructured) error {
+					return unstructured.SetNestedField(obj.Object, "converted", "spec", "note")
+				},
+			},
+		))
+
+		widget := unstructured.Unstructured{Object: map[string]any{}}
+		widget.SetGroupVersionKind(v1alpha1)
+		widget.SetName("widget")
+
+		obj, err := migrate(t.Context(), widget)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GroupVersionKind()).Should(Equal(v1))
+		g.Expect(obj.Object["spec"]).Should(HaveKeyWithValue("note", "converted"))
+	})
+
+	t.Run("should detect migration loops", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+		b := schema.GroupVersionKind{Group: "example.com", Version: "v2", Kind: "Widget"}
+
+		migrate := apiversion.Migrate(apiversion.WithMigrations(
+			apiversion.Migration{From: a, To: b},
+			apiversion.Migration{From: b, To: a},
+		))
+
+		widget := unstructured.Unstructured{Object: map[string]any{}}
+		widget.SetGroupVersionKind(a)
+
+		_, err := migrate(t.Context(), widget)
+		g.Expect(err).Should(MatchError(apiversion.ErrMigrationLoop))
+	})
+}
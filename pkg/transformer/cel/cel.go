@@ -0,0 +1,117 @@
+// Package cel provides transformers mutating objects with CEL expressions, following the
+// ApplyConfiguration and JSONPatch mutations of Kubernetes MutatingAdmissionPolicy.
+package cel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cel"
+)
+
+var (
+	// ErrCelMustReturnObject is returned when an apply configuration expression doesn't return an object.
+	ErrCelMustReturnObject = errors.New("cel expression must return an object")
+
+	// ErrCelMustReturnPatch is returned when a JSON patch expression doesn't return a list of operations.
+	ErrCelMustReturnPatch = errors.New("cel expression must return a list of JSON patch operations")
+)
+
+// Transform creates a new CEL transformer with the given expression and options, like an
+// ApplyConfiguration mutation. The object being transformed is available as the object variable,
+// and the expression must return a partial object that is merged into it: maps are merged
+// recursively, other values, lists included, are replaced, and null values remove the field.
+//
+// Example:
+//
+//	t, err := cel.Transform(`{"metadata": {"labels": {"tier": object.?metadata.?labels.?app.orValue("none")}}}`)
+func Transform(expression string, opts ...cel.Option) (types.Transformer, error) {
+	// Create a new CEL engine
+	engine, err := cel.NewEngine(expression, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cel engine: %w", err)
+	}
+
+	return mutate(engine, func(v any, original []byte) ([]byte, error) {
+		if _, ok := v.(map[string]any); !ok {
+			return nil, fmt.Errorf("%w, got %T", ErrCelMustReturnObject, v)
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonpatch.MergePatch(original, data)
+	}), nil
+}
+
+// JSONPatch creates a new CEL transformer with the given expression and options, like a
+// JSONPatch mutation. The object being transformed is available as the object variable, and the
+// expression must return a list of RFC 6902 operations with op, path, and optionally value and
+// from fields. Keys holding "/" or "~" must be escaped as "~1" and "~0" in paths.
+//
+// Example:
+//
+//	t, err := cel.JSONPatch(`[{"op": "add", "path": "/metadata/annotations", "value": {"mutated": "true"}}]`)
+func JSONPatch(expression string, opts ...cel.Option) (types.Transformer, error) {
+	// Create a new CEL engine
+	engine, err := cel.NewEngine(expression, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cel engine: %w", err)
+	}
+
+	return mutate(engine, func(v any, original []byte) ([]byte, error) {
+		if _, ok := v.([]any); !ok {
+			return nil, fmt.Errorf("%w, got %T", ErrCelMustReturnPatch, v)
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCelMustReturnPatch, err)
+		}
+
+		return p.Apply(original)
+	}), nil
+}
+
+// mutate returns a transformer evaluating the engine and applying its result to the objects in
+// their JSON form.
+func mutate(engine *cel.Engine, apply func(v any, original []byte) ([]byte, error)) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		v, err := engine.Run(ctx, obj.Object)
+		if err != nil {
+			return obj, transformer.Wrap(obj, fmt.Errorf("error executing cel expression: %w", err))
+		}
+
+		original, err := obj.MarshalJSON()
+		if err != nil {
+			return obj, transformer.Wrap(obj, err)
+		}
+
+		mutated, err := apply(v, original)
+		if err != nil {
+			return obj, transformer.Wrap(obj, fmt.Errorf("failed to apply cel mutation: %w", err))
+		}
+
+		result := unstructured.Unstructured{}
+		if err := result.UnmarshalJSON(mutated); err != nil {
+			return obj, transformer.Wrap(obj, fmt.Errorf("invalid mutated object: %w", err))
+		}
+
+		return result, nil
+	}
+}
@@ -0,0 +1,166 @@
+package cel_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/cel"
+	utilcel "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cel"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "app",
+				"labels": map[string]any{
+					"app":  "nginx",
+					"temp": "true",
+				},
+			},
+			"spec": map[string]any{
+				"replicas": int64(1),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "app", "image": "nginx:1.25"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTransform(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should merge the returned object", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(`{"spec": {"replicas": object.spec.replicas + 2}}`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transformer(ctx, makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		replicas, _, _ := unstructured.NestedInt64(result.Object, "spec", "replicas")
+		g.Expect(replicas).To(Equal(int64(3)))
+
+		containers, _, _ := unstructured.NestedSlice(result.Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(HaveLen(1))
+		g.Expect(result.GetName()).To(Equal("app"))
+	})
+
+	t.Run("should remove fields set to null", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(`{"metadata": {"labels": {"temp": null, "team": "platform"}}}`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transformer(ctx, makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.GetLabels()).To(Equal(map[string]string{
+			"app":  "nginx",
+			"team": "platform",
+		}))
+	})
+
+	t.Run("should use optional fields", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(
+			`{"metadata": {"annotations": {"owner": object.?metadata.?annotations.?owner.orValue(owner)}}}`,
+			utilcel.WithVariable("owner", "platform"),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transformer(ctx, makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.GetAnnotations()).To(HaveKeyWithValue("owner", "platform"))
+	})
+
+	t.Run("should return error for non-object result", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(`object.kind`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = transformer(ctx, makeDeployment())
+		g.Expect(err).To(MatchError(cel.ErrCelMustReturnObject))
+	})
+
+	t.Run("should return error for invalid expression", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(`{"spec":`)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("error creating cel engine"))
+		g.Expect(transformer).To(BeNil())
+	})
+
+	t.Run("should return error for execution failure", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.Transform(`{"spec": {"paused": object.spec.paused}}`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = transformer(ctx, makeDeployment())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("error executing cel expression"))
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should apply the returned operations", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.JSONPatch(`[
+			{"op": "replace", "path": "/spec/replicas", "value": 3},
+			{"op": "add", "path": "/spec/template/spec/containers/-", "value": {"name": "sidecar", "image": "envoy:1.30"}},
+			{"op": "remove", "path": "/metadata/labels/temp"}
+		]`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transformer(ctx, makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		replicas, _, _ := unstructured.NestedInt64(result.Object, "spec", "replicas")
+		g.Expect(replicas).To(Equal(int64(3)))
+
+		containers, _, _ := unstructured.NestedSlice(result.Object, "spec", "template", "spec", "containers")
+		g.Expect(containers).To(HaveLen(2))
+		g.Expect(result.GetLabels()).To(Equal(map[string]string{"app": "nginx"}))
+	})
+
+	t.Run("should build operations from the object", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.JSONPatch(`object.spec.template.spec.containers.map(c,
+			{"op": "add", "path": "/metadata/annotations", "value": {"image": c.image}}
+		)`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transformer(ctx, makeDeployment())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.GetAnnotations()).To(Equal(map[string]string{"image": "nginx:1.25"}))
+	})
+
+	t.Run("should return error for non-list result", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.JSONPatch(`{"op": "remove", "path": "/spec"}`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = transformer(ctx, makeDeployment())
+		g.Expect(err).To(MatchError(cel.ErrCelMustReturnPatch))
+	})
+
+	t.Run("should return error for failing operations", func(t *testing.T) {
+		g := NewWithT(t)
+		transformer, err := cel.JSONPatch(`[{"op": "remove", "path": "/spec/missing"}]`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = transformer(ctx, makeDeployment())
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to apply cel mutation"))
+	})
+}
@@ -0,0 +1,77 @@
+package transformer
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// ChainCollection chains collection transformers in sequence.
+// Each collection transformer receives the set returned by the previous one.
+// If no collection transformers are provided, returns the set unchanged.
+// If any collection transformer returns an error, the error is returned immediately.
+func ChainCollection(transformers ...types.CollectionTransformer) types.CollectionTransformer {
+	return func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		result := objects
+
+		for _, transformer := range transformers {
+			var err error
+			result, err = transformer(ctx, result)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// ForEach lifts a transformer into a collection transformer applying it to every object of the
+// set, e.g. to run it after a collection transformer within ChainCollection.
+// If the transformer returns an error, it is returned immediately, wrapped with Wrap.
+func ForEach(transformer types.Transformer) types.CollectionTransformer {
+	return func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		result := make([]unstructured.Unstructured, 0, len(objects))
+
+		for _, obj := range objects {
+			r, err := transformer(ctx, obj)
+			if err != nil {
+				return nil, Wrap(obj, err)
+			}
+
+			result = append(result, r)
+		}
+
+		return result, nil
+	}
+}
+
+// Generate returns a collection transformer appending to the set the companion objects returned
+// by fn for each object, e.g. a PodDisruptionBudget for every Deployment. fn receives the whole
+// set to look up related objects; the generated objects are appended after the original ones, in
+// the order of the objects they were generated from.
+// If fn returns an error, it is returned immediately, wrapped with Wrap.
+func Generate(
+	fn func(ctx context.Context, object unstructured.Unstructured, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error),
+) types.CollectionTransformer {
+	return func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		var generated []unstructured.Unstructured
+
+		for _, obj := range objects {
+			r, err := fn(ctx, obj, objects)
+			if err != nil {
+				return nil, Wrap(obj, err)
+			}
+
+			generated = append(generated, r...)
+		}
+
+		result := make([]unstructured.Unstructured, 0, len(objects)+len(generated))
+		result = append(result, objects...)
+		result = append(result, generated...)
+
+		return result, nil
+	}
+}
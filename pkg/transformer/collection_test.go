@@ -0,0 +1,114 @@
+package transformer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+
+	. "github.com/onsi/gomega"
+)
+
+func reverse(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	result := make([]unstructured.Unstructured, 0, len(objects))
+	for i := len(objects) - 1; i >= 0; i-- {
+		result = append(result, objects[i])
+	}
+
+	return result, nil
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetName())
+	}
+
+	return result
+}
+
+func TestChainCollection(t *testing.T) {
+
+	t.Run("should apply collection transformers in sequence", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.ChainCollection(
+			reverse,
+			transformer.ForEach(setLabel("env", "prod")),
+		)
+
+		objects, err := tr(t.Context(), []unstructured.Unstructured{makePod("a"), makePod("b")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"b", "a"}))
+		g.Expect(objects[0].GetLabels()).Should(HaveKeyWithValue("env", "prod"))
+	})
+
+	t.Run("should return unchanged with no collection transformers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := transformer.ChainCollection()(t.Context(), []unstructured.Unstructured{makePod("a")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"a"}))
+	})
+
+	t.Run("should stop on the first error", func(t *testing.T) {
+		g := NewWithT(t)
+		testErr := errors.New("test error")
+
+		tr := transformer.ChainCollection(
+			func(context.Context, []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+				return nil, testErr
+			},
+			reverse,
+		)
+
+		_, err := tr(t.Context(), []unstructured.Unstructured{makePod("a")})
+		g.Expect(err).Should(MatchError(testErr))
+	})
+}
+
+func TestForEach(t *testing.T) {
+
+	t.Run("should wrap errors with the failing object", func(t *testing.T) {
+		g := NewWithT(t)
+		testErr := errors.New("test error")
+
+		tr := transformer.ForEach(func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+			if obj.GetName() == "b" {
+				return unstructured.Unstructured{}, testErr
+			}
+
+			return obj, nil
+		})
+
+		_, err := tr(t.Context(), []unstructured.Unstructured{makePod("a"), makePod("b")})
+		g.Expect(err).Should(MatchError(testErr))
+
+		var transformerErr *transformer.Error
+		g.Expect(errors.As(err, &transformerErr)).Should(BeTrue())
+		g.Expect(transformerErr.Object.GetName()).Should(Equal("b"))
+	})
+}
+
+func TestGenerate(t *testing.T) {
+
+	t.Run("should append generated objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := transformer.Generate(func(
+			_ context.Context,
+			obj unstructured.Unstructured,
+			objects []unstructured.Unstructured,
+		) ([]unstructured.Unstructured, error) {
+			g.Expect(objects).Should(HaveLen(2))
+
+			return []unstructured.Unstructured{makePod(obj.GetName() + "-companion")}, nil
+		})
+
+		objects, err := tr(t.Context(), []unstructured.Unstructured{makePod("a"), makePod("b")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(objects)).Should(Equal([]string{"a", "b", "a-companion", "b-companion"}))
+	})
+}
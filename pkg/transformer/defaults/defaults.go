@@ -0,0 +1,97 @@
+// Package defaults provides a transformer setting the default values the API server would persist
+// for unset fields, so rendered objects can be compared with live ones without spurious diffs.
+package defaults
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the Default transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple defaulting options at once.
+type Options struct {
+	// Scheme decodes the objects and holds the defaulting functions.
+	Scheme *runtime.Scheme
+}
+
+// ApplyTo applies the defaulting options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Scheme != nil {
+		target.Scheme = opts.Scheme
+	}
+}
+
+// WithScheme sets the scheme decoding the objects and holding the defaulting functions, e.g. one
+// registering the generated defaulters of additional API groups with AddTypeDefaultingFunc.
+// Default: Scheme().
+func WithScheme(s *runtime.Scheme) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Scheme = s
+	})
+}
+
+// Default returns a transformer decoding objects of the kinds known to the scheme into their typed
+// struct, running the defaulting functions of the scheme on it and encoding the result back.
+// Objects of other kinds, e.g. custom resources, are returned unchanged.
+func Default(opts ...Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.Scheme == nil {
+		options.Scheme = Scheme()
+	}
+
+	s := options.Scheme
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		gvk := obj.GroupVersionKind()
+		if !s.Recognizes(gvk) {
+			return obj, nil
+		}
+
+		typed, err := s.New(gvk)
+		if err != nil {
+			return unstructured.Unstructured{}, fmt.Errorf("unable to create %s: %w", gvk, err)
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+			return unstructured.Unstructured{}, fmt.Errorf("unable to convert object to %T: %w", typed, err)
+		}
+
+		s.Default(typed)
+
+		result, err := k8s.ToUnstructured(typed)
+		if err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		result.SetGroupVersionKind(gvk)
+
+		// Zero values the object didn't have are encoded as null or empty fields
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "metadata", "creationTimestamp"); !found {
+			if ts, found, _ := unstructured.NestedFieldNoCopy(result.Object, "metadata", "creationTimestamp"); found && ts == nil {
+				unstructured.RemoveNestedField(result.Object, "metadata", "creationTimestamp")
+			}
+		}
+
+		if _, found := obj.Object["status"]; !found {
+			if status, ok := result.Object["status"].(map[string]any); ok && len(status) == 0 {
+				delete(result.Object, "status")
+			}
+		}
+
+		return *result, nil
+	}
+}
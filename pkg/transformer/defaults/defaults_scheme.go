@@ -0,0 +1,352 @@
+package defaults
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/image"
+)
+
+const (
+	// defaultMode is the default permission of files projected from volume sources (0644).
+	defaultMode int32 = 0o644
+
+	// defaultRevisionHistoryLimit is the default number of old revisions kept by workloads.
+	defaultRevisionHistoryLimit int32 = 10
+)
+
+// Scheme returns a scheme holding the client-go types and the built-in defaulting functions of
+// Pods, ReplicationControllers, Services, Deployments, ReplicaSets, StatefulSets, DaemonSets, Jobs
+// and CronJobs. They mirror the defaults set by the API server for the most common fields; fields
+// defaulted by admission plugins or controllers, e.g. the service account or cluster IPs, are not
+// set.
+func Scheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	// AddToScheme only fails on conflicting registrations, which cannot happen on a new scheme
+	_ = clientgoscheme.AddToScheme(s)
+
+	AddToScheme(s)
+
+	return s
+}
+
+// AddToScheme registers the built-in defaulting functions with a scheme.
+func AddToScheme(s *runtime.Scheme) {
+	addDefaulter(s, &corev1.Pod{}, func(obj *corev1.Pod) {
+		setPodSpecDefaults(&obj.Spec)
+	})
+	addDefaulter(s, &corev1.ReplicationController{}, func(obj *corev1.ReplicationController) {
+		if obj.Spec.Replicas == nil {
+			obj.Spec.Replicas = ptr.To[int32](1)
+		}
+
+		if obj.Spec.Template != nil {
+			setPodSpecDefaults(&obj.Spec.Template.Spec)
+		}
+	})
+	addDefaulter(s, &corev1.Service{}, setServiceDefaults)
+	addDefaulter(s, &appsv1.Deployment{}, setDeploymentDefaults)
+	addDefaulter(s, &appsv1.ReplicaSet{}, func(obj *appsv1.ReplicaSet) {
+		if obj.Spec.Replicas == nil {
+			obj.Spec.Replicas = ptr.To[int32](1)
+		}
+
+		setPodSpecDefaults(&obj.Spec.Template.Spec)
+	})
+	addDefaulter(s, &appsv1.StatefulSet{}, setStatefulSetDefaults)
+	addDefaulter(s, &appsv1.DaemonSet{}, setDaemonSetDefaults)
+	addDefaulter(s, &batchv1.Job{}, func(obj *batchv1.Job) {
+		setJobSpecDefaults(&obj.Spec)
+	})
+	addDefaulter(s, &batchv1.CronJob{}, setCronJobDefaults)
+}
+
+// addDefaulter registers a typed defaulting function for the type of obj.
+func addDefaulter[T runtime.Object](s *runtime.Scheme, obj T, fn func(T)) {
+	s.AddTypeDefaultingFunc(obj, func(in any) {
+		if typed, ok := in.(T); ok {
+			fn(typed)
+		}
+	})
+}
+
+func setPodSpecDefaults(spec *corev1.PodSpec) {
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = corev1.DNSClusterFirst
+	}
+
+	if spec.SchedulerName == "" {
+		spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+
+	if spec.TerminationGracePeriodSeconds == nil {
+		spec.TerminationGracePeriodSeconds = ptr.To[int64](corev1.DefaultTerminationGracePeriodSeconds)
+	}
+
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+
+	for i := range spec.InitContainers {
+		setContainerDefaults(&spec.InitContainers[i])
+	}
+
+	for i := range spec.Containers {
+		setContainerDefaults(&spec.Containers[i])
+	}
+
+	for i := range spec.Volumes {
+		setVolumeDefaults(&spec.Volumes[i])
+	}
+}
+
+func setContainerDefaults(container *corev1.Container) {
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = imagePullPolicy(container.Image)
+	}
+
+	if container.TerminationMessagePath == "" {
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+
+	for i := range container.Ports {
+		if container.Ports[i].Protocol == "" {
+			container.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+
+	for i := range container.Env {
+		if from := container.Env[i].ValueFrom; from != nil && from.FieldRef != nil && from.FieldRef.APIVersion == "" {
+			from.FieldRef.APIVersion = "v1"
+		}
+	}
+}
+
+// imagePullPolicy returns the pull policy of images without one: Always for untagged or latest
+// images, IfNotPresent otherwise.
+func imagePullPolicy(ref string) corev1.PullPolicy {
+	parsed, err := image.Parse(ref)
+	if err == nil && parsed.Digest == "" && (parsed.Tag == "" || parsed.Tag == "latest") {
+		return corev1.PullAlways
+	}
+
+	return corev1.PullIfNotPresent
+}
+
+func setVolumeDefaults(volume *corev1.Volume) {
+	switch {
+	case volume.ConfigMap != nil && volume.ConfigMap.DefaultMode == nil:
+		volume.ConfigMap.DefaultMode = ptr.To(defaultMode)
+	case volume.Secret != nil && volume.Secret.DefaultMode == nil:
+		volume.Secret.DefaultMode = ptr.To(defaultMode)
+	case volume.Projected != nil && volume.Projected.DefaultMode == nil:
+		volume.Projected.DefaultMode = ptr.To(defaultMode)
+	case volume.DownwardAPI != nil && volume.DownwardAPI.DefaultMode == nil:
+		volume.DownwardAPI.DefaultMode = ptr.To(defaultMode)
+	}
+}
+
+func setServiceDefaults(obj *corev1.Service) {
+	spec := &obj.Spec
+
+	if spec.Type == "" {
+		spec.Type = corev1.ServiceTypeClusterIP
+	}
+
+	if spec.SessionAffinity == "" {
+		spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+
+	if spec.Type == corev1.ServiceTypeExternalName {
+		return
+	}
+
+	if spec.InternalTrafficPolicy == nil {
+		spec.InternalTrafficPolicy = ptr.To(corev1.ServiceInternalTrafficPolicyCluster)
+	}
+
+	if spec.ExternalTrafficPolicy == "" && (spec.Type == corev1.ServiceTypeNodePort || spec.Type == corev1.ServiceTypeLoadBalancer) {
+		spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	}
+
+	for i := range spec.Ports {
+		port := &spec.Ports[i]
+
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+
+		if port.TargetPort == (intstr.IntOrString{}) {
+			port.TargetPort = intstr.FromInt32(port.Port)
+		}
+	}
+}
+
+func setDeploymentDefaults(obj *appsv1.Deployment) {
+	spec := &obj.Spec
+
+	if spec.Replicas == nil {
+		spec.Replicas = ptr.To[int32](1)
+	}
+
+	if spec.Strategy.Type == "" {
+		spec.Strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	}
+
+	if spec.Strategy.Type == appsv1.RollingUpdateDeploymentStrategyType {
+		if spec.Strategy.RollingUpdate == nil {
+			spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		}
+
+		if spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+			spec.Strategy.RollingUpdate.MaxUnavailable = ptr.To(intstr.FromString("25%"))
+		}
+
+		if spec.Strategy.RollingUpdate.MaxSurge == nil {
+			spec.Strategy.RollingUpdate.MaxSurge = ptr.To(intstr.FromString("25%"))
+		}
+	}
+
+	if spec.RevisionHistoryLimit == nil {
+		spec.RevisionHistoryLimit = ptr.To(defaultRevisionHistoryLimit)
+	}
+
+	if spec.ProgressDeadlineSeconds == nil {
+		spec.ProgressDeadlineSeconds = ptr.To[int32](600)
+	}
+
+	setPodSpecDefaults(&spec.Template.Spec)
+}
+
+func setStatefulSetDefaults(obj *appsv1.StatefulSet) {
+	spec := &obj.Spec
+
+	if spec.Replicas == nil {
+		spec.Replicas = ptr.To[int32](1)
+	}
+
+	if spec.PodManagementPolicy == "" {
+		spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	}
+
+	if spec.UpdateStrategy.Type == "" {
+		spec.UpdateStrategy.Type = appsv1.RollingUpdateStatefulSetStrategyType
+	}
+
+	if spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		if spec.UpdateStrategy.RollingUpdate == nil {
+			spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateStatefulSetStrategy{}
+		}
+
+		if spec.UpdateStrategy.RollingUpdate.Partition == nil {
+			spec.UpdateStrategy.RollingUpdate.Partition = ptr.To[int32](0)
+		}
+	}
+
+	if spec.RevisionHistoryLimit == nil {
+		spec.RevisionHistoryLimit = ptr.To(defaultRevisionHistoryLimit)
+	}
+
+	if spec.PersistentVolumeClaimRetentionPolicy == nil {
+		spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{}
+	}
+
+	if spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted == "" {
+		spec.PersistentVolumeClaimRetentionPolicy.WhenDeleted = appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	}
+
+	if spec.PersistentVolumeClaimRetentionPolicy.WhenScaled == "" {
+		spec.PersistentVolumeClaimRetentionPolicy.WhenScaled = appsv1.RetainPersistentVolumeClaimRetentionPolicyType
+	}
+
+	setPodSpecDefaults(&spec.Template.Spec)
+}
+
+func setDaemonSetDefaults(obj *appsv1.DaemonSet) {
+	spec := &obj.Spec
+
+	if spec.UpdateStrategy.Type == "" {
+		spec.UpdateStrategy.Type = appsv1.RollingUpdateDaemonSetStrategyType
+	}
+
+	if spec.UpdateStrategy.Type == appsv1.RollingUpdateDaemonSetStrategyType {
+		if spec.UpdateStrategy.RollingUpdate == nil {
+			spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{}
+		}
+
+		if spec.UpdateStrategy.RollingUpdate.MaxUnavailable == nil {
+			spec.UpdateStrategy.RollingUpdate.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+		}
+
+		if spec.UpdateStrategy.RollingUpdate.MaxSurge == nil {
+			spec.UpdateStrategy.RollingUpdate.MaxSurge = ptr.To(intstr.FromInt32(0))
+		}
+	}
+
+	if spec.RevisionHistoryLimit == nil {
+		spec.RevisionHistoryLimit = ptr.To(defaultRevisionHistoryLimit)
+	}
+
+	setPodSpecDefaults(&spec.Template.Spec)
+}
+
+func setJobSpecDefaults(spec *batchv1.JobSpec) {
+	// Like the API server, jobs without completions and parallelism run a single pod
+	if spec.Completions == nil && spec.Parallelism == nil {
+		spec.Completions = ptr.To[int32](1)
+	}
+
+	if spec.Parallelism == nil {
+		spec.Parallelism = ptr.To[int32](1)
+	}
+
+	if spec.BackoffLimit == nil {
+		spec.BackoffLimit = ptr.To[int32](6)
+	}
+
+	if spec.CompletionMode == nil {
+		spec.CompletionMode = ptr.To(batchv1.NonIndexedCompletion)
+	}
+
+	if spec.Suspend == nil {
+		spec.Suspend = ptr.To(false)
+	}
+
+	setPodSpecDefaults(&spec.Template.Spec)
+}
+
+func setCronJobDefaults(obj *batchv1.CronJob) {
+	spec := &obj.Spec
+
+	if spec.ConcurrencyPolicy == "" {
+		spec.ConcurrencyPolicy = batchv1.AllowConcurrent
+	}
+
+	if spec.Suspend == nil {
+		spec.Suspend = ptr.To(false)
+	}
+
+	if spec.SuccessfulJobsHistoryLimit == nil {
+		spec.SuccessfulJobsHistoryLimit = ptr.To[int32](3)
+	}
+
+	if spec.FailedJobsHistoryLimit == nil {
+		spec.FailedJobsHistoryLimit = ptr.To[int32](1)
+	}
+
+	setJobSpecDefaults(&spec.JobTemplate.Spec)
+}
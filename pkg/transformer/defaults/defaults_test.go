@@ -0,0 +1,165 @@
+package defaults_test
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/defaults"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": "web",
+			},
+			"spec": map[string]any{
+				"replicas": int64(3),
+				"selector": map[string]any{
+					"matchLabels": map[string]any{"app": "web"},
+				},
+				"template": map[string]any{
+					"metadata": map[string]any{
+						"labels": map[string]any{"app": "web"},
+					},
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"name":  "web",
+								"image": "nginx",
+								"ports": []any{
+									map[string]any{"containerPort": int64(80)},
+								},
+							},
+							map[string]any{
+								"name":  "proxy",
+								"image": "envoyproxy/envoy:v1.31.0",
+							},
+						},
+						"volumes": []any{
+							map[string]any{
+								"name":      "config",
+								"configMap": map[string]any{"name": "web"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDefault(t *testing.T) {
+
+	t.Run("should default workloads and their pod templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := defaults.Default()(t.Context(), makeDeployment())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.GetAPIVersion()).Should(Equal("apps/v1"))
+		g.Expect(obj.GetKind()).Should(Equal("Deployment"))
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.Object["metadata"]).ShouldNot(HaveKey("creationTimestamp"))
+
+		spec := obj.Object["spec"]
+		g.Expect(spec).Should(HaveKeyWithValue("replicas", BeNumerically("==", 3)))
+		g.Expect(spec).Should(HaveKeyWithValue("revisionHistoryLimit", BeNumerically("==", 10)))
+		g.Expect(spec).Should(HaveKeyWithValue("progressDeadlineSeconds", BeNumerically("==", 600)))
+		g.Expect(spec).Should(HaveKeyWithValue("strategy", Equal(map[string]any{
+			"type": "RollingUpdate",
+			"rollingUpdate": map[string]any{
+				"maxUnavailable": "25%",
+				"maxSurge":       "25%",
+			},
+		})))
+
+		podSpec, _, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+		g.Expect(podSpec).Should(HaveKeyWithValue("restartPolicy", "Always"))
+		g.Expect(podSpec).Should(HaveKeyWithValue("dnsPolicy", "ClusterFirst"))
+		g.Expect(podSpec).Should(HaveKeyWithValue("schedulerName", "default-scheduler"))
+		g.Expect(podSpec).Should(HaveKeyWithValue("terminationGracePeriodSeconds", BeNumerically("==", 30)))
+
+		containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+		g.Expect(containers).Should(HaveLen(2))
+		g.Expect(containers[0]).Should(HaveKeyWithValue("imagePullPolicy", "Always"))
+		g.Expect(containers[0]).Should(HaveKeyWithValue("terminationMessagePath", "/dev/termination-log"))
+		g.Expect(containers[0]).Should(HaveKeyWithValue("ports", ContainElement(HaveKeyWithValue("protocol", "TCP"))))
+		g.Expect(containers[1]).Should(HaveKeyWithValue("imagePullPolicy", "IfNotPresent"))
+
+		volumes, _, _ := unstructured.NestedSlice(podSpec, "volumes")
+		g.Expect(volumes).Should(ConsistOf(
+			HaveKeyWithValue("configMap", HaveKeyWithValue("defaultMode", BeNumerically("==", 0o644))),
+		))
+	})
+
+	t.Run("should default services", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := defaults.Default()(t.Context(), unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]any{"name": "web"},
+				"spec": map[string]any{
+					"ports": []any{
+						map[string]any{"port": int64(80)},
+					},
+				},
+			},
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object["spec"]).Should(HaveKeyWithValue("type", "ClusterIP"))
+		g.Expect(obj.Object["spec"]).Should(HaveKeyWithValue("sessionAffinity", "None"))
+		g.Expect(obj.Object["spec"]).Should(HaveKeyWithValue("ports", ConsistOf(map[string]any{
+			"port":       int64(80),
+			"targetPort": int64(80),
+			"protocol":   "TCP",
+		})))
+	})
+
+	t.Run("should keep objects of unknown kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		in := unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]any{"name": "widget"},
+				"spec":       map[string]any{"size": int64(3)},
+			},
+		}
+
+		obj, err := defaults.Default()(t.Context(), in)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(in))
+	})
+
+	t.Run("should use a custom scheme", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := runtime.NewScheme()
+		g.Expect(clientgoscheme.AddToScheme(s)).Should(Succeed())
+
+		s.AddTypeDefaultingFunc(&appsv1.Deployment{}, func(obj any) {
+			if d, ok := obj.(*appsv1.Deployment); ok {
+				d.Spec.Paused = true
+			}
+		})
+
+		obj, err := defaults.Default(defaults.WithScheme(s))(t.Context(), makeDeployment())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object["spec"]).Should(HaveKeyWithValue("paused", true))
+		g.Expect(obj.Object["spec"]).ShouldNot(HaveKey("revisionHistoryLimit"))
+	})
+}
@@ -0,0 +1,182 @@
+// Package env provides transformers injecting, overriding and removing container environment
+// variables.
+package env
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the env transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple env options at once.
+type Options struct {
+	// Containers are glob patterns (path.Match syntax) selecting the containers by name.
+	Containers []string
+}
+
+// ApplyTo applies the env options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Containers = append(target.Containers, opts.Containers...)
+}
+
+// WithContainers restricts the transformer to the containers whose name matches one of the glob
+// patterns, e.g. "app" or "worker-*".
+// Default: all containers, including init containers.
+func WithContainers(patterns ...string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Containers = append(opts.Containers, patterns...)
+	})
+}
+
+// Set returns a transformer setting environment variables on the containers: variables already
+// defined are replaced in place, the others are appended in the given order. Variables can have
+// a value or a valueFrom source, e.g. a secretKeyRef.
+//
+// Example:
+//
+//	env.Set([]corev1.EnvVar{
+//	    {Name: "LOG_LEVEL", Value: "debug"},
+//	    {Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+//	        SecretKeyRef: &corev1.SecretKeySelector{
+//	            LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+//	            Key:                  "token",
+//	        },
+//	    }},
+//	}, env.WithContainers("app"))
+func Set(vars []corev1.EnvVar, opts ...Option) types.Transformer {
+	return transform(opts, func(env []any) ([]any, error) {
+		return merge(env, vars, true)
+	})
+}
+
+// Default returns a transformer appending the environment variables not yet defined by the
+// containers. Variables already defined are left unchanged.
+func Default(vars []corev1.EnvVar, opts ...Option) types.Transformer {
+	return transform(opts, func(env []any) ([]any, error) {
+		return merge(env, vars, false)
+	})
+}
+
+// Remove returns a transformer removing the environment variables with the given names.
+func Remove(names []string, opts ...Option) types.Transformer {
+	return transform(opts, func(env []any) ([]any, error) {
+		return slices.DeleteFunc(env, func(item any) bool {
+			return slices.Contains(names, varName(item))
+		}), nil
+	})
+}
+
+func transform(opts []Option, fn func(env []any) ([]any, error)) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		err := k8s.VisitContainers(obj.Object, func(container map[string]any) error {
+			name, _ := container["name"].(string)
+
+			ok, err := matches(options.Containers, name)
+			if err != nil || !ok {
+				return err
+			}
+
+			env, _, err := unstructured.NestedSlice(container, "env")
+			if err != nil {
+				return err
+			}
+
+			env, err = fn(env)
+			if err != nil {
+				return err
+			}
+
+			if len(env) == 0 {
+				delete(container, "env")
+
+				return nil
+			}
+
+			container["env"] = env
+
+			return nil
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// merge sets the variables in env, replacing the existing ones only if override is set.
+func merge(env []any, vars []corev1.EnvVar, override bool) ([]any, error) {
+	for _, v := range vars {
+		item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert env var %s: %w", v.Name, err)
+		}
+
+		index := slices.IndexFunc(env, func(existing any) bool {
+			return varName(existing) == v.Name
+		})
+
+		switch {
+		case index < 0:
+			env = append(env, item)
+		case override:
+			env[index] = item
+		}
+	}
+
+	return env, nil
+}
+
+// varName returns the name of an environment variable entry.
+func varName(item any) string {
+	m, ok := item.(map[string]any)
+	if !ok {
+		return ""
+	}
+
+	name, _ := m["name"].(string)
+
+	return name
+}
+
+// matches reports whether a container name matches one of the patterns; no pattern matches all.
+func matches(patterns []string, name string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid container pattern %q: %w", pattern, err)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
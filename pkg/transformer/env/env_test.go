@@ -0,0 +1,125 @@
+package env_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/env"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeStatefulSet() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "StatefulSet",
+			"metadata":   map[string]any{"name": "db"},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{
+								"name": "db",
+								"env": []any{
+									map[string]any{"name": "LOG_LEVEL", "value": "info"},
+									map[string]any{"name": "PORT", "value": "5432"},
+								},
+							},
+							map[string]any{"name": "exporter"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func containerEnv(g *WithT, obj unstructured.Unstructured, index int) []any {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	container, ok := containers[index].(map[string]any)
+	g.Expect(ok).Should(BeTrue())
+
+	e, _, err := unstructured.NestedSlice(container, "env")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return e
+}
+
+func TestEnv(t *testing.T) {
+	vars := []corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+				Key:                  "password",
+			},
+		}},
+	}
+
+	password := map[string]any{
+		"name": "PASSWORD",
+		"valueFrom": map[string]any{
+			"secretKeyRef": map[string]any{"name": "credentials", "key": "password"},
+		},
+	}
+
+	t.Run("should set and override variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := env.Set(vars)(t.Context(), makeStatefulSet())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(containerEnv(g, result, 0)).Should(Equal([]any{
+			map[string]any{"name": "LOG_LEVEL", "value": "debug"},
+			map[string]any{"name": "PORT", "value": "5432"},
+			password,
+		}))
+		g.Expect(containerEnv(g, result, 1)).Should(HaveLen(2))
+	})
+
+	t.Run("should only add missing variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := env.Default(vars)(t.Context(), makeStatefulSet())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(containerEnv(g, result, 0)).Should(Equal([]any{
+			map[string]any{"name": "LOG_LEVEL", "value": "info"},
+			map[string]any{"name": "PORT", "value": "5432"},
+			password,
+		}))
+	})
+
+	t.Run("should target containers by name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := env.Set(vars, env.WithContainers("export*"))(t.Context(), makeStatefulSet())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(containerEnv(g, result, 0)).Should(HaveLen(2))
+		g.Expect(containerEnv(g, result, 1)).Should(HaveLen(2))
+	})
+
+	t.Run("should remove variables", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := env.Remove([]string{"LOG_LEVEL", "PORT"})(t.Context(), makeStatefulSet())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		containers, _, err := unstructured.NestedSlice(result.Object, "spec", "template", "spec", "containers")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(containers[0]).ShouldNot(HaveKey("env"))
+	})
+
+	t.Run("should fail on invalid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := env.Set(vars, env.WithContainers("["))(t.Context(), makeStatefulSet())
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,211 @@
+// Package hashsuffix provides a collection transformer appending a content hash to the names of
+// ConfigMaps and Secrets and updating the pods referencing them, so that changing their content
+// rolls out the pods, like the Kustomize generators do.
+package hashsuffix
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// hashLength is the number of characters of the hash appended to names.
+const hashLength = 10
+
+var (
+	configMapGVK = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	secretGVK    = corev1.SchemeGroupVersion.WithKind("Secret")
+
+	// references are the pod spec fields referencing ConfigMaps and Secrets by name.
+	references = map[schema.GroupVersionKind][]fieldpath.Path{
+		configMapGVK: containerPaths(
+			"volumes[*].configMap.name",
+			"volumes[*].projected.sources[*].configMap.name",
+			"%s[*].envFrom[*].configMapRef.name",
+			"%s[*].env[*].valueFrom.configMapKeyRef.name",
+		),
+		secretGVK: containerPaths(
+			"volumes[*].secret.secretName",
+			"volumes[*].projected.sources[*].secret.name",
+			"imagePullSecrets[*].name",
+			"%s[*].envFrom[*].secretRef.name",
+			"%s[*].env[*].valueFrom.secretKeyRef.name",
+		),
+	}
+
+	// encoding replaces the characters of the hex encoded hash that could form words, like Kustomize.
+	encoding = strings.NewReplacer("0", "g", "1", "h", "3", "k", "a", "m", "e", "t")
+)
+
+// Option is a generic option for the hash suffix transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple hash suffix options at once.
+type Options struct {
+	// Target selects the ConfigMaps and Secrets to rename; other ones keep their name.
+	Target types.Filter
+}
+
+// ApplyTo applies the hash suffix options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+}
+
+// WithTarget restricts the renaming to the ConfigMaps and Secrets accepted by the filter, e.g.
+// labels.PartOf("shop").
+// Default: all ConfigMaps and Secrets.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// Suffix returns a collection transformer appending a hash of their content to the names of
+// ConfigMaps and Secrets, e.g. app-config-5f2m8tk6bh, and rewriting the references to them in the
+// pod specs of the objects in the same namespace: volumes, projected volumes, envFrom, env
+// valueFrom and imagePullSecrets. Changing the content changes the name, so the pods referencing
+// it are rolled out. The input objects are not modified.
+func Suffix(opts ...Option) types.CollectionTransformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		result := k8s.DeepCloneUnstructuredSlice(objects)
+
+		// New names by kind, namespace and old name
+		renames := make(map[schema.GroupVersionKind]map[string]map[string]string)
+
+		for i := range result {
+			gvk := result[i].GroupVersionKind()
+			if gvk != configMapGVK && gvk != secretGVK {
+				continue
+			}
+
+			if options.Target != nil {
+				ok, err := options.Target(ctx, result[i])
+				if err != nil {
+					return nil, err
+				}
+
+				if !ok {
+					continue
+				}
+			}
+
+			hash, err := Hash(result[i])
+			if err != nil {
+				return nil, err
+			}
+
+			name := result[i].GetName()
+			namespace := result[i].GetNamespace()
+
+			if renames[gvk] == nil {
+				renames[gvk] = make(map[string]map[string]string)
+			}
+
+			if renames[gvk][namespace] == nil {
+				renames[gvk][namespace] = make(map[string]string)
+			}
+
+			renames[gvk][namespace][name] = name + "-" + hash
+			result[i].SetName(name + "-" + hash)
+		}
+
+		if len(renames) == 0 {
+			return result, nil
+		}
+
+		for i := range result {
+			namespace := result[i].GetNamespace()
+
+			err := k8s.VisitPodSpecs(result[i].Object, func(podSpec map[string]any) error {
+				for gvk, paths := range references {
+					names := renames[gvk][namespace]
+					if len(names) == 0 {
+						continue
+					}
+
+					for _, path := range paths {
+						path.Update(podSpec, func(value any) any {
+							if name, ok := value.(string); ok {
+								if renamed, ok := names[name]; ok {
+									return renamed
+								}
+							}
+
+							return value
+						})
+					}
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// Hash returns the hash appended to the name of a ConfigMap or Secret, computed from its kind,
+// name, type and content.
+func Hash(obj unstructured.Unstructured) (string, error) {
+	content := map[string]any{
+		"kind": obj.GetKind(),
+		"name": obj.GetName(),
+	}
+
+	for _, field := range []string{"type", "data", "stringData", "binaryData"} {
+		if v, ok := obj.Object[field]; ok {
+			content[field] = v
+		}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return encoding.Replace(hex.EncodeToString(sum[:])[:hashLength]), nil
+}
+
+// containerPaths parses the paths, expanding the ones with a %s placeholder for every container list.
+func containerPaths(paths ...string) []fieldpath.Path {
+	result := make([]fieldpath.Path, 0, len(paths))
+
+	for _, p := range paths {
+		if !strings.Contains(p, "%s") {
+			result = append(result, fieldpath.MustParse(p))
+
+			continue
+		}
+
+		for _, containers := range []string{"containers", "initContainers", "ephemeralContainers"} {
+			result = append(result, fieldpath.MustParse(fmt.Sprintf(p, containers)))
+		}
+	}
+
+	return result
+}
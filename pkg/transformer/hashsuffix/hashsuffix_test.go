@@ -0,0 +1,160 @@
+package hashsuffix_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/hashsuffix"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+
+	. "github.com/onsi/gomega"
+)
+
+const manifests = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: shop
+data:
+  level: debug
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: credentials
+  namespace: shop
+stringData:
+  password: s3cr3t
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+      - name: credentials
+      initContainers:
+      - name: init
+        envFrom:
+        - configMapRef:
+            name: config
+      containers:
+      - name: web
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: credentials
+              key: password
+        - name: OTHER
+          valueFrom:
+            configMapKeyRef:
+              name: other
+              key: value
+      volumes:
+      - name: config
+        configMap:
+          name: config
+      - name: projected
+        projected:
+          sources:
+          - secret:
+              name: credentials
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: elsewhere
+  namespace: other
+spec:
+  containers:
+  - name: app
+    envFrom:
+    - configMapRef:
+        name: config
+`
+
+func lookup(obj unstructured.Unstructured, path string) []any {
+	return fieldpath.MustParse(path).Lookup(obj.Object)
+}
+
+func TestSuffix(t *testing.T) {
+	t.Run("should rename ConfigMaps and Secrets and their references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(manifests))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		configHash, err := hashsuffix.Hash(objects[0])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(configHash).Should(HaveLen(10))
+		g.Expect(configHash).Should(MatchRegexp(`^[^013ae]+$`))
+
+		secretHash, err := hashsuffix.Hash(objects[1])
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		config := "config-" + configHash
+		credentials := "credentials-" + secretHash
+
+		result, err := hashsuffix.Suffix()(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(4))
+
+		g.Expect(result[0].GetName()).Should(Equal(config))
+		g.Expect(result[1].GetName()).Should(Equal(credentials))
+
+		deployment := result[2]
+		g.Expect(lookup(deployment, "spec.template.spec.imagePullSecrets[*].name")).Should(Equal([]any{credentials}))
+		g.Expect(lookup(deployment, "spec.template.spec.initContainers[*].envFrom[*].configMapRef.name")).Should(Equal([]any{config}))
+		g.Expect(lookup(deployment, "spec.template.spec.containers[*].env[*].valueFrom.secretKeyRef.name")).Should(Equal([]any{credentials}))
+		g.Expect(lookup(deployment, "spec.template.spec.containers[*].env[*].valueFrom.configMapKeyRef.name")).Should(Equal([]any{"other"}))
+		g.Expect(lookup(deployment, "spec.template.spec.volumes[*].configMap.name")).Should(Equal([]any{config}))
+		g.Expect(lookup(deployment, "spec.template.spec.volumes[*].projected.sources[*].secret.name")).Should(Equal([]any{credentials}))
+
+		// References from other namespaces are not rewritten
+		g.Expect(lookup(result[3], "spec.containers[*].envFrom[*].configMapRef.name")).Should(Equal([]any{"config"}))
+
+		// The input objects are not modified
+		g.Expect(objects[0].GetName()).Should(Equal("config"))
+	})
+
+	t.Run("should change the hash with the content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(manifests))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		before, err := hashsuffix.Hash(objects[0])
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(unstructured.SetNestedField(objects[0].Object, "info", "data", "level")).Should(Succeed())
+
+		after, err := hashsuffix.Hash(objects[0])
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(after).ShouldNot(Equal(before))
+	})
+
+	t.Run("should only rename targeted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(manifests))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		onlyConfigMaps := func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			return obj.GetKind() == "ConfigMap", nil
+		}
+
+		result, err := hashsuffix.Suffix(hashsuffix.WithTarget(onlyConfigMaps))(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result[0].GetName()).ShouldNot(Equal("config"))
+		g.Expect(result[1].GetName()).Should(Equal("credentials"))
+		g.Expect(lookup(result[2], "spec.template.spec.imagePullSecrets[*].name")).Should(Equal([]any{"credentials"}))
+	})
+}
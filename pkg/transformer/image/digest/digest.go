@@ -0,0 +1,260 @@
+// Package digest provides a transformer resolving container image references to immutable digests.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/image"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+)
+
+const (
+	// defaultConcurrency is the default maximum number of concurrent registry requests.
+	defaultConcurrency = 4
+
+	// defaultTag is the tag used when an image reference has neither tag nor digest.
+	defaultTag = "latest"
+
+	// dockerHub is the canonical registry of Docker Hub images.
+	dockerHub = "docker.io"
+
+	// dockerHubAPI is the API endpoint serving the Docker Hub registry.
+	dockerHubAPI = "registry-1.docker.io"
+)
+
+var (
+	// ErrInvalidImage is returned when an image reference cannot be parsed.
+	// It is the image package error, so that both can be matched with errors.Is.
+	ErrInvalidImage = image.ErrInvalidImage
+)
+
+// Resolver resolves image tags to digests by querying the registries, like kbld.
+// Resolved digests are memoized, concurrent lookups of the same image are deduplicated
+// and the number of in-flight registry requests is bounded.
+//
+// Thread-safety: Resolver is safe for concurrent use.
+type Resolver struct {
+	auth  registry.Auth
+	sem   *semaphore.Weighted
+	group singleflight.Group
+
+	mu      sync.RWMutex
+	digests map[string]string
+}
+
+// Option is a generic option for Resolver.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple resolver options at once.
+type Options struct {
+	// Auth holds the registry credentials and transport settings.
+	Auth registry.Auth
+
+	// Concurrency bounds the number of concurrent registry requests.
+	Concurrency int
+}
+
+// ApplyTo applies the resolver options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Auth = opts.Auth
+
+	if opts.Concurrency > 0 {
+		target.Concurrency = opts.Concurrency
+	}
+}
+
+// WithRegistryAuth sets the credentials and transport settings used to query registries.
+func WithRegistryAuth(auth registry.Auth) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Auth = auth
+	})
+}
+
+// WithConcurrency bounds the number of concurrent registry requests.
+// Default: 4.
+func WithConcurrency(n int) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Concurrency = n
+	})
+}
+
+// NewResolver creates a new Resolver with the given options.
+func NewResolver(opts ...Option) *Resolver {
+	options := Options{
+		Concurrency: defaultConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaultConcurrency
+	}
+
+	return &Resolver{
+		auth:    options.Auth,
+		sem:     semaphore.NewWeighted(int64(options.Concurrency)),
+		digests: make(map[string]string),
+	}
+}
+
+// Resolve returns the image reference pinned to its digest, in the form name@sha256:...
+// References already pinned to a digest are returned unchanged.
+// Concurrent lookups of the same image share a single registry request, which keeps running for
+// the other callers when one of them is canceled.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	parsed, err := image.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if parsed.Digest != "" {
+		return ref, nil
+	}
+
+	if parsed.Tag == "" {
+		parsed.Tag = defaultTag
+	}
+
+	canonical := parsed.Canonical()
+	key := canonical.String()
+
+	r.mu.RLock()
+	resolved, ok := r.digests[key]
+	r.mu.RUnlock()
+
+	if !ok {
+		// The lookup is shared, so it must not be bound to the context of the first caller
+		ch := r.group.DoChan(key, func() (any, error) {
+			digest, err := r.lookup(context.WithoutCancel(ctx), canonical)
+			if err != nil {
+				return "", err
+			}
+
+			// Memoize before the flight ends, so that later callers don't start another one
+			r.mu.Lock()
+			r.digests[key] = digest
+			r.mu.Unlock()
+
+			return digest, nil
+		})
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("failed to resolve image %s: %w", ref, ctx.Err())
+		case result := <-ch:
+			if result.Err != nil {
+				return "", fmt.Errorf("failed to resolve image %s: %w", ref, result.Err)
+			}
+
+			resolved, _ = result.Val.(string)
+		}
+	}
+
+	parsed.Tag, parsed.Digest = "", resolved
+
+	return parsed.String(), nil
+}
+
+// Transformer returns a transformer replacing the images of all containers with their digest form.
+// The images of an object are resolved concurrently, bounded by the resolver concurrency.
+func (r *Resolver) Transformer() types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		containers := make([]map[string]any, 0)
+
+		err := k8s.VisitContainers(obj.Object, func(container map[string]any) error {
+			if ref, ok := container["image"].(string); ok && ref != "" {
+				containers = append(containers, container)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		resolved := make([]string, len(containers))
+
+		g, gctx := errgroup.WithContext(ctx)
+
+		for i := range containers {
+			ref, _ := containers[i]["image"].(string)
+
+			g.Go(func() error {
+				result, err := r.Resolve(gctx, ref)
+				if err != nil {
+					return err
+				}
+
+				resolved[i] = result
+
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		for i := range containers {
+			containers[i]["image"] = resolved[i]
+		}
+
+		return obj, nil
+	}
+}
+
+// Resolve creates a transformer pinning container images to their digests.
+// It is a shortcut for NewResolver(opts...).Transformer().
+func Resolve(opts ...Option) types.Transformer {
+	return NewResolver(opts...).Transformer()
+}
+
+// lookup queries the registry for the digest of the tag of a canonical reference.
+func (r *Resolver) lookup(ctx context.Context, ref image.Reference) (string, error) {
+	if err := r.sem.Acquire(ctx, 1); err != nil {
+		return "", err
+	}
+	defer r.sem.Release(1)
+
+	repo, err := registry.NewRepository(repositoryReference(ref), r.auth)
+	if err != nil {
+		return "", err
+	}
+
+	desc, err := repo.Resolve(ctx, ref.Tag)
+	if err != nil {
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// repositoryReference returns the registry/repository reference of a canonical reference,
+// addressing Docker Hub images through the Docker Hub API endpoint.
+func repositoryReference(ref image.Reference) string {
+	if ref.Registry == dockerHub {
+		ref.Registry = dockerHubAPI
+	}
+
+	return ref.Name()
+}
@@ -0,0 +1,253 @@
+package digest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/image"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/image/digest"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/registry"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	appDigest     = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+	sidecarDigest = "sha256:b5b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7"
+)
+
+// newRegistry starts a registry serving manifest digests for the given repository tags.
+func newRegistry(t *testing.T, digests map[string]string, requests *atomic.Int32) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		repo, ref, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		if !found {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		d, ok := digests[repo+":"+ref]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", d)
+		w.Header().Set("Content-Length", "2")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func newDeployment(images ...string) unstructured.Unstructured {
+	containers := make([]any, 0, len(images))
+	for _, img := range images {
+		containers = append(containers, map[string]any{"name": "c", "image": img})
+	}
+
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "web"},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": containers,
+					},
+				},
+			},
+		},
+	}
+}
+
+func images(obj unstructured.Unstructured) []string {
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+
+	result := make([]string, 0, len(containers))
+	for _, c := range containers {
+		result = append(result, c.(map[string]any)["image"].(string))
+	}
+
+	return result
+}
+
+func TestResolve(t *testing.T) {
+	t.Run("should pin images to digests", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		host := newRegistry(t, map[string]string{
+			"team/app:v1":         appDigest,
+			"team/sidecar:latest": sidecarDigest,
+		}, &requests)
+
+		transform := digest.Resolve(digest.WithRegistryAuth(registry.Auth{PlainHTTP: true}))
+
+		obj := newDeployment(host+"/team/app:v1", host+"/team/sidecar", host+"/team/app:v1")
+
+		result, err := transform(t.Context(), obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(images(result)).To(Equal([]string{
+			host + "/team/app@" + appDigest,
+			host + "/team/sidecar@" + sidecarDigest,
+			host + "/team/app@" + appDigest,
+		}))
+
+		// Resolved digests are memoized
+		g.Expect(requests.Load()).To(BeEquivalentTo(2))
+	})
+
+	t.Run("should keep images already pinned", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		host := newRegistry(t, map[string]string{}, &requests)
+
+		ref := host + "/team/app:v1@" + appDigest
+
+		result, err := digest.Resolve()(t.Context(), newDeployment(ref))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(images(result)).To(Equal([]string{ref}))
+		g.Expect(requests.Load()).To(BeZero())
+	})
+
+	t.Run("should fail on unknown images", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		host := newRegistry(t, map[string]string{}, &requests)
+
+		transform := digest.Resolve(
+			digest.WithRegistryAuth(registry.Auth{PlainHTTP: true}),
+			digest.WithConcurrency(1),
+		)
+
+		_, err := transform(t.Context(), newDeployment(host+"/team/missing:v1"))
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should resolve the images of an object concurrently", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var running atomic.Int32
+		var peak atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", appDigest)
+			w.Header().Set("Content-Length", "2")
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		host := strings.TrimPrefix(server.URL, "http://")
+
+		transform := digest.Resolve(
+			digest.WithRegistryAuth(registry.Auth{PlainHTTP: true}),
+			digest.WithConcurrency(2),
+		)
+
+		obj := newDeployment(host+"/team/a:v1", host+"/team/b:v1", host+"/team/c:v1", host+"/team/d:v1")
+
+		result, err := transform(t.Context(), obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(images(result)).To(Equal([]string{
+			host + "/team/a@" + appDigest,
+			host + "/team/b@" + appDigest,
+			host + "/team/c@" + appDigest,
+			host + "/team/d@" + appDigest,
+		}))
+		g.Expect(peak.Load()).To(BeEquivalentTo(2))
+	})
+
+	t.Run("should not fail shared lookups when a caller is canceled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var requests atomic.Int32
+		received := make(chan struct{})
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				close(received)
+			}
+
+			<-release
+
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", appDigest)
+			w.Header().Set("Content-Length", "2")
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		ref := strings.TrimPrefix(server.URL, "http://") + "/team/app:v1"
+		resolver := digest.NewResolver(digest.WithRegistryAuth(registry.Auth{PlainHTTP: true}))
+
+		ctx, cancel := context.WithCancel(t.Context())
+		canceled := make(chan error, 1)
+
+		go func() {
+			_, err := resolver.Resolve(ctx, ref)
+			canceled <- err
+		}()
+
+		<-received
+
+		resolved := make(chan string, 1)
+
+		go func() {
+			result, err := resolver.Resolve(t.Context(), ref)
+			if err != nil {
+				result = err.Error()
+			}
+			resolved <- result
+		}()
+
+		cancel()
+		g.Expect(<-canceled).To(MatchError(context.Canceled))
+
+		close(release)
+		g.Expect(<-resolved).To(HaveSuffix("/team/app@" + appDigest))
+		g.Expect(requests.Load()).To(BeEquivalentTo(1))
+	})
+
+	t.Run("should reject invalid references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := digest.NewResolver().Resolve(t.Context(), "invalid image")
+		g.Expect(err).To(MatchError(digest.ErrInvalidImage))
+		g.Expect(err).To(MatchError(image.ErrInvalidImage))
+	})
+}
@@ -0,0 +1,223 @@
+// Package image provides transformers rewriting the container images of workloads, e.g. to
+// redirect them to an air-gapped mirror, override their tags or pin them to known digests.
+//
+// Images are rewritten in every container list of an object (containers, initContainers and
+// ephemeralContainers) of Pods, workload pod templates, CronJob job templates and custom
+// resources embedding pod specs. To resolve digests from registries, see the digest package.
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const (
+	// dockerHub is the registry implied by image references without a registry host.
+	dockerHub = "docker.io"
+
+	// dockerHubIndex is the legacy host of the Docker Hub registry.
+	dockerHubIndex = "index.docker.io"
+
+	// dockerHubLibrary is the namespace implied by single component Docker Hub repositories.
+	dockerHubLibrary = "library/"
+)
+
+var (
+	// ErrInvalidImage is returned when an image reference cannot be parsed.
+	ErrInvalidImage = errors.New("invalid image reference")
+)
+
+// Reference is a parsed container image reference: [registry/]repository[:tag][@digest].
+type Reference struct {
+	// Registry is the registry host, empty when implied, e.g. for Docker Hub images like nginx.
+	Registry string
+
+	// Repository is the repository within the registry, e.g. library/nginx or org/app.
+	Repository string
+
+	// Tag is the tag, if any.
+	Tag string
+
+	// Digest is the digest, if any, e.g. sha256:...
+	Digest string
+}
+
+// Parse parses an image reference.
+func Parse(image string) (Reference, error) {
+	name, digest, _ := strings.Cut(image, "@")
+
+	ref := Reference{Digest: digest}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+
+	if host, path, found := strings.Cut(name, "/"); found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry, name = host, path
+	}
+
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return Reference{}, fmt.Errorf("%w: %q", ErrInvalidImage, image)
+	}
+
+	ref.Repository = name
+
+	return ref, nil
+}
+
+// Name returns the image name, without tag and digest.
+func (r Reference) Name() string {
+	if r.Registry == "" {
+		return r.Repository
+	}
+
+	return r.Registry + "/" + r.Repository
+}
+
+// Canonical returns the reference with the Docker Hub defaults made explicit: images without a
+// registry host, or hosted by index.docker.io, belong to docker.io, and single component Docker
+// Hub repositories to the library namespace, so that nginx, docker.io/nginx and
+// docker.io/library/nginx all have the same canonical form.
+func (r Reference) Canonical() Reference {
+	if r.Registry != "" && r.Registry != dockerHub && r.Registry != dockerHubIndex {
+		return r
+	}
+
+	r.Registry = dockerHub
+
+	if !strings.Contains(r.Repository, "/") {
+		r.Repository = dockerHubLibrary + r.Repository
+	}
+
+	return r
+}
+
+// String returns the image reference.
+func (r Reference) String() string {
+	s := r.Name()
+
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+
+	return s
+}
+
+// Image is a rewrite rule for the images with a given name, like the images field of a
+// Kustomization.
+type Image struct {
+	// Name selects the images to rewrite by name, as written in the manifests and without tag
+	// or digest, e.g. nginx or quay.io/org/app.
+	Name string
+
+	// NewName replaces the name of the image, keeping its tag and digest unless overridden.
+	NewName string
+
+	// NewTag replaces the tag of the image and drops its digest.
+	NewTag string
+
+	// Digest pins the image to a digest and drops its tag. It takes precedence over NewTag.
+	Digest string
+}
+
+// Set returns a transformer applying the rewrite rules to the images matching their names.
+// When several rules match an image, the first one wins.
+//
+// Example:
+//
+//	image.Set(
+//	    image.Image{Name: "nginx", NewTag: "1.27"},
+//	    image.Image{Name: "quay.io/org/app", NewName: "mirror.local/org/app", Digest: "sha256:..."},
+//	)
+func Set(images ...Image) types.Transformer {
+	return Rewrite(func(ref Reference) Reference {
+		for _, image := range images {
+			if image.Name != ref.Name() {
+				continue
+			}
+
+			if image.NewName != "" {
+				ref.Registry, ref.Repository = "", image.NewName
+			}
+
+			switch {
+			case image.Digest != "":
+				ref.Tag, ref.Digest = "", image.Digest
+			case image.NewTag != "":
+				ref.Tag, ref.Digest = image.NewTag, ""
+			}
+
+			break
+		}
+
+		return ref
+	})
+}
+
+// Mirror returns a transformer redirecting images to mirrors, keyed by the source registry
+// host. Mirrors can include a path prefix, and Docker Hub images are matched in their canonical
+// form, so that nginx and docker.io/nginx are both redirected to <mirror>/library/nginx by a
+// docker.io key.
+//
+// Example:
+//
+//	image.Mirror(map[string]string{
+//	    "docker.io": "mirror.local/dockerhub",
+//	    "quay.io":   "mirror.local/quay",
+//	})
+func Mirror(mirrors map[string]string) types.Transformer {
+	return Rewrite(func(ref Reference) Reference {
+		canonical := ref.Canonical()
+
+		mirror, ok := mirrors[canonical.Registry]
+		if !ok {
+			return ref
+		}
+
+		canonical.Registry = strings.TrimSuffix(mirror, "/")
+
+		return canonical
+	})
+}
+
+// Rewrite returns a transformer replacing every container image with the reference returned by
+// fn, for custom rewrites.
+func Rewrite(fn func(ref Reference) Reference) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		err := k8s.VisitContainers(obj.Object, func(container map[string]any) error {
+			image, ok := container["image"].(string)
+			if !ok || image == "" {
+				return nil
+			}
+
+			ref, err := Parse(image)
+			if err != nil {
+				return err
+			}
+
+			container["image"] = fn(ref).String()
+
+			return nil
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
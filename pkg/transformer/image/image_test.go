@@ -0,0 +1,213 @@
+package image_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/image"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+
+	. "github.com/onsi/gomega"
+)
+
+const appDigest = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+
+// makeCronJob returns a CronJob with an init container running the first image and containers
+// running the others.
+func makeCronJob(images ...string) unstructured.Unstructured {
+	containers := make([]any, 0, len(images))
+	for _, img := range images {
+		containers = append(containers, map[string]any{"name": "c", "image": img})
+	}
+
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "batch/v1",
+			"kind":       "CronJob",
+			"metadata":   map[string]any{"name": "job"},
+			"spec": map[string]any{
+				"jobTemplate": map[string]any{
+					"spec": map[string]any{
+						"template": map[string]any{
+							"spec": map[string]any{
+								"initContainers": containers[:1],
+								"containers":     containers[1:],
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func transformImages(t *testing.T, g *WithT, tr types.Transformer, images ...string) []string {
+	t.Helper()
+
+	result, err := tr(t.Context(), makeCronJob(images...))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	refs := make([]string, 0, len(images))
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, err := unstructured.NestedSlice(
+			result.Object, "spec", "jobTemplate", "spec", "template", "spec", field,
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		for _, c := range containers {
+			container, ok := c.(map[string]any)
+			g.Expect(ok).Should(BeTrue())
+
+			refs = append(refs, container["image"].(string))
+		}
+	}
+
+	return refs
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected image.Reference
+	}{
+		{image: "nginx", expected: image.Reference{Repository: "nginx"}},
+		{image: "nginx:1.27", expected: image.Reference{Repository: "nginx", Tag: "1.27"}},
+		{image: "org/app@" + appDigest, expected: image.Reference{Repository: "org/app", Digest: appDigest}},
+		{
+			image:    "localhost:5000/app:1.0@" + appDigest,
+			expected: image.Reference{Registry: "localhost:5000", Repository: "app", Tag: "1.0", Digest: appDigest},
+		},
+		{image: "quay.io/org/app:v1", expected: image.Reference{Registry: "quay.io", Repository: "org/app", Tag: "v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ref, err := image.Parse(tt.image)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ref).Should(Equal(tt.expected))
+			g.Expect(ref.String()).Should(Equal(tt.image))
+		})
+	}
+
+	t.Run("should reject invalid references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := image.Parse("quay.io/:v1")
+		g.Expect(err).Should(MatchError(image.ErrInvalidImage))
+	})
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		images   []image.Image
+		input    []string
+		expected []string
+	}{
+		{
+			name:     "should override tags and drop digests",
+			images:   []image.Image{{Name: "nginx", NewTag: "1.27"}},
+			input:    []string{"nginx:1.25@" + appDigest, "busybox:1.0"},
+			expected: []string{"nginx:1.27", "busybox:1.0"},
+		},
+		{
+			name:     "should pin digests and drop tags",
+			images:   []image.Image{{Name: "quay.io/org/app", Digest: appDigest}},
+			input:    []string{"busybox", "quay.io/org/app:v1"},
+			expected: []string{"busybox", "quay.io/org/app@" + appDigest},
+		},
+		{
+			name:     "should rename images keeping their tags",
+			images:   []image.Image{{Name: "quay.io/org/app", NewName: "mirror.local/app"}},
+			input:    []string{"quay.io/org/app:v1", "quay.io/org/other:v1"},
+			expected: []string{"mirror.local/app:v1", "quay.io/org/other:v1"},
+		},
+		{
+			name: "should apply the first matching rule",
+			images: []image.Image{
+				{Name: "nginx", NewTag: "first"},
+				{Name: "nginx", NewTag: "second"},
+			},
+			input:    []string{"nginx", "nginx:1.0"},
+			expected: []string{"nginx:first", "nginx:first"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(transformImages(t, g, image.Set(tt.images...), tt.input...)).Should(Equal(tt.expected))
+		})
+	}
+}
+
+func TestMirror(t *testing.T) {
+	g := NewWithT(t)
+
+	tr := image.Mirror(map[string]string{
+		"docker.io": "mirror.local/dockerhub/",
+		"quay.io":   "mirror.local/quay",
+	})
+
+	result := transformImages(t, g, tr,
+		"nginx:1.27",
+		"bitnami/redis@"+appDigest,
+		"docker.io/nginx:1.26",
+		"index.docker.io/library/busybox",
+		"quay.io/org/app:v1",
+		"ghcr.io/org/tool:v2",
+	)
+
+	g.Expect(result).Should(Equal([]string{
+		"mirror.local/dockerhub/library/nginx:1.27",
+		"mirror.local/dockerhub/bitnami/redis@" + appDigest,
+		"mirror.local/dockerhub/library/nginx:1.26",
+		"mirror.local/dockerhub/library/busybox",
+		"mirror.local/quay/org/app:v1",
+		"ghcr.io/org/tool:v2",
+	}))
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected string
+	}{
+		{image: "nginx", expected: "docker.io/library/nginx"},
+		{image: "docker.io/nginx:1.27", expected: "docker.io/library/nginx:1.27"},
+		{image: "index.docker.io/bitnami/redis", expected: "docker.io/bitnami/redis"},
+		{image: "quay.io/app:v1", expected: "quay.io/app:v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ref, err := image.Parse(tt.image)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(ref.Canonical().String()).Should(Equal(tt.expected))
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	t.Run("should fail on invalid images", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := image.Rewrite(func(ref image.Reference) image.Reference {
+			return ref
+		})
+
+		_, err := tr(t.Context(), makeCronJob("busybox", "quay.io/:v1"))
+		g.Expect(err).Should(MatchError(image.ErrInvalidImage))
+
+		var transformerErr *transformer.Error
+		g.Expect(err).Should(BeAssignableToTypeOf(transformerErr))
+	})
+}
@@ -0,0 +1,47 @@
+package krm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	utilkrm "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/krm"
+)
+
+var (
+	// ErrUnexpectedOutput is returned when a function does not return exactly one object.
+	ErrUnexpectedOutput = errors.New("function must return exactly one object")
+)
+
+// Transform creates a transformer running a KRM function on each object.
+// The function receives a ResourceList holding the single object and must return exactly one object.
+// Functions operating on the whole set of objects, such as generators, should be run with the
+// krm renderer instead.
+func Transform(fn utilkrm.Function) (types.Transformer, error) {
+	if err := fn.Validate(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		result, err := utilkrm.Run(ctx, fn, []unstructured.Unstructured{obj})
+		if err != nil {
+			return unstructured.Unstructured{}, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		if len(result) != 1 {
+			return unstructured.Unstructured{}, &transformer.Error{
+				Object: obj,
+				Err:    fmt.Errorf("%w, got %d", ErrUnexpectedOutput, len(result)),
+			}
+		}
+
+		return result[0], nil
+	}, nil
+}
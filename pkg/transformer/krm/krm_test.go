@@ -0,0 +1,90 @@
+package krm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/krm"
+	utilkrm "github.com/lburgazzoli/k8s-manifests-lib/pkg/util/krm"
+
+	. "github.com/onsi/gomega"
+)
+
+// labelFn is a KRM function that adds a label to every resource.
+const labelFn = `#!/bin/sh
+sed 's/^\(  *\)name: \(.*\)$/\1name: \2\n\1labels:\n\1  fn: applied/'
+`
+
+// splitFn is a KRM function that replaces its input with two resources.
+const splitFn = `#!/bin/sh
+cat > /dev/null
+cat <<'EOF'
+apiVersion: config.kubernetes.io/v1
+kind: ResourceList
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: second
+EOF
+`
+
+func writeFunction(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "fn.sh")
+	if err := os.WriteFile(path, []byte(content), 0o700); err != nil { //nolint:gosec // test function must be executable
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newConfigMap() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "config"},
+			"data":       map[string]any{"key": "value"},
+		},
+	}
+}
+
+func TestTransform(t *testing.T) {
+	t.Run("should run the function on the object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transform, err := krm.Transform(utilkrm.Function{Exec: writeFunction(t, labelFn)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := transform(t.Context(), newConfigMap())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.GetName()).To(Equal("config"))
+		g.Expect(result.GetLabels()).To(HaveKeyWithValue("fn", "applied"))
+	})
+
+	t.Run("should fail when the function returns multiple objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		transform, err := krm.Transform(utilkrm.Function{Exec: writeFunction(t, splitFn)})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = transform(t.Context(), newConfigMap())
+		g.Expect(err).To(MatchError(krm.ErrUnexpectedOutput))
+	})
+
+	t.Run("should reject invalid functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := krm.Transform(utilkrm.Function{})
+		g.Expect(err).To(MatchError(utilkrm.ErrInvalidFunction))
+	})
+}
@@ -0,0 +1,44 @@
+package annotations
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Propagate returns a transformer that adds or updates annotations on objects and on the pod
+// templates they hold, e.g. the template of a Deployment or the job template of a CronJob, so
+// the annotations reach the pods as well. Changing the annotations of a pod template rolls out
+// new pods.
+func Propagate(annotationsToApply map[string]string) types.Transformer {
+	set := Set(annotationsToApply)
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		obj, err := set(ctx, obj)
+		if err != nil {
+			return obj, err
+		}
+
+		err = k8s.VisitPodTemplates(obj.Object, func(template map[string]any) error {
+			current, _, err := unstructured.NestedStringMap(template, "metadata", "annotations")
+			if err != nil {
+				return fmt.Errorf("failed to read pod template annotations: %w", err)
+			}
+
+			if current == nil {
+				current = make(map[string]string)
+			}
+
+			maps.Copy(current, annotationsToApply)
+
+			return unstructured.SetNestedStringMap(template, current, "metadata", "annotations")
+		})
+
+		return obj, err
+	}
+}
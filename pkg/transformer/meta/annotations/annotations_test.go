@@ -6,6 +6,7 @@ import (
 	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
 	"github.com/onsi/gomega/types"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -187,3 +188,33 @@ func TestRemoveIf(t *testing.T) {
 		g.Expect(transformed.GetAnnotations()).Should(Equal(map[string]string{"key": "value"}))
 	})
 }
+
+func TestPropagate(t *testing.T) {
+	g := NewWithT(t)
+
+	cronJob := &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		Spec: batchv1.CronJobSpec{
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"existing": "value"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "backup", Image: "restic/restic"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	transformer := annotations.Propagate(map[string]string{"rendered-by": "k8s-manifests-lib"})
+	transformed, err := transformer(t.Context(), toUnstructured(t, cronJob))
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(transformed.Object).To(And(
+		jqmatcher.Match(`.metadata.annotations["rendered-by"] == "k8s-manifests-lib"`),
+		jqmatcher.Match(`.spec.jobTemplate.spec.template.metadata.annotations["rendered-by"] == "k8s-manifests-lib"`),
+		jqmatcher.Match(`.spec.jobTemplate.spec.template.metadata.annotations["existing"] == "value"`),
+	))
+}
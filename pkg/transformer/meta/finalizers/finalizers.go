@@ -0,0 +1,49 @@
+package finalizers
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+// Add returns a transformer that adds finalizers to objects. Finalizers already present are not
+// duplicated.
+func Add(finalizersToAdd ...string) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		values := obj.GetFinalizers()
+
+		for _, finalizer := range finalizersToAdd {
+			if !slices.Contains(values, finalizer) {
+				values = append(values, finalizer)
+			}
+		}
+
+		obj.SetFinalizers(values)
+
+		return obj, nil
+	}
+}
+
+// Remove returns a transformer that removes specific finalizers from objects.
+func Remove(finalizersToRemove ...string) types.Transformer {
+	return RemoveIf(func(finalizer string) bool {
+		return slices.Contains(finalizersToRemove, finalizer)
+	})
+}
+
+// RemoveIf returns a transformer that removes finalizers matching a predicate.
+func RemoveIf(predicate func(finalizer string) bool) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		values := obj.GetFinalizers()
+		if values == nil {
+			return obj, nil
+		}
+
+		obj.SetFinalizers(slices.DeleteFunc(values, predicate))
+
+		return obj, nil
+	}
+}
@@ -0,0 +1,68 @@
+package finalizers_test
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/finalizers"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeObject(values ...string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Database",
+			"metadata":   map[string]any{"name": "db"},
+		},
+	}
+
+	obj.SetFinalizers(values)
+
+	return obj
+}
+
+func TestFinalizers(t *testing.T) {
+	t.Run("should add finalizers without duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := finalizers.Add("example.com/cleanup", "example.com/backup")
+
+		result, err := tr(t.Context(), makeObject("example.com/cleanup"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GetFinalizers()).Should(Equal([]string{"example.com/cleanup", "example.com/backup"}))
+	})
+
+	t.Run("should remove finalizers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := finalizers.Remove("example.com/cleanup")(t.Context(), makeObject("example.com/cleanup", "other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GetFinalizers()).Should(Equal([]string{"other"}))
+	})
+
+	t.Run("should remove finalizers matching a predicate", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := finalizers.RemoveIf(func(finalizer string) bool {
+			return strings.HasPrefix(finalizer, "example.com/")
+		})
+
+		result, err := tr(t.Context(), makeObject("example.com/cleanup", "example.com/backup", "other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GetFinalizers()).Should(Equal([]string{"other"}))
+	})
+
+	t.Run("should leave objects without finalizers unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeObject()
+
+		result, err := finalizers.Remove("example.com/cleanup")(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(obj))
+	})
+}
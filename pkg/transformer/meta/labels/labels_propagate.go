@@ -0,0 +1,110 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the Propagate transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple propagation options at once.
+type Options struct {
+	// Selectors also adds the labels to the selectors of workloads and Services.
+	Selectors bool
+}
+
+// ApplyTo applies the propagation options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Selectors = opts.Selectors
+}
+
+// WithSelectors also adds the labels to the existing selectors of Services, ReplicationControllers,
+// Deployments, ReplicaSets, StatefulSets and DaemonSets, like Kustomize commonLabels.
+// Selectors of workloads are immutable, so enabling this on already deployed objects makes
+// their update fail.
+// Default: false, selectors are left unchanged.
+func WithSelectors(selectors bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Selectors = selectors
+	})
+}
+
+// Propagate returns a transformer that adds or updates labels on objects and on the pod templates
+// they hold, e.g. the template of a Deployment or the job template of a CronJob, so the labels
+// reach the pods as well.
+func Propagate(labelsToApply map[string]string, opts ...Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	set := Set(labelsToApply)
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		obj, err := set(ctx, obj)
+		if err != nil {
+			return obj, err
+		}
+
+		err = k8s.VisitPodTemplates(obj.Object, func(template map[string]any) error {
+			return merge(template, labelsToApply, "metadata", "labels")
+		})
+		if err != nil {
+			return obj, err
+		}
+
+		if !options.Selectors {
+			return obj, nil
+		}
+
+		switch obj.GroupVersionKind().GroupKind() {
+		case corev1.SchemeGroupVersion.WithKind("Service").GroupKind(),
+			corev1.SchemeGroupVersion.WithKind("ReplicationController").GroupKind():
+			err = mergeExisting(obj.Object, labelsToApply, "spec", "selector")
+		case appsv1.SchemeGroupVersion.WithKind("Deployment").GroupKind(),
+			appsv1.SchemeGroupVersion.WithKind("ReplicaSet").GroupKind(),
+			appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind(),
+			appsv1.SchemeGroupVersion.WithKind("DaemonSet").GroupKind():
+			err = mergeExisting(obj.Object, labelsToApply, "spec", "selector", "matchLabels")
+		}
+
+		return obj, err
+	}
+}
+
+// merge adds values to the string map at fields, creating it if missing.
+func merge(content map[string]any, values map[string]string, fields ...string) error {
+	current, _, err := unstructured.NestedStringMap(content, fields...)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", strings.Join(fields, "."), err)
+	}
+
+	if current == nil {
+		current = make(map[string]string)
+	}
+
+	maps.Copy(current, values)
+
+	return unstructured.SetNestedStringMap(content, current, fields...)
+}
+
+// mergeExisting adds values to the string map at fields, only if it exists.
+func mergeExisting(content map[string]any, values map[string]string, fields ...string) error {
+	if _, found, _ := unstructured.NestedFieldNoCopy(content, fields...); !found {
+		return nil
+	}
+
+	return merge(content, values, fields...)
+}
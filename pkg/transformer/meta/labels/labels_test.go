@@ -6,6 +6,7 @@ import (
 	jqmatcher "github.com/lburgazzoli/gomega-matchers/pkg/matchers/jq"
 	"github.com/onsi/gomega/types"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -187,3 +188,99 @@ func TestRemoveIf(t *testing.T) {
 		g.Expect(transformed.GetLabels()).Should(Equal(map[string]string{"key": "value"}))
 	})
 }
+
+func TestPropagate(t *testing.T) {
+	deployment := func() runtime.Object {
+		return &appsv1.Deployment{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web"}},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		options     []labels.Option
+		inputObject runtime.Object
+		expected    types.GomegaMatcher
+	}{
+		{
+			name:        "should add labels to the object and the pod template",
+			inputObject: deployment(),
+			expected: And(
+				jqmatcher.Match(`.metadata.labels["team"] == "platform"`),
+				jqmatcher.Match(`.spec.template.metadata.labels["team"] == "platform"`),
+				jqmatcher.Match(`.spec.template.metadata.labels["app"] == "web"`),
+				jqmatcher.Match(`.spec.selector.matchLabels | has("team") | not`),
+			),
+		},
+		{
+			name:        "should add labels to workload selectors",
+			options:     []labels.Option{labels.WithSelectors(true)},
+			inputObject: deployment(),
+			expected: And(
+				jqmatcher.Match(`.spec.template.metadata.labels["team"] == "platform"`),
+				jqmatcher.Match(`.spec.selector.matchLabels["team"] == "platform"`),
+				jqmatcher.Match(`.spec.selector.matchLabels["app"] == "web"`),
+			),
+		},
+		{
+			name:    "should add labels to Service selectors",
+			options: []labels.Option{labels.WithSelectors(true)},
+			inputObject: &corev1.Service{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				Spec: corev1.ServiceSpec{
+					Selector: map[string]string{"app": "web"},
+				},
+			},
+			expected: And(
+				jqmatcher.Match(`.metadata.labels["team"] == "platform"`),
+				jqmatcher.Match(`.spec.selector["team"] == "platform"`),
+			),
+		},
+		{
+			name:    "should not add selectors to Services without one",
+			options: []labels.Option{labels.WithSelectors(true)},
+			inputObject: &corev1.Service{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				Spec: corev1.ServiceSpec{
+					Type:         corev1.ServiceTypeExternalName,
+					ExternalName: "example.com",
+				},
+			},
+			expected: jqmatcher.Match(`.spec | has("selector") | not`),
+		},
+		{
+			name:    "should only set object labels on Pods",
+			options: []labels.Option{labels.WithSelectors(true)},
+			inputObject: &corev1.Pod{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "web", Image: "nginx"}},
+				},
+			},
+			expected: And(
+				jqmatcher.Match(`.metadata.labels["team"] == "platform"`),
+				jqmatcher.Match(`.spec | has("metadata") | not`),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			transformer := labels.Propagate(map[string]string{"team": "platform"}, tt.options...)
+			transformed, err := transformer(t.Context(), toUnstructured(t, tt.inputObject))
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(transformed.Object).To(tt.expected)
+		})
+	}
+}
@@ -3,9 +3,13 @@ package namespace
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
 )
 
 // Set returns a transformer that sets the namespace on all objects.
@@ -28,3 +32,138 @@ func EnsureDefault(namespace string) types.Transformer {
 		return obj, nil
 	}
 }
+
+// Option is a generic option for the namespace mapping transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple namespace mapping options at once.
+type Options struct {
+	// References also remaps the namespaces referenced within known kinds.
+	References bool
+}
+
+// ApplyTo applies the namespace mapping options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.References = opts.References
+}
+
+// WithReferences also remaps the namespaces referenced within objects: subjects of RoleBindings
+// and ClusterRoleBindings, namespace selectors of ServiceMonitors and PodMonitors, and services of
+// webhook configurations and APIServices.
+// Default: false, only the namespace of the objects is remapped.
+func WithReferences(references bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.References = references
+	})
+}
+
+// Map returns a transformer that moves objects from the namespaces in the keys of the mapping to
+// the corresponding values. Namespace objects named after a key are renamed as well; objects in
+// other namespaces are left unchanged.
+//
+// Example:
+//
+//	namespace.Map(map[string]string{
+//	    "monitoring": "observability",
+//	    "default":    "shop",
+//	}, namespace.WithReferences(true))
+func Map(mapping map[string]string, opts ...Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	remap := func(namespace string) string {
+		if target, ok := mapping[namespace]; ok {
+			return target
+		}
+
+		return namespace
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(remap(obj.GetNamespace()))
+		}
+
+		if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Namespace") {
+			obj.SetName(remap(obj.GetName()))
+		}
+
+		if options.References {
+			if err := remapReferences(obj, remap); err != nil {
+				return obj, &transformer.Error{
+					Object: obj,
+					Err:    err,
+				}
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// Rename returns a transformer that moves objects from one namespace to another.
+// It is a shortcut for Map with a single entry.
+func Rename(from string, to string, opts ...Option) types.Transformer {
+	return Map(map[string]string{from: to}, opts...)
+}
+
+// remapReferences remaps the namespaces referenced within the known kinds.
+func remapReferences(obj unstructured.Unstructured, remap func(string) string) error {
+	gk := obj.GroupVersionKind().GroupKind()
+
+	switch gk {
+	case schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"},
+		schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:
+		return remapList(obj.Object, []string{"subjects"}, []string{"namespace"}, remap)
+	case schema.GroupKind{Group: "monitoring.coreos.com", Kind: "ServiceMonitor"},
+		schema.GroupKind{Group: "monitoring.coreos.com", Kind: "PodMonitor"}:
+		names, found, err := unstructured.NestedStringSlice(obj.Object, "spec", "namespaceSelector", "matchNames")
+		if err != nil || !found {
+			return err
+		}
+
+		for i := range names {
+			names[i] = remap(names[i])
+		}
+
+		return unstructured.SetNestedStringSlice(obj.Object, names, "spec", "namespaceSelector", "matchNames")
+	case schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+		schema.GroupKind{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:
+		return remapList(obj.Object, []string{"webhooks"}, []string{"clientConfig", "service", "namespace"}, remap)
+	case schema.GroupKind{Group: "apiregistration.k8s.io", Kind: "APIService"}:
+		return remapField(obj.Object, []string{"spec", "service", "namespace"}, remap)
+	default:
+		return nil
+	}
+}
+
+// remapList remaps the namespace field of every item of a list.
+func remapList(content map[string]any, list []string, field []string, remap func(string) string) error {
+	items, found, err := unstructured.NestedSlice(content, list...)
+	if err != nil || !found {
+		return err
+	}
+
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			if err := remapField(m, field, remap); err != nil {
+				return err
+			}
+		}
+	}
+
+	return unstructured.SetNestedSlice(content, items, list...)
+}
+
+// remapField remaps a namespace field, if set.
+func remapField(content map[string]any, field []string, remap func(string) string) error {
+	namespace, found, err := unstructured.NestedString(content, field...)
+	if err != nil || !found {
+		return err
+	}
+
+	return unstructured.SetNestedField(content, remap(namespace), field...)
+}
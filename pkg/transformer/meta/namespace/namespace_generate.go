@@ -0,0 +1,136 @@
+package namespace
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// GenerateOption is a generic option for the Namespace generator.
+type GenerateOption = util.Option[GenerateOptions]
+
+// GenerateOptions is a struct-based option that can set multiple Namespace generator options at once.
+type GenerateOptions struct {
+	// Labels are set on the generated Namespaces.
+	Labels map[string]string
+
+	// Annotations are set on the generated Namespaces.
+	Annotations map[string]string
+
+	// Exclude lists the namespaces never generated.
+	Exclude []string
+}
+
+// ApplyTo applies the Namespace generator options to the target configuration.
+func (opts GenerateOptions) ApplyTo(target *GenerateOptions) {
+	if opts.Labels != nil {
+		target.Labels = maps.Clone(opts.Labels)
+	}
+
+	if opts.Annotations != nil {
+		target.Annotations = maps.Clone(opts.Annotations)
+	}
+
+	if opts.Exclude != nil {
+		target.Exclude = slices.Clone(opts.Exclude)
+	}
+}
+
+// WithLabels sets the labels of the generated Namespaces.
+// Default: none.
+func WithLabels(labels map[string]string) GenerateOption {
+	return util.FunctionalOption[GenerateOptions](func(opts *GenerateOptions) {
+		opts.Labels = maps.Clone(labels)
+	})
+}
+
+// WithAnnotations sets the annotations of the generated Namespaces.
+// Default: none.
+func WithAnnotations(annotations map[string]string) GenerateOption {
+	return util.FunctionalOption[GenerateOptions](func(opts *GenerateOptions) {
+		opts.Annotations = maps.Clone(annotations)
+	})
+}
+
+// WithExclude sets the namespaces never generated, e.g. those managed outside of the rendered set.
+// Default: default, kube-system, kube-public and kube-node-lease, which always exist.
+func WithExclude(namespaces ...string) GenerateOption {
+	return util.FunctionalOption[GenerateOptions](func(opts *GenerateOptions) {
+		opts.Exclude = slices.Clone(namespaces)
+	})
+}
+
+// Generate returns a collection transformer that adds a Namespace object for every namespace
+// the objects are in, unless the set already holds it. The generated Namespaces are sorted by
+// name and placed before the other objects, so they are applied first.
+func Generate(opts ...GenerateOption) types.CollectionTransformer {
+	options := GenerateOptions{
+		Exclude: []string{"default", "kube-system", "kube-public", "kube-node-lease"},
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		existing := make(map[string]struct{})
+
+		for _, obj := range objects {
+			if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Namespace") {
+				existing[obj.GetName()] = struct{}{}
+			}
+		}
+
+		for _, ns := range options.Exclude {
+			existing[ns] = struct{}{}
+		}
+
+		missing := make(map[string]struct{})
+
+		for _, obj := range objects {
+			ns := obj.GetNamespace()
+			if ns == "" {
+				continue
+			}
+
+			if _, ok := existing[ns]; !ok {
+				missing[ns] = struct{}{}
+			}
+		}
+
+		if len(missing) == 0 {
+			return objects, nil
+		}
+
+		result := make([]unstructured.Unstructured, 0, len(missing)+len(objects))
+
+		for _, ns := range slices.Sorted(maps.Keys(missing)) {
+			result = append(result, options.namespace(ns))
+		}
+
+		return append(result, objects...), nil
+	}
+}
+
+// namespace returns a Namespace object with the configured labels and annotations.
+func (opts GenerateOptions) namespace(name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+	obj.SetName(name)
+
+	if len(opts.Labels) > 0 {
+		obj.SetLabels(maps.Clone(opts.Labels))
+	}
+
+	if len(opts.Annotations) > 0 {
+		obj.SetAnnotations(maps.Clone(opts.Annotations))
+	}
+
+	return obj
+}
@@ -51,9 +51,175 @@ func TestEnsureDefault(t *testing.T) {
 	})
 }
 
+func TestMap(t *testing.T) {
+	g := NewWithT(t)
+
+	mapping := map[string]string{
+		"default":    "shop",
+		"monitoring": "observability",
+	}
+
+	t.Run("should remap mapped namespaces only", func(t *testing.T) {
+		transformer := namespace.Map(mapping)
+
+		obj, err := transformer(t.Context(), makePod("test", "monitoring"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetNamespace()).Should(Equal("observability"))
+
+		obj, err = transformer(t.Context(), makePod("test", "production"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetNamespace()).Should(Equal("production"))
+
+		obj, err = transformer(t.Context(), makePod("test", ""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetNamespace()).Should(BeEmpty())
+	})
+
+	t.Run("should rename Namespace objects", func(t *testing.T) {
+		transformer := namespace.Rename("monitoring", "observability")
+
+		ns := unstructured.Unstructured{}
+		ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		ns.SetName("monitoring")
+
+		obj, err := transformer(t.Context(), ns)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetName()).Should(Equal("observability"))
+	})
+
+	t.Run("should remap references when enabled", func(t *testing.T) {
+		binding := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRoleBinding",
+			"metadata":   map[string]any{"name": "reader"},
+			"subjects": []any{
+				map[string]any{"kind": "ServiceAccount", "name": "app", "namespace": "default"},
+				map[string]any{"kind": "ServiceAccount", "name": "other", "namespace": "other"},
+				map[string]any{"kind": "Group", "name": "readers"},
+			},
+		}}
+
+		obj, err := namespace.Map(mapping)(t.Context(), *binding.DeepCopy())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["subjects"]).Should(ContainElement(HaveKeyWithValue("namespace", "default")))
+
+		obj, err = namespace.Map(mapping, namespace.WithReferences(true))(t.Context(), *binding.DeepCopy())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["subjects"]).Should(Equal([]any{
+			map[string]any{"kind": "ServiceAccount", "name": "app", "namespace": "shop"},
+			map[string]any{"kind": "ServiceAccount", "name": "other", "namespace": "other"},
+			map[string]any{"kind": "Group", "name": "readers"},
+		}))
+
+		monitor := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "ServiceMonitor",
+			"metadata":   map[string]any{"name": "app", "namespace": "monitoring"},
+			"spec": map[string]any{
+				"namespaceSelector": map[string]any{"matchNames": []any{"default", "other"}},
+			},
+		}}
+
+		obj, err = namespace.Map(mapping, namespace.WithReferences(true))(t.Context(), monitor)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetNamespace()).Should(Equal("observability"))
+
+		names, _, err := unstructured.NestedStringSlice(obj.Object, "spec", "namespaceSelector", "matchNames")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names).Should(Equal([]string{"shop", "other"}))
+
+		webhook := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "admissionregistration.k8s.io/v1",
+			"kind":       "ValidatingWebhookConfiguration",
+			"metadata":   map[string]any{"name": "app"},
+			"webhooks": []any{
+				map[string]any{
+					"name":         "validate.example.com",
+					"clientConfig": map[string]any{"service": map[string]any{"name": "webhook", "namespace": "default"}},
+				},
+			},
+		}}
+
+		obj, err = namespace.Map(mapping, namespace.WithReferences(true))(t.Context(), webhook)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["webhooks"]).Should(ContainElement(
+			HaveKeyWithValue("clientConfig", HaveKeyWithValue("service", HaveKeyWithValue("namespace", "shop"))),
+		))
+	})
+}
+
 // Helper function
 
 //nolint:unparam // Test helper needs consistent signature
+func TestGenerate(t *testing.T) {
+	namespaceGVK := corev1.SchemeGroupVersion.WithKind("Namespace")
+
+	t.Run("should add missing namespaces before the objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []unstructured.Unstructured{
+			makePod("a", "team-b"),
+			makePod("b", "team-a"),
+			makePod("c", "team-b"),
+			makePod("d", ""),
+		}
+
+		result, err := namespace.Generate()(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(6))
+
+		g.Expect(result[0].GroupVersionKind()).Should(Equal(namespaceGVK))
+		g.Expect(result[0].GetName()).Should(Equal("team-a"))
+		g.Expect(result[0].GetNamespace()).Should(BeEmpty())
+		g.Expect(result[1].GetName()).Should(Equal("team-b"))
+		g.Expect(result[2:]).Should(Equal(objects))
+	})
+
+	t.Run("should skip namespaces already in the set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		existing := unstructured.Unstructured{}
+		existing.SetGroupVersionKind(namespaceGVK)
+		existing.SetName("team-a")
+
+		objects := []unstructured.Unstructured{existing, makePod("a", "team-a")}
+
+		result, err := namespace.Generate()(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(objects))
+	})
+
+	t.Run("should skip excluded namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects := []unstructured.Unstructured{makePod("a", "default"), makePod("b", "kube-system")}
+
+		result, err := namespace.Generate()(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+
+		result, err = namespace.Generate(namespace.WithExclude("kube-system"))(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(3))
+		g.Expect(result[0].GetName()).Should(Equal("default"))
+	})
+
+	t.Run("should set labels and annotations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		generate := namespace.Generate(
+			namespace.WithLabels(map[string]string{"team": "a"}),
+			namespace.WithAnnotations(map[string]string{"owner": "platform"}),
+		)
+
+		result, err := generate(t.Context(), []unstructured.Unstructured{makePod("a", "team-a")})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+		g.Expect(result[0].GetLabels()).Should(Equal(map[string]string{"team": "a"}))
+		g.Expect(result[0].GetAnnotations()).Should(Equal(map[string]string{"owner": "platform"}))
+	})
+}
+
 func makePod(name string, ns string) unstructured.Unstructured {
 	obj := unstructured.Unstructured{
 		Object: map[string]any{
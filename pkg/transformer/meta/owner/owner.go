@@ -0,0 +1,82 @@
+package owner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+)
+
+var (
+	// ErrControllerConflict is returned when setting a controller reference on an object
+	// already controlled by another owner.
+	ErrControllerConflict = errors.New("object already has a controller")
+)
+
+// Set returns a transformer that adds an owner reference to objects, replacing an existing
+// reference with the same UID. Use metav1.NewControllerRef to build controller references, or
+// set the Controller and BlockOwnerDeletion flags directly; setting a controller reference on
+// an object controlled by another owner fails with ErrControllerConflict.
+//
+// Example:
+//
+//	owner.Set(*metav1.NewControllerRef(myApp, myAppGVK))
+func Set(ref metav1.OwnerReference) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		refs := obj.GetOwnerReferences()
+
+		index := slices.IndexFunc(refs, func(existing metav1.OwnerReference) bool {
+			return existing.UID == ref.UID
+		})
+
+		if isController(ref) {
+			for i, existing := range refs {
+				if i != index && isController(existing) {
+					return obj, &transformer.Error{
+						Object: obj,
+						Err:    fmt.Errorf("%w: %s %s", ErrControllerConflict, existing.Kind, existing.Name),
+					}
+				}
+			}
+		}
+
+		if index >= 0 {
+			refs[index] = ref
+		} else {
+			refs = append(refs, ref)
+		}
+
+		obj.SetOwnerReferences(refs)
+
+		return obj, nil
+	}
+}
+
+// Remove returns a transformer that removes the owner references with the given UIDs.
+func Remove(uids ...k8stypes.UID) types.Transformer {
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		refs := obj.GetOwnerReferences()
+		if len(refs) == 0 {
+			return obj, nil
+		}
+
+		refs = slices.DeleteFunc(refs, func(ref metav1.OwnerReference) bool {
+			return slices.Contains(uids, ref.UID)
+		})
+
+		obj.SetOwnerReferences(refs)
+
+		return obj, nil
+	}
+}
+
+func isController(ref metav1.OwnerReference) bool {
+	return ref.Controller != nil && *ref.Controller
+}
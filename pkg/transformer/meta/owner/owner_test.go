@@ -0,0 +1,94 @@
+package owner_test
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/meta/owner"
+
+	. "github.com/onsi/gomega"
+)
+
+var appGVK = schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "MyApp"}
+
+func makeConfigMap() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "config",
+				"namespace": "default",
+			},
+		},
+	}
+}
+
+func controllerRef(name string, uid string) metav1.OwnerReference {
+	return *metav1.NewControllerRef(&metav1.ObjectMeta{Name: name, UID: k8stypes.UID(uid)}, appGVK)
+}
+
+func TestOwner(t *testing.T) {
+	t.Run("should add owner references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := owner.Set(controllerRef("shop", "uid-1"))(t.Context(), makeConfigMap())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		refs := result.GetOwnerReferences()
+		g.Expect(refs).Should(HaveLen(1))
+		g.Expect(refs[0].Kind).Should(Equal("MyApp"))
+		g.Expect(refs[0].APIVersion).Should(Equal("example.com/v1"))
+		g.Expect(*refs[0].Controller).Should(BeTrue())
+		g.Expect(*refs[0].BlockOwnerDeletion).Should(BeTrue())
+	})
+
+	t.Run("should replace references with the same UID", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := owner.Set(controllerRef("shop", "uid-1"))(t.Context(), makeConfigMap())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		ref := controllerRef("shop", "uid-1")
+		ref.BlockOwnerDeletion = nil
+
+		result, err = owner.Set(ref)(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		refs := result.GetOwnerReferences()
+		g.Expect(refs).Should(HaveLen(1))
+		g.Expect(refs[0].BlockOwnerDeletion).Should(BeNil())
+	})
+
+	t.Run("should reject a second controller", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := owner.Set(controllerRef("shop", "uid-1"))(t.Context(), makeConfigMap())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = owner.Set(controllerRef("other", "uid-2"))(t.Context(), result)
+		g.Expect(err).Should(MatchError(owner.ErrControllerConflict))
+
+		ref := controllerRef("other", "uid-2")
+		ref.Controller = nil
+
+		result, err = owner.Set(ref)(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GetOwnerReferences()).Should(HaveLen(2))
+	})
+
+	t.Run("should remove references by UID", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := owner.Set(controllerRef("shop", "uid-1"))(t.Context(), makeConfigMap())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = owner.Remove("uid-1")(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.GetOwnerReferences()).Should(BeEmpty())
+	})
+}
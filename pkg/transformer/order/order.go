@@ -0,0 +1,166 @@
+package order
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/graph"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// installOrder is the default order in which kinds are applied, mirroring the Helm install order:
+// cluster wide prerequisites like Namespaces, CRDs and RBAC come first, webhooks last.
+var installOrder = []string{
+	"PriorityClass",
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// Comparator compares two objects, returning a negative number when a sorts before b, a
+// positive number when a sorts after b and zero when their order does not matter.
+type Comparator func(a unstructured.Unstructured, b unstructured.Unstructured) int
+
+// Option is a generic option for the kind ordering.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple kind ordering options at once.
+type Options struct {
+	// Kinds is the order in which kinds are applied.
+	Kinds []string
+}
+
+// ApplyTo applies the kind ordering options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Kinds != nil {
+		target.Kinds = slices.Clone(opts.Kinds)
+	}
+}
+
+// WithKinds sets the order in which kinds are applied. Kinds not listed sort after the listed ones.
+// Default: Namespaces, CRDs and RBAC first, then workloads, then webhooks, like Helm.
+func WithKinds(kinds ...string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Kinds = slices.Clone(kinds)
+	})
+}
+
+// Sort returns a collection transformer that sorts objects with the comparators, each one
+// deciding only among objects the previous ones consider equal. Sorting is stable, so objects
+// all comparators consider equal keep their order.
+func Sort(comparators ...Comparator) types.CollectionTransformer {
+	return func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		result := slices.Clone(objects)
+
+		slices.SortStableFunc(result, func(a unstructured.Unstructured, b unstructured.Unstructured) int {
+			for _, c := range comparators {
+				if r := c(a, b); r != 0 {
+					return r
+				}
+			}
+
+			return 0
+		})
+
+		return result, nil
+	}
+}
+
+// ByKind returns a collection transformer that sorts objects in install order, so they can be
+// applied in sequence. Objects of the same kind are sorted alphabetically.
+func ByKind(opts ...Option) types.CollectionTransformer {
+	return Sort(KindOrder(opts...), Alphabetical)
+}
+
+// ByName returns a collection transformer that sorts objects alphabetically, giving a
+// deterministic output suited to diffs.
+func ByName() types.CollectionTransformer {
+	return Sort(Alphabetical)
+}
+
+// ByDependencies returns a collection transformer that sorts objects after the objects they depend
+// on, as inferred by the graph resolvers, e.g. a custom resource after its CRD. Objects are
+// emitted in the graph waves, each wave keeping the relative order of its objects, so ByKind can
+// be applied first as a tie-breaker.
+// Returns graph.ErrCycle if some objects depend on each other.
+func ByDependencies(opts ...graph.Option) types.CollectionTransformer {
+	return func(_ context.Context, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+		return graph.New(objects, opts...).Sorted()
+	}
+}
+
+// KindOrder returns a comparator ordering objects by the install order of their kind.
+// Kinds not in the order sort last.
+func KindOrder(opts ...Option) Comparator {
+	options := Options{
+		Kinds: installOrder,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	rank := make(map[string]int, len(options.Kinds))
+	for i, kind := range options.Kinds {
+		if _, ok := rank[kind]; !ok {
+			rank[kind] = i
+		}
+	}
+
+	position := func(obj unstructured.Unstructured) int {
+		if i, ok := rank[obj.GetKind()]; ok {
+			return i
+		}
+
+		return len(options.Kinds)
+	}
+
+	return func(a unstructured.Unstructured, b unstructured.Unstructured) int {
+		return cmp.Compare(position(a), position(b))
+	}
+}
+
+// Alphabetical is a comparator ordering objects by group, kind, namespace and name.
+func Alphabetical(a unstructured.Unstructured, b unstructured.Unstructured) int {
+	ga := a.GroupVersionKind()
+	gb := b.GroupVersionKind()
+
+	return cmp.Or(
+		cmp.Compare(ga.Group, gb.Group),
+		cmp.Compare(ga.Kind, gb.Kind),
+		cmp.Compare(a.GetNamespace(), b.GetNamespace()),
+		cmp.Compare(a.GetName(), b.GetName()),
+	)
+}
@@ -0,0 +1,144 @@
+package order_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/order"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeObject(apiVersion string, kind string, namespace string, name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return obj
+}
+
+func names(objects []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		result = append(result, obj.GetKind()+"/"+obj.GetName())
+	}
+
+	return result
+}
+
+func TestByKind(t *testing.T) {
+	objects := []unstructured.Unstructured{
+		makeObject("example.com/v1", "Widget", "apps", "widget"),
+		makeObject("apps/v1", "Deployment", "apps", "web"),
+		makeObject("admissionregistration.k8s.io/v1", "ValidatingWebhookConfiguration", "", "hook"),
+		makeObject("v1", "Service", "apps", "web"),
+		makeObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "reader"),
+		makeObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "widgets.example.com"),
+		makeObject("v1", "ConfigMap", "apps", "b"),
+		makeObject("v1", "ConfigMap", "apps", "a"),
+		makeObject("v1", "Namespace", "", "apps"),
+	}
+
+	t.Run("should sort objects in install order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := order.ByKind()(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(result)).Should(Equal([]string{
+			"Namespace/apps",
+			"ConfigMap/a",
+			"ConfigMap/b",
+			"CustomResourceDefinition/widgets.example.com",
+			"ClusterRole/reader",
+			"Service/web",
+			"Deployment/web",
+			"ValidatingWebhookConfiguration/hook",
+			"Widget/widget",
+		}))
+
+		// The input is not modified
+		g.Expect(objects[0].GetKind()).Should(Equal("Widget"))
+	})
+
+	t.Run("should use a custom kind order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := order.ByKind(order.WithKinds("Widget", "Service"))(t.Context(), objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(result)[:3]).Should(Equal([]string{
+			"Widget/widget",
+			"Service/web",
+			"ConfigMap/a",
+		}))
+	})
+}
+
+func TestByName(t *testing.T) {
+	g := NewWithT(t)
+
+	objects := []unstructured.Unstructured{
+		makeObject("v1", "Service", "b", "web"),
+		makeObject("apps/v1", "Deployment", "a", "web"),
+		makeObject("v1", "Service", "a", "web"),
+		makeObject("v1", "ConfigMap", "a", "web"),
+	}
+
+	result, err := order.ByName()(t.Context(), objects)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(result[0].GetKind()).Should(Equal("ConfigMap"))
+	g.Expect(result[1].GetKind()).Should(Equal("Service"))
+	g.Expect(result[1].GetNamespace()).Should(Equal("a"))
+	g.Expect(result[2].GetNamespace()).Should(Equal("b"))
+	g.Expect(result[3].GetKind()).Should(Equal("Deployment"))
+}
+
+func TestSort(t *testing.T) {
+	g := NewWithT(t)
+
+	objects := []unstructured.Unstructured{
+		makeObject("v1", "ConfigMap", "", "b"),
+		makeObject("v1", "Secret", "", "c"),
+		makeObject("v1", "ConfigMap", "", "a"),
+	}
+
+	byLength := func(a unstructured.Unstructured, b unstructured.Unstructured) int {
+		return len(a.GetKind()) - len(b.GetKind())
+	}
+
+	result, err := order.Sort(byLength)(t.Context(), objects)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(names(result)).Should(Equal([]string{"Secret/c", "ConfigMap/b", "ConfigMap/a"}))
+
+	result, err = order.Sort(byLength, order.Alphabetical)(t.Context(), objects)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(names(result)).Should(Equal([]string{"Secret/c", "ConfigMap/a", "ConfigMap/b"}))
+}
+
+func TestByDependencies(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := makeObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", "widgets.example.com")
+	crd.Object["spec"] = map[string]any{
+		"group": "example.com",
+		"names": map[string]any{"kind": "Widget"},
+	}
+
+	objects := []unstructured.Unstructured{
+		makeObject("example.com/v1", "Widget", "apps", "widget"),
+		makeObject("v1", "ConfigMap", "apps", "config"),
+		crd,
+		makeObject("v1", "Namespace", "", "apps"),
+	}
+
+	result, err := order.ByDependencies()(t.Context(), objects)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(names(result)).Should(Equal([]string{
+		"CustomResourceDefinition/widgets.example.com",
+		"Namespace/apps",
+		"Widget/widget",
+		"ConfigMap/config",
+	}))
+}
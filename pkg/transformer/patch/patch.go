@@ -0,0 +1,232 @@
+// Package patch provides transformers applying Kustomize-style strategic merge patches and
+// RFC 6902 JSON patches to objects.
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+var (
+	// ErrInvalidPatch is returned when a patch cannot be parsed.
+	ErrInvalidPatch = errors.New("invalid patch")
+)
+
+// Option is a generic option for the patch transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple patch options at once.
+type Options struct {
+	// Target selects the objects the patch is applied to; other objects are returned unchanged.
+	Target types.Filter
+
+	// Scheme resolves the Go types whose patch strategies drive strategic merge patches.
+	Scheme *runtime.Scheme
+}
+
+// ApplyTo applies the patch options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+
+	if opts.Scheme != nil {
+		target.Scheme = opts.Scheme
+	}
+}
+
+// WithTarget restricts the patch to the objects accepted by the filter, e.g. gvk.Kind("Deployment")
+// or name.Exact("app").
+// Default: for strategic merge patches, the objects matching the apiVersion, kind, name and
+// namespace set in the patch; for JSON patches, all objects.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// WithScheme sets the scheme resolving the Go types of the patched objects, e.g. a scheme with
+// custom resource types registered. Objects of kinds not registered are patched with a JSON
+// merge patch, replacing lists instead of merging them.
+// Default: the client-go scheme with the built-in Kubernetes types.
+func WithScheme(s *runtime.Scheme) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Scheme = s
+	})
+}
+
+// Operation is a JSON patch (RFC 6902) operation.
+type Operation struct {
+	// Op is the operation: add, remove, replace, move, copy or test.
+	Op string `json:"op"`
+
+	// Path is the JSON pointer to the target location, e.g. /spec/replicas.
+	Path string `json:"path"`
+
+	// From is the source location of move and copy operations.
+	From string `json:"from,omitempty"`
+
+	// Value is the value of add, replace and test operations.
+	Value any `json:"value,omitempty"`
+}
+
+// StrategicMerge returns a transformer applying a strategic merge patch, written in YAML or JSON,
+// like Kustomize patchesStrategicMerge. Lists of built-in kinds are merged by their merge keys,
+// e.g. containers by name, and $patch directives are honored.
+//
+// Example:
+//
+//	t, err := patch.StrategicMerge(`
+//	apiVersion: apps/v1
+//	kind: Deployment
+//	metadata:
+//	  name: app
+//	spec:
+//	  template:
+//	    spec:
+//	      containers:
+//	      - name: app
+//	        resources:
+//	          limits:
+//	            memory: 512Mi
+//	`)
+func StrategicMerge(patch string, opts ...Option) (types.Transformer, error) {
+	data, err := yaml.YAMLToJSON([]byte(patch))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	content := make(map[string]any)
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("%w: patch must be an object: %w", ErrInvalidPatch, err)
+	}
+
+	options := Options{
+		Target: identity(unstructured.Unstructured{Object: content}),
+		Scheme: scheme.Scheme,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return apply(options.Target, func(obj unstructured.Unstructured, original []byte) ([]byte, error) {
+		typed, err := options.Scheme.New(obj.GroupVersionKind())
+		if runtime.IsNotRegisteredError(err) {
+			return jsonpatch.MergePatch(original, data)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return strategicpatch.StrategicMergePatch(original, data, typed)
+	}), nil
+}
+
+// JSONPatch returns a transformer applying RFC 6902 JSON patch operations.
+//
+// Example:
+//
+//	t, err := patch.JSONPatch(
+//	    []patch.Operation{{Op: "replace", Path: "/spec/replicas", Value: 3}},
+//	    patch.WithTarget(gvk.Kind("Deployment")),
+//	)
+func JSONPatch(operations []Operation, opts ...Option) (types.Transformer, error) {
+	for i, op := range operations {
+		switch op.Op {
+		case "add", "remove", "replace", "move", "copy", "test":
+		default:
+			return nil, fmt.Errorf("%w: operation %d: unknown op %q", ErrInvalidPatch, i, op.Op)
+		}
+	}
+
+	data, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	p, err := jsonpatch.DecodePatch(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return apply(options.Target, func(_ unstructured.Unstructured, original []byte) ([]byte, error) {
+		return p.Apply(original)
+	}), nil
+}
+
+// apply returns a transformer patching the targeted objects in their JSON form.
+func apply(
+	target types.Filter,
+	patch func(obj unstructured.Unstructured, original []byte) ([]byte, error),
+) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if target != nil {
+			ok, err := target(ctx, obj)
+			if err != nil {
+				return obj, transformer.Wrap(obj, err)
+			}
+
+			if !ok {
+				return obj, nil
+			}
+		}
+
+		original, err := obj.MarshalJSON()
+		if err != nil {
+			return obj, transformer.Wrap(obj, err)
+		}
+
+		patched, err := patch(obj, original)
+		if err != nil {
+			return obj, transformer.Wrap(obj, fmt.Errorf("failed to apply patch: %w", err))
+		}
+
+		result := unstructured.Unstructured{}
+		if err := result.UnmarshalJSON(patched); err != nil {
+			return obj, transformer.Wrap(obj, fmt.Errorf("invalid patched object: %w", err))
+		}
+
+		return result, nil
+	}
+}
+
+// identity returns a filter selecting the objects matching the apiVersion, kind, name and
+// namespace set in a strategic merge patch.
+func identity(patch unstructured.Unstructured) types.Filter {
+	return func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+		switch {
+		case patch.GetAPIVersion() != "" && patch.GetAPIVersion() != obj.GetAPIVersion():
+			return false, nil
+		case patch.GetKind() != "" && patch.GetKind() != obj.GetKind():
+			return false, nil
+		case patch.GetName() != "" && patch.GetName() != obj.GetName():
+			return false, nil
+		case patch.GetNamespace() != "" && patch.GetNamespace() != obj.GetNamespace():
+			return false, nil
+		default:
+			return true, nil
+		}
+	}
+}
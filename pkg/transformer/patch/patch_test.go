@@ -0,0 +1,215 @@
+package patch_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/patch"
+
+	. "github.com/onsi/gomega"
+)
+
+const resourcesPatch = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          limits:
+            memory: 512Mi
+`
+
+func makeDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": name,
+			},
+			"spec": map[string]any{
+				"replicas": int64(1),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "app", "image": "app:1.0"},
+							map[string]any{"name": "sidecar", "image": "sidecar:1.0"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func containers(g *WithT, obj unstructured.Unstructured) []any {
+	c, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(found).Should(BeTrue())
+
+	return c
+}
+
+func TestStrategicMerge(t *testing.T) {
+	t.Run("should merge lists of built-in kinds by key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.StrategicMerge(resourcesPatch)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		c := containers(g, result)
+		g.Expect(c).Should(HaveLen(2))
+		g.Expect(c[0]).Should(HaveKeyWithValue("image", "app:1.0"))
+		g.Expect(c[0]).Should(HaveKeyWithValue("resources", HaveKeyWithValue("limits", HaveKeyWithValue("memory", "512Mi"))))
+		g.Expect(c[1]).ShouldNot(HaveKey("resources"))
+		g.Expect(result.Object).Should(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", int64(1))))
+	})
+
+	t.Run("should honor patch directives", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.StrategicMerge(`
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        $patch: delete
+`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(containers(g, result)).Should(HaveLen(1))
+	})
+
+	t.Run("should only patch objects matching the patch identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.StrategicMerge(resourcesPatch)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		other := makeDeployment("other")
+
+		result, err := tr(t.Context(), other)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(other))
+	})
+
+	t.Run("should use the target instead of the patch identity", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.StrategicMerge(resourcesPatch, patch.WithTarget(
+			func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetKind() == "Deployment", nil
+			},
+		))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := tr(t.Context(), makeDeployment("other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(containers(g, result)[0]).Should(HaveKey("resources"))
+		g.Expect(result.GetName()).Should(Equal("app"))
+	})
+
+	t.Run("should merge unregistered kinds with a merge patch", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := makeDeployment("app")
+		obj.SetAPIVersion("example.com/v1")
+
+		tr, err := patch.StrategicMerge(`
+apiVersion: example.com/v1
+kind: Deployment
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:2.0
+`)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := tr(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", int64(3))))
+		g.Expect(containers(g, result)).Should(HaveLen(1))
+	})
+
+	t.Run("should reject invalid patches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := patch.StrategicMerge("- not an object")
+		g.Expect(err).Should(MatchError(patch.ErrInvalidPatch))
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	t.Run("should apply operations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.JSONPatch([]patch.Operation{
+			{Op: "replace", Path: "/spec/replicas", Value: 3},
+			{Op: "remove", Path: "/spec/template/spec/containers/1"},
+			{Op: "add", Path: "/metadata/labels", Value: map[string]any{"team": "platform"}},
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", int64(3))))
+		g.Expect(containers(g, result)).Should(HaveLen(1))
+		g.Expect(result.GetLabels()).Should(HaveKeyWithValue("team", "platform"))
+	})
+
+	t.Run("should skip objects not accepted by the target", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.JSONPatch(
+			[]patch.Operation{{Op: "replace", Path: "/spec/replicas", Value: 3}},
+			patch.WithTarget(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "app", nil
+			}),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		other := makeDeployment("other")
+
+		result, err := tr(t.Context(), other)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(other))
+	})
+
+	t.Run("should fail on operations that cannot be applied", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := patch.JSONPatch([]patch.Operation{{Op: "remove", Path: "/spec/missing"}})
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).Should(HaveOccurred())
+
+		var transformerErr *transformer.Error
+		g.Expect(err).Should(BeAssignableToTypeOf(transformerErr))
+	})
+
+	t.Run("should reject unknown operations", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := patch.JSONPatch([]patch.Operation{{Op: "unknown", Path: "/spec"}})
+		g.Expect(err).Should(MatchError(patch.ErrInvalidPatch))
+	})
+}
@@ -0,0 +1,106 @@
+// Package prune provides a transformer stripping server-populated and other noisy fields, e.g.
+// from objects read from a cluster or from YAML dumps of live objects.
+package prune
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+)
+
+// DefaultPaths are the server-populated fields removed by default.
+var DefaultPaths = []string{
+	"status",
+	"metadata.managedFields",
+	"metadata.creationTimestamp",
+	"metadata.generation",
+	"metadata.resourceVersion",
+	"metadata.uid",
+	"metadata.selfLink",
+	"metadata.annotations['kubectl.kubernetes.io/last-applied-configuration']",
+	"metadata.annotations['deployment.kubernetes.io/revision']",
+}
+
+// Option is a generic option for the Prune transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple pruning options at once.
+type Options struct {
+	// Defaults enables removing the DefaultPaths.
+	Defaults bool
+
+	// Paths select additional fields to remove.
+	Paths []string
+}
+
+// ApplyTo applies the pruning options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Defaults = opts.Defaults
+	target.Paths = append(target.Paths, opts.Paths...)
+}
+
+// WithDefaults enables or disables removing the DefaultPaths.
+// Default: true.
+func WithDefaults(enabled bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Defaults = enabled
+	})
+}
+
+// WithPaths adds field paths selecting fields to remove from every object, e.g.
+// "spec.clusterIP" or "metadata.labels['pod-template-hash']"; see fieldpath.Parse for the syntax.
+// Default: none.
+func WithPaths(paths ...string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Paths = append(opts.Paths, paths...)
+	})
+}
+
+// Prune returns a transformer removing the DefaultPaths and the additional paths from objects.
+// Annotations left empty are removed as well. The transformer works on a copy of the objects.
+// Returns an error if a path is malformed.
+func Prune(opts ...Option) (types.Transformer, error) {
+	options := Options{
+		Defaults: true,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	var raw []string
+	if options.Defaults {
+		raw = append(raw, DefaultPaths...)
+	}
+
+	raw = append(raw, options.Paths...)
+
+	paths := make([]fieldpath.Path, 0, len(raw))
+
+	for _, p := range raw {
+		path, err := fieldpath.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		obj = *obj.DeepCopy()
+
+		for _, path := range paths {
+			path.Remove(obj.Object)
+		}
+
+		if annotations, found, _ := unstructured.NestedMap(obj.Object, "metadata", "annotations"); found && len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+		}
+
+		return obj, nil
+	}, nil
+}
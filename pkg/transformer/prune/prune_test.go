@@ -0,0 +1,108 @@
+package prune_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/prune"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeService() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]any{
+				"name":              "web",
+				"namespace":         "default",
+				"uid":               "0d8e6c4a-5d0f-4a64-8c0e-3b2a8a1f6c10",
+				"resourceVersion":   "1234",
+				"generation":        int64(1),
+				"creationTimestamp": "2024-01-01T00:00:00Z",
+				"managedFields": []any{
+					map[string]any{"manager": "kubectl", "operation": "Apply"},
+				},
+				"annotations": map[string]any{
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				},
+				"labels": map[string]any{
+					"app": "web",
+				},
+			},
+			"spec": map[string]any{
+				"clusterIP": "10.0.0.1",
+				"ports": []any{
+					map[string]any{"port": int64(80)},
+				},
+			},
+			"status": map[string]any{
+				"loadBalancer": map[string]any{},
+			},
+		},
+	}
+}
+
+func TestPrune(t *testing.T) {
+
+	t.Run("should remove server-populated fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := prune.Prune()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		original := makeService()
+		obj, err := tr(t.Context(), original)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.Object["metadata"]).Should(Equal(map[string]any{
+			"name":      "web",
+			"namespace": "default",
+			"labels":    map[string]any{"app": "web"},
+		}))
+		g.Expect(obj.Object["spec"]).Should(HaveKey("clusterIP"))
+
+		// The input is not modified
+		g.Expect(original.Object).Should(HaveKey("status"))
+		g.Expect(original.GetUID()).ShouldNot(BeEmpty())
+	})
+
+	t.Run("should remove additional paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := prune.Prune(prune.WithPaths("spec.clusterIP", "metadata.labels['app']"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		obj, err := tr(t.Context(), makeService())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.Object["spec"]).ShouldNot(HaveKey("clusterIP"))
+		g.Expect(obj.GetLabels()).Should(BeEmpty())
+	})
+
+	t.Run("should keep the default paths when disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := prune.Prune(prune.WithDefaults(false), prune.WithPaths("metadata.managedFields"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		obj, err := tr(t.Context(), makeService())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object).Should(HaveKey("status"))
+		g.Expect(obj.GetResourceVersion()).Should(Equal("1234"))
+		g.Expect(obj.GetManagedFields()).Should(BeEmpty())
+	})
+
+	t.Run("should fail on malformed paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := prune.Prune(prune.WithPaths("spec.ports["))
+		g.Expect(err).Should(MatchError(fieldpath.ErrInvalidPath))
+	})
+}
@@ -0,0 +1,123 @@
+// Package redact provides a transformer masking sensitive values, for pipelines dumping the
+// rendered manifests into logs, pull requests or diff previews.
+package redact
+
+import (
+	"context"
+	"encoding/base64"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+)
+
+const (
+	// DefaultPlaceholder is the value sensitive values are replaced with.
+	DefaultPlaceholder = "REDACTED"
+
+	// lastAppliedAnnotation holds a copy of the object, Secret values included.
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	dataPath       = fieldpath.MustParse("data[*]")
+	stringDataPath = fieldpath.MustParse("stringData[*]")
+)
+
+// Option is a generic option for the Redact transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple redaction options at once.
+type Options struct {
+	// Placeholder replaces the sensitive values.
+	Placeholder string
+
+	// Paths select additional sensitive fields of any object.
+	Paths []string
+}
+
+// ApplyTo applies the redaction options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Placeholder != "" {
+		target.Placeholder = opts.Placeholder
+	}
+
+	target.Paths = append(target.Paths, opts.Paths...)
+}
+
+// WithPlaceholder sets the value sensitive values are replaced with.
+// Default: DefaultPlaceholder.
+func WithPlaceholder(placeholder string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Placeholder = placeholder
+	})
+}
+
+// WithPaths adds field paths selecting sensitive values of any object, e.g.
+// "spec.template.spec.containers[*].env[*].value"; see fieldpath.Parse for the syntax.
+// Default: none, only Secrets are redacted.
+func WithPaths(paths ...string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Paths = append(opts.Paths, paths...)
+	})
+}
+
+// Redact returns a transformer masking sensitive values with a placeholder. The values of
+// Secret data and stringData are always masked, keeping their keys, and data values stay valid
+// base64. The last-applied-configuration annotation of Secrets, holding a copy of the values, is
+// removed. Values selected by the additional paths are masked on every object. The transformed
+// object is a copy, so the input is left untouched.
+// Returns an error if a path is malformed.
+func Redact(opts ...Option) (types.Transformer, error) {
+	options := Options{
+		Placeholder: DefaultPlaceholder,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	paths := make([]fieldpath.Path, 0, len(options.Paths))
+
+	for _, p := range options.Paths {
+		path, err := fieldpath.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, path)
+	}
+
+	placeholder := func(any) any {
+		return options.Placeholder
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(options.Placeholder))
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		obj = *obj.DeepCopy()
+
+		if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Secret") {
+			dataPath.Update(obj.Object, func(any) any {
+				return encoded
+			})
+			stringDataPath.Update(obj.Object, placeholder)
+
+			if annotations := obj.GetAnnotations(); annotations != nil {
+				if _, ok := annotations[lastAppliedAnnotation]; ok {
+					delete(annotations, lastAppliedAnnotation)
+					obj.SetAnnotations(annotations)
+				}
+			}
+		}
+
+		for _, path := range paths {
+			path.Update(obj.Object, placeholder)
+		}
+
+		return obj, nil
+	}, nil
+}
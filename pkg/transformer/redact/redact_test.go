@@ -0,0 +1,103 @@
+package redact_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/redact"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeSecret() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name": "db",
+				"annotations": map[string]any{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"czNjcjN0"}}`,
+					"owner": "platform",
+				},
+			},
+			"data": map[string]any{
+				"password": base64.StdEncoding.EncodeToString([]byte("s3cr3t")),
+			},
+			"stringData": map[string]any{
+				"user": "admin",
+			},
+		},
+	}
+}
+
+func TestRedact(t *testing.T) {
+
+	t.Run("should mask Secret values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := redact.Redact()
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		original := makeSecret()
+		obj, err := tr(t.Context(), original)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(obj.Object["data"]).Should(Equal(map[string]any{
+			"password": base64.StdEncoding.EncodeToString([]byte(redact.DefaultPlaceholder)),
+		}))
+		g.Expect(obj.Object["stringData"]).Should(Equal(map[string]any{
+			"user": redact.DefaultPlaceholder,
+		}))
+		g.Expect(obj.GetAnnotations()).Should(Equal(map[string]string{"owner": "platform"}))
+
+		// The input is not modified
+		g.Expect(original.Object["stringData"]).Should(HaveKeyWithValue("user", "admin"))
+	})
+
+	t.Run("should mask additional paths with a custom placeholder", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr, err := redact.Redact(
+			redact.WithPlaceholder("***"),
+			redact.WithPaths("spec.containers[*].env[*].value"),
+		)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		pod := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "app"},
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{
+						"name": "app",
+						"env": []any{
+							map[string]any{"name": "TOKEN", "value": "abc"},
+							map[string]any{"name": "FROM_SECRET", "valueFrom": map[string]any{}},
+						},
+					},
+				},
+			},
+		}}
+
+		obj, err := tr(t.Context(), pod)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(fieldpath.MustParse("spec.containers[*].env[*].value").Lookup(obj.Object)).Should(Equal([]any{"***"}))
+		g.Expect(fieldpath.MustParse("spec.containers[*].env[*].name").Lookup(obj.Object)).Should(HaveLen(2))
+
+		obj, err = tr(t.Context(), makeSecret())
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.Object["stringData"]).Should(HaveKeyWithValue("user", "***"))
+	})
+
+	t.Run("should reject malformed paths", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := redact.Redact(redact.WithPaths("spec..env"))
+		g.Expect(err).Should(MatchError(fieldpath.ErrInvalidPath))
+	})
+}
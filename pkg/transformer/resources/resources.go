@@ -0,0 +1,137 @@
+// Package resources provides transformers setting the resource requests and limits of
+// containers, e.g. to enforce organization-wide defaults on charts that omit them.
+package resources
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the resources transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple resources options at once.
+type Options struct {
+	// Containers are glob patterns (path.Match syntax) selecting the containers by name.
+	Containers []string
+}
+
+// ApplyTo applies the resources options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	target.Containers = append(target.Containers, opts.Containers...)
+}
+
+// WithContainers restricts the transformer to the containers whose name matches one of the glob
+// patterns, e.g. "app" or "istio-*".
+// Default: all containers, including init containers.
+func WithContainers(patterns ...string) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Containers = append(opts.Containers, patterns...)
+	})
+}
+
+// Set returns a transformer setting the given requests and limits on the containers, replacing
+// the existing values of the same resources. Other resources are left unchanged.
+//
+// Example:
+//
+//	resources.Set(corev1.ResourceRequirements{
+//	    Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+//	}, resources.WithContainers("app"))
+func Set(requirements corev1.ResourceRequirements, opts ...Option) types.Transformer {
+	return transform(requirements, true, opts)
+}
+
+// Default returns a transformer setting the given requests and limits on the containers that do
+// not define them yet. Resources already set are left unchanged.
+func Default(requirements corev1.ResourceRequirements, opts ...Option) types.Transformer {
+	return transform(requirements, false, opts)
+}
+
+func transform(requirements corev1.ResourceRequirements, override bool, opts []Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		err := k8s.VisitContainers(obj.Object, func(container map[string]any) error {
+			name, _ := container["name"].(string)
+
+			ok, err := matches(options.Containers, name)
+			if err != nil || !ok {
+				return err
+			}
+
+			if err := apply(container, "requests", requirements.Requests, override); err != nil {
+				return err
+			}
+
+			return apply(container, "limits", requirements.Limits, override)
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// matches reports whether a container name matches one of the patterns; no pattern matches all.
+func matches(patterns []string, name string) (bool, error) {
+	if len(patterns) == 0 {
+		return true, nil
+	}
+
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid container pattern %q: %w", pattern, err)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// apply sets the resources of a list (requests or limits) of a container.
+func apply(container map[string]any, field string, resources corev1.ResourceList, override bool) error {
+	if len(resources) == 0 {
+		return nil
+	}
+
+	values, _, err := unstructured.NestedMap(container, "resources", field)
+	if err != nil {
+		return err
+	}
+
+	if values == nil {
+		values = make(map[string]any)
+	}
+
+	for name, quantity := range resources {
+		if _, exists := values[string(name)]; exists && !override {
+			continue
+		}
+
+		values[string(name)] = quantity.String()
+	}
+
+	return unstructured.SetNestedMap(container, values, "resources", field)
+}
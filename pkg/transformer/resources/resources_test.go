@@ -0,0 +1,125 @@
+package resources_test
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/resources"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": "app"},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"initContainers": []any{
+							map[string]any{"name": "init"},
+						},
+						"containers": []any{
+							map[string]any{
+								"name": "app",
+								"resources": map[string]any{
+									"requests": map[string]any{"cpu": "500m"},
+								},
+							},
+							map[string]any{"name": "istio-proxy"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func containerResources(g *WithT, obj unstructured.Unstructured, field string, index int) map[string]any {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	container, ok := containers[index].(map[string]any)
+	g.Expect(ok).Should(BeTrue())
+
+	r, _, err := unstructured.NestedMap(container, "resources")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return r
+}
+
+func TestResources(t *testing.T) {
+	requirements := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+
+	t.Run("should set resources on all containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := resources.Set(requirements)(t.Context(), makeDeployment())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		app := containerResources(g, result, "containers", 0)
+		g.Expect(app).Should(HaveKeyWithValue("requests", map[string]any{"cpu": "100m", "memory": "128Mi"}))
+		g.Expect(app).Should(HaveKeyWithValue("limits", map[string]any{"memory": "1Gi"}))
+
+		g.Expect(containerResources(g, result, "containers", 1)).Should(HaveKey("limits"))
+		g.Expect(containerResources(g, result, "initContainers", 0)).Should(HaveKey("limits"))
+	})
+
+	t.Run("should only default missing resources", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := resources.Default(requirements)(t.Context(), makeDeployment())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		app := containerResources(g, result, "containers", 0)
+		g.Expect(app).Should(HaveKeyWithValue("requests", map[string]any{"cpu": "500m", "memory": "128Mi"}))
+		g.Expect(app).Should(HaveKeyWithValue("limits", map[string]any{"memory": "1Gi"}))
+	})
+
+	t.Run("should target containers by name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := resources.Set(requirements, resources.WithContainers("istio-*", "init"))
+
+		result, err := tr(t.Context(), makeDeployment())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(containerResources(g, result, "containers", 0)).ShouldNot(HaveKey("limits"))
+		g.Expect(containerResources(g, result, "containers", 1)).Should(HaveKey("limits"))
+		g.Expect(containerResources(g, result, "initContainers", 0)).Should(HaveKey("limits"))
+	})
+
+	t.Run("should leave objects without containers unchanged", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "config"},
+		}}
+
+		result, err := resources.Set(requirements)(t.Context(), obj)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(obj))
+	})
+
+	t.Run("should fail on invalid patterns", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := resources.Set(requirements, resources.WithContainers("["))(t.Context(), makeDeployment())
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,171 @@
+// Package scheduling provides transformers setting the placement of workloads: node selectors,
+// tolerations, affinity and topology spread constraints, so that placement policies can be
+// enforced over any rendered source.
+package scheduling
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the scheduling transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple scheduling options at once.
+type Options struct {
+	// Target selects the objects to update; other objects are returned unchanged.
+	Target types.Filter
+}
+
+// ApplyTo applies the scheduling options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+}
+
+// WithTarget restricts the transformer to the objects accepted by the filter, e.g.
+// gvk.Kind("Deployment", "StatefulSet").
+// Default: all objects holding a pod spec.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// NodeSelector returns a transformer adding entries to the node selector of the pod specs,
+// replacing the values of existing keys.
+func NodeSelector(selector map[string]string, opts ...Option) types.Transformer {
+	return transform(opts, func(podSpec map[string]any) error {
+		current, _, err := unstructured.NestedStringMap(podSpec, "nodeSelector")
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			current = make(map[string]string, len(selector))
+		}
+
+		maps.Copy(current, selector)
+
+		return unstructured.SetNestedStringMap(podSpec, current, "nodeSelector")
+	})
+}
+
+// Tolerations returns a transformer appending tolerations to the pod specs. Tolerations already
+// present are not duplicated.
+func Tolerations(tolerations []corev1.Toleration, opts ...Option) types.Transformer {
+	return transform(opts, func(podSpec map[string]any) error {
+		return appendItems(podSpec, "tolerations", tolerations, func(a map[string]any, b map[string]any) bool {
+			return reflect.DeepEqual(a, b)
+		})
+	})
+}
+
+// TopologySpreadConstraints returns a transformer adding topology spread constraints to the pod
+// specs. A constraint with the same topologyKey and whenUnsatisfiable is replaced.
+func TopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint, opts ...Option) types.Transformer {
+	return transform(opts, func(podSpec map[string]any) error {
+		return appendItems(podSpec, "topologySpreadConstraints", constraints, func(a map[string]any, b map[string]any) bool {
+			return a["topologyKey"] == b["topologyKey"] && a["whenUnsatisfiable"] == b["whenUnsatisfiable"]
+		})
+	})
+}
+
+// Affinity returns a transformer setting the affinity of the pod specs. Only the sections set in
+// affinity (nodeAffinity, podAffinity, podAntiAffinity) are replaced, the others are kept.
+func Affinity(affinity corev1.Affinity, opts ...Option) types.Transformer {
+	return transform(opts, func(podSpec map[string]any) error {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&affinity)
+		if err != nil {
+			return fmt.Errorf("failed to convert affinity: %w", err)
+		}
+
+		current, _, err := unstructured.NestedMap(podSpec, "affinity")
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			current = make(map[string]any, len(content))
+		}
+
+		maps.Copy(current, content)
+
+		return unstructured.SetNestedMap(podSpec, current, "affinity")
+	})
+}
+
+func transform(opts []Option, fn func(podSpec map[string]any) error) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if options.Target != nil {
+			ok, err := options.Target(ctx, obj)
+			if err != nil {
+				return obj, transformer.Wrap(obj, err)
+			}
+
+			if !ok {
+				return obj, nil
+			}
+		}
+
+		if err := k8s.VisitPodSpecs(obj.Object, fn); err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// appendItems converts items and adds them to a list of the pod spec, replacing the existing
+// items they are the same as.
+func appendItems[T any](podSpec map[string]any, field string, items []T, same func(a map[string]any, b map[string]any) bool) error {
+	current, _, err := unstructured.NestedSlice(podSpec, field)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&items[i])
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", field, err)
+		}
+
+		index := slices.IndexFunc(current, func(existing any) bool {
+			m, ok := existing.(map[string]any)
+
+			return ok && same(m, item)
+		})
+
+		if index >= 0 {
+			current[index] = item
+		} else {
+			current = append(current, item)
+		}
+	}
+
+	podSpec[field] = current
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package scheduling_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/scheduling"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDaemonSet(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "DaemonSet",
+			"metadata":   map[string]any{"name": name},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers":   []any{map[string]any{"name": "agent"}},
+						"nodeSelector": map[string]any{"kubernetes.io/os": "windows", "tier": "edge"},
+						"tolerations": []any{
+							map[string]any{"key": "dedicated", "operator": "Exists", "effect": "NoSchedule"},
+						},
+						"affinity": map[string]any{
+							"podAffinity": map[string]any{"preferredDuringSchedulingIgnoredDuringExecution": []any{}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func podSpec(g *WithT, obj unstructured.Unstructured) map[string]any {
+	spec, found, err := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(found).Should(BeTrue())
+
+	return spec
+}
+
+func TestScheduling(t *testing.T) {
+	t.Run("should merge node selector entries", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := scheduling.NodeSelector(map[string]string{"kubernetes.io/os": "linux", "pool": "system"})
+
+		result, err := tr(t.Context(), makeDaemonSet("agent"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(podSpec(g, result)).Should(HaveKeyWithValue("nodeSelector", map[string]any{
+			"kubernetes.io/os": "linux",
+			"pool":             "system",
+			"tier":             "edge",
+		}))
+	})
+
+	t.Run("should append tolerations without duplicates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := scheduling.Tolerations([]corev1.Toleration{
+			{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists},
+		})
+
+		result, err := tr(t.Context(), makeDaemonSet("agent"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(podSpec(g, result)["tolerations"]).Should(HaveLen(2))
+	})
+
+	t.Run("should replace constraints on the same topology", func(t *testing.T) {
+		g := NewWithT(t)
+
+		constraint := func(skew int32) corev1.TopologySpreadConstraint {
+			return corev1.TopologySpreadConstraint{
+				MaxSkew:           skew,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+			}
+		}
+
+		result, err := scheduling.TopologySpreadConstraints([]corev1.TopologySpreadConstraint{constraint(1)})(t.Context(), makeDaemonSet("agent"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = scheduling.TopologySpreadConstraints([]corev1.TopologySpreadConstraint{constraint(2)})(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		constraints := podSpec(g, result)["topologySpreadConstraints"]
+		g.Expect(constraints).Should(HaveLen(1))
+		g.Expect(constraints).Should(ContainElement(HaveKeyWithValue("maxSkew", int64(2))))
+	})
+
+	t.Run("should set affinity sections", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := scheduling.Affinity(corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					TopologyKey:   "kubernetes.io/hostname",
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "agent"}},
+				}},
+			},
+		})
+
+		result, err := tr(t.Context(), makeDaemonSet("agent"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		affinity := podSpec(g, result)["affinity"]
+		g.Expect(affinity).Should(HaveKey("podAffinity"))
+		g.Expect(affinity).Should(HaveKeyWithValue("podAntiAffinity", HaveKey("requiredDuringSchedulingIgnoredDuringExecution")))
+		g.Expect(affinity).ShouldNot(HaveKey("nodeAffinity"))
+	})
+
+	t.Run("should only update targeted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := scheduling.NodeSelector(
+			map[string]string{"pool": "system"},
+			scheduling.WithTarget(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "agent", nil
+			}),
+		)
+
+		other := makeDaemonSet("other")
+
+		result, err := tr(t.Context(), other)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(podSpec(g, result)["nodeSelector"]).ShouldNot(HaveKey("pool"))
+	})
+}
@@ -0,0 +1,324 @@
+// Package secret provides transformers keeping secret values out of the rendered manifests, by
+// sealing Secrets for the Bitnami Sealed Secrets controller or replacing them with External
+// Secrets Operator ExternalSecrets.
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// Scope is the scope a sealed Secret can be unsealed in.
+type Scope string
+
+const (
+	// ScopeStrict only allows unsealing with the same name and namespace.
+	ScopeStrict Scope = "strict"
+
+	// ScopeNamespaceWide allows unsealing with any name within the same namespace.
+	ScopeNamespaceWide Scope = "namespace-wide"
+
+	// ScopeClusterWide allows unsealing with any name and namespace.
+	ScopeClusterWide Scope = "cluster-wide"
+
+	namespaceWideAnnotation = "sealedsecrets.bitnami.com/namespace-wide"
+	clusterWideAnnotation   = "sealedsecrets.bitnami.com/cluster-wide"
+
+	// sessionKeySize is the size of the AES-256 key encrypting each value.
+	sessionKeySize = 32
+)
+
+var (
+	// ErrInvalidKey is returned when the sealing public key can't be parsed.
+	ErrInvalidKey = errors.New("invalid public key")
+
+	// ErrMissingNamespace is returned when sealing a Secret without namespace in a scope that
+	// binds the namespace.
+	ErrMissingNamespace = errors.New("secret has no namespace")
+)
+
+var (
+	// SealedSecretGVK is the GroupVersionKind of the objects Seal produces.
+	SealedSecretGVK = schema.GroupVersionKind{Group: "bitnami.com", Version: "v1alpha1", Kind: "SealedSecret"}
+
+	// ExternalSecretGVK is the GroupVersionKind of the objects External produces.
+	ExternalSecretGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1beta1", Kind: "ExternalSecret"}
+
+	secretGVK = corev1.SchemeGroupVersion.WithKind("Secret")
+)
+
+// SealOption is a generic option for the Seal transformer.
+type SealOption = util.Option[SealOptions]
+
+// SealOptions is a struct-based option that can set multiple sealing options at once.
+type SealOptions struct {
+	// Scope is the scope the sealed Secrets can be unsealed in.
+	Scope Scope
+}
+
+// ApplyTo applies the sealing options to the target configuration.
+func (opts SealOptions) ApplyTo(target *SealOptions) {
+	if opts.Scope != "" {
+		target.Scope = opts.Scope
+	}
+}
+
+// WithScope sets the scope the sealed Secrets can be unsealed in.
+// Default: ScopeStrict.
+func WithScope(scope Scope) SealOption {
+	return util.FunctionalOption[SealOptions](func(opts *SealOptions) {
+		opts.Scope = scope
+	})
+}
+
+// ParsePublicKey parses the PEM encoded public key of the Sealed Secrets controller, either the
+// certificate fetched with kubeseal --fetch-cert or a PKIX public key.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", ErrInvalidKey)
+	}
+
+	var key any
+
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+
+		key = cert.PublicKey
+	case "PUBLIC KEY":
+		k, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+
+		key = k
+	default:
+		return nil, fmt.Errorf("%w: unsupported PEM block %q", ErrInvalidKey, block.Type)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not an RSA key", ErrInvalidKey, key)
+	}
+
+	return rsaKey, nil
+}
+
+// Seal returns a transformer replacing Secrets with SealedSecrets encrypted with the public key of
+// the Sealed Secrets controller, so the rendered manifests can be stored safely. Values are
+// encrypted like kubeseal does. Other objects are returned unchanged.
+func Seal(key *rsa.PublicKey, opts ...SealOption) types.Transformer {
+	options := SealOptions{
+		Scope: ScopeStrict,
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GroupVersionKind() != secretGVK {
+			return obj, nil
+		}
+
+		label, err := options.label(obj)
+		if err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		values, err := secretValues(obj)
+		if err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		encrypted := make(map[string]any, len(values))
+
+		for k, v := range values {
+			ciphertext, err := encrypt(key, v, label)
+			if err != nil {
+				return unstructured.Unstructured{}, fmt.Errorf("failed to encrypt key %s: %w", k, err)
+			}
+
+			encrypted[k] = base64.StdEncoding.EncodeToString(ciphertext)
+		}
+
+		result := unstructured.Unstructured{Object: map[string]any{}}
+		result.SetGroupVersionKind(SealedSecretGVK)
+		result.SetName(obj.GetName())
+		result.SetNamespace(obj.GetNamespace())
+		result.SetLabels(obj.GetLabels())
+
+		annotations := obj.GetAnnotations()
+
+		switch options.Scope {
+		case ScopeNamespaceWide:
+			annotations = withAnnotation(annotations, namespaceWideAnnotation)
+		case ScopeClusterWide:
+			annotations = withAnnotation(annotations, clusterWideAnnotation)
+		}
+
+		result.SetAnnotations(annotations)
+
+		metadata := templateMetadata(obj)
+		metadata["name"] = obj.GetName()
+
+		if obj.GetNamespace() != "" {
+			metadata["namespace"] = obj.GetNamespace()
+		}
+
+		template := map[string]any{
+			"metadata": metadata,
+		}
+
+		if t, ok := obj.Object["type"].(string); ok && t != "" {
+			template["type"] = t
+		}
+
+		result.Object["spec"] = map[string]any{
+			"encryptedData": encrypted,
+			"template":      template,
+		}
+
+		return result, nil
+	}
+}
+
+// label returns the label binding the encrypted values to the scope.
+func (opts SealOptions) label(obj unstructured.Unstructured) ([]byte, error) {
+	switch opts.Scope {
+	case ScopeClusterWide:
+		return nil, nil
+	case ScopeNamespaceWide:
+		if obj.GetNamespace() == "" {
+			return nil, fmt.Errorf("%w: %s", ErrMissingNamespace, obj.GetName())
+		}
+
+		return []byte(obj.GetNamespace()), nil
+	case ScopeStrict:
+		if obj.GetNamespace() == "" {
+			return nil, fmt.Errorf("%w: %s", ErrMissingNamespace, obj.GetName())
+		}
+
+		return []byte(obj.GetNamespace() + "/" + obj.GetName()), nil
+	default:
+		return nil, fmt.Errorf("unknown scope %q", opts.Scope)
+	}
+}
+
+// encrypt encrypts a value like kubeseal: a random AES-256 session key encrypts the value with
+// AES-GCM, and is itself encrypted with RSA-OAEP using the scope label. The result is the length
+// of the encrypted session key as a big endian uint16, the encrypted session key, and the
+// encrypted value.
+func encrypt(key *rsa.PublicKey, plaintext []byte, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, sessionKeySize)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+
+	result := binary.BigEndian.AppendUint16(nil, uint16(len(encryptedKey)))
+	result = append(result, encryptedKey...)
+
+	// The session key is used once, so a zero nonce is safe
+	return aead.Seal(result, make([]byte, aead.NonceSize()), plaintext, nil), nil
+}
+
+// secretValues returns the decoded data of a Secret merged with its stringData, which takes
+// precedence like on the API server.
+func secretValues(obj unstructured.Unstructured) (map[string][]byte, error) {
+	data, _, err := unstructured.NestedStringMap(obj.Object, "data")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data: %w", err)
+	}
+
+	stringData, _, err := unstructured.NestedStringMap(obj.Object, "stringData")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stringData: %w", err)
+	}
+
+	values := make(map[string][]byte, len(data)+len(stringData))
+
+	for k, v := range data {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %s: %w", k, err)
+		}
+
+		values[k] = decoded
+	}
+
+	for k, v := range stringData {
+		values[k] = []byte(v)
+	}
+
+	return values, nil
+}
+
+// templateMetadata returns the labels and annotations of the Secret to restore from the sealed or
+// external one.
+func templateMetadata(obj unstructured.Unstructured) map[string]any {
+	metadata := make(map[string]any)
+
+	for field, values := range map[string]map[string]string{
+		"labels":      obj.GetLabels(),
+		"annotations": obj.GetAnnotations(),
+	} {
+		if len(values) == 0 {
+			continue
+		}
+
+		m := make(map[string]any, len(values))
+		for k, v := range values {
+			m[k] = v
+		}
+
+		metadata[field] = m
+	}
+
+	return metadata
+}
+
+func withAnnotation(annotations map[string]string, key string) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[key] = "true"
+
+	return annotations
+}
@@ -0,0 +1,141 @@
+package secret
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+// StoreReference references the SecretStore or ClusterSecretStore ExternalSecrets read from.
+type StoreReference struct {
+	// Kind is SecretStore or ClusterSecretStore.
+	Kind string
+
+	// Name is the name of the store.
+	Name string
+}
+
+// ExternalOption is a generic option for the External transformer.
+type ExternalOption = util.Option[ExternalOptions]
+
+// ExternalOptions is a struct-based option that can set multiple ExternalSecret options at once.
+type ExternalOptions struct {
+	// RefreshInterval is how often the values are read from the store, e.g. 1h.
+	RefreshInterval string
+
+	// RemoteKey returns the key of the store holding the values of a Secret.
+	RemoteKey func(secret unstructured.Unstructured) string
+}
+
+// ApplyTo applies the ExternalSecret options to the target configuration.
+func (opts ExternalOptions) ApplyTo(target *ExternalOptions) {
+	if opts.RefreshInterval != "" {
+		target.RefreshInterval = opts.RefreshInterval
+	}
+
+	if opts.RemoteKey != nil {
+		target.RemoteKey = opts.RemoteKey
+	}
+}
+
+// WithRefreshInterval sets how often the values are read from the store.
+// Default: the External Secrets Operator default.
+func WithRefreshInterval(interval string) ExternalOption {
+	return util.FunctionalOption[ExternalOptions](func(opts *ExternalOptions) {
+		opts.RefreshInterval = interval
+	})
+}
+
+// WithRemoteKey sets the function returning the key of the store holding the values of a Secret.
+// Each value of the Secret is read from the property of that key named after the value.
+// Default: namespace/name of the Secret, or its name for Secrets without namespace.
+func WithRemoteKey(fn func(secret unstructured.Unstructured) string) ExternalOption {
+	return util.FunctionalOption[ExternalOptions](func(opts *ExternalOptions) {
+		opts.RemoteKey = fn
+	})
+}
+
+// External returns a transformer replacing Secrets with ExternalSecrets reading the same keys from
+// an external store, so the values never appear in the rendered manifests. The generated Secret
+// keeps the name, type, labels and annotations of the original one. Other objects are returned
+// unchanged.
+func External(store StoreReference, opts ...ExternalOption) types.Transformer {
+	options := ExternalOptions{
+		RemoteKey: func(secret unstructured.Unstructured) string {
+			if secret.GetNamespace() == "" {
+				return secret.GetName()
+			}
+
+			return secret.GetNamespace() + "/" + secret.GetName()
+		},
+	}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(_ context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GroupVersionKind() != secretGVK {
+			return obj, nil
+		}
+
+		values, err := secretValues(obj)
+		if err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		remoteKey := options.RemoteKey(obj)
+		data := make([]any, 0, len(values))
+
+		for _, k := range slices.Sorted(maps.Keys(values)) {
+			data = append(data, map[string]any{
+				"secretKey": k,
+				"remoteRef": map[string]any{
+					"key":      remoteKey,
+					"property": k,
+				},
+			})
+		}
+
+		template := map[string]any{}
+
+		if metadata := templateMetadata(obj); len(metadata) > 0 {
+			template["metadata"] = metadata
+		}
+
+		if t, ok := obj.Object["type"].(string); ok && t != "" {
+			template["type"] = t
+		}
+
+		spec := map[string]any{
+			"secretStoreRef": map[string]any{
+				"kind": store.Kind,
+				"name": store.Name,
+			},
+			"target": map[string]any{
+				"name":     obj.GetName(),
+				"template": template,
+			},
+			"data": data,
+		}
+
+		if options.RefreshInterval != "" {
+			spec["refreshInterval"] = options.RefreshInterval
+		}
+
+		result := unstructured.Unstructured{Object: map[string]any{}}
+		result.SetGroupVersionKind(ExternalSecretGVK)
+		result.SetName(obj.GetName())
+		result.SetNamespace(obj.GetNamespace())
+		result.SetLabels(obj.GetLabels())
+		result.SetAnnotations(obj.GetAnnotations())
+		result.Object["spec"] = spec
+
+		return result, nil
+	}
+}
@@ -0,0 +1,241 @@
+package secret_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/secret"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeSecret(namespace string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]any{
+				"name":   "db",
+				"labels": map[string]any{"app": "shop"},
+			},
+			"type": "Opaque",
+			"data": map[string]any{
+				"password": base64.StdEncoding.EncodeToString([]byte("s3cr3t")),
+			},
+			"stringData": map[string]any{
+				"user": "admin",
+			},
+		},
+	}
+	obj.SetNamespace(namespace)
+
+	return obj
+}
+
+// decrypt reverses the kubeseal hybrid encryption.
+func decrypt(g *WithT, key *rsa.PrivateKey, value any, label string) ([]byte, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(value.(string))
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	size := int(binary.BigEndian.Uint16(ciphertext))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext[2:2+size], []byte(label))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(sessionKey)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	aead, err := cipher.NewGCM(block)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[2+size:], nil)
+}
+
+func TestSeal(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	t.Run("should seal Secrets in strict scope", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := secret.Seal(&key.PublicKey)(t.Context(), makeSecret("shop"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GroupVersionKind()).Should(Equal(secret.SealedSecretGVK))
+		g.Expect(obj.GetName()).Should(Equal("db"))
+		g.Expect(obj.GetNamespace()).Should(Equal("shop"))
+		g.Expect(obj.Object).ShouldNot(HaveKey("data"))
+
+		encrypted, _, err := unstructured.NestedMap(obj.Object, "spec", "encryptedData")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(encrypted).Should(HaveLen(2))
+
+		password, err := decrypt(g, key, encrypted["password"], "shop/db")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(string(password)).Should(Equal("s3cr3t"))
+
+		user, err := decrypt(g, key, encrypted["user"], "shop/db")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(string(user)).Should(Equal("admin"))
+
+		// Values are bound to the name and namespace
+		_, err = decrypt(g, key, encrypted["user"], "other/db")
+		g.Expect(err).Should(HaveOccurred())
+
+		template, _, err := unstructured.NestedMap(obj.Object, "spec", "template")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(template).Should(HaveKeyWithValue("type", "Opaque"))
+		g.Expect(template).Should(HaveKeyWithValue("metadata", And(
+			HaveKeyWithValue("name", "db"),
+			HaveKeyWithValue("namespace", "shop"),
+			HaveKeyWithValue("labels", HaveKeyWithValue("app", "shop")),
+		)))
+	})
+
+	t.Run("should seal Secrets in wider scopes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := secret.Seal(&key.PublicKey, secret.WithScope(secret.ScopeNamespaceWide))(t.Context(), makeSecret("shop"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue("sealedsecrets.bitnami.com/namespace-wide", "true"))
+
+		encrypted, _, _ := unstructured.NestedMap(obj.Object, "spec", "encryptedData")
+		_, err = decrypt(g, key, encrypted["user"], "shop")
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		obj, err = secret.Seal(&key.PublicKey, secret.WithScope(secret.ScopeClusterWide))(t.Context(), makeSecret(""))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetAnnotations()).Should(HaveKeyWithValue("sealedsecrets.bitnami.com/cluster-wide", "true"))
+
+		encrypted, _, _ = unstructured.NestedMap(obj.Object, "spec", "encryptedData")
+		_, err = decrypt(g, key, encrypted["user"], "")
+		g.Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	t.Run("should fail on Secrets without namespace in strict scope", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := secret.Seal(&key.PublicKey)(t.Context(), makeSecret(""))
+		g.Expect(err).Should(MatchError(secret.ErrMissingNamespace))
+	})
+
+	t.Run("should not change other objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cm := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "config"},
+		}}
+
+		obj, err := secret.Seal(&key.PublicKey)(t.Context(), cm)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj).Should(Equal(cm))
+	})
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	NewWithT(t).Expect(err).ShouldNot(HaveOccurred())
+
+	t.Run("should parse certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		template := x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "sealed-secret"},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		parsed, err := secret.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(parsed.Equal(&key.PublicKey)).Should(BeTrue())
+	})
+
+	t.Run("should parse public keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		parsed, err := secret.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(parsed.Equal(&key.PublicKey)).Should(BeTrue())
+	})
+
+	t.Run("should reject invalid data", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := secret.ParsePublicKey([]byte("not a key"))
+		g.Expect(err).Should(MatchError(secret.ErrInvalidKey))
+	})
+}
+
+func TestExternal(t *testing.T) {
+	store := secret.StoreReference{Kind: "ClusterSecretStore", Name: "vault"}
+
+	t.Run("should replace Secrets with ExternalSecrets", func(t *testing.T) {
+		g := NewWithT(t)
+
+		obj, err := secret.External(store, secret.WithRefreshInterval("1h"))(t.Context(), makeSecret("shop"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GroupVersionKind()).Should(Equal(secret.ExternalSecretGVK))
+		g.Expect(obj.GetName()).Should(Equal("db"))
+		g.Expect(obj.GetNamespace()).Should(Equal("shop"))
+
+		spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(spec).Should(HaveKeyWithValue("refreshInterval", "1h"))
+		g.Expect(spec).Should(HaveKeyWithValue("secretStoreRef", map[string]any{"kind": "ClusterSecretStore", "name": "vault"}))
+		g.Expect(spec).Should(HaveKeyWithValue("target", And(
+			HaveKeyWithValue("name", "db"),
+			HaveKeyWithValue("template", And(
+				HaveKeyWithValue("type", "Opaque"),
+				HaveKeyWithValue("metadata", HaveKeyWithValue("labels", HaveKeyWithValue("app", "shop"))),
+			)),
+		)))
+		g.Expect(spec["data"]).Should(Equal([]any{
+			map[string]any{
+				"secretKey": "password",
+				"remoteRef": map[string]any{"key": "shop/db", "property": "password"},
+			},
+			map[string]any{
+				"secretKey": "user",
+				"remoteRef": map[string]any{"key": "shop/db", "property": "user"},
+			},
+		}))
+	})
+
+	t.Run("should use a custom remote key", func(t *testing.T) {
+		g := NewWithT(t)
+
+		remoteKey := func(s unstructured.Unstructured) string {
+			return "apps/" + s.GetName()
+		}
+
+		obj, err := secret.External(store, secret.WithRemoteKey(remoteKey))(t.Context(), makeSecret("shop"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		data, _, err := unstructured.NestedSlice(obj.Object, "spec", "data")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(data).Should(HaveEach(HaveKeyWithValue("remoteRef", HaveKeyWithValue("key", "apps/db"))))
+		g.Expect(obj.Object["spec"]).ShouldNot(HaveKey("refreshInterval"))
+	})
+}
@@ -0,0 +1,244 @@
+// Package securitycontext provides a transformer hardening the security context of workloads
+// with Pod Security Standards defaults.
+package securitycontext
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// SeccompRuntimeDefault is the seccomp profile type of the container runtime default profile.
+const SeccompRuntimeDefault = "RuntimeDefault"
+
+// Settings are the security context fields applied by the transformer. Nil and empty fields are
+// not applied, so that presets can be adjusted field by field.
+type Settings struct {
+	// RunAsNonRoot is set on the pod security context.
+	RunAsNonRoot *bool
+
+	// SeccompProfile is the seccomp profile type set on the pod security context.
+	SeccompProfile string
+
+	// Privileged is set on the container security contexts.
+	Privileged *bool
+
+	// AllowPrivilegeEscalation is set on the container security contexts.
+	AllowPrivilegeEscalation *bool
+
+	// DropCapabilities are dropped by the container security contexts, e.g. ALL.
+	DropCapabilities []string
+
+	// ReadOnlyRootFilesystem is set on the container security contexts.
+	ReadOnlyRootFilesystem *bool
+}
+
+// Baseline returns the settings of the baseline Pod Security Standard: no privileged containers
+// and the runtime default seccomp profile.
+func Baseline() Settings {
+	return Settings{
+		SeccompProfile: SeccompRuntimeDefault,
+		Privileged:     ptr(false),
+	}
+}
+
+// Restricted returns the settings of the restricted Pod Security Standard: the baseline ones,
+// running as non-root, no privilege escalation and all capabilities dropped. It also sets a
+// read-only root filesystem, which goes beyond the standard; set ReadOnlyRootFilesystem to nil
+// for workloads writing to their filesystem.
+func Restricted() Settings {
+	settings := Baseline()
+	settings.RunAsNonRoot = ptr(true)
+	settings.AllowPrivilegeEscalation = ptr(false)
+	settings.DropCapabilities = []string{"ALL"}
+	settings.ReadOnlyRootFilesystem = ptr(true)
+
+	return settings
+}
+
+// Option is a generic option for the security context transformer.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple security context options at once.
+type Options struct {
+	// Target selects the objects to harden; other objects are returned unchanged.
+	Target types.Filter
+
+	// Override replaces the values explicitly set by the workloads.
+	Override bool
+}
+
+// ApplyTo applies the security context options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+
+	target.Override = opts.Override
+}
+
+// WithTarget restricts the transformer to the objects accepted by the filter.
+// Default: all objects holding a pod spec.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// WithOverride replaces the values explicitly set by the workloads, instead of only setting the
+// missing ones. Dropped capabilities are added to the ones already dropped.
+// Default: false.
+func WithOverride(override bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Override = override
+	})
+}
+
+// Harden returns a transformer applying the settings to the pod and container security contexts
+// of the workloads. Containers and init containers are hardened.
+//
+// Example:
+//
+//	settings := securitycontext.Restricted()
+//	settings.ReadOnlyRootFilesystem = nil
+//
+//	t := securitycontext.Harden(settings)
+func Harden(settings Settings, opts ...Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if options.Target != nil {
+			ok, err := options.Target(ctx, obj)
+			if err != nil {
+				return obj, transformer.Wrap(obj, err)
+			}
+
+			if !ok {
+				return obj, nil
+			}
+		}
+
+		err := k8s.VisitPodSpecs(obj.Object, func(podSpec map[string]any) error {
+			return harden(podSpec, settings, options.Override)
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+func harden(podSpec map[string]any, settings Settings, override bool) error {
+	podContext, err := securityContext(podSpec)
+	if err != nil {
+		return err
+	}
+
+	setBool(podContext, "runAsNonRoot", settings.RunAsNonRoot, override)
+
+	if settings.SeccompProfile != "" {
+		if _, ok := podContext["seccompProfile"]; !ok || override {
+			podContext["seccompProfile"] = map[string]any{"type": settings.SeccompProfile}
+		}
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, err := unstructured.NestedSlice(podSpec, field)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range containers {
+			container, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if err := hardenContainer(container, settings, override); err != nil {
+				return err
+			}
+		}
+
+		if containers != nil {
+			podSpec[field] = containers
+		}
+	}
+
+	return nil
+}
+
+func hardenContainer(container map[string]any, settings Settings, override bool) error {
+	containerContext, err := securityContext(container)
+	if err != nil {
+		return err
+	}
+
+	setBool(containerContext, "privileged", settings.Privileged, override)
+	setBool(containerContext, "allowPrivilegeEscalation", settings.AllowPrivilegeEscalation, override)
+	setBool(containerContext, "readOnlyRootFilesystem", settings.ReadOnlyRootFilesystem, override)
+
+	if len(settings.DropCapabilities) == 0 {
+		return nil
+	}
+
+	drop, found, err := unstructured.NestedStringSlice(containerContext, "capabilities", "drop")
+	if err != nil {
+		return err
+	}
+
+	if found && !override {
+		return nil
+	}
+
+	for _, capability := range settings.DropCapabilities {
+		if !slices.Contains(drop, capability) {
+			drop = append(drop, capability)
+		}
+	}
+
+	return unstructured.SetNestedStringSlice(containerContext, drop, "capabilities", "drop")
+}
+
+// securityContext returns the securityContext map of content, creating it if missing.
+func securityContext(content map[string]any) (map[string]any, error) {
+	if _, ok := content["securityContext"]; !ok {
+		content["securityContext"] = make(map[string]any)
+	}
+
+	sc, ok := content["securityContext"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("securityContext is %T, expected an object", content["securityContext"])
+	}
+
+	return sc, nil
+}
+
+// setBool sets a boolean field if not set yet, or if override is set.
+func setBool(content map[string]any, field string, value *bool, override bool) {
+	if value == nil {
+		return
+	}
+
+	if _, ok := content[field]; !ok || override {
+		content[field] = *value
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
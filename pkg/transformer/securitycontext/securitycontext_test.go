@@ -0,0 +1,129 @@
+package securitycontext_test
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/securitycontext"
+
+	. "github.com/onsi/gomega"
+)
+
+func makePod() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": "app"},
+			"spec": map[string]any{
+				"securityContext": map[string]any{"runAsNonRoot": false},
+				"initContainers": []any{
+					map[string]any{"name": "init"},
+				},
+				"containers": []any{
+					map[string]any{
+						"name": "app",
+						"securityContext": map[string]any{
+							"readOnlyRootFilesystem": false,
+							"capabilities":           map[string]any{"drop": []any{"NET_RAW"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func containerContext(g *WithT, obj unstructured.Unstructured, field string) map[string]any {
+	containers, _, err := unstructured.NestedSlice(obj.Object, "spec", field)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	container, ok := containers[0].(map[string]any)
+	g.Expect(ok).Should(BeTrue())
+
+	sc, _, err := unstructured.NestedMap(container, "securityContext")
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	return sc
+}
+
+func TestHarden(t *testing.T) {
+	t.Run("should set missing restricted fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := securitycontext.Harden(securitycontext.Restricted())(t.Context(), makePod())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		pod, _, err := unstructured.NestedMap(result.Object, "spec", "securityContext")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(pod).Should(Equal(map[string]any{
+			"runAsNonRoot":   false,
+			"seccompProfile": map[string]any{"type": "RuntimeDefault"},
+		}))
+
+		g.Expect(containerContext(g, result, "initContainers")).Should(Equal(map[string]any{
+			"privileged":               false,
+			"allowPrivilegeEscalation": false,
+			"readOnlyRootFilesystem":   true,
+			"capabilities":             map[string]any{"drop": []any{"ALL"}},
+		}))
+
+		app := containerContext(g, result, "containers")
+		g.Expect(app).Should(HaveKeyWithValue("readOnlyRootFilesystem", false))
+		g.Expect(app).Should(HaveKeyWithValue("capabilities", map[string]any{"drop": []any{"NET_RAW"}}))
+	})
+
+	t.Run("should override explicit values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := securitycontext.Harden(securitycontext.Restricted(), securitycontext.WithOverride(true))
+
+		result, err := tr(t.Context(), makePod())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		pod, _, err := unstructured.NestedMap(result.Object, "spec", "securityContext")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(pod).Should(HaveKeyWithValue("runAsNonRoot", true))
+
+		app := containerContext(g, result, "containers")
+		g.Expect(app).Should(HaveKeyWithValue("readOnlyRootFilesystem", true))
+		g.Expect(app).Should(HaveKeyWithValue("capabilities", map[string]any{"drop": []any{"NET_RAW", "ALL"}}))
+	})
+
+	t.Run("should skip unset fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		settings := securitycontext.Restricted()
+		settings.ReadOnlyRootFilesystem = nil
+		settings.DropCapabilities = nil
+
+		result, err := securitycontext.Harden(settings)(t.Context(), makePod())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		init := containerContext(g, result, "initContainers")
+		g.Expect(init).ShouldNot(HaveKey("readOnlyRootFilesystem"))
+		g.Expect(init).ShouldNot(HaveKey("capabilities"))
+	})
+
+	t.Run("should apply the baseline settings", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := securitycontext.Harden(securitycontext.Baseline())(t.Context(), makePod())
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(containerContext(g, result, "initContainers")).Should(Equal(map[string]any{
+			"privileged": false,
+		}))
+	})
+
+	t.Run("should fail on invalid security contexts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := makePod()
+		g.Expect(unstructured.SetNestedField(pod.Object, "invalid", "spec", "securityContext")).Should(Succeed())
+
+		_, err := securitycontext.Harden(securitycontext.Restricted())(t.Context(), pod)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
@@ -0,0 +1,125 @@
+// Package serviceaccount provides transformers wiring workloads into pre-provisioned identities:
+// the service account they run as and the secrets used to pull their images.
+package serviceaccount
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the service account transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple service account options at once.
+type Options struct {
+	// Target selects the objects to update; other objects are returned unchanged.
+	Target types.Filter
+}
+
+// ApplyTo applies the service account options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+}
+
+// WithTarget restricts the transformer to the objects accepted by the filter, e.g.
+// labels.PartOf("shop").
+// Default: all objects holding a pod spec, and ServiceAccounts for ImagePullSecrets.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// Set returns a transformer setting the service account the pod specs run as. The deprecated
+// serviceAccount field is updated as well when present.
+func Set(name string, opts ...Option) types.Transformer {
+	return transform(opts, func(obj unstructured.Unstructured) error {
+		return k8s.VisitPodSpecs(obj.Object, func(podSpec map[string]any) error {
+			podSpec["serviceAccountName"] = name
+
+			if _, ok := podSpec["serviceAccount"]; ok {
+				podSpec["serviceAccount"] = name
+			}
+
+			return nil
+		})
+	})
+}
+
+// ImagePullSecrets returns a transformer appending image pull secrets to the pod specs and to
+// ServiceAccount objects, so that both pods referencing them and pods running as the service
+// accounts can pull their images. Secrets already referenced are not duplicated.
+func ImagePullSecrets(secrets []string, opts ...Option) types.Transformer {
+	return transform(opts, func(obj unstructured.Unstructured) error {
+		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "ServiceAccount" {
+			return appendSecrets(obj.Object, secrets)
+		}
+
+		return k8s.VisitPodSpecs(obj.Object, func(podSpec map[string]any) error {
+			return appendSecrets(podSpec, secrets)
+		})
+	})
+}
+
+func transform(opts []Option, fn func(obj unstructured.Unstructured) error) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if options.Target != nil {
+			ok, err := options.Target(ctx, obj)
+			if err != nil {
+				return obj, transformer.Wrap(obj, err)
+			}
+
+			if !ok {
+				return obj, nil
+			}
+		}
+
+		if err := fn(obj); err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// appendSecrets adds the secrets missing from the imagePullSecrets list of content.
+func appendSecrets(content map[string]any, secrets []string) error {
+	current, _, err := unstructured.NestedSlice(content, "imagePullSecrets")
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		exists := slices.ContainsFunc(current, func(item any) bool {
+			m, ok := item.(map[string]any)
+
+			return ok && m["name"] == secret
+		})
+
+		if !exists {
+			current = append(current, map[string]any{"name": secret})
+		}
+	}
+
+	content["imagePullSecrets"] = current
+
+	return nil
+}
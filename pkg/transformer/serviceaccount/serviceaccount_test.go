@@ -0,0 +1,93 @@
+package serviceaccount_test
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/serviceaccount"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeJob(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata":   map[string]any{"name": name},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"serviceAccount":   "default",
+						"containers":       []any{map[string]any{"name": "job"}},
+						"imagePullSecrets": []any{map[string]any{"name": "registry"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func podSpec(g *WithT, obj unstructured.Unstructured) map[string]any {
+	spec, found, err := unstructured.NestedMap(obj.Object, "spec", "template", "spec")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(found).Should(BeTrue())
+
+	return spec
+}
+
+func TestServiceAccount(t *testing.T) {
+	t.Run("should set the service account", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := serviceaccount.Set("runner")(t.Context(), makeJob("job"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		spec := podSpec(g, result)
+		g.Expect(spec).Should(HaveKeyWithValue("serviceAccountName", "runner"))
+		g.Expect(spec).Should(HaveKeyWithValue("serviceAccount", "runner"))
+	})
+
+	t.Run("should append image pull secrets to pod specs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := serviceaccount.ImagePullSecrets([]string{"registry", "mirror"})(t.Context(), makeJob("job"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(podSpec(g, result)).Should(HaveKeyWithValue("imagePullSecrets", []any{
+			map[string]any{"name": "registry"},
+			map[string]any{"name": "mirror"},
+		}))
+	})
+
+	t.Run("should append image pull secrets to service accounts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		sa := unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ServiceAccount",
+			"metadata":   map[string]any{"name": "runner"},
+		}}
+
+		result, err := serviceaccount.ImagePullSecrets([]string{"mirror"})(t.Context(), sa)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result.Object).Should(HaveKeyWithValue("imagePullSecrets", []any{
+			map[string]any{"name": "mirror"},
+		}))
+	})
+
+	t.Run("should only update targeted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := serviceaccount.Set("runner", serviceaccount.WithTarget(
+			func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+				return obj.GetName() == "job", nil
+			},
+		))
+
+		result, err := tr(t.Context(), makeJob("other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(podSpec(g, result)).ShouldNot(HaveKey("serviceAccountName"))
+	})
+}
@@ -0,0 +1,177 @@
+// Package sidecar provides transformers injecting containers into the pod specs of workloads,
+// e.g. logging or mesh agents not supported by upstream charts.
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Option is a generic option for the sidecar transformers.
+type Option = util.Option[Options]
+
+// Options is a struct-based option that can set multiple sidecar options at once.
+type Options struct {
+	// Target selects the objects to inject into; other objects are returned unchanged.
+	Target types.Filter
+
+	// Volumes are added to the pod specs along with the container.
+	Volumes []corev1.Volume
+
+	// Prepend inserts the container before the existing ones instead of after them.
+	Prepend bool
+}
+
+// ApplyTo applies the sidecar options to the target configuration.
+func (opts Options) ApplyTo(target *Options) {
+	if opts.Target != nil {
+		target.Target = opts.Target
+	}
+
+	target.Volumes = append(target.Volumes, opts.Volumes...)
+	target.Prepend = opts.Prepend
+}
+
+// WithTarget restricts the injection to the objects accepted by the filter, e.g.
+// gvk.Kind("Deployment") or labels.PartOf("shop").
+// Default: all objects holding a pod spec.
+func WithTarget(target types.Filter) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Target = target
+	})
+}
+
+// WithVolumes adds volumes to the pod specs along with the container, e.g. a shared emptyDir.
+// Volumes already defined with the same name are left unchanged.
+func WithVolumes(volumes ...corev1.Volume) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Volumes = append(opts.Volumes, volumes...)
+	})
+}
+
+// WithPrepend inserts the container before the existing ones, e.g. for init containers that
+// must run first.
+// Default: false, the container is appended.
+func WithPrepend(prepend bool) Option {
+	return util.FunctionalOption[Options](func(opts *Options) {
+		opts.Prepend = prepend
+	})
+}
+
+// Inject returns a transformer adding the container to the containers of the pod specs.
+// A container with the same name is replaced in place, so injecting is idempotent.
+//
+// Example:
+//
+//	sidecar.Inject(corev1.Container{
+//	    Name:  "log-agent",
+//	    Image: "fluent/fluent-bit:3.0",
+//	    VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/var/log/app"}},
+//	},
+//	    sidecar.WithTarget(gvk.Kind("Deployment")),
+//	    sidecar.WithVolumes(corev1.Volume{Name: "logs", VolumeSource: corev1.VolumeSource{
+//	        EmptyDir: &corev1.EmptyDirVolumeSource{},
+//	    }}),
+//	)
+func Inject(container corev1.Container, opts ...Option) types.Transformer {
+	return inject("containers", container, opts)
+}
+
+// InjectInit returns a transformer adding the container to the init containers of the pod specs.
+// Native sidecars are init containers with restartPolicy Always.
+func InjectInit(container corev1.Container, opts ...Option) types.Transformer {
+	return inject("initContainers", container, opts)
+}
+
+func inject(field string, container corev1.Container, opts []Option) types.Transformer {
+	options := Options{}
+
+	for _, opt := range opts {
+		opt.ApplyTo(&options)
+	}
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if options.Target != nil {
+			ok, err := options.Target(ctx, obj)
+			if err != nil {
+				return obj, transformer.Wrap(obj, err)
+			}
+
+			if !ok {
+				return obj, nil
+			}
+		}
+
+		err := k8s.VisitPodSpecs(obj.Object, func(podSpec map[string]any) error {
+			item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&container)
+			if err != nil {
+				return fmt.Errorf("failed to convert container %s: %w", container.Name, err)
+			}
+
+			if err := add(podSpec, field, item, true, options.Prepend); err != nil {
+				return err
+			}
+
+			for _, volume := range options.Volumes {
+				item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&volume)
+				if err != nil {
+					return fmt.Errorf("failed to convert volume %s: %w", volume.Name, err)
+				}
+
+				if err := add(podSpec, "volumes", item, false, false); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return obj, &transformer.Error{
+				Object: obj,
+				Err:    err,
+			}
+		}
+
+		return obj, nil
+	}
+}
+
+// add adds an item to a list of named items of the pod spec. An item with the same name is
+// replaced in place if override is set, kept otherwise.
+func add(podSpec map[string]any, field string, item map[string]any, override bool, prepend bool) error {
+	items, _, err := unstructured.NestedSlice(podSpec, field)
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(items, func(existing any) bool {
+		m, ok := existing.(map[string]any)
+
+		return ok && m["name"] == item["name"]
+	})
+
+	switch {
+	case index >= 0 && override:
+		items[index] = item
+	case index >= 0:
+		return nil
+	case prepend:
+		items = slices.Insert(items, 0, any(item))
+	default:
+		items = append(items, item)
+	}
+
+	podSpec[field] = items
+
+	return nil
+}
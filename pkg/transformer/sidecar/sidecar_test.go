@@ -0,0 +1,121 @@
+package sidecar_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer/sidecar"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": name},
+			"spec": map[string]any{
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "app", "image": "app:1.0"},
+						},
+						"volumes": []any{
+							map[string]any{"name": "logs", "configMap": map[string]any{"name": "logs"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func names(g *WithT, obj unstructured.Unstructured, field string) []string {
+	items, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", field)
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		g.Expect(ok).Should(BeTrue())
+
+		result = append(result, m["name"].(string))
+	}
+
+	return result
+}
+
+func TestSidecar(t *testing.T) {
+	agent := corev1.Container{
+		Name:  "log-agent",
+		Image: "fluent/fluent-bit:3.0",
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "logs", MountPath: "/var/log/app"},
+		},
+	}
+
+	t.Run("should append the container and volumes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := sidecar.Inject(agent, sidecar.WithVolumes(
+			corev1.Volume{Name: "logs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			corev1.Volume{Name: "buffer", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		))
+
+		result, err := tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(names(g, result, "containers")).Should(Equal([]string{"app", "log-agent"}))
+		g.Expect(names(g, result, "volumes")).Should(Equal([]string{"logs", "buffer"}))
+
+		volumes, _, err := unstructured.NestedSlice(result.Object, "spec", "template", "spec", "volumes")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(volumes[0]).Should(HaveKey("configMap"))
+	})
+
+	t.Run("should replace a container with the same name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := sidecar.Inject(agent)
+
+		result, err := tr(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = tr(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(names(g, result, "containers")).Should(Equal([]string{"app", "log-agent"}))
+	})
+
+	t.Run("should prepend init containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		first := sidecar.InjectInit(corev1.Container{Name: "setup", Image: "busybox"})
+		second := sidecar.InjectInit(corev1.Container{Name: "mesh-init", Image: "mesh"}, sidecar.WithPrepend(true))
+
+		result, err := first(t.Context(), makeDeployment("app"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err = second(t.Context(), result)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		g.Expect(names(g, result, "initContainers")).Should(Equal([]string{"mesh-init", "setup"}))
+		g.Expect(names(g, result, "containers")).Should(Equal([]string{"app"}))
+	})
+
+	t.Run("should only inject into targeted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tr := sidecar.Inject(agent, sidecar.WithTarget(func(_ context.Context, obj unstructured.Unstructured) (bool, error) {
+			return obj.GetName() == "app", nil
+		}))
+
+		result, err := tr(t.Context(), makeDeployment("other"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(names(g, result, "containers")).Should(Equal([]string{"app"}))
+	})
+}
@@ -0,0 +1,77 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/types"
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+// Map returns a transformer that decodes objects into the typed struct T, e.g. appsv1.Deployment,
+// lets fn mutate it and encodes the result back, so complex mutations are checked at compile time
+// instead of navigating maps. Fields unknown to T are dropped, so T has to match the kind of the
+// objects; use ForKind to only transform objects of a given kind.
+func Map[T any](fn func(ctx context.Context, object *T) error) types.Transformer {
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		var typed T
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &typed); err != nil {
+			return unstructured.Unstructured{}, fmt.Errorf("unable to convert object to %T: %w", typed, err)
+		}
+
+		if err := fn(ctx, &typed); err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		result, err := k8s.ToUnstructured(&typed)
+		if err != nil {
+			return unstructured.Unstructured{}, err
+		}
+
+		result.SetGroupVersionKind(obj.GroupVersionKind())
+
+		// Zero values the object didn't have are encoded as null or empty fields
+		if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "metadata", "creationTimestamp"); !found {
+			if ts, found, _ := unstructured.NestedFieldNoCopy(result.Object, "metadata", "creationTimestamp"); found && ts == nil {
+				unstructured.RemoveNestedField(result.Object, "metadata", "creationTimestamp")
+			}
+		}
+
+		if _, found := obj.Object["status"]; !found {
+			if status, ok := result.Object["status"].(map[string]any); ok && len(status) == 0 {
+				delete(result.Object, "status")
+			}
+		}
+
+		return *result, nil
+	}
+}
+
+// ForKind returns a transformer that decodes objects of the given GroupVersionKind into the typed
+// struct T, lets fn mutate it and encodes the result back, like Map. Objects of other kinds are
+// returned unchanged.
+//
+// Example:
+//
+//	transformer.ForKind(appsv1.SchemeGroupVersion.WithKind("Deployment"), func(d *appsv1.Deployment) error {
+//		d.Spec.Replicas = ptr.To[int32](3)
+//		return nil
+//	})
+func ForKind[T any](gvk schema.GroupVersionKind, fn func(object *T) error) types.Transformer {
+	mapper := Map(func(_ context.Context, object *T) error {
+		return fn(object)
+	})
+
+	return func(ctx context.Context, obj unstructured.Unstructured) (unstructured.Unstructured, error) {
+		if obj.GroupVersionKind() != gvk {
+			return obj, nil
+		}
+
+		return mapper(ctx, obj)
+	}
+}
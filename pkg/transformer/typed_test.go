@@ -0,0 +1,119 @@
+package transformer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/transformer"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]any{
+				"name": name,
+			},
+			"spec": map[string]any{
+				"replicas": int64(1),
+				"template": map[string]any{
+					"spec": map[string]any{
+						"containers": []any{
+							map[string]any{"name": "app", "image": "nginx"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMap(t *testing.T) {
+
+	t.Run("should mutate the typed object", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.Map(func(_ context.Context, d *appsv1.Deployment) error {
+			d.Spec.Replicas = ptr.To[int32](3)
+			d.Spec.Template.Spec.Containers[0].Image = "nginx:1.27"
+
+			return nil
+		})
+
+		obj, err := tr(t.Context(), makeDeployment("web"))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(obj.GetKind()).Should(Equal("Deployment"))
+		g.Expect(obj.GetAPIVersion()).Should(Equal("apps/v1"))
+		g.Expect(obj.GetName()).Should(Equal("web"))
+
+		replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(replicas).Should(Equal(int64(3)))
+
+		containers, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(containers).Should(ConsistOf(HaveKeyWithValue("image", "nginx:1.27")))
+
+		// Zero values are not added
+		g.Expect(obj.Object).ShouldNot(HaveKey("status"))
+		g.Expect(obj.Object["metadata"]).ShouldNot(HaveKey("creationTimestamp"))
+	})
+
+	t.Run("should return error from fn", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.Map(func(_ context.Context, _ *metav1.PartialObjectMetadata) error {
+			return errors.New("typed error")
+		})
+
+		_, err := tr(t.Context(), makePod("test"))
+		g.Expect(err).Should(MatchError("typed error"))
+	})
+
+	t.Run("should return error for objects not matching the type", func(t *testing.T) {
+		g := NewWithT(t)
+		tr := transformer.Map(func(_ context.Context, _ *corev1.Pod) error {
+			return nil
+		})
+
+		obj := makePod("test")
+		obj.Object["spec"] = "invalid"
+
+		_, err := tr(t.Context(), obj)
+		g.Expect(err).Should(HaveOccurred())
+	})
+}
+
+func TestForKind(t *testing.T) {
+	g := NewWithT(t)
+
+	called := 0
+	tr := transformer.ForKind(appsv1.SchemeGroupVersion.WithKind("Deployment"), func(d *appsv1.Deployment) error {
+		called++
+		d.Spec.Replicas = ptr.To[int32](5)
+
+		return nil
+	})
+
+	pod := makePod("test")
+	obj, err := tr(t.Context(), pod)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(obj).Should(Equal(pod))
+	g.Expect(called).Should(Equal(0))
+
+	obj, err = tr(t.Context(), makeDeployment("web"))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(called).Should(Equal(1))
+
+	replicas, _, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(replicas).Should(Equal(int64(5)))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -21,10 +22,26 @@ var (
 // and returns true if the object should be kept, or false if it should be discarded.
 type Filter func(ctx context.Context, object unstructured.Unstructured) (bool, error)
 
+// CollectionFilter is a function type that decides whether to keep a single unstructured.Unstructured
+// object given the whole set of objects it belongs to, e.g. to keep Services only if a matching
+// Deployment exists. The object is part of objects.
+type CollectionFilter func(
+	ctx context.Context,
+	object unstructured.Unstructured,
+	objects []unstructured.Unstructured,
+) (bool, error)
+
 // Transformer is a function type that processes a single unstructured.Unstructured object
 // and returns the transformed object.
 type Transformer func(ctx context.Context, object unstructured.Unstructured) (unstructured.Unstructured, error)
 
+// CollectionTransformer is a function type that processes the whole set of unstructured.Unstructured
+// objects and returns the resulting set, e.g. to add objects derived from the others or to reorder them.
+type CollectionTransformer func(
+	ctx context.Context,
+	objects []unstructured.Unstructured,
+) ([]unstructured.Unstructured, error)
+
 // Renderer is a non-generic interface that concrete renderer types implement.
 // This allows the Engine to manage them heterogeneously.
 type Renderer interface {
@@ -39,6 +56,38 @@ type Renderer interface {
 	Name() string
 }
 
+// Source is implemented by the sources of the renderers that can be created with their default
+// options, so that an engine can be assembled from sources only, e.g. by engine.FromSources.
+type Source interface {
+	// NewRenderer creates a renderer with default options rendering the source.
+	NewRenderer() (Renderer, error)
+}
+
+// Invalidator is implemented by renderers caching their results, so the cached results can be
+// discarded when the sources change, e.g. by engine.Watch.
+type Invalidator interface {
+	// Invalidate discards all the cached results of the renderer.
+	Invalidate()
+}
+
+// WatchSource is a set of local files read by a renderer: the files matching Pattern within FS,
+// or within the local filesystem when FS is nil, including the files of the matched directories.
+type WatchSource struct {
+	// FS is the filesystem containing the files, nil for the local filesystem.
+	FS fs.FS
+
+	// Pattern is the glob pattern matching the files and directories, as accepted by fs.Glob,
+	// or by filepath.Glob when FS is nil.
+	Pattern string
+}
+
+// Watchable is implemented by renderers reading local files, so that changes to those files can
+// be detected, e.g. by engine.Watch.
+type Watchable interface {
+	// WatchSources returns the local files read by the renderer.
+	WatchSources() []WatchSource
+}
+
 // ValidateRenderer checks if a Renderer implementation is valid.
 // Returns an error if the renderer is nil or if Name() returns an empty string.
 func ValidateRenderer(r Renderer) error {
@@ -27,6 +27,24 @@ type Interface[T any] interface {
 	Sync()
 }
 
+// Clearer is optionally implemented by caches able to remove all their entries at once, e.g. when
+// the cached sources changed. The caches created by New and NewRenderCache implement it.
+type Clearer interface {
+	// Clear removes all entries from the cache.
+	Clear()
+}
+
+// Clear removes all entries from c if it implements Clearer, and reports whether it did.
+// Entries of other caches are left to expire with their TTL.
+func Clear[T any](c Interface[T]) bool {
+	clearer, ok := c.(Clearer)
+	if ok {
+		clearer.Clear()
+	}
+
+	return ok
+}
+
 type entry[T any] struct {
 	value      T
 	expiration time.Time
@@ -109,6 +127,14 @@ func (c *defaultCache[T]) Sync() {
 	}
 }
 
+// Clear removes all entries from the cache.
+func (c *defaultCache[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
+
 // renderCache wraps a cache and automatically deep clones unstructured slices on get/set.
 type renderCache struct {
 	cache Interface[[]unstructured.Unstructured]
@@ -150,3 +176,11 @@ func (r *renderCache) Sync() {
 
 	r.cache.Sync()
 }
+
+func (r *renderCache) Clear() {
+	if r == nil || r.cache == nil {
+		return
+	}
+
+	Clear(r.cache)
+}
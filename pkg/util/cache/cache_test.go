@@ -204,8 +204,50 @@ func TestCache(t *testing.T) {
 		g.Expect(found).To(BeTrue())
 		g.Expect(cached[0].GetName()).To(Equal("v2"))
 	})
+	t.Run("should remove all entries on Clear", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.New[[]unstructured.Unstructured](cache.WithTTL(5 * time.Minute))
+
+		c.Set("key1", []unstructured.Unstructured{})
+		c.Set("key2", []unstructured.Unstructured{})
+
+		g.Expect(cache.Clear(c)).To(BeTrue())
+
+		_, found := c.Get("key1")
+		g.Expect(found).To(BeFalse())
+
+		_, found = c.Get("key2")
+		g.Expect(found).To(BeFalse())
+	})
+	t.Run("should keep entries of caches not implementing Clearer", func(t *testing.T) {
+		g := NewWithT(t)
+		c := &mapCache{entries: map[string]string{"key": "value"}}
+
+		g.Expect(cache.Clear[string](c)).To(BeFalse())
+
+		value, found := c.Get("key")
+		g.Expect(found).To(BeTrue())
+		g.Expect(value).To(Equal("value"))
+	})
 }
 
+// mapCache is a cache implementing only Interface, as user-supplied caches may.
+type mapCache struct {
+	entries map[string]string
+}
+
+func (c *mapCache) Get(key string) (string, bool) {
+	value, found := c.entries[key]
+
+	return value, found
+}
+
+func (c *mapCache) Set(key string, value string) {
+	c.entries[key] = value
+}
+
+func (c *mapCache) Sync() {}
+
 func TestRenderCache(t *testing.T) {
 
 	t.Run("should cache and retrieve results", func(t *testing.T) {
@@ -349,4 +391,15 @@ func TestRenderCache(t *testing.T) {
 		_, found = c.Get(key)
 		g.Expect(found).To(BeFalse())
 	})
+	t.Run("should remove all entries on Clear", func(t *testing.T) {
+		g := NewWithT(t)
+		c := cache.NewRenderCache(cache.WithTTL(5 * time.Minute))
+
+		c.Set("key", []unstructured.Unstructured{{Object: map[string]any{"kind": "Pod"}}})
+
+		g.Expect(cache.Clear(c)).To(BeTrue())
+
+		_, found := c.Get("key")
+		g.Expect(found).To(BeFalse())
+	})
 }
@@ -0,0 +1,180 @@
+package cel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util"
+)
+
+const (
+	// ObjectVariable is the name of the variable holding the object being evaluated,
+	// matching the variable exposed by Kubernetes admission policies.
+	ObjectVariable = "object"
+
+	// interruptCheckFrequency is the number of comprehension iterations between checks of the
+	// context cancellation.
+	interruptCheckFrequency = 100
+)
+
+var (
+	// ErrCelUnsupportedType is returned when a CEL value cannot be converted to a Go value.
+	ErrCelUnsupportedType = errors.New("cel: unsupported result type")
+)
+
+// variable represents a CEL variable with its name and value.
+type variable struct {
+	name  string
+	value any
+}
+
+// Engine represents a CEL execution engine.
+type Engine struct {
+	program   cel.Program
+	variables []variable
+}
+
+// Option is a generic option for Engine.
+type Option = util.Option[Engine]
+
+// Options is a struct-based option that can set multiple engine options at once.
+type Options struct {
+	// Variables are CEL variables to make available during execution.
+	Variables []variable
+}
+
+// ApplyTo applies the CEL engine options to the target engine.
+func (opts Options) ApplyTo(target *Engine) {
+	target.variables = opts.Variables
+}
+
+// WithVariable adds a variable to the CEL engine.
+func WithVariable(name string, value any) Option {
+	return util.FunctionalOption[Engine](func(e *Engine) {
+		e.variables = append(e.variables, variable{
+			name:  name,
+			value: value,
+		})
+	})
+}
+
+// NewEngine creates a new CEL engine with the given expression and options.
+// The object is available as the object variable; optional types and the
+// strings extension are enabled.
+func NewEngine(expression string, opts ...Option) (*Engine, error) {
+	e := &Engine{
+		variables: make([]variable, 0),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt.ApplyTo(e)
+	}
+
+	envOpts := []cel.EnvOption{
+		cel.Variable(ObjectVariable, cel.DynType),
+		cel.OptionalTypes(),
+		ext.Strings(),
+	}
+
+	for _, v := range e.variables {
+		envOpts = append(envOpts, cel.Variable(v.name, cel.DynType))
+	}
+
+	env, err := cel.NewEnv(envOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("failed to compile CEL expression: %w", iss.Err())
+	}
+
+	program, err := env.Program(ast, cel.InterruptCheckFrequency(interruptCheckFrequency))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL program: %w", err)
+	}
+
+	e.program = program
+
+	return e, nil
+}
+
+// Run evaluates the CEL expression against the given object and returns the
+// result converted to plain Go values (maps, slices, strings, int64, float64, bool, nil).
+func (e *Engine) Run(ctx context.Context, object map[string]any) (any, error) {
+	activation := make(map[string]any, len(e.variables)+1)
+	for _, v := range e.variables {
+		activation[v.name] = v.value
+	}
+
+	activation[ObjectVariable] = object
+
+	out, _, err := e.program.ContextEval(ctx, activation)
+	if err != nil {
+		return nil, fmt.Errorf("cel: error during evaluation: %w", err)
+	}
+
+	return toNative(out)
+}
+
+// toNative converts a CEL value to the Go types used by unstructured objects.
+func toNative(val ref.Val) (any, error) {
+	switch v := val.(type) {
+	case types.Null:
+		return nil, nil
+	case types.Bool:
+		return bool(v), nil
+	case types.Int:
+		return int64(v), nil
+	case types.Uint:
+		return int64(v), nil
+	case types.Double:
+		return float64(v), nil
+	case types.String:
+		return string(v), nil
+	case traits.Mapper:
+		ret := make(map[string]any)
+
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+
+			k, ok := key.(types.String)
+			if !ok {
+				return nil, fmt.Errorf("%w: map key of type %s", ErrCelUnsupportedType, key.Type())
+			}
+
+			item, err := toNative(v.Get(key))
+			if err != nil {
+				return nil, err
+			}
+
+			ret[string(k)] = item
+		}
+
+		return ret, nil
+	case traits.Lister:
+		ret := make([]any, 0)
+
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			item, err := toNative(it.Next())
+			if err != nil {
+				return nil, err
+			}
+
+			ret = append(ret, item)
+		}
+
+		return ret, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrCelUnsupportedType, val.Type())
+	}
+}
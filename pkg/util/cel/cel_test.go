@@ -0,0 +1,85 @@
+package cel_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/cel"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewEngine(t *testing.T) {
+
+	t.Run("should create engine with valid expression", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`object.kind`)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(engine).ToNot(BeNil())
+	})
+
+	t.Run("should return error for invalid expression", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`object.kind ==`)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to compile CEL expression"))
+		g.Expect(engine).To(BeNil())
+	})
+
+	t.Run("should return error for undeclared variable", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`unknown.kind`)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(engine).To(BeNil())
+	})
+}
+
+func TestEngineRun(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should extract field from object", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`object.name`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := engine.Run(ctx, map[string]any{"name": "test-pod"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal("test-pod"))
+	})
+
+	t.Run("should convert results to plain values", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`{"replicas": 3, "ratio": 0.5, "tags": ["a", "b"], "empty": null}`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := engine.Run(ctx, map[string]any{})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(map[string]any{
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"tags":     []any{"a", "b"},
+			"empty":    nil,
+		}))
+	})
+
+	t.Run("should use variables", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(
+			`object.count * factor`,
+			cel.WithVariable("factor", int64(2)),
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := engine.Run(ctx, map[string]any{"count": int64(21)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(Equal(int64(42)))
+	})
+
+	t.Run("should return error for unsupported result types", func(t *testing.T) {
+		g := NewWithT(t)
+		engine, err := cel.NewEngine(`duration("1s")`)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		_, err = engine.Run(ctx, map[string]any{})
+		g.Expect(err).To(MatchError(cel.ErrCelUnsupportedType))
+	})
+}
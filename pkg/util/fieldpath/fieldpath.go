@@ -0,0 +1,269 @@
+// Package fieldpath parses dotted field paths, e.g. "spec.template.spec.containers[*].image",
+// and reads, updates or removes the values they select in unstructured content.
+package fieldpath
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath is returned for malformed field paths.
+var ErrInvalidPath = errors.New("invalid field path")
+
+// segment is a single step of a field path: a map key, a list index or a wildcard.
+type segment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Path is a parsed field path.
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+// Parse parses a dotted field path such as "spec.template.spec.containers[*].image".
+// List elements are selected with [N] or [*], and keys containing dots are written in
+// brackets, e.g. metadata.labels['app.kubernetes.io/name']. A leading dot is optional.
+// Wildcards select every element of a list or every value of a map.
+func Parse(path string) (Path, error) {
+	segments, err := parseSegments(path)
+	if err != nil {
+		return Path{}, err
+	}
+
+	return Path{raw: path, segments: segments}, nil
+}
+
+// MustParse is like Parse but panics on malformed paths.
+func MustParse(path string) Path {
+	p, err := Parse(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// String returns the path as written.
+func (p Path) String() string {
+	return p.raw
+}
+
+// Lookup returns the non-null values selected by the path. Values of maps selected by a
+// wildcard are returned in key order.
+func (p Path) Lookup(content any) []any {
+	return lookup(content, p.segments)
+}
+
+// Update replaces the non-null values selected by the path with the result of fn, in place.
+// Missing fields are not created.
+func (p Path) Update(content any, fn func(value any) any) {
+	update(content, p.segments, fn)
+}
+
+// Remove removes the fields and list elements selected by the path, in place. Removing list
+// elements requires the path to select the list through a map key.
+func (p Path) Remove(content any) {
+	if len(p.segments) > 0 {
+		remove(content, p.segments)
+	}
+}
+
+// parseSegments parses a dotted field path into its segments.
+func parseSegments(path string) ([]segment, error) {
+	p := strings.TrimPrefix(path, ".")
+	if p == "" {
+		return nil, fmt.Errorf("%w: %q is empty", ErrInvalidPath, path)
+	}
+
+	segments := make([]segment, 0)
+
+	for i := 0; i < len(p); {
+		switch p[i] {
+		case '.':
+			i++
+			if i == len(p) || p[i] == '.' || p[i] == '[' {
+				return nil, fmt.Errorf("%w: %q has an empty key", ErrInvalidPath, path)
+			}
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q has an unterminated bracket", ErrInvalidPath, path)
+			}
+
+			s, err := parseBracket(p[i+1 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidPath, path, err)
+			}
+
+			segments = append(segments, s)
+			i += end + 1
+		default:
+			if i > 0 && p[i-1] == ']' {
+				return nil, fmt.Errorf("%w: %q is missing a dot after a bracket", ErrInvalidPath, path)
+			}
+
+			j := i
+			for j < len(p) && p[j] != '.' && p[j] != '[' {
+				j++
+			}
+
+			segments = append(segments, segment{key: p[i:j]})
+			i = j
+		}
+	}
+
+	return segments, nil
+}
+
+// parseBracket parses the content of a bracket: *, a non-negative index or a quoted key.
+func parseBracket(content string) (segment, error) {
+	if content == "*" {
+		return segment{wildcard: true}, nil
+	}
+
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		return segment{key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return segment{}, fmt.Errorf("invalid index %q", content)
+	}
+
+	return segment{index: index, isIndex: true}, nil
+}
+
+// lookup returns the non-null values selected by the segments. Wildcards select every element
+// of a list, or every value of a map in key order.
+func lookup(value any, segments []segment) []any {
+	if value == nil {
+		return nil
+	}
+
+	if len(segments) == 0 {
+		return []any{value}
+	}
+
+	s, rest := segments[0], segments[1:]
+
+	switch v := value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			keys := slices.Sorted(maps.Keys(v))
+
+			result := make([]any, 0, len(keys))
+			for _, k := range keys {
+				result = append(result, lookup(v[k], rest)...)
+			}
+
+			return result
+		}
+
+		if s.isIndex {
+			return nil
+		}
+
+		return lookup(v[s.key], rest)
+	case []any:
+		if s.wildcard {
+			result := make([]any, 0, len(v))
+			for _, item := range v {
+				result = append(result, lookup(item, rest)...)
+			}
+
+			return result
+		}
+
+		if !s.isIndex || s.index >= len(v) {
+			return nil
+		}
+
+		return lookup(v[s.index], rest)
+	default:
+		return nil
+	}
+}
+
+// update replaces the non-null values selected by the segments with the result of fn, and
+// returns value with the replacement applied.
+func update(value any, segments []segment, fn func(value any) any) any {
+	if value == nil {
+		return nil
+	}
+
+	if len(segments) == 0 {
+		return fn(value)
+	}
+
+	s, rest := segments[0], segments[1:]
+
+	switch v := value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			for k := range v {
+				v[k] = update(v[k], rest, fn)
+			}
+		} else if item, ok := v[s.key]; ok && !s.isIndex {
+			v[s.key] = update(item, rest, fn)
+		}
+	case []any:
+		if s.wildcard {
+			for i := range v {
+				v[i] = update(v[i], rest, fn)
+			}
+		} else if s.isIndex && s.index < len(v) {
+			v[s.index] = update(v[s.index], rest, fn)
+		}
+	}
+
+	return value
+}
+
+// remove removes the fields and list elements selected by the segments, and returns value with
+// the removal applied; lists are returned shortened when their elements are removed.
+func remove(value any, segments []segment) any {
+	s, rest := segments[0], segments[1:]
+
+	switch v := value.(type) {
+	case map[string]any:
+		switch {
+		case len(rest) == 0 && s.wildcard:
+			clear(v)
+		case len(rest) == 0 && !s.isIndex:
+			delete(v, s.key)
+		case s.wildcard:
+			for k := range v {
+				v[k] = remove(v[k], rest)
+			}
+		case !s.isIndex:
+			if item, ok := v[s.key]; ok {
+				v[s.key] = remove(item, rest)
+			}
+		}
+	case []any:
+		switch {
+		case len(rest) == 0 && s.wildcard:
+			return v[:0]
+		case len(rest) == 0 && s.isIndex:
+			if s.index < len(v) {
+				return slices.Delete(v, s.index, s.index+1)
+			}
+		case s.wildcard:
+			for i := range v {
+				v[i] = remove(v[i], rest)
+			}
+		case s.isIndex && s.index < len(v):
+			v[s.index] = remove(v[s.index], rest)
+		}
+	}
+
+	return value
+}
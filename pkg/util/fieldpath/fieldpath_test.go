@@ -0,0 +1,102 @@
+package fieldpath_test
+
+import (
+	"testing"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/fieldpath"
+
+	. "github.com/onsi/gomega"
+)
+
+func makeContent() map[string]any {
+	return map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				"app.kubernetes.io/name": "web",
+				"tier":                   "frontend",
+			},
+		},
+		"spec": map[string]any{
+			"containers": []any{
+				map[string]any{"name": "app", "image": "nginx"},
+				map[string]any{"name": "sidecar", "image": "envoy"},
+			},
+		},
+	}
+}
+
+func TestLookup(t *testing.T) {
+	g := NewWithT(t)
+
+	content := makeContent()
+
+	g.Expect(fieldpath.MustParse("spec.containers[*].image").Lookup(content)).Should(Equal([]any{"nginx", "envoy"}))
+	g.Expect(fieldpath.MustParse("spec.containers[1].name").Lookup(content)).Should(Equal([]any{"sidecar"}))
+	g.Expect(fieldpath.MustParse("metadata.labels['app.kubernetes.io/name']").Lookup(content)).Should(Equal([]any{"web"}))
+	g.Expect(fieldpath.MustParse("metadata.labels[*]").Lookup(content)).Should(Equal([]any{"web", "frontend"}))
+	g.Expect(fieldpath.MustParse("spec.missing").Lookup(content)).Should(BeEmpty())
+}
+
+func TestUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	content := makeContent()
+
+	fieldpath.MustParse("spec.containers[*].image").Update(content, func(v any) any {
+		return v.(string) + ":latest"
+	})
+	fieldpath.MustParse("spec.missing").Update(content, func(_ any) any {
+		return "created"
+	})
+
+	g.Expect(fieldpath.MustParse("spec.containers[*].image").Lookup(content)).Should(Equal([]any{"nginx:latest", "envoy:latest"}))
+	g.Expect(content["spec"]).ShouldNot(HaveKey("missing"))
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("should remove map keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		content := makeContent()
+		fieldpath.MustParse("spec.containers[*].image").Remove(content)
+		fieldpath.MustParse("metadata.labels.tier").Remove(content)
+
+		g.Expect(fieldpath.MustParse("spec.containers[*].image").Lookup(content)).Should(BeEmpty())
+		g.Expect(fieldpath.MustParse("spec.containers[*].name").Lookup(content)).Should(HaveLen(2))
+		g.Expect(content["metadata"]).Should(HaveKeyWithValue("labels", HaveLen(1)))
+	})
+
+	t.Run("should remove list elements", func(t *testing.T) {
+		g := NewWithT(t)
+
+		content := makeContent()
+		fieldpath.MustParse("spec.containers[0]").Remove(content)
+
+		g.Expect(fieldpath.MustParse("spec.containers[*].name").Lookup(content)).Should(Equal([]any{"sidecar"}))
+
+		fieldpath.MustParse("spec.containers[*]").Remove(content)
+		g.Expect(content["spec"]).Should(HaveKeyWithValue("containers", BeEmpty()))
+	})
+
+	t.Run("should clear maps", func(t *testing.T) {
+		g := NewWithT(t)
+
+		content := makeContent()
+		fieldpath.MustParse("metadata.labels[*]").Remove(content)
+
+		g.Expect(content["metadata"]).Should(HaveKeyWithValue("labels", BeEmpty()))
+	})
+}
+
+func TestParse(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, path := range []string{"", "spec..containers", "spec.containers[", "spec.containers[-1]", "spec[0]name"} {
+		_, err := fieldpath.Parse(path)
+		g.Expect(err).To(MatchError(fieldpath.ErrInvalidPath), path)
+	}
+
+	p, err := fieldpath.Parse(".spec.replicas")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(p.String()).Should(Equal(".spec.replicas"))
+}
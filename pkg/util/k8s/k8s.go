@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // DeepCloneUnstructuredSlice creates a deep copy of a slice of unstructured objects.
@@ -29,6 +32,8 @@ func DeepCloneUnstructuredSlice(objects []unstructured.Unstructured) []unstructu
 }
 
 // DecodeYAML decodes YAML content into a slice of unstructured objects.
+// Documents may be single objects or sequences of objects, so JSON arrays such as the
+// ones produced by cdk8s are decoded as well. Entries without apiVersion or kind are skipped.
 func DecodeYAML(content []byte) ([]unstructured.Unstructured, error) {
 	results := make([]unstructured.Unstructured, 0)
 
@@ -37,7 +42,7 @@ func DecodeYAML(content []byte) ([]unstructured.Unstructured, error) {
 
 	docIndex := 0
 	for {
-		var out map[string]any
+		var out any
 
 		err := yd.Decode(&out)
 		if err != nil {
@@ -50,32 +55,125 @@ func DecodeYAML(content []byte) ([]unstructured.Unstructured, error) {
 
 		docIndex++
 
-		if len(out) == 0 {
+		var items []any
+
+		switch v := out.(type) {
+		case map[string]any:
+			items = []any{v}
+		case []any:
+			items = v
+		default:
 			continue
 		}
 
-		// Validate kind field exists and is a non-empty string
-		kind, ok := out["kind"].(string)
-		if !ok || kind == "" {
-			continue
+		for _, item := range items {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			obj, err := decodeObject(m)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode YAML document[%d]: %w", docIndex-1, err)
+			}
+
+			if obj != nil {
+				results = append(results, *obj)
+			}
 		}
+	}
 
-		// Validate apiVersion field exists and is a non-empty string
-		apiVersion, ok := out["apiVersion"].(string)
-		if !ok || apiVersion == "" {
-			continue
+	return results, nil
+}
+
+// EncodeYAML encodes a slice of unstructured objects into a multi-document YAML stream.
+func EncodeYAML(objects []unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for i := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
 		}
 
-		obj, err := ToUnstructured(&out)
+		data, err := yaml.Marshal(objects[i].Object)
 		if err != nil {
-			if runtime.IsMissingKind(err) {
-				continue
-			}
+			return nil, fmt.Errorf("unable to encode %s %s: %w", objects[i].GetKind(), objects[i].GetName(), err)
+		}
+
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeObject converts a decoded document into an unstructured object.
+// It returns nil if the document is not a Kubernetes object.
+func decodeObject(out map[string]any) (*unstructured.Unstructured, error) {
+	if len(out) == 0 {
+		return nil, nil //nolint:nilnil // not an object, skipped by the caller
+	}
+
+	// Validate kind field exists and is a non-empty string
+	kind, ok := out["kind"].(string)
+	if !ok || kind == "" {
+		return nil, nil //nolint:nilnil // not an object, skipped by the caller
+	}
 
-			return nil, fmt.Errorf("unable to decode YAML document[%d]: %w", docIndex-1, err)
+	// Validate apiVersion field exists and is a non-empty string
+	apiVersion, ok := out["apiVersion"].(string)
+	if !ok || apiVersion == "" {
+		return nil, nil //nolint:nilnil // not an object, skipped by the caller
+	}
+
+	obj, err := ToUnstructured(&out)
+	if err != nil {
+		if runtime.IsMissingKind(err) {
+			return nil, nil //nolint:nilnil // not an object, skipped by the caller
+		}
+
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// ExpandList unwraps list objects (kind List or any *List kind with an items field) into
+// their items. Nested lists are expanded recursively; other objects are returned unchanged.
+func ExpandList(obj unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	if !strings.HasSuffix(obj.GetKind(), "List") || !obj.IsList() {
+		return []unstructured.Unstructured{obj}, nil
+	}
+
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert %s to list: %w", obj.GetKind(), err)
+	}
+
+	results := make([]unstructured.Unstructured, 0, len(list.Items))
+
+	for i := range list.Items {
+		items, err := ExpandList(list.Items[i])
+		if err != nil {
+			return nil, err
 		}
 
-		results = append(results, *obj)
+		results = append(results, items...)
+	}
+
+	return results, nil
+}
+
+// ExpandLists applies ExpandList to every object of the slice.
+func ExpandLists(objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	results := make([]unstructured.Unstructured, 0, len(objects))
+
+	for i := range objects {
+		items, err := ExpandList(objects[i])
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, items...)
 	}
 
 	return results, nil
@@ -94,3 +192,169 @@ func ToUnstructured(obj any) (*unstructured.Unstructured, error) {
 
 	return &u, nil
 }
+
+// clusterScopedKinds are the built-in Kubernetes kinds that are not namespaced.
+var clusterScopedKinds = map[schema.GroupKind]struct{}{
+	{Group: "", Kind: "ComponentStatus"}:                                              {},
+	{Group: "", Kind: "Namespace"}:                                                    {},
+	{Group: "", Kind: "Node"}:                                                         {},
+	{Group: "", Kind: "PersistentVolume"}:                                             {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingAdmissionPolicy"}:          {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingAdmissionPolicyBinding"}:   {},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: {},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   {},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 {},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             {},
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 {},
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"}:                        {},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       {},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       {},
+	{Group: "networking.k8s.io", Kind: "IPAddress"}:                                   {},
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                {},
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"}:                                 {},
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      {},
+	{Group: "policy", Kind: "PodSecurityPolicy"}:                                      {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         {},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  {},
+	{Group: "resource.k8s.io", Kind: "DeviceClass"}:                                   {},
+	{Group: "resource.k8s.io", Kind: "ResourceSlice"}:                                 {},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               {},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      {},
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        {},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   {},
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               {},
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"}:                          {},
+}
+
+// IsClusterScoped reports whether objects of the given kind are cluster-scoped.
+// Built-in kinds are known; custom kinds are resolved from the given CustomResourceDefinitions,
+// for instance the ones rendered alongside the objects. Unknown kinds are assumed to be namespaced.
+func IsClusterScoped(gk schema.GroupKind, crds ...unstructured.Unstructured) bool {
+	if _, ok := clusterScopedKinds[gk]; ok {
+		return true
+	}
+
+	for i := range crds {
+		if crds[i].GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(crds[i].Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crds[i].Object, "spec", "names", "kind")
+
+		if group == gk.Group && kind == gk.Kind {
+			scope, _, _ := unstructured.NestedString(crds[i].Object, "spec", "scope")
+
+			return scope == "Cluster"
+		}
+	}
+
+	return false
+}
+
+// containerFields are the pod spec fields holding container lists.
+var containerFields = []string{"containers", "initContainers", "ephemeralContainers"}
+
+// VisitContainers calls fn for every container found in the object content, e.g. the
+// containers of a Pod, of a workload pod template or of a CronJob job template.
+// Container lists are searched recursively so custom resources embedding pod specs are
+// supported as well. Containers are passed as maps and can be modified in place.
+func VisitContainers(content map[string]any, fn func(container map[string]any) error) error {
+	for key, value := range content {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := VisitContainers(v, fn); err != nil {
+				return err
+			}
+		case []any:
+			if slices.Contains(containerFields, key) {
+				for _, item := range v {
+					if container, ok := item.(map[string]any); ok {
+						if err := fn(container); err != nil {
+							return err
+						}
+					}
+				}
+
+				continue
+			}
+
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					if err := VisitContainers(m, fn); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// VisitPodSpecs calls fn for every pod spec found in the object content, e.g. the spec of a Pod,
+// the pod template of a workload or the job template of a CronJob. Pod specs are recognized as
+// maps holding a containers list and are searched recursively, like VisitContainers, so custom
+// resources embedding pod specs are supported as well. Pod specs can be modified in place.
+func VisitPodSpecs(content map[string]any, fn func(podSpec map[string]any) error) error {
+	if _, ok := content["containers"].([]any); ok {
+		return fn(content)
+	}
+
+	for _, value := range content {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := VisitPodSpecs(v, fn); err != nil {
+				return err
+			}
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					if err := VisitPodSpecs(m, fn); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// VisitPodTemplates calls fn for every pod template found in the object content, e.g. the
+// template of a workload or the pod template of a CronJob job template. Pod templates are
+// recognized as maps whose spec is a pod spec and are searched recursively, like VisitPodSpecs.
+// The object itself is never visited, so a Pod has no pod template. Pod templates can be
+// modified in place.
+func VisitPodTemplates(content map[string]any, fn func(template map[string]any) error) error {
+	for _, value := range content {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := visitPodTemplates(v, fn); err != nil {
+				return err
+			}
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					if err := visitPodTemplates(m, fn); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func visitPodTemplates(content map[string]any, fn func(template map[string]any) error) error {
+	if spec, ok := content["spec"].(map[string]any); ok {
+		if _, ok := spec["containers"].([]any); ok {
+			return fn(content)
+		}
+	}
+
+	return VisitPodTemplates(content, fn)
+}
@@ -1,9 +1,11 @@
 package k8s_test
 
 import (
+	"errors"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
 
@@ -110,6 +112,28 @@ metadata:
   invalid: [unclosed bracket
 `
 
+const jsonArray = `[
+  {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config1"}},
+  {"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "secret1"}},
+  {"metadata": {"name": "not-an-object"}}
+]`
+
+const yamlSequence = `
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: config1
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: secret1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deployment1
+`
+
 const yamlWithComments = `
 # This is a comment
 apiVersion: v1
@@ -250,6 +274,29 @@ func TestDecodeYAML(t *testing.T) {
 		g.Expect(result[2].GetKind()).Should(Equal("Deployment"))
 	})
 
+	t.Run("decodes JSON arrays", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := k8s.DecodeYAML([]byte(jsonArray))
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(2))
+		g.Expect(result[0].GetKind()).Should(Equal("ConfigMap"))
+		g.Expect(result[1].GetKind()).Should(Equal("Secret"))
+	})
+
+	t.Run("decodes YAML sequences mixed with documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := k8s.DecodeYAML([]byte(yamlSequence))
+
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(3))
+		g.Expect(result[0].GetName()).Should(Equal("config1"))
+		g.Expect(result[1].GetName()).Should(Equal("secret1"))
+		g.Expect(result[2].GetName()).Should(Equal("deployment1"))
+	})
+
 	t.Run("skips empty documents", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -352,6 +399,107 @@ func TestDecodeYAML(t *testing.T) {
 	})
 }
 
+const listYAML = `
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: config1
+  - apiVersion: v1
+    kind: ConfigMapList
+    items:
+      - apiVersion: v1
+        kind: ConfigMap
+        metadata:
+          name: config2
+      - apiVersion: v1
+        kind: ConfigMap
+        metadata:
+          name: config3
+`
+
+func TestExpandLists(t *testing.T) {
+	t.Run("expands nested lists", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(listYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		result, err := k8s.ExpandLists(objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(HaveLen(3))
+		g.Expect(result[0].GetName()).Should(Equal("config1"))
+		g.Expect(result[1].GetName()).Should(Equal("config2"))
+		g.Expect(result[2].GetName()).Should(Equal("config3"))
+	})
+
+	t.Run("keeps non-list objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(multipleDocumentsYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		result, err := k8s.ExpandLists(objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(result).Should(Equal(objects))
+	})
+}
+
+func TestEncodeYAML(t *testing.T) {
+	t.Run("round-trips multiple documents", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(multipleDocumentsYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(len(objects)).Should(BeNumerically(">", 1))
+
+		data, err := k8s.EncodeYAML(objects)
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		decoded, err := k8s.DecodeYAML(data)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(decoded).Should(Equal(objects))
+	})
+
+	t.Run("encodes no objects as empty content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		data, err := k8s.EncodeYAML(nil)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(data).Should(BeEmpty())
+	})
+}
+
+func TestIsClusterScoped(t *testing.T) {
+	crd := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]any{
+			"group": "example.com",
+			"scope": "Cluster",
+			"names": map[string]any{"kind": "Widget"},
+		},
+	}}
+
+	t.Run("detects built-in cluster-scoped kinds", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(k8s.IsClusterScoped(schema.GroupKind{Kind: "Namespace"})).Should(BeTrue())
+		g.Expect(k8s.IsClusterScoped(schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"})).Should(BeTrue())
+		g.Expect(k8s.IsClusterScoped(schema.GroupKind{Kind: "ConfigMap"})).Should(BeFalse())
+	})
+
+	t.Run("resolves custom kinds from CRDs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(k8s.IsClusterScoped(schema.GroupKind{Group: "example.com", Kind: "Widget"}, crd)).Should(BeTrue())
+		g.Expect(k8s.IsClusterScoped(schema.GroupKind{Group: "example.com", Kind: "Gadget"}, crd)).Should(BeFalse())
+	})
+}
+
 func TestToUnstructured(t *testing.T) {
 	t.Run("converts map to unstructured", func(t *testing.T) {
 		g := NewWithT(t)
@@ -441,3 +589,142 @@ func TestToUnstructured(t *testing.T) {
 		g.Expect(spec).Should(HaveKey("selector"))
 	})
 }
+
+const cronJobYAML = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: init
+            image: busybox
+          containers:
+          - name: backup
+            image: restic/restic
+          - name: sidecar
+            image: envoy
+`
+
+func TestVisitContainers(t *testing.T) {
+	t.Run("visits nested containers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(cronJobYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		images := make([]string, 0)
+		err = k8s.VisitContainers(objects[0].Object, func(container map[string]any) error {
+			images = append(images, container["image"].(string))
+			container["image"] = "registry.example.com/" + container["image"].(string)
+
+			return nil
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(images).Should(ConsistOf("busybox", "restic/restic", "envoy"))
+
+		containers, found, err := unstructured.NestedSlice(objects[0].Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(containers[0]).Should(HaveKeyWithValue("image", "registry.example.com/restic/restic"))
+	})
+
+	t.Run("propagates errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(cronJobYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = k8s.VisitContainers(objects[0].Object, func(_ map[string]any) error {
+			return errors.New("boom")
+		})
+		g.Expect(err).Should(MatchError("boom"))
+	})
+}
+
+func TestVisitPodSpecs(t *testing.T) {
+	t.Run("visits nested pod specs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(cronJobYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		count := 0
+		err = k8s.VisitPodSpecs(objects[0].Object, func(podSpec map[string]any) error {
+			count++
+			podSpec["serviceAccountName"] = "backup"
+
+			return nil
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(count).Should(Equal(1))
+
+		name, found, err := unstructured.NestedString(objects[0].Object, "spec", "jobTemplate", "spec", "template", "spec", "serviceAccountName")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(name).Should(Equal("backup"))
+	})
+
+	t.Run("propagates errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(cronJobYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+
+		err = k8s.VisitPodSpecs(objects[0].Object, func(_ map[string]any) error {
+			return errors.New("boom")
+		})
+		g.Expect(err).Should(MatchError("boom"))
+	})
+}
+
+func TestVisitPodTemplates(t *testing.T) {
+	t.Run("visits nested pod templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := k8s.DecodeYAML([]byte(cronJobYAML))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(objects).Should(HaveLen(1))
+
+		count := 0
+		err = k8s.VisitPodTemplates(objects[0].Object, func(template map[string]any) error {
+			count++
+
+			return unstructured.SetNestedField(template, "backup", "metadata", "labels", "app")
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(count).Should(Equal(1))
+
+		app, found, err := unstructured.NestedString(objects[0].Object, "spec", "jobTemplate", "spec", "template", "metadata", "labels", "app")
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(app).Should(Equal("backup"))
+	})
+
+	t.Run("does not visit the object itself", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pod := map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "app"}},
+			},
+		}
+
+		count := 0
+		err := k8s.VisitPodTemplates(pod, func(_ map[string]any) error {
+			count++
+
+			return nil
+		})
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(count).Should(BeZero())
+	})
+}
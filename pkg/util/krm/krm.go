@@ -0,0 +1,200 @@
+// Package krm runs KRM functions, executables or containers reading a ResourceList on stdin
+// and writing the resulting ResourceList on stdout, as used by the Kustomize and kpt ecosystems.
+package krm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/container"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/exec"
+	"sigs.k8s.io/kustomize/kyaml/fn/runtime/runtimeutil"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/k8s"
+)
+
+const (
+	// containerUser is the user functions run as inside containers, matching kpt defaults.
+	containerUser = "nobody"
+
+	// internalAnnotationPrefix is the prefix of the bookkeeping annotations of the kio framework.
+	internalAnnotationPrefix = "internal.config.kubernetes.io/"
+)
+
+var (
+	// ErrInvalidFunction is returned when a function declares neither or both of image and exec.
+	ErrInvalidFunction = errors.New("function must specify exactly one of image or exec")
+)
+
+// Function describes a KRM function.
+type Function struct {
+	// Image is the container image implementing the function. Mutually exclusive with Exec.
+	Image string
+
+	// Exec is the path to an executable implementing the function. Mutually exclusive with Image.
+	Exec string
+
+	// Args are the arguments passed to the executable. Only used with Exec.
+	Args []string
+
+	// Env are the environment variables, in KEY=VALUE form, exposed to the function.
+	Env []string
+
+	// WorkingDir is the directory the executable runs in. Only used with Exec.
+	// Default: the current working directory.
+	WorkingDir string
+
+	// Network enables network access for container functions. Default: false.
+	Network bool
+
+	// Config is the functionConfig object passed through ResourceList.functionConfig. Optional.
+	Config map[string]any
+}
+
+// String returns a human readable identifier for the function.
+func (f Function) String() string {
+	if f.Image != "" {
+		return f.Image
+	}
+
+	return f.Exec
+}
+
+// Validate checks if the Function configuration is valid.
+func (f Function) Validate() error {
+	if (strings.TrimSpace(f.Image) == "") == (strings.TrimSpace(f.Exec) == "") {
+		return ErrInvalidFunction
+	}
+
+	return nil
+}
+
+// Run executes the function over the given objects and returns the resulting objects.
+func Run(ctx context.Context, fn Function, objects []unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	if err := fn.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	filter, err := newFilter(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*kyaml.RNode, 0, len(objects))
+	for i := range objects {
+		node, err := kyaml.FromMap(objects[i].Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s/%s: %w", objects[i].GetKind(), objects[i].GetName(), err)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	nodes, err = filter.Filter(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("function %s failed: %w", fn, err)
+	}
+
+	result := make([]unstructured.Unstructured, 0, len(nodes))
+
+	for _, node := range nodes {
+		if err := clearInternalAnnotations(node); err != nil {
+			return nil, err
+		}
+
+		m, err := node.Map()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert function output: %w", err)
+		}
+
+		// Normalize values (e.g. int to int64) to what unstructured objects expect
+		obj, err := k8s.ToUnstructured(&m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert function output: %w", err)
+		}
+
+		result = append(result, *obj)
+	}
+
+	return result, nil
+}
+
+// newFilter creates the kio.Filter running the function.
+func newFilter(fn Function) (kio.Filter, error) {
+	var config *kyaml.RNode
+
+	if len(fn.Config) > 0 {
+		c, err := kyaml.FromMap(fn.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid function config: %w", err)
+		}
+
+		config = c
+	}
+
+	if fn.Exec != "" {
+		dir := fn.WorkingDir
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine working directory: %w", err)
+			}
+
+			dir = wd
+		}
+
+		return &exec.Filter{
+			Path:       fn.Exec,
+			Args:       fn.Args,
+			Env:        fn.Env,
+			WorkingDir: dir,
+			FunctionFilter: runtimeutil.FunctionFilter{
+				FunctionConfig: config,
+				GlobalScope:    true,
+			},
+		}, nil
+	}
+
+	filter := container.NewContainer(
+		runtimeutil.ContainerSpec{
+			Image:   fn.Image,
+			Network: fn.Network,
+			Env:     fn.Env,
+		},
+		containerUser,
+	)
+	filter.Exec.FunctionConfig = config
+	filter.Exec.GlobalScope = true
+
+	return &filter, nil
+}
+
+// clearInternalAnnotations removes the bookkeeping annotations added by the kio framework.
+func clearInternalAnnotations(node *kyaml.RNode) error {
+	for key := range node.GetAnnotations() {
+		if !strings.HasPrefix(key, internalAnnotationPrefix) &&
+			key != kioutil.LegacyPathAnnotation &&
+			key != kioutil.LegacyIndexAnnotation &&
+			key != kioutil.LegacyIdAnnotation {
+			continue
+		}
+
+		if err := node.PipeE(kyaml.ClearAnnotation(key)); err != nil {
+			return fmt.Errorf("failed to clear annotation %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package krm_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/lburgazzoli/k8s-manifests-lib/pkg/util/krm"
+
+	. "github.com/onsi/gomega"
+)
+
+// scaleFn is a KRM function that sets the replicas of every resource to 3.
+const scaleFn = `#!/bin/sh
+sed 's/replicas: 1/replicas: 3/'
+`
+
+// configFn is a KRM function that fails unless its functionConfig carries env: prod.
+const configFn = `#!/bin/sh
+input=$(cat)
+echo "$input" | grep -q "env: prod" || exit 1
+echo "$input"
+`
+
+func writeFunction(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "fn.sh")
+	if err := os.WriteFile(path, []byte(content), 0o700); err != nil { //nolint:gosec // test function must be executable
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newDeployment(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]any{"name": name},
+			"spec":       map[string]any{"replicas": int64(1)},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("should run exec functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		result, err := krm.Run(
+			t.Context(),
+			krm.Function{Exec: writeFunction(t, scaleFn)},
+			[]unstructured.Unstructured{newDeployment("web"), newDeployment("api")},
+		)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(HaveLen(2))
+
+		for _, obj := range result {
+			replicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+			g.Expect(replicas).To(BeEquivalentTo(3))
+			g.Expect(obj.GetAnnotations()).To(BeEmpty())
+		}
+	})
+
+	t.Run("should pass function config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fn := krm.Function{
+			Exec: writeFunction(t, configFn),
+			Config: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "config"},
+				"data":       map[string]any{"env": "prod"},
+			},
+		}
+
+		result, err := krm.Run(t.Context(), fn, []unstructured.Unstructured{newDeployment("web")})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result).To(HaveLen(1))
+
+		fn.Config = nil
+
+		_, err = krm.Run(t.Context(), fn, []unstructured.Unstructured{newDeployment("web")})
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("should reject invalid functions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := krm.Run(t.Context(), krm.Function{}, nil)
+		g.Expect(err).To(MatchError(krm.ErrInvalidFunction))
+
+		_, err = krm.Run(t.Context(), krm.Function{Image: "fn:v1", Exec: "fn"}, nil)
+		g.Expect(err).To(MatchError(krm.ErrInvalidFunction))
+	})
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"maps"
 	"sync"
 	"time"
 )
@@ -124,3 +125,32 @@ type RendererSummary struct {
 	TotalObjects    int
 	Errors          int
 }
+
+// RetryMetric collects renderer retries in memory.
+type RetryMetric struct {
+	mu      sync.RWMutex
+	Retries map[string]int
+}
+
+// NewRetryMetric creates a new retry metrics collector.
+func NewRetryMetric() *RetryMetric {
+	return &RetryMetric{
+		Retries: make(map[string]int),
+	}
+}
+
+// Observe records a renderer retry.
+func (m *RetryMetric) Observe(_ context.Context, rendererType string, _ int, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Retries[rendererType]++
+}
+
+// Summary returns a snapshot of the number of retries per renderer type.
+func (m *RetryMetric) Summary() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return maps.Clone(m.Retries)
+}
@@ -111,3 +111,20 @@ func TestRendererMetric(t *testing.T) {
 		g.Expect(helmStats.TotalObjects).To(Equal(10))
 	})
 }
+
+func TestRetryMetric(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should count retries per renderer", func(t *testing.T) {
+		g := NewWithT(t)
+		m := memory.NewRetryMetric()
+		m.Observe(ctx, "helm", 1, errors.New("test error"))
+		m.Observe(ctx, "helm", 2, errors.New("test error"))
+		m.Observe(ctx, "kustomize", 1, errors.New("test error"))
+
+		g.Expect(m.Summary()).To(Equal(map[string]int{
+			"helm":      2,
+			"kustomize": 1,
+		}))
+	})
+}
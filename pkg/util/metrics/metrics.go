@@ -52,6 +52,28 @@ type RendererMetric interface {
 	Observe(ctx context.Context, rendererType string, duration time.Duration, objectCount int, err error)
 }
 
+// RetryMetric observes retries of failed renderer executions.
+//
+// This interface is called once per retry, after a failed Renderer.Process() invocation and
+// before the next attempt, when the engine is configured with a retry policy.
+//
+// Implementations must be thread-safe as renderers may execute concurrently
+// when parallel rendering is enabled.
+type RetryMetric interface {
+	// Observe records a single retry.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and tracing
+	//   - rendererType: Type of renderer ("helm", "kustomize", "gotemplate", "yaml", "mem")
+	//   - attempt: Number of the failed attempt being retried, starting at 1
+	//   - err: Error of the failed attempt
+	//
+	// Example usage:
+	//   Observe(ctx, "helm", 1, fmt.Errorf("registry unavailable"))
+	//   // Records a retry of a helm render after its first attempt failed
+	Observe(ctx context.Context, rendererType string, attempt int, err error)
+}
+
 // Metrics holds all available metrics collectors.
 //
 // All fields are optional (may be nil). If a field is nil, the corresponding
@@ -78,6 +100,10 @@ type Metrics struct {
 	// RendererMetric collects renderer-specific metrics (one observation per renderer execution).
 	// Optional - may be nil.
 	RendererMetric RendererMetric
+
+	// RetryMetric collects renderer retries (one observation per retry).
+	// Optional - may be nil.
+	RetryMetric RetryMetric
 }
 
 type contextKey struct{}
@@ -141,3 +167,17 @@ func ObserveRender(ctx context.Context, duration time.Duration, objectCount int)
 		m.RenderMetric.Observe(ctx, duration, objectCount)
 	}
 }
+
+// ObserveRetry records a renderer retry if available in context.
+//
+// This is a convenience helper that safely handles cases where:
+//   - No metrics are in the context
+//   - Metrics exist but RetryMetric is nil
+//
+// Called internally by the engine when retrying failed renderers. Users typically
+// don't need to call this directly.
+func ObserveRetry(ctx context.Context, rendererType string, attempt int, err error) {
+	if m := FromContext(ctx); m != nil && m.RetryMetric != nil {
+		m.RetryMetric.Observe(ctx, rendererType, attempt, err)
+	}
+}
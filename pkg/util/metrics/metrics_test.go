@@ -96,6 +96,21 @@ func TestObserveRenderNilSafety(t *testing.T) {
 	})
 }
 
+func TestObserveRetryNilSafety(t *testing.T) {
+	t.Run("should safely no-op when no metrics in context", func(t *testing.T) {
+		metrics.ObserveRetry(t.Context(), "helm", 1, nil)
+	})
+
+	t.Run("should safely no-op when RetryMetric is nil", func(t *testing.T) {
+		m := &metrics.Metrics{
+			RendererMetric: memory.NewRendererMetric(),
+		}
+		ctx := metrics.WithMetrics(t.Context(), m)
+
+		metrics.ObserveRetry(ctx, "helm", 1, nil)
+	})
+}
+
 func TestThreadSafety(t *testing.T) {
 
 	t.Run("should be thread-safe for concurrent renderer observations", func(t *testing.T) {
@@ -18,3 +18,10 @@ type RendererMetric struct{}
 // Observe does nothing; it's a no-op implementation.
 func (RendererMetric) Observe(_ context.Context, _ string, _ time.Duration, _ int, _ error) {
 }
+
+// RetryMetric is a no-op retry metrics collector that discards all observations.
+type RetryMetric struct{}
+
+// Observe does nothing; it's a no-op implementation.
+func (RetryMetric) Observe(_ context.Context, _ string, _ int, _ error) {
+}
@@ -32,3 +32,15 @@ func TestRendererMetric(t *testing.T) {
 		}).ToNot(Panic())
 	})
 }
+
+func TestRetryMetric(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("should not panic", func(t *testing.T) {
+		g := NewWithT(t)
+		m := noop.RetryMetric{}
+		g.Expect(func() {
+			m.Observe(ctx, "helm", 1, nil)
+		}).ToNot(Panic())
+	})
+}
@@ -0,0 +1,114 @@
+// Package registry provides authentication and transport settings shared by renderers
+// that pull content from OCI registries and chart repositories.
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+var (
+	// ErrInvalidCA is returned when the configured CA file does not contain any valid certificate.
+	ErrInvalidCA = errors.New("no valid certificates found in CA file")
+
+	// ErrIncompleteClientCert is returned when only one of the client certificate and key is set.
+	ErrIncompleteClientCert = errors.New("client certificate and key must be set together")
+)
+
+// Auth holds the credentials and transport settings used to access a registry or repository.
+//
+// Credentials are resolved in order: Username/Password, Token, ConfigFile.
+// When none is set, access is anonymous.
+type Auth struct {
+	// Username and Password are used for basic authentication.
+	Username string
+	Password string
+
+	// Token is a bearer/identity token, used when Username is empty.
+	Token string
+
+	// ConfigFile is the path to a docker-style config.json holding credentials per registry.
+	ConfigFile string
+
+	// PlainHTTP allows connecting over HTTP instead of HTTPS.
+	PlainHTTP bool
+
+	// InsecureSkipTLSVerify disables verification of the server certificate.
+	InsecureSkipTLSVerify bool
+
+	// CAFile is the path to a PEM bundle used to verify the server certificate.
+	CAFile string
+
+	// CertFile and KeyFile are the paths to a PEM client certificate and key for mutual TLS.
+	CertFile string
+	KeyFile  string
+}
+
+// HasTLSConfig reports whether any TLS setting differs from the defaults.
+func (a *Auth) HasTLSConfig() bool {
+	return a.InsecureSkipTLSVerify || a.CAFile != "" || a.CertFile != "" || a.KeyFile != ""
+}
+
+// TLSConfig builds a tls.Config from the configured TLS settings.
+func (a *Auth) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: a.InsecureSkipTLSVerify, //nolint:gosec // explicitly requested by the caller
+	}
+
+	if a.CAFile != "" {
+		pem, err := os.ReadFile(a.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", a.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCA, a.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (a.CertFile == "") != (a.KeyFile == "") {
+		return nil, ErrIncompleteClientCert
+	}
+
+	if a.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(a.CertFile, a.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// HTTPClient returns an HTTP client honoring the TLS settings.
+// When no TLS setting is configured, http.DefaultClient is returned.
+func (a *Auth) HTTPClient() (*http.Client, error) {
+	if !a.HasTLSConfig() {
+		return http.DefaultClient, nil
+	}
+
+	config, err := a.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		transport = &http.Transport{}
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = config
+
+	return &http.Client{Transport: transport}, nil
+}
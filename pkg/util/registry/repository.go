@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"fmt"
+
+	"oras.land/oras-go/v2/registry/remote"
+	orasauth "oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// NewRepository creates a remote repository client for the given reference
+// (registry/repository[:tag|@digest]) configured with the authentication settings.
+func NewRepository(reference string, auth Auth) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(reference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference %q: %w", reference, err)
+	}
+
+	client, err := auth.HTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure registry client: %w", err)
+	}
+
+	credential, err := auth.Credential(repo.Reference.Registry)
+	if err != nil {
+		return nil, err
+	}
+
+	repo.PlainHTTP = auth.PlainHTTP
+	repo.Client = &orasauth.Client{
+		Client:     client,
+		Cache:      orasauth.NewCache(),
+		Credential: credential,
+	}
+
+	return repo, nil
+}
+
+// Credential resolves the credentials to use for a registry host.
+// Static credentials are only returned for the given host.
+func (a *Auth) Credential(host string) (orasauth.CredentialFunc, error) {
+	switch {
+	case a.Username != "":
+		return orasauth.StaticCredential(host, orasauth.Credential{
+			Username: a.Username,
+			Password: a.Password,
+		}), nil
+	case a.Token != "":
+		return orasauth.StaticCredential(host, orasauth.Credential{
+			AccessToken: a.Token,
+		}), nil
+	case a.ConfigFile != "":
+		store, err := credentials.NewStore(a.ConfigFile, credentials.StoreOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to load credentials from %s: %w", a.ConfigFile, err)
+		}
+
+		return credentials.Credential(store), nil
+	default:
+		return orasauth.StaticCredential(host, orasauth.EmptyCredential), nil
+	}
+}